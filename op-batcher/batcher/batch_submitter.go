@@ -53,6 +53,8 @@ func Main(version string, cliCtx *cli.Context) error {
 
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel() // Stop pprof and metrics only after main loop returns
+	// Close the journal only after the batcher is stopped
+	defer batchSubmitter.Journal.Close()
 	defer batchSubmitter.StopIfRunning(context.Background())
 
 	pprofConfig := cfg.PprofConfig
@@ -0,0 +1,180 @@
+package batcher
+
+import (
+	"math"
+
+	"github.com/ethereum-optimism/optimism/op-batcher/rpc"
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// channel is a channel builder together with the transactions that carry its
+// frames. It tracks the transactions independently of other channels, so that
+// each channel can time out and be confirmed on its own.
+type channel struct {
+	cfg ChannelConfig
+
+	// channel builder of the channel
+	builder *channelBuilder
+	// Set of unconfirmed txID -> frame data of this channel. For tx resubmission
+	pendingTransactions map[string]txData
+	// Set of txID -> frame data of this channel that is stored on DA, but not yet anchored on L1.
+	// For determining if the channel is fully submitted
+	storedTransactions map[string]txData
+	// Set of confirmed txID -> inclusion block. For determining if the channel is timed out
+	confirmedTransactions map[string]eth.BlockID
+
+	// number of frames confirmed on L1, and stored on DA
+	confirmedFrames int
+	storedFrames    int
+}
+
+func newChannel(cfg ChannelConfig) (*channel, error) {
+	cb, err := newChannelBuilder(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &channel{
+		cfg:                   cfg,
+		builder:               cb,
+		pendingTransactions:   make(map[string]txData),
+		storedTransactions:    make(map[string]txData),
+		confirmedTransactions: make(map[string]eth.BlockID),
+	}, nil
+}
+
+func (c *channel) ID() derive.ChannelID {
+	return c.builder.ID()
+}
+
+// Blocks returns the blocks that were added to the channel.
+func (c *channel) Blocks() []*types.Block {
+	return c.builder.Blocks()
+}
+
+// HasFrame returns whether there's a frame of this channel ready to be sent.
+func (c *channel) HasFrame() bool {
+	return c.builder.HasFrame()
+}
+
+// HasPendingTx returns whether the transaction is pending in this channel.
+func (c *channel) HasPendingTx(id txID) bool {
+	_, ok := c.pendingTransactions[id.String()]
+	return ok
+}
+
+// HasStoredTx returns whether the transaction is stored on DA in this channel, and awaits its L1 anchor.
+func (c *channel) HasStoredTx(id txID) bool {
+	_, ok := c.storedTransactions[id.String()]
+	return ok
+}
+
+// HasSubmittedTxs returns whether any frame of this channel is pending, stored on DA or confirmed in a transaction.
+func (c *channel) HasSubmittedTxs() bool {
+	return len(c.pendingTransactions) > 0 || len(c.storedTransactions) > 0 || len(c.confirmedTransactions) > 0
+}
+
+// NextFrameLen returns the length of the next frame of this channel.
+// It must only be called if HasFrame returns true.
+func (c *channel) NextFrameLen() int {
+	return len(c.builder.frames[0].data)
+}
+
+// NextFrame returns the next frame of this channel.
+// It must only be called if HasFrame returns true.
+func (c *channel) NextFrame() frameData {
+	return c.builder.NextFrame()
+}
+
+// TxPending marks the frames of this channel in the transaction with the given id as pending.
+func (c *channel) TxPending(id txID, frames []frameData) {
+	c.pendingTransactions[id.String()] = txData{frames: frames}
+}
+
+// TxFailed re-queues the frames of this channel in the failed pending transaction.
+func (c *channel) TxFailed(id txID) {
+	data := c.pendingTransactions[id.String()]
+	for _, f := range data.Frames() {
+		c.builder.PushFrame(f)
+	}
+	delete(c.pendingTransactions, id.String())
+}
+
+// TxConfirmed marks the pending transaction as included in the given L1 block.
+func (c *channel) TxConfirmed(id txID, inclusionBlock eth.BlockID) {
+	c.confirmedFrames += len(c.pendingTransactions[id.String()].frames)
+	delete(c.pendingTransactions, id.String())
+	c.confirmedTransactions[id.String()] = inclusionBlock
+	c.builder.FramePublished(inclusionBlock.Number)
+}
+
+// TxStoredOnDA marks the pending transaction as stored on the DA backend. Its frames are only referenced
+// on L1 later, by an anchor, so the transaction awaits the L1 inclusion block of its anchor, see TxAnchored.
+func (c *channel) TxStoredOnDA(id txID) {
+	data := c.pendingTransactions[id.String()]
+	c.storedFrames += len(data.frames)
+	delete(c.pendingTransactions, id.String())
+	c.storedTransactions[id.String()] = data
+}
+
+// TxAnchored marks the transaction that is stored on the DA backend as referenced by an anchor
+// that is included in the given L1 block, which counts as the inclusion block of its frames.
+// The transaction may still be pending, if its anchor is reported before its store.
+func (c *channel) TxAnchored(id txID, inclusionBlock eth.BlockID) {
+	if data, ok := c.storedTransactions[id.String()]; ok {
+		c.pendingTransactions[id.String()] = data
+		delete(c.storedTransactions, id.String())
+	}
+	c.TxConfirmed(id, inclusionBlock)
+}
+
+// State returns the state of the channel, as reported by the admin API.
+func (c *channel) State() rpc.ChannelState {
+	state := rpc.ChannelState{
+		ID:               c.ID(),
+		Full:             c.builder.IsFull(),
+		FramesReady:      c.builder.NumFrames(),
+		FramesConfirmed:  c.confirmedFrames,
+		FramesStoredOnDA: c.storedFrames,
+	}
+	if blocks := c.Blocks(); len(blocks) > 0 {
+		state.StartBlock = eth.ToBlockID(blocks[0])
+		state.EndBlock = eth.ToBlockID(blocks[len(blocks)-1])
+	}
+	if err := c.builder.FullErr(); err != nil {
+		state.FullReason = err.Error()
+	}
+	for _, td := range c.pendingTransactions {
+		state.FramesPending += len(td.frames)
+	}
+	return state
+}
+
+// IsTimedOut returns true if the channel has timed out.
+// A channel has timed out if the difference in L1 Inclusion blocks between
+// the first & last included block is greater than or equal to the channel timeout.
+func (c *channel) IsTimedOut() bool {
+	// No confirmed transactions => not timed out
+	if len(c.confirmedTransactions) == 0 {
+		return false
+	}
+	// If there are confirmed transactions, find the first + last confirmed block numbers
+	min := uint64(math.MaxUint64)
+	max := uint64(0)
+	for _, inclusionBlock := range c.confirmedTransactions {
+		if inclusionBlock.Number < min {
+			min = inclusionBlock.Number
+		}
+		if inclusionBlock.Number > max {
+			max = inclusionBlock.Number
+		}
+	}
+	return max-min >= c.cfg.ChannelTimeout
+}
+
+// IsFullySubmitted returns true if the channel is full and all of its frames have been submitted.
+// Frames that are stored on DA are only submitted once their anchor is included on L1.
+func (c *channel) IsFullySubmitted() bool {
+	return c.builder.IsFull() && len(c.pendingTransactions)+len(c.storedTransactions)+c.builder.NumFrames() == 0
+}
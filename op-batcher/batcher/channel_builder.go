@@ -58,6 +58,23 @@ type ChannelConfig struct {
 
 	// CompressorConfig contains the configuration for creating new compressors.
 	CompressorConfig compressor.Config
+	// ChannelCompressionTime is the L1 timestamp of the channel compression upgrade, from the rollup config.
+	// Channels opened at an earlier L1 head are compressed with zlib, regardless of the configured algorithm.
+	ChannelCompressionTime *uint64
+
+	// Channel manager config
+
+	// MaxPendingChannels is the maximum number of channels that are kept in
+	// flight at once, each with its own timeout and confirmation tracking.
+	// Channels with frames stored on DA are in flight until their anchors are included.
+	//
+	// If 0, a single channel is kept in flight.
+	MaxPendingChannels uint64
+}
+
+// IsChannelCompression returns whether the channel compression upgrade is active at the given L1 timestamp.
+func (cc *ChannelConfig) IsChannelCompression(l1Timestamp uint64) bool {
+	return cc.ChannelCompressionTime != nil && l1Timestamp >= *cc.ChannelCompressionTime
 }
 
 // Check validates the [ChannelConfig] parameters.
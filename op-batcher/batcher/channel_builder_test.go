@@ -385,7 +385,7 @@ func TestChannelBuilder_NextFrame(t *testing.T) {
 	require.NoError(t, err)
 
 	// Push one frame into to the channel builder
-	expectedTx := txID{chID: co.ID(), frameNumber: fn}
+	expectedTx := frameID{chID: co.ID(), frameNumber: fn}
 	expectedBytes := buf.Bytes()
 	frameData := frameData{
 		id: frameID{
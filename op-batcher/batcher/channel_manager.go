@@ -4,9 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"math"
+	"sync"
 
 	"github.com/ethereum-optimism/optimism/op-batcher/metrics"
+	"github.com/ethereum-optimism/optimism/op-batcher/rpc"
 	"github.com/ethereum-optimism/optimism/op-node/eth"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
 	"github.com/ethereum/go-ethereum/common"
@@ -14,16 +15,23 @@ import (
 	"github.com/ethereum/go-ethereum/log"
 )
 
-var ErrReorg = errors.New("block does not extend existing chain")
+var (
+	ErrReorg         = errors.New("block does not extend existing chain")
+	ErrNoOpenChannel = errors.New("no channel open for new blocks")
+)
 
 // channelManager stores a contiguous set of blocks & turns them into channels.
 // Upon receiving tx confirmation (or a tx failure), it does channel error handling.
 //
-// For simplicity, it only creates a single pending channel at a time & waits for
-// the channel to either successfully be submitted or timeout before creating a new
-// channel.
-// Functions on channelManager are not safe for concurrent access.
+// It keeps up to MaxPendingChannels channels in flight at once, including channels
+// whose frames are stored on DA but not yet anchored on L1. New blocks are
+// only added to the newest channel, so the block ranges of the channels are
+// ordered and non-overlapping, and frames are handed out oldest channel first.
+// Each channel times out independently. A timed out channel is rebuilt together
+// with all channels after it, to keep the block ranges ordered.
+// Its exported functions are safe for concurrent access.
 type channelManager struct {
+	mu   sync.Mutex
 	log  log.Logger
 	metr metrics.Metricer
 	cfg  ChannelConfig
@@ -33,14 +41,13 @@ type channelManager struct {
 	// last block hash - for reorg detection
 	tip common.Hash
 
-	// Pending data returned by TxData waiting on Tx Confirmed/Failed
-
-	// pending channel builder
-	pendingChannel *channelBuilder
-	// Set of unconfirmed txID -> frame data. For tx resubmission
-	pendingTransactions map[txID]txData
-	// Set of confirmed txID -> inclusion block. For determining if the channel is timed out
-	confirmedTransactions map[txID]eth.BlockID
+	// Channels in flight, oldest first. A channel is only removed once it and
+	// all channels before it are fully submitted, so that it can still be
+	// rebuilt if an earlier channel times out.
+	channelQueue []*channel
+	// channel new blocks are added to, the last channel of the queue. nil if
+	// no channel is open for new blocks.
+	currentChannel *channel
 
 	// if set to true, prevents production of any new channel frames
 	closed bool
@@ -51,145 +58,262 @@ func NewChannelManager(log log.Logger, metr metrics.Metricer, cfg ChannelConfig)
 		log:  log,
 		metr: metr,
 		cfg:  cfg,
-
-		pendingTransactions:   make(map[txID]txData),
-		confirmedTransactions: make(map[txID]eth.BlockID),
 	}
 }
 
 // Clear clears the entire state of the channel manager.
 // It is intended to be used after an L2 reorg.
 func (s *channelManager) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.log.Trace("clearing channel manager state")
 	s.blocks = s.blocks[:0]
 	s.tip = common.Hash{}
 	s.closed = false
-	s.clearPendingChannel()
+	s.channelQueue = nil
+	s.currentChannel = nil
+}
+
+// maxPendingChannels returns the maximum number of channels in flight, at least one.
+func (s *channelManager) maxPendingChannels() int {
+	if s.cfg.MaxPendingChannels == 0 {
+		return 1
+	}
+	return int(s.cfg.MaxPendingChannels)
 }
 
-// TxFailed records a transaction as failed. It will attempt to resubmit the data
+// pendingTxChannels returns the channels, oldest first, in which frames of the transaction are pending.
+// It returns no channels if the transaction is unknown.
+func (s *channelManager) pendingTxChannels(id txID) []*channel {
+	var chs []*channel
+	for _, ch := range s.channelQueue {
+		if ch.HasPendingTx(id) {
+			chs = append(chs, ch)
+		}
+	}
+	return chs
+}
+
+// TxFailed records a transaction as failed. It will attempt to resubmit the frames
 // in the failed transaction.
 func (s *channelManager) TxFailed(id txID) {
-	if data, ok := s.pendingTransactions[id]; ok {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if chs := s.pendingTxChannels(id); len(chs) > 0 {
 		s.log.Trace("marked transaction as failed", "id", id)
-		// Note: when the batcher is changed to send multiple frames per tx,
-		// this needs to be changed to iterate over all frames of the tx data
-		// and re-queue them.
-		s.pendingChannel.PushFrame(data.Frame())
-		delete(s.pendingTransactions, id)
+		for _, ch := range chs {
+			ch.TxFailed(id)
+		}
 	} else {
 		s.log.Warn("unknown transaction marked as failed", "id", id)
 	}
 
 	s.metr.RecordBatchTxFailed()
-	if s.closed && len(s.confirmedTransactions) == 0 && len(s.pendingTransactions) == 0 && s.pendingChannel != nil {
-		s.log.Info("Channel has no submitted transactions, clearing for shutdown", "chID", s.pendingChannel.ID())
-		s.clearPendingChannel()
+	if s.closed {
+		s.clearUnsubmittedChannels()
 	}
 }
 
 // TxConfirmed marks a transaction as confirmed on L1. Unfortunately even if all frames in
 // a channel have been marked as confirmed on L1 the channel may be invalid & need to be
 // resubmitted.
-// This function may reset the pending channel if the pending channel has timed out.
+// This function may rebuild a channel of the transaction and all channels after it if
+// the channel has timed out.
 func (s *channelManager) TxConfirmed(id txID, inclusionBlock eth.BlockID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	s.metr.RecordBatchTxSubmitted()
 	s.log.Debug("marked transaction as confirmed", "id", id, "block", inclusionBlock)
-	if _, ok := s.pendingTransactions[id]; !ok {
+	chs := s.pendingTxChannels(id)
+	if len(chs) == 0 {
 		s.log.Warn("unknown transaction marked as confirmed", "id", id, "block", inclusionBlock)
 		// TODO: This can occur if we clear the channel while there are still pending transactions
 		// We need to keep track of stale transactions instead
 		return
 	}
-	delete(s.pendingTransactions, id)
-	s.confirmedTransactions[id] = inclusionBlock
-	s.pendingChannel.FramePublished(inclusionBlock.Number)
+	for _, ch := range chs {
+		ch.TxConfirmed(id, inclusionBlock)
+	}
+	s.handleInclusion(chs)
+}
 
-	// If this channel timed out, put the pending blocks back into the local saved blocks
-	// and then reset this state so it can try to build a new channel.
-	if s.pendingChannelIsTimedOut() {
-		s.metr.RecordChannelTimedOut(s.pendingChannel.ID())
-		s.log.Warn("Channel timed out", "id", s.pendingChannel.ID())
-		s.blocks = append(s.pendingChannel.Blocks(), s.blocks...)
-		s.clearPendingChannel()
+// TxStoredOnDA marks a transaction as stored on the DA backend. Its frames are only referenced on L1 later,
+// by an anchor transaction that is sent separately. The channels of the transaction are kept until the
+// anchor is included on L1, see TxAnchored, so that they can still time out.
+func (s *channelManager) TxStoredOnDA(id txID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.metr.RecordBatchTxSubmitted()
+	s.log.Debug("marked transaction as stored on DA", "id", id)
+	chs := s.pendingTxChannels(id)
+	if len(chs) == 0 {
+		s.log.Warn("unknown transaction marked as stored on DA", "id", id)
+		return
+	}
+	for _, ch := range chs {
+		ch.TxStoredOnDA(id)
+	}
+}
+
+// TxAnchored marks a transaction that is stored on the DA backend as anchored on L1, in the given block.
+// Like TxConfirmed, this function may rebuild a channel of the transaction and all channels after it
+// if the channel has timed out.
+func (s *channelManager) TxAnchored(id txID, inclusionBlock eth.BlockID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.log.Debug("marked transaction as anchored", "id", id, "block", inclusionBlock)
+	var chs []*channel
+	for _, ch := range s.channelQueue {
+		if ch.HasStoredTx(id) || ch.HasPendingTx(id) {
+			chs = append(chs, ch)
+		}
+	}
+	if len(chs) == 0 {
+		s.log.Warn("unknown transaction marked as anchored", "id", id, "block", inclusionBlock)
+		return
 	}
-	// If we are done with this channel, record that.
-	if s.pendingChannelIsFullySubmitted() {
-		s.metr.RecordChannelFullySubmitted(s.pendingChannel.ID())
-		s.log.Info("Channel is fully submitted", "id", s.pendingChannel.ID())
-		s.clearPendingChannel()
+	for _, ch := range chs {
+		ch.TxAnchored(id, inclusionBlock)
 	}
+	s.handleInclusion(chs)
 }
 
-// clearPendingChannel resets all pending state back to an initialized but empty state.
-// TODO: Create separate "pending" state
-func (s *channelManager) clearPendingChannel() {
-	s.pendingChannel = nil
-	s.pendingTransactions = make(map[txID]txData)
-	s.confirmedTransactions = make(map[txID]eth.BlockID)
+// handleInclusion rewinds to the first of the channels that timed out, now that frames of a transaction
+// are included on L1, or prunes the channels that are fully submitted.
+func (s *channelManager) handleInclusion(chs []*channel) {
+	// If a channel timed out, put its blocks and those of all later channels
+	// back into the local saved blocks, so that they get rebuilt in order.
+	for _, ch := range chs {
+		if ch.IsTimedOut() {
+			s.metr.RecordChannelTimedOut(ch.ID())
+			s.log.Warn("Channel timed out", "id", ch.ID())
+			s.rewindToChannel(ch)
+			return
+		}
+	}
+	s.pruneSubmittedChannels()
 }
 
-// pendingChannelIsTimedOut returns true if submitted channel has timed out.
-// A channel has timed out if the difference in L1 Inclusion blocks between
-// the first & last included block is greater than or equal to the channel timeout.
-func (s *channelManager) pendingChannelIsTimedOut() bool {
-	if s.pendingChannel == nil {
-		return false // no channel to be timed out
-	}
-	// No confirmed transactions => not timed out
-	if len(s.confirmedTransactions) == 0 {
-		return false
-	}
-	// If there are confirmed transactions, find the first + last confirmed block numbers
-	min := uint64(math.MaxUint64)
-	max := uint64(0)
-	for _, inclusionBlock := range s.confirmedTransactions {
-		if inclusionBlock.Number < min {
-			min = inclusionBlock.Number
+// rewindToChannel removes the channel and all channels after it from the queue,
+// and puts their blocks back in front of the local saved blocks.
+func (s *channelManager) rewindToChannel(ch *channel) {
+	idx := -1
+	for i, c := range s.channelQueue {
+		if c == ch {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return
+	}
+	var blocks []*types.Block
+	for _, c := range s.channelQueue[idx:] {
+		if c != ch {
+			s.log.Warn("Rebuilding channel after timed out channel", "id", c.ID(), "timed_out", ch.ID())
 		}
-		if inclusionBlock.Number > max {
-			max = inclusionBlock.Number
+		blocks = append(blocks, c.Blocks()...)
+	}
+	s.blocks = append(blocks, s.blocks...)
+	s.channelQueue = s.channelQueue[:idx]
+	s.currentChannel = nil
+}
+
+// pruneSubmittedChannels removes the fully submitted channels from the front of the queue.
+func (s *channelManager) pruneSubmittedChannels() {
+	for len(s.channelQueue) > 0 && s.channelQueue[0].IsFullySubmitted() {
+		ch := s.channelQueue[0]
+		s.metr.RecordChannelFullySubmitted(ch.ID())
+		s.log.Info("Channel is fully submitted", "id", ch.ID())
+		s.channelQueue = s.channelQueue[1:]
+		if ch == s.currentChannel {
+			s.currentChannel = nil
 		}
 	}
-	return max-min >= s.cfg.ChannelTimeout
 }
 
-// pendingChannelIsFullySubmitted returns true if the channel has been fully submitted.
-func (s *channelManager) pendingChannelIsFullySubmitted() bool {
-	if s.pendingChannel == nil {
-		return false // todo: can decide either way here. Nonsensical answer though
+// clearUnsubmittedChannels removes the channels at the end of the queue that have no
+// submitted transactions. Channels before a channel with submitted transactions are
+// kept, so that no gaps arise in the submitted block ranges.
+func (s *channelManager) clearUnsubmittedChannels() {
+	for len(s.channelQueue) > 0 {
+		ch := s.channelQueue[len(s.channelQueue)-1]
+		if ch.HasSubmittedTxs() {
+			return
+		}
+		s.log.Info("Channel has no submitted transactions, clearing for shutdown", "chID", ch.ID())
+		s.channelQueue = s.channelQueue[:len(s.channelQueue)-1]
+		if ch == s.currentChannel {
+			s.currentChannel = nil
+		}
 	}
-	return s.pendingChannel.IsFull() && len(s.pendingTransactions)+s.pendingChannel.NumFrames() == 0
 }
 
-// nextTxData pops off s.datas & handles updating the internal state
-func (s *channelManager) nextTxData() (txData, error) {
-	if s.pendingChannel == nil || !s.pendingChannel.HasFrame() {
+// nextTxData returns tx data with as many frames as fit into maxSize bytes, taken from the
+// oldest channels first, & handles updating the internal state. The tx data holds at least
+// one frame, even if it exceeds maxSize.
+func (s *channelManager) nextTxData(maxSize uint64) (txData, error) {
+	var (
+		td       txData
+		size     = uint64(1) // version byte
+		txFrames = make(map[*channel][]frameData)
+		txChs    []*channel
+	)
+fill:
+	for _, ch := range s.channelQueue {
+		for ch.HasFrame() {
+			frameLen := uint64(ch.NextFrameLen())
+			if len(td.frames) > 0 && size+frameLen > maxSize {
+				break fill
+			}
+			f := ch.NextFrame()
+			td.frames = append(td.frames, f)
+			size += frameLen
+			if _, ok := txFrames[ch]; !ok {
+				txChs = append(txChs, ch)
+			}
+			txFrames[ch] = append(txFrames[ch], f)
+		}
+	}
+	if len(td.frames) == 0 {
 		s.log.Trace("no next tx data")
 		return txData{}, io.EOF // TODO: not enough data error instead
 	}
 
-	frame := s.pendingChannel.NextFrame()
-	txdata := txData{frame}
-	id := txdata.ID()
+	id := td.ID()
+	for _, ch := range txChs {
+		ch.TxPending(id, txFrames[ch])
+	}
+	s.log.Trace("returning next tx data", "id", id, "num_frames", len(td.frames), "size", size)
+	return td, nil
+}
 
-	s.log.Trace("returning next tx data", "id", id)
-	s.pendingTransactions[id] = txdata
-	return txdata, nil
+// hasFrame returns whether any channel in flight has a frame ready to be sent.
+func (s *channelManager) hasFrame() bool {
+	for _, ch := range s.channelQueue {
+		if ch.HasFrame() {
+			return true
+		}
+	}
+	return false
 }
 
-// TxData returns the next tx data that should be submitted to L1.
+// TxData returns the next tx data that should be submitted to L1 or stored on DA.
 //
-// It currently only uses one frame per transaction. If the pending channel is
-// full, it only returns the remaining frames of this channel until it got
-// successfully fully sent to L1. It returns io.EOF if there's no pending frame.
-func (s *channelManager) TxData(l1Head eth.BlockID) (txData, error) {
-	dataPending := s.pendingChannel != nil && s.pendingChannel.HasFrame()
-	s.log.Debug("Requested tx data", "l1Head", l1Head, "data_pending", dataPending, "blocks_pending", len(s.blocks))
+// The tx data holds as many frames as fit into maxSize bytes, but at least one.
+// Frames of older channels are returned first. If the current channel is full, a new channel is only opened
+// if less than MaxPendingChannels channels are in flight. It returns io.EOF if
+// there's no pending frame.
+func (s *channelManager) TxData(l1Head eth.L1BlockRef, maxSize uint64) (txData, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	dataPending := s.hasFrame()
+	s.log.Debug("Requested tx data", "l1Head", l1Head, "data_pending", dataPending,
+		"blocks_pending", len(s.blocks), "channels_pending", len(s.channelQueue))
 
 	// Short circuit if there is a pending frame or the channel manager is closed.
 	if dataPending || s.closed {
-		return s.nextTxData()
+		return s.nextTxData(maxSize)
 	}
 
 	// No pending frame, so we have to add new blocks to the channel
@@ -199,12 +323,12 @@ func (s *channelManager) TxData(l1Head eth.BlockID) (txData, error) {
 		return txData{}, io.EOF
 	}
 
-	// we have blocks, but we cannot add them to the channel right now
-	if s.pendingChannel != nil && s.pendingChannel.IsFull() {
+	// we have blocks, but we cannot add them to a channel right now
+	if (s.currentChannel == nil || s.currentChannel.builder.IsFull()) && len(s.channelQueue) >= s.maxPendingChannels() {
 		return txData{}, io.EOF
 	}
 
-	if err := s.ensurePendingChannel(l1Head); err != nil {
+	if err := s.ensureChannelWithSpace(l1Head); err != nil {
 		return txData{}, err
 	}
 
@@ -221,48 +345,60 @@ func (s *channelManager) TxData(l1Head eth.BlockID) (txData, error) {
 		return txData{}, err
 	}
 
-	return s.nextTxData()
+	return s.nextTxData(maxSize)
 }
 
-func (s *channelManager) ensurePendingChannel(l1Head eth.BlockID) error {
-	if s.pendingChannel != nil {
+// ensureChannelWithSpace opens a new current channel if there is none, or if it is full.
+func (s *channelManager) ensureChannelWithSpace(l1Head eth.L1BlockRef) error {
+	if s.currentChannel != nil && !s.currentChannel.builder.IsFull() {
 		return nil
 	}
 
-	cb, err := newChannelBuilder(s.cfg)
+	cfg := s.cfg
+	if !cfg.IsChannelCompression(l1Head.Time) {
+		// The channel is included on L1 after the current L1 head, so it may only use
+		// the configured compression algorithm once the upgrade is active at the L1 head.
+		cfg.CompressorConfig.Algo = derive.Zlib
+	}
+	ch, err := newChannel(cfg)
 	if err != nil {
 		return fmt.Errorf("creating new channel: %w", err)
 	}
-	s.pendingChannel = cb
+	s.currentChannel = ch
+	s.channelQueue = append(s.channelQueue, ch)
 	s.log.Info("Created channel",
-		"id", cb.ID(),
+		"id", ch.ID(),
 		"l1Head", l1Head,
-		"blocks_pending", len(s.blocks))
-	s.metr.RecordChannelOpened(cb.ID(), len(s.blocks))
+		"compression", cfg.CompressorConfig.CompressionAlgo(),
+		"blocks_pending", len(s.blocks),
+		"channels_pending", len(s.channelQueue))
+	s.metr.RecordChannelOpened(ch.ID(), len(s.blocks))
 
 	return nil
 }
 
-// registerL1Block registers the given block at the pending channel.
-func (s *channelManager) registerL1Block(l1Head eth.BlockID) {
-	s.pendingChannel.RegisterL1Block(l1Head.Number)
+// registerL1Block registers the given block at the current channel.
+func (s *channelManager) registerL1Block(l1Head eth.L1BlockRef) {
+	cb := s.currentChannel.builder
+	cb.RegisterL1Block(l1Head.Number)
 	s.log.Debug("new L1-block registered at channel builder",
 		"l1Head", l1Head,
-		"channel_full", s.pendingChannel.IsFull(),
-		"full_reason", s.pendingChannel.FullErr(),
+		"channel_full", cb.IsFull(),
+		"full_reason", cb.FullErr(),
 	)
 }
 
-// processBlocks adds blocks from the blocks queue to the pending channel until
+// processBlocks adds blocks from the blocks queue to the current channel until
 // either the queue got exhausted or the channel is full.
 func (s *channelManager) processBlocks() error {
+	cb := s.currentChannel.builder
 	var (
 		blocksAdded int
 		_chFullErr  *ChannelFullError // throw away, just for type checking
 		latestL2ref eth.L2BlockRef
 	)
 	for i, block := range s.blocks {
-		l1info, err := s.pendingChannel.AddBlock(block)
+		l1info, err := cb.AddBlock(block)
 		if errors.As(err, &_chFullErr) {
 			// current block didn't get added because channel is already full
 			break
@@ -273,7 +409,7 @@ func (s *channelManager) processBlocks() error {
 		latestL2ref = l2BlockRefFromBlockAndL1Info(block, l1info)
 		s.metr.RecordL2BlockInChannel(block)
 		// current block got added but channel is now full
-		if s.pendingChannel.IsFull() {
+		if cb.IsFull() {
 			break
 		}
 	}
@@ -289,34 +425,35 @@ func (s *channelManager) processBlocks() error {
 	s.metr.RecordL2BlocksAdded(latestL2ref,
 		blocksAdded,
 		len(s.blocks),
-		s.pendingChannel.InputBytes(),
-		s.pendingChannel.ReadyBytes())
+		cb.InputBytes(),
+		cb.ReadyBytes())
 	s.log.Debug("Added blocks to channel",
 		"blocks_added", blocksAdded,
 		"blocks_pending", len(s.blocks),
-		"channel_full", s.pendingChannel.IsFull(),
-		"input_bytes", s.pendingChannel.InputBytes(),
-		"ready_bytes", s.pendingChannel.ReadyBytes(),
+		"channel_full", cb.IsFull(),
+		"input_bytes", cb.InputBytes(),
+		"ready_bytes", cb.ReadyBytes(),
 	)
 	return nil
 }
 
 func (s *channelManager) outputFrames() error {
-	if err := s.pendingChannel.OutputFrames(); err != nil {
+	cb := s.currentChannel.builder
+	if err := cb.OutputFrames(); err != nil {
 		return fmt.Errorf("creating frames with channel builder: %w", err)
 	}
-	if !s.pendingChannel.IsFull() {
+	if !cb.IsFull() {
 		return nil
 	}
 
-	inBytes, outBytes := s.pendingChannel.InputBytes(), s.pendingChannel.OutputBytes()
+	inBytes, outBytes := cb.InputBytes(), cb.OutputBytes()
 	s.metr.RecordChannelClosed(
-		s.pendingChannel.ID(),
+		cb.ID(),
 		len(s.blocks),
-		s.pendingChannel.NumFrames(),
+		cb.NumFrames(),
 		inBytes,
 		outBytes,
-		s.pendingChannel.FullErr(),
+		cb.FullErr(),
 	)
 
 	var comprRatio float64
@@ -324,21 +461,60 @@ func (s *channelManager) outputFrames() error {
 		comprRatio = float64(outBytes) / float64(inBytes)
 	}
 	s.log.Info("Channel closed",
-		"id", s.pendingChannel.ID(),
+		"id", cb.ID(),
 		"blocks_pending", len(s.blocks),
-		"num_frames", s.pendingChannel.NumFrames(),
+		"num_frames", cb.NumFrames(),
 		"input_bytes", inBytes,
 		"output_bytes", outBytes,
-		"full_reason", s.pendingChannel.FullErr(),
+		"full_reason", cb.FullErr(),
 		"compr_ratio", comprRatio,
 	)
 	return nil
 }
 
+// ForceCloseChannel closes the current channel and outputs its remaining frames, so that it
+// gets fully submitted. New blocks are added to a new channel. It returns the ID of the closed
+// channel, or ErrNoOpenChannel if there is no channel that blocks can be added to.
+func (s *channelManager) ForceCloseChannel() (derive.ChannelID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.currentChannel == nil || s.currentChannel.builder.IsFull() {
+		return derive.ChannelID{}, ErrNoOpenChannel
+	}
+	id := s.currentChannel.ID()
+	s.log.Info("Force closing channel", "id", id)
+	s.currentChannel.builder.Close()
+	if err := s.outputFrames(); err != nil {
+		return derive.ChannelID{}, err
+	}
+	return id, nil
+}
+
+// State returns the channels in flight and the number of blocks not yet added to a channel,
+// as reported by the admin API.
+func (s *channelManager) State() *rpc.BatcherState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state := &rpc.BatcherState{
+		Channels:      make([]rpc.ChannelState, 0, len(s.channelQueue)),
+		BlocksPending: len(s.blocks),
+	}
+	for _, ch := range s.channelQueue {
+		state.Channels = append(state.Channels, ch.State())
+	}
+	if s.currentChannel != nil && !s.currentChannel.builder.IsFull() {
+		id := s.currentChannel.ID()
+		state.CurrentChannel = &id
+	}
+	return state
+}
+
 // AddL2Block adds an L2 block to the internal blocks queue. It returns ErrReorg
 // if the block does not extend the last block loaded into the state. If no
 // blocks were added yet, the parent hash check is skipped.
 func (s *channelManager) AddL2Block(block *types.Block) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	if s.tip != (common.Hash{}) && s.tip != block.ParentHash() {
 		return ErrReorg
 	}
@@ -361,26 +537,26 @@ func l2BlockRefFromBlockAndL1Info(block *types.Block, l1info derive.L1BlockInfo)
 	}
 }
 
-// Close closes the current pending channel, if one exists, outputs any remaining frames,
+// Close closes the current channel, if one exists, outputs any remaining frames,
 // and prevents the creation of any new channels.
 // Any outputted frames still need to be published.
 func (s *channelManager) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	if s.closed {
 		return nil
 	}
 
 	s.closed = true
 
-	// Any pending state can be proactively cleared if there are no submitted transactions
-	if len(s.confirmedTransactions) == 0 && len(s.pendingTransactions) == 0 {
-		s.clearPendingChannel()
-	}
+	// Any trailing channels can be proactively cleared if they have no submitted transactions
+	s.clearUnsubmittedChannels()
 
-	if s.pendingChannel == nil {
+	if s.currentChannel == nil {
 		return nil
 	}
 
-	s.pendingChannel.Close()
+	s.currentChannel.builder.Close()
 
 	return s.outputFrames()
 }
@@ -1,6 +1,7 @@
 package batcher
 
 import (
+	"bytes"
 	"io"
 	"math/big"
 	"math/rand"
@@ -19,47 +20,40 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-// TestPendingChannelTimeout tests that the channel manager
-// correctly identifies when a pending channel is timed out.
+// TestPendingChannelTimeout tests that a channel correctly
+// identifies when it is timed out.
 func TestPendingChannelTimeout(t *testing.T) {
-	// Create a new channel manager with a ChannelTimeout
-	log := testlog.Logger(t, log.LvlCrit)
-	m := NewChannelManager(log, metrics.NoopMetrics, ChannelConfig{
+	// Create a new channel with a ChannelTimeout
+	ch, err := newChannel(ChannelConfig{
 		ChannelTimeout: 100,
 	})
-
-	// Pending channel is nil so is cannot be timed out
-	timeout := m.pendingChannelIsTimedOut()
-	require.False(t, timeout)
-
-	// Set the pending channel
-	require.NoError(t, m.ensurePendingChannel(eth.BlockID{}))
+	require.NoError(t, err)
 
 	// There are no confirmed transactions so
 	// the pending channel cannot be timed out
-	timeout = m.pendingChannelIsTimedOut()
+	timeout := ch.IsTimedOut()
 	require.False(t, timeout)
 
 	// Manually set a confirmed transactions
 	// To avoid other methods clearing state
-	m.confirmedTransactions[frameID{frameNumber: 0}] = eth.BlockID{Number: 0}
-	m.confirmedTransactions[frameID{frameNumber: 1}] = eth.BlockID{Number: 99}
+	ch.confirmedTransactions[txID{{frameNumber: 0}}.String()] = eth.BlockID{Number: 0}
+	ch.confirmedTransactions[txID{{frameNumber: 1}}.String()] = eth.BlockID{Number: 99}
 
 	// Since the ChannelTimeout is 100, the
 	// pending channel should not be timed out
-	timeout = m.pendingChannelIsTimedOut()
+	timeout = ch.IsTimedOut()
 	require.False(t, timeout)
 
 	// Add a confirmed transaction with a higher number
 	// than the ChannelTimeout
-	m.confirmedTransactions[frameID{
+	ch.confirmedTransactions[txID{{
 		frameNumber: 2,
-	}] = eth.BlockID{
+	}}.String()] = eth.BlockID{
 		Number: 101,
 	}
 
 	// Now the pending channel should be timed out
-	timeout = m.pendingChannelIsTimedOut()
+	timeout = ch.IsTimedOut()
 	require.True(t, timeout)
 }
 
@@ -111,9 +105,9 @@ func TestChannelManagerReturnsErrReorgWhenDrained(t *testing.T) {
 
 	require.NoError(t, m.AddL2Block(a))
 
-	_, err := m.TxData(eth.BlockID{})
+	_, err := m.TxData(eth.L1BlockRef{}, 0)
 	require.NoError(t, err)
-	_, err = m.TxData(eth.BlockID{})
+	_, err = m.TxData(eth.L1BlockRef{}, 0)
 	require.ErrorIs(t, err, io.EOF)
 
 	require.ErrorIs(t, m.AddL2Block(x), ErrReorg)
@@ -125,20 +119,20 @@ func TestChannelManagerNextTxData(t *testing.T) {
 	m := NewChannelManager(log, metrics.NoopMetrics, ChannelConfig{})
 
 	// Nil pending channel should return EOF
-	returnedTxData, err := m.nextTxData()
+	returnedTxData, err := m.nextTxData(0)
 	require.ErrorIs(t, err, io.EOF)
 	require.Equal(t, txData{}, returnedTxData)
 
 	// Set the pending channel
 	// The nextTxData function should still return EOF
 	// since the pending channel has no frames
-	require.NoError(t, m.ensurePendingChannel(eth.BlockID{}))
-	returnedTxData, err = m.nextTxData()
+	require.NoError(t, m.ensureChannelWithSpace(eth.L1BlockRef{}))
+	returnedTxData, err = m.nextTxData(0)
 	require.ErrorIs(t, err, io.EOF)
 	require.Equal(t, txData{}, returnedTxData)
 
 	// Manually push a frame into the pending channel
-	channelID := m.pendingChannel.ID()
+	channelID := m.currentChannel.builder.ID()
 	frame := frameData{
 		data: []byte{},
 		id: frameID{
@@ -146,17 +140,17 @@ func TestChannelManagerNextTxData(t *testing.T) {
 			frameNumber: uint16(0),
 		},
 	}
-	m.pendingChannel.PushFrame(frame)
-	require.Equal(t, 1, m.pendingChannel.NumFrames())
+	m.currentChannel.builder.PushFrame(frame)
+	require.Equal(t, 1, m.currentChannel.builder.NumFrames())
 
 	// Now the nextTxData function should return the frame
-	returnedTxData, err = m.nextTxData()
-	expectedTxData := txData{frame}
+	returnedTxData, err = m.nextTxData(0)
+	expectedTxData := singleFrameTxData(frame)
 	expectedChannelID := expectedTxData.ID()
 	require.NoError(t, err)
 	require.Equal(t, expectedTxData, returnedTxData)
-	require.Equal(t, 0, m.pendingChannel.NumFrames())
-	require.Equal(t, expectedTxData, m.pendingTransactions[expectedChannelID])
+	require.Equal(t, 0, m.currentChannel.builder.NumFrames())
+	require.Equal(t, expectedTxData, m.currentChannel.pendingTransactions[expectedChannelID.String()])
 }
 
 // TestChannelManager_Clear tests clearing the channel manager.
@@ -184,36 +178,35 @@ func TestChannelManager_Clear(t *testing.T) {
 	// Channel Manager state should be empty by default
 	require.Empty(m.blocks)
 	require.Equal(common.Hash{}, m.tip)
-	require.Nil(m.pendingChannel)
-	require.Empty(m.pendingTransactions)
-	require.Empty(m.confirmedTransactions)
+	require.Nil(m.currentChannel)
+	require.Empty(m.channelQueue)
 
 	// Add a block to the channel manager
 	a, _ := derivetest.RandomL2Block(rng, 4)
 	newL1Tip := a.Hash()
-	l1BlockID := eth.BlockID{
+	l1BlockID := eth.L1BlockRef{
 		Hash:   a.Hash(),
 		Number: a.NumberU64(),
 	}
 	require.NoError(m.AddL2Block(a))
 
 	// Make sure there is a channel builder
-	require.NoError(m.ensurePendingChannel(l1BlockID))
-	require.NotNil(m.pendingChannel)
-	require.Len(m.confirmedTransactions, 0)
+	require.NoError(m.ensureChannelWithSpace(l1BlockID))
+	require.NotNil(m.currentChannel)
+	require.Len(m.currentChannel.confirmedTransactions, 0)
 
 	// Process the blocks
 	// We should have a pending channel with 1 frame
 	// and no more blocks since processBlocks consumes
 	// the list
 	require.NoError(m.processBlocks())
-	require.NoError(m.pendingChannel.co.Flush())
-	require.NoError(m.pendingChannel.OutputFrames())
-	_, err := m.nextTxData()
+	require.NoError(m.currentChannel.builder.co.Flush())
+	require.NoError(m.currentChannel.builder.OutputFrames())
+	_, err := m.nextTxData(0)
 	require.NoError(err)
 	require.Len(m.blocks, 0)
 	require.Equal(newL1Tip, m.tip)
-	require.Len(m.pendingTransactions, 1)
+	require.Len(m.currentChannel.pendingTransactions, 1)
 
 	// Add a new block so we can test clearing
 	// the channel manager with a full state
@@ -231,9 +224,8 @@ func TestChannelManager_Clear(t *testing.T) {
 	// Check that the entire channel manager state cleared
 	require.Empty(m.blocks)
 	require.Equal(common.Hash{}, m.tip)
-	require.Nil(m.pendingChannel)
-	require.Empty(m.pendingTransactions)
-	require.Empty(m.confirmedTransactions)
+	require.Nil(m.currentChannel)
+	require.Empty(m.channelQueue)
 }
 
 // TestChannelManagerTxConfirmed checks the [ChannelManager.TxConfirmed] function.
@@ -249,8 +241,8 @@ func TestChannelManagerTxConfirmed(t *testing.T) {
 
 	// Let's add a valid pending transaction to the channel manager
 	// So we can demonstrate that TxConfirmed's correctness
-	require.NoError(t, m.ensurePendingChannel(eth.BlockID{}))
-	channelID := m.pendingChannel.ID()
+	require.NoError(t, m.ensureChannelWithSpace(eth.L1BlockRef{}))
+	channelID := m.currentChannel.builder.ID()
 	frame := frameData{
 		data: []byte{},
 		id: frameID{
@@ -258,35 +250,108 @@ func TestChannelManagerTxConfirmed(t *testing.T) {
 			frameNumber: uint16(0),
 		},
 	}
-	m.pendingChannel.PushFrame(frame)
-	require.Equal(t, 1, m.pendingChannel.NumFrames())
-	returnedTxData, err := m.nextTxData()
-	expectedTxData := txData{frame}
+	m.currentChannel.builder.PushFrame(frame)
+	require.Equal(t, 1, m.currentChannel.builder.NumFrames())
+	returnedTxData, err := m.nextTxData(0)
+	expectedTxData := singleFrameTxData(frame)
 	expectedChannelID := expectedTxData.ID()
 	require.NoError(t, err)
 	require.Equal(t, expectedTxData, returnedTxData)
-	require.Equal(t, 0, m.pendingChannel.NumFrames())
-	require.Equal(t, expectedTxData, m.pendingTransactions[expectedChannelID])
-	require.Len(t, m.pendingTransactions, 1)
+	require.Equal(t, 0, m.currentChannel.builder.NumFrames())
+	require.Equal(t, expectedTxData, m.currentChannel.pendingTransactions[expectedChannelID.String()])
+	require.Len(t, m.currentChannel.pendingTransactions, 1)
 
 	// An unknown pending transaction should not be marked as confirmed
 	// and should not be removed from the pending transactions map
-	actualChannelID := m.pendingChannel.ID()
+	actualChannelID := m.currentChannel.builder.ID()
 	unknownChannelID := derive.ChannelID([derive.ChannelIDLength]byte{0x69})
 	require.NotEqual(t, actualChannelID, unknownChannelID)
-	unknownTxID := frameID{chID: unknownChannelID, frameNumber: 0}
+	unknownTxID := txID{{chID: unknownChannelID, frameNumber: 0}}
 	blockID := eth.BlockID{Number: 0, Hash: common.Hash{0x69}}
 	m.TxConfirmed(unknownTxID, blockID)
-	require.Empty(t, m.confirmedTransactions)
-	require.Len(t, m.pendingTransactions, 1)
+	require.Empty(t, m.currentChannel.confirmedTransactions)
+	require.Len(t, m.currentChannel.pendingTransactions, 1)
 
 	// Now let's mark the pending transaction as confirmed
 	// and check that it is removed from the pending transactions map
 	// and added to the confirmed transactions map
 	m.TxConfirmed(expectedChannelID, blockID)
-	require.Empty(t, m.pendingTransactions)
-	require.Len(t, m.confirmedTransactions, 1)
-	require.Equal(t, blockID, m.confirmedTransactions[expectedChannelID])
+	require.Empty(t, m.currentChannel.pendingTransactions)
+	require.Len(t, m.currentChannel.confirmedTransactions, 1)
+	require.Equal(t, blockID, m.currentChannel.confirmedTransactions[expectedChannelID.String()])
+}
+
+// TestChannelManagerTxStoredOnDA checks the [ChannelManager.TxStoredOnDA] function.
+func TestChannelManagerTxStoredOnDA(t *testing.T) {
+	log := testlog.Logger(t, log.LvlCrit)
+	m := NewChannelManager(log, metrics.NoopMetrics, ChannelConfig{
+		ChannelTimeout: 10,
+	})
+
+	require.NoError(t, m.ensureChannelWithSpace(eth.L1BlockRef{}))
+	frame := frameData{
+		data: []byte{},
+		id: frameID{
+			chID:        m.currentChannel.builder.ID(),
+			frameNumber: uint16(0),
+		},
+	}
+	m.currentChannel.builder.PushFrame(frame)
+	txdata, err := m.nextTxData(0)
+	require.NoError(t, err)
+	require.Len(t, m.currentChannel.pendingTransactions, 1)
+
+	// An unknown transaction doesn't modify state
+	m.TxStoredOnDA(txID{})
+	require.Len(t, m.currentChannel.pendingTransactions, 1)
+
+	// The stored transaction is no longer pending, but has no L1 inclusion block
+	// that counts towards the channel timeout
+	m.TxStoredOnDA(txdata.ID())
+	require.Empty(t, m.currentChannel.pendingTransactions)
+	require.Empty(t, m.currentChannel.confirmedTransactions)
+	require.NotNil(t, m.currentChannel, "channel is not full yet")
+
+	// The channel is kept until the anchor of the stored transaction is included on L1
+	m.currentChannel.builder.setFullErr(ErrMaxFrameIndex)
+	require.False(t, m.currentChannel.IsFullySubmitted())
+	ch := m.currentChannel
+	blockID := eth.BlockID{Number: 7, Hash: common.Hash{0x69}}
+	m.TxAnchored(txdata.ID(), blockID)
+	require.Empty(t, ch.storedTransactions)
+	require.Equal(t, blockID, ch.confirmedTransactions[txdata.ID().String()])
+	require.Empty(t, m.channelQueue, "anchored channel is fully submitted")
+	require.Nil(t, m.currentChannel)
+}
+
+// TestChannelManagerTxAnchoredTimeout checks that a channel whose frames are stored on DA
+// times out once the anchors of its frames are included too far apart.
+func TestChannelManagerTxAnchoredTimeout(t *testing.T) {
+	log := testlog.Logger(t, log.LvlCrit)
+	m := NewChannelManager(log, metrics.NoopMetrics, ChannelConfig{
+		ChannelTimeout: 10,
+	})
+	a := newMiniL2Block(0)
+	require.NoError(t, m.AddL2Block(a))
+	require.NoError(t, m.ensureChannelWithSpace(eth.L1BlockRef{}))
+	require.NoError(t, m.processBlocks())
+	ch := m.currentChannel
+	require.Len(t, ch.Blocks(), 1)
+	for i := uint16(0); i < 2; i++ {
+		ch.builder.PushFrame(frameData{data: []byte{}, id: frameID{chID: ch.ID(), frameNumber: i}})
+	}
+	first, err := m.nextTxData(0)
+	require.NoError(t, err)
+	second, err := m.nextTxData(0)
+	require.NoError(t, err)
+	m.TxStoredOnDA(first.ID())
+	m.TxStoredOnDA(second.ID())
+
+	m.TxAnchored(first.ID(), eth.BlockID{Number: 1})
+	require.Len(t, m.channelQueue, 1)
+	m.TxAnchored(second.ID(), eth.BlockID{Number: 11})
+	require.Empty(t, m.channelQueue, "timed out channel is removed")
+	require.Equal(t, ch.Blocks(), m.blocks, "blocks of the timed out channel are requeued")
 }
 
 // TestChannelManagerTxFailed checks the [ChannelManager.TxFailed] function.
@@ -297,8 +362,8 @@ func TestChannelManagerTxFailed(t *testing.T) {
 
 	// Let's add a valid pending transaction to the channel
 	// manager so we can demonstrate correctness
-	require.NoError(t, m.ensurePendingChannel(eth.BlockID{}))
-	channelID := m.pendingChannel.ID()
+	require.NoError(t, m.ensureChannelWithSpace(eth.L1BlockRef{}))
+	channelID := m.currentChannel.builder.ID()
 	frame := frameData{
 		data: []byte{},
 		id: frameID{
@@ -306,29 +371,29 @@ func TestChannelManagerTxFailed(t *testing.T) {
 			frameNumber: uint16(0),
 		},
 	}
-	m.pendingChannel.PushFrame(frame)
-	require.Equal(t, 1, m.pendingChannel.NumFrames())
-	returnedTxData, err := m.nextTxData()
-	expectedTxData := txData{frame}
+	m.currentChannel.builder.PushFrame(frame)
+	require.Equal(t, 1, m.currentChannel.builder.NumFrames())
+	returnedTxData, err := m.nextTxData(0)
+	expectedTxData := singleFrameTxData(frame)
 	expectedChannelID := expectedTxData.ID()
 	require.NoError(t, err)
 	require.Equal(t, expectedTxData, returnedTxData)
-	require.Equal(t, 0, m.pendingChannel.NumFrames())
-	require.Equal(t, expectedTxData, m.pendingTransactions[expectedChannelID])
-	require.Len(t, m.pendingTransactions, 1)
+	require.Equal(t, 0, m.currentChannel.builder.NumFrames())
+	require.Equal(t, expectedTxData, m.currentChannel.pendingTransactions[expectedChannelID.String()])
+	require.Len(t, m.currentChannel.pendingTransactions, 1)
 
 	// Trying to mark an unknown pending transaction as failed
 	// shouldn't modify state
-	m.TxFailed(frameID{})
-	require.Equal(t, 0, m.pendingChannel.NumFrames())
-	require.Equal(t, expectedTxData, m.pendingTransactions[expectedChannelID])
+	m.TxFailed(txID{})
+	require.Equal(t, 0, m.currentChannel.builder.NumFrames())
+	require.Equal(t, expectedTxData, m.currentChannel.pendingTransactions[expectedChannelID.String()])
 
 	// Now we still have a pending transaction
 	// Let's mark it as failed
 	m.TxFailed(expectedChannelID)
-	require.Empty(t, m.pendingTransactions)
+	require.Empty(t, m.currentChannel.pendingTransactions)
 	// There should be a frame in the pending channel now
-	require.Equal(t, 1, m.pendingChannel.NumFrames())
+	require.Equal(t, 1, m.currentChannel.builder.NumFrames())
 }
 
 func TestChannelManager_TxResend(t *testing.T) {
@@ -348,7 +413,7 @@ func TestChannelManager_TxResend(t *testing.T) {
 
 	require.NoError(m.AddL2Block(a))
 
-	txdata0, err := m.TxData(eth.BlockID{})
+	txdata0, err := m.TxData(eth.L1BlockRef{}, 0)
 	require.NoError(err)
 	txdata0bytes := txdata0.Bytes()
 	data0 := make([]byte, len(txdata0bytes))
@@ -356,13 +421,13 @@ func TestChannelManager_TxResend(t *testing.T) {
 	copy(data0, txdata0bytes)
 
 	// ensure channel is drained
-	_, err = m.TxData(eth.BlockID{})
+	_, err = m.TxData(eth.L1BlockRef{}, 0)
 	require.ErrorIs(err, io.EOF)
 
 	// requeue frame
 	m.TxFailed(txdata0.ID())
 
-	txdata1, err := m.TxData(eth.BlockID{})
+	txdata1, err := m.TxData(eth.L1BlockRef{}, 0)
 	require.NoError(err)
 
 	data1 := txdata1.Bytes()
@@ -395,7 +460,7 @@ func TestChannelManagerCloseBeforeFirstUse(t *testing.T) {
 	err := m.AddL2Block(a)
 	require.NoError(err, "Failed to add L2 block")
 
-	_, err = m.TxData(eth.BlockID{})
+	_, err = m.TxData(eth.L1BlockRef{}, 0)
 	require.ErrorIs(err, io.EOF, "Expected closed channel manager to contain no tx data")
 }
 
@@ -421,12 +486,12 @@ func TestChannelManagerCloseNoPendingChannel(t *testing.T) {
 	err := m.AddL2Block(a)
 	require.NoError(err, "Failed to add L2 block")
 
-	txdata, err := m.TxData(eth.BlockID{})
+	txdata, err := m.TxData(eth.L1BlockRef{}, 0)
 	require.NoError(err, "Expected channel manager to return valid tx data")
 
 	m.TxConfirmed(txdata.ID(), eth.BlockID{})
 
-	_, err = m.TxData(eth.BlockID{})
+	_, err = m.TxData(eth.L1BlockRef{}, 0)
 	require.ErrorIs(err, io.EOF, "Expected channel manager to EOF")
 
 	m.Close()
@@ -434,7 +499,7 @@ func TestChannelManagerCloseNoPendingChannel(t *testing.T) {
 	err = m.AddL2Block(b)
 	require.NoError(err, "Failed to add L2 block")
 
-	_, err = m.TxData(eth.BlockID{})
+	_, err = m.TxData(eth.L1BlockRef{}, 0)
 	require.ErrorIs(err, io.EOF, "Expected closed channel manager to return no new tx data")
 }
 
@@ -461,25 +526,25 @@ func TestChannelManagerClosePendingChannel(t *testing.T) {
 	err := m.AddL2Block(a)
 	require.NoError(err, "Failed to add L2 block")
 
-	txdata, err := m.TxData(eth.BlockID{})
+	txdata, err := m.TxData(eth.L1BlockRef{}, 0)
 	require.NoError(err, "Expected channel manager to produce valid tx data")
 
 	m.TxConfirmed(txdata.ID(), eth.BlockID{})
 
 	m.Close()
 
-	txdata, err = m.TxData(eth.BlockID{})
+	txdata, err = m.TxData(eth.L1BlockRef{}, 0)
 	require.NoError(err, "Expected channel manager to produce tx data from remaining L2 block data")
 
 	m.TxConfirmed(txdata.ID(), eth.BlockID{})
 
-	_, err = m.TxData(eth.BlockID{})
+	_, err = m.TxData(eth.L1BlockRef{}, 0)
 	require.ErrorIs(err, io.EOF, "Expected channel manager to have no more tx data")
 
 	err = m.AddL2Block(b)
 	require.NoError(err, "Failed to add L2 block")
 
-	_, err = m.TxData(eth.BlockID{})
+	_, err = m.TxData(eth.L1BlockRef{}, 0)
 	require.ErrorIs(err, io.EOF, "Expected closed channel manager to produce no more tx data")
 }
 
@@ -505,20 +570,414 @@ func TestChannelManagerCloseAllTxsFailed(t *testing.T) {
 	err := m.AddL2Block(a)
 	require.NoError(err, "Failed to add L2 block")
 
-	txdata, err := m.TxData(eth.BlockID{})
+	txdata, err := m.TxData(eth.L1BlockRef{}, 0)
 	require.NoError(err, "Expected channel manager to produce valid tx data")
 
 	m.TxFailed(txdata.ID())
 
 	// Show that this data will continue to be emitted as long as the transaction
 	// fails and the channel manager is not closed
-	txdata, err = m.TxData(eth.BlockID{})
+	txdata, err = m.TxData(eth.L1BlockRef{}, 0)
 	require.NoError(err, "Expected channel manager to re-attempt the failed transaction")
 
 	m.TxFailed(txdata.ID())
 
 	m.Close()
 
-	_, err = m.TxData(eth.BlockID{})
+	_, err = m.TxData(eth.L1BlockRef{}, 0)
 	require.ErrorIs(err, io.EOF, "Expected closed channel manager to produce no more tx data")
 }
+
+// multiChannelConfig returns a channel config in which every block fills a
+// channel of its own that is split into multiple frames, and up to maxPending
+// channels are kept in flight.
+func multiChannelConfig(maxPending uint64) ChannelConfig {
+	return ChannelConfig{
+		MaxFrameSize:       derive.FrameV0OverHeadSize + 20,
+		ChannelTimeout:     10,
+		MaxPendingChannels: maxPending,
+		CompressorConfig: compressor.Config{
+			TargetFrameSize:  1,
+			TargetNumFrames:  1,
+			ApproxComprRatio: 1.0,
+		},
+	}
+}
+
+// addChainedBlocks adds n blocks building on each other to the channel manager.
+func addChainedBlocks(t *testing.T, m *channelManager, n int) []*types.Block {
+	blocks := make([]*types.Block, 0, n)
+	parent := common.Hash{}
+	for i := 0; i < n; i++ {
+		b := newMiniL2BlockWithNumberParent(0, big.NewInt(int64(i)), parent)
+		require.NoError(t, m.AddL2Block(b))
+		blocks = append(blocks, b)
+		parent = b.Hash()
+	}
+	return blocks
+}
+
+// drainTxData requests tx data until the channel manager returns io.EOF and
+// returns the tx data grouped by channel, in the order of the channels.
+func drainTxData(t *testing.T, m *channelManager) [][]txData {
+	var txs [][]txData
+	var lastID derive.ChannelID
+	for {
+		txdata, err := m.TxData(eth.L1BlockRef{}, 0)
+		if err == io.EOF {
+			return txs
+		}
+		require.NoError(t, err)
+		if id := txdata.Frames()[0].id.chID; len(txs) == 0 || id != lastID {
+			txs = append(txs, nil)
+			lastID = id
+		}
+		txs[len(txs)-1] = append(txs[len(txs)-1], txdata)
+	}
+}
+
+// TestChannelManagerMultiplePendingChannels checks that multiple channels are
+// kept in flight with ordered block ranges, and that channels are only removed
+// once all channels before them are fully submitted.
+func TestChannelManagerMultiplePendingChannels(t *testing.T) {
+	require := require.New(t)
+	log := testlog.Logger(t, log.LvlCrit)
+	m := NewChannelManager(log, metrics.NoopMetrics, multiChannelConfig(2))
+
+	blocks := addChainedBlocks(t, m, 3)
+	txs := drainTxData(t, m)
+
+	// Only two channels are opened, the last block must wait for a free slot
+	require.Len(txs, 2)
+	require.Len(m.channelQueue, 2)
+	require.Equal(blocks[0:1], m.channelQueue[0].Blocks())
+	require.Equal(blocks[1:2], m.channelQueue[1].Blocks())
+	require.Equal(blocks[2:], m.blocks)
+
+	// The second channel is fully submitted first, but must be kept
+	// until the first channel is fully submitted too.
+	for _, txdata := range txs[1] {
+		m.TxConfirmed(txdata.ID(), eth.BlockID{Number: 1})
+	}
+	require.Len(m.channelQueue, 2)
+	require.True(m.channelQueue[1].IsFullySubmitted())
+	_, err := m.TxData(eth.L1BlockRef{}, 0)
+	require.ErrorIs(err, io.EOF, "no new channel must be opened while the queue is full")
+
+	for _, txdata := range txs[0] {
+		m.TxConfirmed(txdata.ID(), eth.BlockID{Number: 2})
+	}
+	require.Empty(m.channelQueue)
+	require.Nil(m.currentChannel)
+
+	// The remaining block goes into a new channel
+	txs = drainTxData(t, m)
+	require.Len(txs, 1)
+	require.Len(m.channelQueue, 1)
+	require.Equal(blocks[2:], m.channelQueue[0].Blocks())
+	require.Empty(m.blocks)
+}
+
+// TestChannelManagerPendingChannelsStoredOnDA checks that channels stored on DA count
+// as in flight until their anchors are included, and are rebuilt if their anchors time out.
+func TestChannelManagerPendingChannelsStoredOnDA(t *testing.T) {
+	require := require.New(t)
+	log := testlog.Logger(t, log.LvlCrit)
+	m := NewChannelManager(log, metrics.NoopMetrics, multiChannelConfig(2))
+
+	blocks := addChainedBlocks(t, m, 3)
+	txs := drainTxData(t, m)
+	require.Len(txs, 2)
+	for _, chTxs := range txs {
+		for _, txdata := range chTxs {
+			m.TxStoredOnDA(txdata.ID())
+		}
+	}
+	require.Len(m.channelQueue, 2, "stored channels are kept until anchored")
+	_, err := m.TxData(eth.L1BlockRef{}, 0)
+	require.ErrorIs(err, io.EOF, "no new channel must be opened while stored channels await their anchors")
+
+	// The anchors of the first channel are included too far apart, so it times out
+	m.TxAnchored(txs[0][0].ID(), eth.BlockID{Number: 1})
+	m.TxAnchored(txs[0][1].ID(), eth.BlockID{Number: 11})
+	require.Empty(m.channelQueue)
+	require.Equal(blocks, m.blocks, "blocks of the timed out channel and later channels are requeued")
+
+	txs = drainTxData(t, m)
+	require.Len(txs, 2, "requeued blocks are submitted again")
+	require.Equal(blocks[0:1], m.channelQueue[0].Blocks())
+}
+
+// TestChannelManagerTimeoutMultiplePendingChannels checks that a timed out channel
+// is rebuilt together with all later channels, while earlier channels are kept.
+func TestChannelManagerTimeoutMultiplePendingChannels(t *testing.T) {
+	require := require.New(t)
+	log := testlog.Logger(t, log.LvlCrit)
+	m := NewChannelManager(log, metrics.NoopMetrics, multiChannelConfig(4))
+
+	blocks := addChainedBlocks(t, m, 4)
+	txs := drainTxData(t, m)
+	require.Len(txs, 4)
+	require.Len(m.channelQueue, 4)
+	for _, chTxs := range txs {
+		require.GreaterOrEqual(len(chTxs), 2, "test requires multiple frames per channel")
+	}
+
+	// The first channel gets confirmed in time
+	for i, txdata := range txs[0] {
+		m.TxConfirmed(txdata.ID(), eth.BlockID{Number: uint64(1 + i)})
+	}
+	require.Len(m.channelQueue, 3)
+
+	// The third channel gets confirmed in time, but after the second channel starts
+	// to get confirmed, so it's kept in the queue.
+	for _, txdata := range txs[2] {
+		m.TxConfirmed(txdata.ID(), eth.BlockID{Number: 2})
+	}
+	require.Len(m.channelQueue, 3)
+
+	// The second channel times out. Frames of the fourth channel are still pending.
+	m.TxConfirmed(txs[1][0].ID(), eth.BlockID{Number: 1})
+	require.Len(m.channelQueue, 3)
+	m.TxConfirmed(txs[1][1].ID(), eth.BlockID{Number: 11})
+
+	// Channels 2-4 are rebuilt, in order
+	require.Empty(m.channelQueue)
+	require.Nil(m.currentChannel)
+	require.Equal(blocks[1:], m.blocks)
+
+	// Confirmations of transactions of the dropped channels are ignored
+	m.TxConfirmed(txs[3][0].ID(), eth.BlockID{Number: 12})
+	require.Equal(blocks[1:], m.blocks)
+
+	txs = drainTxData(t, m)
+	require.Len(txs, 3)
+	require.Len(m.channelQueue, 3)
+	for i, ch := range m.channelQueue {
+		require.Equal(blocks[1+i:2+i], ch.Blocks())
+	}
+	require.Empty(m.blocks)
+}
+
+// TestChannelManagerReorgMultiplePendingChannels checks that multiple channels
+// in flight are cleared on a reorg, and that only trailing channels without
+// submitted transactions are dropped when the channel manager is closed.
+func TestChannelManagerReorgMultiplePendingChannels(t *testing.T) {
+	require := require.New(t)
+	log := testlog.Logger(t, log.LvlCrit)
+	m := NewChannelManager(log, metrics.NoopMetrics, multiChannelConfig(3))
+
+	blocks := addChainedBlocks(t, m, 3)
+	txs := drainTxData(t, m)
+	require.Len(txs, 3)
+
+	x := newMiniL2BlockWithNumberParent(0, big.NewInt(3), common.Hash{0xff})
+	require.ErrorIs(m.AddL2Block(x), ErrReorg)
+
+	// All frames of the last channel failed, so it gets dropped on close.
+	// The second channel has a pending tx, so it and all channels before it are kept.
+	for _, txdata := range txs[2] {
+		m.TxFailed(txdata.ID())
+	}
+	for _, txdata := range txs[1][1:] {
+		m.TxFailed(txdata.ID())
+	}
+	require.NoError(m.Close())
+	require.Len(m.channelQueue, 2)
+	require.Equal(blocks[1:2], m.channelQueue[1].Blocks())
+
+	// The remaining frames of the kept channels are still published
+	// but no new channels are created
+	drained := drainTxData(t, m)
+	require.Len(drained, 1)
+	require.Len(drained[0], len(txs[1])-1)
+
+	m.Clear()
+	require.Empty(m.channelQueue)
+	require.Nil(m.currentChannel)
+	require.Empty(m.blocks)
+
+	// Transactions of cleared channels are ignored
+	m.TxConfirmed(txs[0][0].ID(), eth.BlockID{Number: 1})
+	m.TxFailed(txs[1][0].ID())
+
+	// The channel manager starts over on the new chain
+	a := newMiniL2BlockWithNumberParent(0, big.NewInt(2), blocks[1].Hash())
+	require.NoError(m.AddL2Block(a))
+	txs = drainTxData(t, m)
+	require.Len(txs, 1)
+	require.Equal([]*types.Block{a}, m.channelQueue[0].Blocks())
+}
+
+// TestChannelManagerMultiFrameTxData checks that tx data holds frames of multiple
+// channels, oldest channel first, bounded by the max tx size.
+func TestChannelManagerMultiFrameTxData(t *testing.T) {
+	require := require.New(t)
+	log := testlog.Logger(t, log.LvlCrit)
+	m := NewChannelManager(log, metrics.NoopMetrics, multiChannelConfig(2))
+
+	addChainedBlocks(t, m, 2)
+	// Fail all single-frame txs, so that the frames of both channels are ready to be sent
+	txs := drainTxData(t, m)
+	require.Len(txs, 2)
+	var frames []frameData
+	for _, chTxs := range txs {
+		for _, txdata := range chTxs {
+			m.TxFailed(txdata.ID())
+			frames = append(frames, txdata.Frames()...)
+		}
+	}
+
+	require.GreaterOrEqual(len(txs[0]), 3, "test requires multiple frames per channel")
+
+	// The tx data is bounded by the max size
+	maxSize := uint64(1 + len(frames[0].data) + len(frames[1].data))
+	txdata, err := m.TxData(eth.L1BlockRef{}, maxSize)
+	require.NoError(err)
+	require.Equal(frames[:2], txdata.Frames())
+	require.LessOrEqual(uint64(txdata.Len()), maxSize)
+
+	// The remaining frames of both channels fit into a single tx
+	txdata1, err := m.TxData(eth.L1BlockRef{}, 120_000)
+	require.NoError(err)
+	require.Equal(frames[2:], txdata1.Frames())
+	require.NotEqual(txdata1.Frames()[0].id.chID, txdata1.Frames()[len(txdata1.Frames())-1].id.chID,
+		"tx data must span both channels")
+	_, err = m.TxData(eth.L1BlockRef{}, 120_000)
+	require.ErrorIs(err, io.EOF)
+
+	// The tx data is valid input for the derivation pipeline
+	parsed, err := derive.ParseFrames(txdata1.Bytes())
+	require.NoError(err)
+	require.Len(parsed, len(txdata1.Frames()))
+	require.Equal(txdata1.Frames()[0].id.chID, parsed[0].ID)
+
+	// Confirmations are tracked per channel
+	m.TxConfirmed(txdata.ID(), eth.BlockID{Number: 1})
+	require.Len(m.channelQueue, 2)
+	m.TxConfirmed(txdata1.ID(), eth.BlockID{Number: 2})
+	require.Empty(m.channelQueue, "both channels must be fully submitted")
+}
+
+// TestChannelManagerMultiFrameTxFailed checks that the frames of a failed tx that spans
+// multiple channels are re-queued in their channels.
+func TestChannelManagerMultiFrameTxFailed(t *testing.T) {
+	require := require.New(t)
+	log := testlog.Logger(t, log.LvlCrit)
+	m := NewChannelManager(log, metrics.NoopMetrics, multiChannelConfig(2))
+
+	addChainedBlocks(t, m, 2)
+	txs := drainTxData(t, m)
+	require.Len(txs, 2)
+	for _, chTxs := range txs {
+		for _, txdata := range chTxs {
+			m.TxFailed(txdata.ID())
+		}
+	}
+	numFrames0 := m.channelQueue[0].builder.NumFrames()
+	numFrames1 := m.channelQueue[1].builder.NumFrames()
+
+	txdata, err := m.TxData(eth.L1BlockRef{}, 120_000)
+	require.NoError(err)
+	require.Len(txdata.Frames(), numFrames0+numFrames1)
+	require.True(m.channelQueue[0].HasPendingTx(txdata.ID()))
+	require.True(m.channelQueue[1].HasPendingTx(txdata.ID()))
+	require.False(m.hasFrame())
+
+	m.TxFailed(txdata.ID())
+	require.Equal(numFrames0, m.channelQueue[0].builder.NumFrames())
+	require.Equal(numFrames1, m.channelQueue[1].builder.NumFrames())
+	require.Empty(m.channelQueue[0].pendingTransactions)
+	require.Empty(m.channelQueue[1].pendingTransactions)
+}
+
+// TestChannelManagerForceCloseChannel checks that force closing the open channel
+// outputs its frames right away, and that the state reflects the closed channel.
+func TestChannelManagerForceCloseChannel(t *testing.T) {
+	require := require.New(t)
+	log := testlog.Logger(t, log.LvlCrit)
+	m := NewChannelManager(log, metrics.NoopMetrics,
+		ChannelConfig{
+			MaxFrameSize:   120_000,
+			ChannelTimeout: 1000,
+			CompressorConfig: compressor.Config{
+				TargetFrameSize:  120_000,
+				TargetNumFrames:  1,
+				ApproxComprRatio: 1.0,
+			},
+		})
+
+	_, err := m.ForceCloseChannel()
+	require.ErrorIs(err, ErrNoOpenChannel)
+
+	blocks := addChainedBlocks(t, m, 2)
+	_, err = m.TxData(eth.L1BlockRef{}, 0)
+	require.ErrorIs(err, io.EOF, "channel must not be full yet")
+
+	state := m.State()
+	require.NotNil(state.CurrentChannel)
+	require.Len(state.Channels, 1)
+	require.Equal(*state.CurrentChannel, state.Channels[0].ID)
+	require.Equal(eth.ToBlockID(blocks[0]), state.Channels[0].StartBlock)
+	require.Equal(eth.ToBlockID(blocks[1]), state.Channels[0].EndBlock)
+	require.False(state.Channels[0].Full)
+	require.Zero(state.Channels[0].FramesReady)
+	require.Zero(state.BlocksPending)
+
+	id, err := m.ForceCloseChannel()
+	require.NoError(err)
+	require.Equal(*state.CurrentChannel, id)
+
+	state = m.State()
+	require.Nil(state.CurrentChannel)
+	require.True(state.Channels[0].Full)
+	require.Contains(state.Channels[0].FullReason, ErrTerminated.Error())
+	require.Equal(1, state.Channels[0].FramesReady)
+
+	txdata, err := m.TxData(eth.L1BlockRef{}, 0)
+	require.NoError(err)
+	require.Equal(id, txdata.Frames()[0].id.chID)
+	state = m.State()
+	require.Zero(state.Channels[0].FramesReady)
+	require.Equal(1, state.Channels[0].FramesPending)
+
+	m.TxConfirmed(txdata.ID(), eth.BlockID{Number: 1})
+	require.Empty(m.State().Channels)
+	_, err = m.ForceCloseChannel()
+	require.ErrorIs(err, ErrNoOpenChannel)
+}
+
+// TestChannelManagerCompressionActivation checks that channels are only compressed with the
+// configured algorithm once the channel compression upgrade is active at the L1 head.
+func TestChannelManagerCompressionActivation(t *testing.T) {
+	require := require.New(t)
+	log := testlog.Logger(t, log.LvlCrit)
+	cfg := multiChannelConfig(1)
+	cfg.CompressorConfig.Algo = derive.Brotli
+	activation := uint64(100)
+	cfg.ChannelCompressionTime = &activation
+	m := NewChannelManager(log, metrics.NoopMetrics, cfg)
+
+	firstByte := func(l1Head eth.L1BlockRef) byte {
+		txdata, err := m.TxData(l1Head, 0)
+		require.NoError(err)
+		var f derive.Frame
+		require.NoError(f.UnmarshalBinary(bytes.NewReader(txdata.Frames()[0].data)))
+		require.Zero(f.FrameNumber)
+		for _, txs := range drainTxData(t, m) {
+			for _, txdata := range txs {
+				m.TxConfirmed(txdata.ID(), l1Head.ID())
+			}
+		}
+		m.TxConfirmed(txdata.ID(), l1Head.ID())
+		return f.Data[0]
+	}
+
+	addChainedBlocks(t, m, 1)
+	require.NotEqual(derive.ChannelVersionBrotli, firstByte(eth.L1BlockRef{Time: activation - 1}), "zlib before the activation")
+	require.Empty(m.channelQueue)
+
+	m.Clear()
+	addChainedBlocks(t, m, 1)
+	require.Equal(derive.ChannelVersionBrotli, firstByte(eth.L1BlockRef{Time: activation}))
+}
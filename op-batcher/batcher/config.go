@@ -1,6 +1,8 @@
 package batcher
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/ethereum/go-ethereum/ethclient"
@@ -13,6 +15,7 @@ import (
 	"github.com/ethereum-optimism/optimism/op-batcher/rpc"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-node/sources"
+	"github.com/ethereum-optimism/optimism/op-service/da"
 	oplog "github.com/ethereum-optimism/optimism/op-service/log"
 	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
 	oppprof "github.com/ethereum-optimism/optimism/op-service/pprof"
@@ -20,20 +23,31 @@ import (
 )
 
 type Config struct {
-	log         log.Logger
-	metr        metrics.Metricer
-	L1Client    *ethclient.Client
-	L1DAClient  *ethclient.Client
-	DaType      string
-	L2Client    *ethclient.Client
-	RollupNode  *sources.RollupClient
-	TxManager   txmgr.TxManager
-	TxDAManager txmgr.TxManager
+	log        log.Logger
+	metr       metrics.Metricer
+	L1Client   *ethclient.Client
+	L2Client   *ethclient.Client
+	RollupNode *sources.RollupClient
+	TxManager  txmgr.TxManager
+
+	// DA is the data availability backend that batch data is stored on
+	DA da.DataAvailability
+	// Journal keeps the references to data stored on DA until they are sent to L1
+	Journal *txmgr.Journal
+	// AnchorPolicy controls when the references to data stored on DA are sent to L1
+	AnchorPolicy txmgr.AnchorPolicy
+	// DAFallback configures posting batch data as calldata while the DA backend is unavailable
+	DAFallback DAFallbackConfig
 
 	NetworkTimeout         time.Duration
 	PollInterval           time.Duration
 	MaxPendingTransactions uint64
 
+	// MaxL1TxSize is the maximum size of a batch tx posted to L1 as calldata
+	MaxL1TxSize uint64
+	// MaxDATxSize is the maximum size of a batch tx stored on the DA backend
+	MaxDATxSize uint64
+
 	// RollupConfig is queried at startup
 	Rollup *rollup.Config
 
@@ -49,13 +63,33 @@ func (c *Config) Check() error {
 	if err := c.Channel.Check(); err != nil {
 		return err
 	}
+	if c.DA == nil {
+		return errors.New("no data availability backend configured")
+	}
+	if c.Journal == nil {
+		return errors.New("no DA journal configured")
+	}
+	if err := c.AnchorPolicy.Check(); err != nil {
+		return err
+	}
+	if c.MaxDATxSize < c.Channel.MaxFrameSize+1 || c.MaxL1TxSize < c.Channel.MaxFrameSize+1 {
+		return errors.New("max L1 and DA tx sizes must fit a frame")
+	}
+	if c.DAFallback.Threshold < 0 || c.DAFallback.ProbeInterval < 0 {
+		return errors.New("DA fallback threshold and probe interval cannot be negative")
+	}
+	if typ := da.TypeOrDefault(c.Rollup.DAType); da.Types[typ] != c.DA.Prefix() {
+		return fmt.Errorf("data availability backend with prefix %d does not match rollup DA type %s", c.DA.Prefix(), typ)
+	}
 	return nil
 }
 
 type CLIConfig struct {
 	// L1EthRpc is the HTTP provider URL for L1.
-	L1EthRpc    string
-	L1EthDARpc  string
+	L1EthRpc string
+
+	// L1EthDAType is the data availability backend to store batch data on.
+	// If empty, the DA type of the rollup config is used. Otherwise it must match it.
 	L1EthDAType string
 
 	// L2EthRpc is the HTTP provider URL for the L2 execution engine.
@@ -74,6 +108,12 @@ type CLIConfig struct {
 	// If 0, duration checks are disabled.
 	MaxChannelDuration uint64
 
+	// MaxPendingChannels is the maximum number of channels to keep in flight at
+	// once. Blocks are added to channels in order, so their block ranges never
+	// overlap, but each channel times out and is confirmed independently.
+	// Channels with frames stored on DA are in flight until their anchors are included on L1.
+	MaxPendingChannels uint64
+
 	// The batcher tx submission safety margin (in #L1-blocks) to subtract from
 	// a channel's timeout and sequencing window, to guarantee safe inclusion of
 	// a channel on L1.
@@ -90,10 +130,33 @@ type CLIConfig struct {
 	// MaxL1TxSize is the maximum size of a batch tx submitted to L1.
 	MaxL1TxSize uint64
 
+	// MaxDATxSize is the maximum size of a batch tx stored on the DA backend, which can hold
+	// multiple frames. The default of the DA backend is used if zero.
+	MaxDATxSize uint64
+
+	// DAJournal is the path of the journal of references to data stored on DA. In memory only if empty.
+	DAJournal string
+
+	// DAAnchorMinBatch and DAAnchorMaxWait control how references to data stored on DA are batched on L1,
+	// and up to DAAnchorMaxBatch of them are aggregated into a single L1 transaction.
+	DAAnchorMinBatch int
+	DAAnchorMaxWait  time.Duration
+	DAAnchorMaxBatch int
+
+	// DAFinalityDepth is the number of DA-blocks that stored data must be buried under before it is referenced
+	// on L1. The default of the DA backend is used if zero.
+	DAFinalityDepth uint64
+
+	// DAFallbackThreshold is the number of consecutive DA failures after which batch data is posted as calldata.
+	// Disabled if zero.
+	DAFallbackThreshold     int
+	DAFallbackProbeInterval time.Duration
+
 	Stopped bool
 
 	TxMgrConfig      txmgr.CLIConfig
 	TxDAMgrConfig    txmgr.CLIConfig
+	DAConfig         da.CLIConfig
 	RPCConfig        rpc.CLIConfig
 	LogConfig        oplog.CLIConfig
 	MetricsConfig    opmetrics.CLIConfig
@@ -117,6 +180,17 @@ func (c CLIConfig) Check() error {
 	if err := c.TxMgrConfig.Check(); err != nil {
 		return err
 	}
+	if err := c.DAConfig.Check(); err != nil {
+		return err
+	}
+	if err := c.CompressorConfig.Check(); err != nil {
+		return err
+	}
+	if c.L1EthDAType != "" {
+		if err := c.DAConfig.CheckType(c.L1EthDAType); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -126,23 +200,32 @@ func NewConfig(ctx *cli.Context) CLIConfig {
 		/* Required Flags */
 		L1EthRpc:        ctx.GlobalString(flags.L1EthRpcFlag.Name),
 		L2EthRpc:        ctx.GlobalString(flags.L2EthRpcFlag.Name),
-		L1EthDARpc:      ctx.GlobalString(flags.L1EthDARpcFlag.Name),
 		L1EthDAType:     ctx.GlobalString(flags.L1EthDATypeFlag.Name),
 		RollupRpc:       ctx.GlobalString(flags.RollupRpcFlag.Name),
 		SubSafetyMargin: ctx.GlobalUint64(flags.SubSafetyMarginFlag.Name),
 		PollInterval:    ctx.GlobalDuration(flags.PollIntervalFlag.Name),
 
 		/* Optional Flags */
-		MaxPendingTransactions: ctx.GlobalUint64(flags.MaxPendingTransactionsFlag.Name),
-		MaxChannelDuration:     ctx.GlobalUint64(flags.MaxChannelDurationFlag.Name),
-		MaxL1TxSize:            ctx.GlobalUint64(flags.MaxL1TxSizeBytesFlag.Name),
-		Stopped:                ctx.GlobalBool(flags.StoppedFlag.Name),
-		TxMgrConfig:            txmgr.ReadCLIConfig(ctx),
-		TxDAMgrConfig:          txmgr.ReadCLIConfigDA(ctx),
-		RPCConfig:              rpc.ReadCLIConfig(ctx),
-		LogConfig:              oplog.ReadCLIConfig(ctx),
-		MetricsConfig:          opmetrics.ReadCLIConfig(ctx),
-		PprofConfig:            oppprof.ReadCLIConfig(ctx),
-		CompressorConfig:       compressor.ReadCLIConfig(ctx),
+		MaxPendingTransactions:  ctx.GlobalUint64(flags.MaxPendingTransactionsFlag.Name),
+		MaxChannelDuration:      ctx.GlobalUint64(flags.MaxChannelDurationFlag.Name),
+		MaxPendingChannels:      ctx.GlobalUint64(flags.MaxPendingChannelsFlag.Name),
+		MaxL1TxSize:             ctx.GlobalUint64(flags.MaxL1TxSizeBytesFlag.Name),
+		MaxDATxSize:             ctx.GlobalUint64(flags.MaxDATxSizeBytesFlag.Name),
+		DAJournal:               ctx.GlobalString(flags.DAJournalFlag.Name),
+		DAAnchorMinBatch:        ctx.GlobalInt(flags.DAAnchorMinBatchFlag.Name),
+		DAAnchorMaxWait:         ctx.GlobalDuration(flags.DAAnchorMaxWaitFlag.Name),
+		DAAnchorMaxBatch:        ctx.GlobalInt(flags.DAAnchorMaxBatchFlag.Name),
+		DAFinalityDepth:         ctx.GlobalUint64(flags.DAFinalityDepthFlag.Name),
+		DAFallbackThreshold:     ctx.GlobalInt(flags.DAFallbackThresholdFlag.Name),
+		DAFallbackProbeInterval: ctx.GlobalDuration(flags.DAFallbackProbeIntervalFlag.Name),
+		Stopped:                 ctx.GlobalBool(flags.StoppedFlag.Name),
+		TxMgrConfig:             txmgr.ReadCLIConfig(ctx),
+		TxDAMgrConfig:           txmgr.ReadCLIConfigDA(ctx),
+		DAConfig:                da.ReadCLIConfig(ctx),
+		RPCConfig:               rpc.ReadCLIConfig(ctx),
+		LogConfig:               oplog.ReadCLIConfig(ctx),
+		MetricsConfig:           opmetrics.ReadCLIConfig(ctx),
+		PprofConfig:             oppprof.ReadCLIConfig(ctx),
+		CompressorConfig:        compressor.ReadCLIConfig(ctx),
 	}
 }
@@ -0,0 +1,100 @@
+package batcher
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-batcher/metrics"
+)
+
+// DAFallbackConfig configures the fallback to posting batch data as L1 calldata
+// while the DA backend is unavailable.
+type DAFallbackConfig struct {
+	// Threshold is the number of consecutive failures to store data on the DA backend
+	// after which batch data is posted as calldata instead. The fallback is disabled if zero.
+	Threshold int
+	// ProbeInterval is the interval at which the DA backend is tried again while falling back to calldata.
+	ProbeInterval time.Duration
+}
+
+// daFallback tracks the health of the DA backend, and decides whether batch data is stored on it
+// or falls back to calldata. While falling back, a single transaction is stored on the DA backend
+// every probe interval, and the batcher switches back to the DA backend as soon as one succeeds.
+type daFallback struct {
+	mu   sync.Mutex
+	log  log.Logger
+	metr metrics.Metricer
+	cfg  DAFallbackConfig
+	now  func() time.Time
+
+	failures  int
+	active    bool
+	probing   bool
+	lastProbe time.Time
+}
+
+func newDAFallback(log log.Logger, metr metrics.Metricer, cfg DAFallbackConfig) *daFallback {
+	return &daFallback{log: log, metr: metr, cfg: cfg, now: time.Now}
+}
+
+// UseDA returns whether the next transaction is stored on the DA backend,
+// or falls back to calldata.
+func (f *daFallback) UseDA() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.active {
+		return true
+	}
+	if f.probing || f.now().Sub(f.lastProbe) < f.cfg.ProbeInterval {
+		return false
+	}
+	f.log.Info("Probing DA backend while falling back to calldata")
+	f.probing = true
+	f.lastProbe = f.now()
+	return true
+}
+
+// Active returns whether batch data currently falls back to calldata.
+func (f *daFallback) Active() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.active
+}
+
+// DASucceeded records that data was stored on the DA backend, which ends the fallback to calldata.
+func (f *daFallback) DASucceeded() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failures = 0
+	f.probing = false
+	if f.active {
+		f.active = false
+		f.log.Info("DA backend recovered, switching back from calldata to DA")
+		f.metr.RecordDAFallback(false)
+	}
+}
+
+// DAFailed records a failure to store data on the DA backend, which starts the fallback to calldata
+// once the threshold of consecutive failures is reached.
+func (f *daFallback) DAFailed() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failures++
+	if f.active {
+		if f.probing {
+			f.probing = false
+			f.lastProbe = f.now()
+			f.log.Warn("DA backend still unavailable, keeping calldata fallback", "failures", f.failures)
+		}
+		return
+	}
+	if f.cfg.Threshold == 0 || f.failures < f.cfg.Threshold {
+		return
+	}
+	f.active = true
+	f.lastProbe = f.now()
+	f.log.Warn("DA backend unavailable, falling back to calldata", "failures", f.failures, "probe_interval", f.cfg.ProbeInterval)
+	f.metr.RecordDAFallback(true)
+}
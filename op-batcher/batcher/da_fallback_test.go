@@ -0,0 +1,71 @@
+package batcher
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-batcher/metrics"
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+)
+
+type testDAFallbackMetrics struct {
+	metrics.Metricer
+	transitions []bool
+}
+
+func (m *testDAFallbackMetrics) RecordDAFallback(active bool) {
+	m.transitions = append(m.transitions, active)
+}
+
+func TestDAFallback(t *testing.T) {
+	m := &testDAFallbackMetrics{Metricer: metrics.NoopMetrics}
+	f := newDAFallback(testlog.Logger(t, log.LvlCrit), m, DAFallbackConfig{Threshold: 3, ProbeInterval: time.Minute})
+	now := time.Unix(1000, 0)
+	f.now = func() time.Time { return now }
+
+	// failures below the threshold, or that are not consecutive, keep using DA
+	f.DAFailed()
+	f.DAFailed()
+	f.DASucceeded()
+	f.DAFailed()
+	f.DAFailed()
+	require.True(t, f.UseDA())
+	require.Empty(t, m.transitions)
+
+	require.False(t, f.Active())
+	f.DAFailed()
+	require.False(t, f.UseDA(), "falls back to calldata at the threshold")
+	require.True(t, f.Active())
+	require.Equal(t, []bool{true}, m.transitions)
+
+	now = now.Add(time.Minute)
+	require.True(t, f.UseDA(), "probes DA after the probe interval")
+	require.True(t, f.Active(), "still falls back while probing")
+	require.False(t, f.UseDA(), "probes a single tx at a time")
+	f.DAFailed()
+	require.False(t, f.UseDA(), "failed probe keeps falling back")
+	now = now.Add(30 * time.Second)
+	require.False(t, f.UseDA(), "next probe waits for the probe interval")
+	now = now.Add(30 * time.Second)
+	require.True(t, f.UseDA())
+	require.Equal(t, []bool{true}, m.transitions)
+
+	f.DASucceeded()
+	require.True(t, f.UseDA(), "switches back to DA once it recovers")
+	require.False(t, f.Active())
+	require.True(t, f.UseDA())
+	require.Equal(t, []bool{true, false}, m.transitions)
+}
+
+func TestDAFallbackDisabled(t *testing.T) {
+	m := &testDAFallbackMetrics{Metricer: metrics.NoopMetrics}
+	f := newDAFallback(testlog.Logger(t, log.LvlCrit), m, DAFallbackConfig{})
+	for i := 0; i < 100; i++ {
+		f.DAFailed()
+	}
+	require.True(t, f.UseDA())
+	require.Empty(t, m.transitions)
+}
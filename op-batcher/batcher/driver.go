@@ -4,24 +4,30 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"io"
 	"math/big"
 	_ "net/http/pprof"
-	"os"
 	"sync"
 	"time"
 
 	"github.com/ethereum-optimism/optimism/op-batcher/metrics"
+	"github.com/ethereum-optimism/optimism/op-batcher/rpc"
 	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
 	opclient "github.com/ethereum-optimism/optimism/op-service/client"
+	"github.com/ethereum-optimism/optimism/op-service/da"
 	"github.com/ethereum-optimism/optimism/op-service/txmgr"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
 )
 
+// polygonConfirmationDepth is the default number of Polygon blocks a batch data transaction must be buried under
+// before it is referenced on L1.
+const polygonConfirmationDepth = 375
+
 // BatchSubmitter encapsulates a service responsible for submitting L2 tx
 // batches to L1 for availability.
 type BatchSubmitter struct {
@@ -37,12 +43,18 @@ type BatchSubmitter struct {
 
 	mutex   sync.Mutex
 	running bool
+	// queue sends the batcher txs while running
+	queue *txmgr.Queue[txData]
 
 	// lastStoredBlock is the last block loaded into `state`. If it is empty it should be set to the l2 safe head.
-	lastStoredBlock eth.BlockID
-	lastL1Tip       eth.L1BlockRef
+	// It is only written by the main loop, through setLastStoredBlock, so that the admin API can read it.
+	lastStoredBlock   eth.BlockID
+	lastStoredBlockMu sync.Mutex
+	lastL1Tip         eth.L1BlockRef
 
 	state *channelManager
+	// daFallback decides whether batch data is stored on the DA backend, or posted as calldata instead
+	daFallback *daFallback
 }
 
 // NewBatchSubmitterFromCLIConfig initializes the BatchSubmitter, gathering any resources
@@ -57,14 +69,6 @@ func NewBatchSubmitterFromCLIConfig(cfg CLIConfig, l log.Logger, m metrics.Metri
 		return nil, err
 	}
 
-	var l1DAClient *ethclient.Client
-	if cfg.L1EthDAType != "CELESTIA" && cfg.L1EthDAType != "EIGEN" {
-		l1DAClient, err = opclient.DialEthClientWithTimeout(ctx, cfg.L1EthDARpc, opclient.DefaultDialTimeout)
-		if err != nil {
-			return nil, err
-		}
-	}
-
 	l2Client, err := opclient.DialEthClientWithTimeout(ctx, cfg.L2EthRpc, opclient.DefaultDialTimeout)
 	if err != nil {
 		return nil, err
@@ -85,33 +89,50 @@ func NewBatchSubmitterFromCLIConfig(cfg CLIConfig, l log.Logger, m metrics.Metri
 		return nil, err
 	}
 
-	var txdaManager *txmgr.SimpleTxManager
-	if cfg.L1EthDAType != "CELESTIA" && cfg.L1EthDAType != "EIGEN" {
-		txdaManager, err = txmgr.NewSimpleTxManager("batcher", l, m, cfg.TxDAMgrConfig)
-		if err != nil {
-			return nil, err
-		}
+	daBackend, err := newDataAvailability(ctx, cfg, l, m, rcfg)
+	if err != nil {
+		return nil, err
+	}
+
+	journal, err := txmgr.OpenJournal(cfg.DAJournal, m)
+	if err != nil {
+		return nil, err
 	}
+	l.Info("Opened DA journal", "path", cfg.DAJournal, "depth", journal.Depth())
 
 	batcherCfg := Config{
 		L1Client:               l1Client,
-		L1DAClient:             l1DAClient,
-		DaType:                 cfg.L1EthDAType,
+		DA:                     daBackend,
+		Journal:                journal,
 		L2Client:               l2Client,
 		RollupNode:             rollupClient,
 		PollInterval:           cfg.PollInterval,
 		MaxPendingTransactions: cfg.MaxPendingTransactions,
+		MaxL1TxSize:            cfg.MaxL1TxSize,
+		MaxDATxSize:            daMaxTxSize(cfg.MaxDATxSize, cfg.MaxL1TxSize, daBackend),
 		NetworkTimeout:         cfg.TxMgrConfig.NetworkTimeout,
 		TxManager:              txManager,
-		TxDAManager:            txdaManager,
 		Rollup:                 rcfg,
 		Channel: ChannelConfig{
-			SeqWindowSize:      rcfg.SeqWindowSize,
-			ChannelTimeout:     rcfg.ChannelTimeout,
-			MaxChannelDuration: cfg.MaxChannelDuration,
-			SubSafetyMargin:    cfg.SubSafetyMargin,
-			MaxFrameSize:       cfg.MaxL1TxSize - 1, // subtract 1 byte for version
-			CompressorConfig:   cfg.CompressorConfig.Config(),
+			SeqWindowSize:          rcfg.SeqWindowSize,
+			ChannelTimeout:         rcfg.ChannelTimeout,
+			MaxChannelDuration:     cfg.MaxChannelDuration,
+			SubSafetyMargin:        cfg.SubSafetyMargin,
+			MaxFrameSize:           cfg.MaxL1TxSize - 1, // subtract 1 byte for version
+			CompressorConfig:       cfg.CompressorConfig.Config(),
+			ChannelCompressionTime: rcfg.ChannelCompressionTime,
+			MaxPendingChannels:     cfg.MaxPendingChannels,
+		},
+		AnchorPolicy: txmgr.AnchorPolicy{
+			MinBatch:          cfg.DAAnchorMinBatch,
+			MaxWait:           cfg.DAAnchorMaxWait,
+			ConfirmationDepth: daFinalityDepth(cfg.DAFinalityDepth, daBackend),
+			MaxBatch:          cfg.DAAnchorMaxBatch,
+			Aggregate:         da.EncodeAggregatePayload,
+		},
+		DAFallback: DAFallbackConfig{
+			Threshold:     cfg.DAFallbackThreshold,
+			ProbeInterval: cfg.DAFallbackProbeInterval,
 		},
 	}
 
@@ -123,6 +144,36 @@ func NewBatchSubmitterFromCLIConfig(cfg CLIConfig, l log.Logger, m metrics.Metri
 	return NewBatchSubmitter(ctx, batcherCfg, l, m)
 }
 
+// newDataAvailability creates the data availability backend that batch data is stored on,
+// as configured by the DA type of the rollup.
+func newDataAvailability(ctx context.Context, cfg CLIConfig, l log.Logger, m metrics.Metricer, rcfg *rollup.Config) (da.DataAvailability, error) {
+	typ := da.TypeOrDefault(rcfg.DAType)
+	if cfg.L1EthDAType != "" && cfg.L1EthDAType != typ {
+		return nil, fmt.Errorf("configured DA type %s does not match rollup DA type %s", cfg.L1EthDAType, typ)
+	}
+	if err := cfg.DAConfig.CheckType(typ); err != nil {
+		return nil, err
+	}
+	l.Info("Initializing data availability", "type", typ, "rpc", cfg.DAConfig.RPC, "server", cfg.DAConfig.Server,
+		"tls", cfg.DAConfig.TLSConfig.TLSEnabled())
+	switch typ {
+	case da.CalldataType:
+		return da.NewCalldata(), nil
+	case da.PolygonType:
+		daClient, err := opclient.DialEthClientWithTimeout(ctx, cfg.DAConfig.RPC, opclient.DefaultDialTimeout)
+		if err != nil {
+			return nil, err
+		}
+		daTxManager, err := txmgr.NewSimpleTxManager("batcher", l, m, cfg.TxDAMgrConfig)
+		if err != nil {
+			return nil, err
+		}
+		return da.NewPolygon(daTxManager, rcfg.BatchInboxAddress, da.NewEthClientChain(daClient)), nil
+	default:
+		return cfg.DAConfig.NewHTTPBlob(l, typ)
+	}
+}
+
 // NewBatchSubmitter initializes the BatchSubmitter, gathering any resources
 // that will be needed during operation.
 func NewBatchSubmitter(ctx context.Context, cfg Config, l log.Logger, m metrics.Metricer) (*BatchSubmitter, error) {
@@ -137,9 +188,10 @@ func NewBatchSubmitter(ctx context.Context, cfg Config, l log.Logger, m metrics.
 	cfg.metr = m
 
 	return &BatchSubmitter{
-		Config: cfg,
-		txMgr:  cfg.TxManager,
-		state:  NewChannelManager(l, m, cfg.Channel),
+		Config:     cfg,
+		txMgr:      cfg.TxManager,
+		state:      NewChannelManager(l, m, cfg.Channel),
+		daFallback: newDAFallback(l, m, cfg.DAFallback),
 	}, nil
 
 }
@@ -158,10 +210,19 @@ func (l *BatchSubmitter) Start() error {
 	l.shutdownCtx, l.cancelShutdownCtx = context.WithCancel(context.Background())
 	l.killCtx, l.cancelKillCtx = context.WithCancel(context.Background())
 	l.state.Clear()
-	l.lastStoredBlock = eth.BlockID{}
+	l.setLastStoredBlock(eth.BlockID{})
+
+	if da.IsInline(l.DA) {
+		l.queue = txmgr.NewQueue[txData](l.killCtx, l.txMgr, l.MaxPendingTransactions)
+	} else {
+		l.queue = txmgr.NewQueueWithJournal[txData](l.killCtx, l.log, l.txMgr, l.MaxPendingTransactions, l.Journal, l.AnchorPolicy)
+		if err := l.queue.ResumeDA(daStore{l.DA}); err != nil {
+			l.log.Error("Failed to resume DA journal", "err", err)
+		}
+	}
 
 	l.wg.Add(1)
-	go l.loop()
+	go l.loop(l.queue)
 
 	l.log.Info("Batch Submitter started")
 
@@ -195,12 +256,63 @@ func (l *BatchSubmitter) Stop(ctx context.Context) error {
 	l.cancelShutdownCtx()
 	l.wg.Wait()
 	l.cancelKillCtx()
+	l.queue.Close()
 
 	l.log.Info("Batch Submitter stopped")
 
 	return nil
 }
 
+// setLastStoredBlock sets the last block loaded into `state`.
+func (l *BatchSubmitter) setLastStoredBlock(id eth.BlockID) {
+	l.lastStoredBlockMu.Lock()
+	defer l.lastStoredBlockMu.Unlock()
+	l.lastStoredBlock = id
+}
+
+// State returns the in-flight state of the batcher, as reported by the admin API.
+func (l *BatchSubmitter) State() (*rpc.BatcherState, error) {
+	l.mutex.Lock()
+	running := l.running
+	l.mutex.Unlock()
+
+	state := l.state.State()
+	state.Running = running
+	l.lastStoredBlockMu.Lock()
+	state.LastStoredBlock = l.lastStoredBlock
+	l.lastStoredBlockMu.Unlock()
+
+	entries, err := l.Journal.Entries()
+	if err != nil {
+		return nil, fmt.Errorf("reading DA journal: %w", err)
+	}
+	state.DAAnchors = make([]rpc.DAAnchor, 0, len(entries))
+	for _, e := range entries {
+		anchor := rpc.DAAnchor{Seq: e.Seq, Confirmed: e.Confirmed, Ref: e.Candidate.TxData}
+		if p, err := da.DecodePayload(e.Candidate.TxData); err == nil {
+			anchor.Ref = p.Ref
+		}
+		state.DAAnchors = append(state.DAAnchors, anchor)
+	}
+	return state, nil
+}
+
+// ForceCloseChannel closes the channel new blocks are added to, so that its remaining frames are sent.
+func (l *BatchSubmitter) ForceCloseChannel() (derive.ChannelID, error) {
+	return l.state.ForceCloseChannel()
+}
+
+// FlushAnchors sends the L1 anchors of the data on the DA backend that is confirmed deep enough right away.
+func (l *BatchSubmitter) FlushAnchors(ctx context.Context) (int, error) {
+	l.mutex.Lock()
+	running, queue := l.running, l.queue
+	l.mutex.Unlock()
+	if !running {
+		return 0, errors.New("batcher is not running")
+	}
+	return queue.FlushAnchors(ctx)
+}
+
 // loadBlocksIntoState loads all blocks since the previous stored block
 // It does the following:
 // 1. Fetch the sync status of the sequencer
@@ -224,13 +336,13 @@ func (l *BatchSubmitter) loadBlocksIntoState(ctx context.Context) error {
 		block, err := l.loadBlockIntoState(ctx, i)
 		if errors.Is(err, ErrReorg) {
 			l.log.Warn("Found L2 reorg", "block_number", i)
-			l.lastStoredBlock = eth.BlockID{}
+			l.setLastStoredBlock(eth.BlockID{})
 			return err
 		} else if err != nil {
 			l.log.Warn("failed to load block into state", "err", err)
 			return err
 		}
-		l.lastStoredBlock = eth.ToBlockID(block)
+		l.setLastStoredBlock(eth.ToBlockID(block))
 		latestBlock = block
 	}
 
@@ -279,10 +391,10 @@ func (l *BatchSubmitter) calculateL2BlockRangeToStore(ctx context.Context) (eth.
 	// It lagging implies that the op-node processed some batches that were submitted prior to the current instance of the batcher being alive.
 	if l.lastStoredBlock == (eth.BlockID{}) {
 		l.log.Info("Starting batch-submitter work at safe-head", "safe", syncStatus.SafeL2)
-		l.lastStoredBlock = syncStatus.SafeL2.ID()
+		l.setLastStoredBlock(syncStatus.SafeL2.ID())
 	} else if l.lastStoredBlock.Number < syncStatus.SafeL2.Number {
 		l.log.Warn("last submitted block lagged behind L2 safe head: batch submission will continue from the safe head now", "last", l.lastStoredBlock, "safe", syncStatus.SafeL2)
-		l.lastStoredBlock = syncStatus.SafeL2.ID()
+		l.setLastStoredBlock(syncStatus.SafeL2.ID())
 	}
 
 	// Check if we should even attempt to load any blocks. TODO: May not need this check
@@ -304,14 +416,13 @@ func (l *BatchSubmitter) calculateL2BlockRangeToStore(ctx context.Context) (eth.
 // Submitted batch, but it is not valid
 // Missed L2 block somehow.
 
-func (l *BatchSubmitter) loop() {
+func (l *BatchSubmitter) loop(queue *txmgr.Queue[txData]) {
 	defer l.wg.Done()
 
 	ticker := time.NewTicker(l.PollInterval)
 	defer ticker.Stop()
 
 	receiptsCh := make(chan txmgr.TxReceipt[txData])
-	queue := txmgr.NewQueue[txData](l.killCtx, l.txMgr, l.Config.TxDAManager, l.MaxPendingTransactions)
 
 	for {
 		select {
@@ -384,7 +495,7 @@ func (l *BatchSubmitter) publishTxToL1(ctx context.Context, queue *txmgr.Queue[t
 	l.recordL1Tip(l1tip)
 
 	// Collect next transaction data
-	txdata, err := l.state.TxData(l1tip.ID())
+	txdata, err := l.state.TxData(l1tip, l.maxTxSize())
 	if err == io.EOF {
 		l.log.Trace("no transaction data available")
 		return err
@@ -414,29 +525,93 @@ func (l *BatchSubmitter) sendTransaction(txdata txData, queue *txmgr.Queue[txDat
 		TxData:   data,
 		GasLimit: intrinsicGas,
 	}
-	if l.DaType == "BTC" {
-		candidate.TxData = append([]byte{0}, data...)
+	useCalldata := da.IsInline(l.DA) || !l.daFallback.UseDA()
+	if useCalldata && uint64(len(data)) > l.MaxL1TxSize {
+		// The tx data was collected for the DA backend before falling back to calldata started. It is
+		// stored on the DA backend regardless, and its frames are re-queued in smaller txs if that fails.
+		l.log.Warn("Tx data too large for calldata, storing on DA backend", "data_size", len(data))
+		useCalldata = false
+	}
+	if useCalldata {
+		candidate.TxData = da.EncodePayload(da.CalldataPrefix, data, data)
 		candidate.GasLimit = intrinsicGas * 2
 		queue.Send(txdata, candidate, receiptsCh)
-	} else if l.DaType == "POLYGON" {
-		queue.Send2Step(txdata, candidate, receiptsCh)
-	} else if l.DaType == "CELESTIA" {
-		queue.StoreOnDaServer(os.Getenv("CELESTIA"), 2, txdata, candidate, receiptsCh)
-	} else if l.DaType == "EIGEN" {
-		queue.StoreOnDaServer(os.Getenv("EIGEN"), 3, txdata, candidate, receiptsCh)
-	} else if l.DaType == "NEARDA" {
-		queue.StoreOnDaServer(os.Getenv("NEARDA"), 4, txdata, candidate, receiptsCh)
 	} else {
-		l.log.Error("No DA type specified")
-		return
+		queue.StoreOnDA(daStore{l.DA}, txdata, candidate, receiptsCh)
+	}
+}
+
+// maxTxSize returns the maximum size of the next batch tx: the max DA tx size if it is stored on the
+// DA backend, or the max L1 tx size if it is posted as calldata, including while falling back to calldata.
+func (l *BatchSubmitter) maxTxSize() uint64 {
+	if da.IsInline(l.DA) || l.daFallback.Active() {
+		return l.MaxL1TxSize
+	}
+	return l.MaxDATxSize
+}
+
+// daMaxTxSize is the maximum size of a batch tx stored on the DA backend: the configured size, or the
+// max blob size of the backend if zero. Backends without a known max blob size are bounded by the max L1 tx size.
+func daMaxTxSize(configured uint64, maxL1TxSize uint64, backend da.DataAvailability) uint64 {
+	if configured != 0 {
+		return configured
+	}
+	if size, ok := da.MaxBlobSizes[backend.Prefix()]; ok {
+		return size
+	}
+	return maxL1TxSize
+}
+
+// daFinalityDepth is the number of DA-blocks that data stored on the DA backend must be buried under
+// before a reference to it is posted to L1: the configured depth, or the default of the backend if zero.
+func daFinalityDepth(configured uint64, backend da.DataAvailability) uint64 {
+	if configured != 0 {
+		return configured
+	}
+	if backend.Prefix() == da.PolygonPrefix {
+		return polygonConfirmationDepth
+	}
+	return 0
+}
+
+// daStore adapts a data availability backend to the txmgr queue,
+// which sends the references returned by Store to L1 as they are.
+type daStore struct {
+	da.DataAvailability
+}
+
+// Store stores the data and returns the inbox payload that refers to, and commits to, the stored data.
+func (s daStore) Store(ctx context.Context, data []byte) ([]byte, error) {
+	ref, err := s.DataAvailability.Store(ctx, data)
+	if err != nil {
+		return nil, err
 	}
+	return da.EncodePayload(s.Prefix(), data, ref), nil
+}
+
+// Confirmations returns the confirmations of the data referred to by the inbox payload.
+func (s daStore) Confirmations(ctx context.Context, payload []byte) (uint64, error) {
+	p, err := da.DecodePayload(payload)
+	if err != nil {
+		return 0, err
+	}
+	return s.DataAvailability.Confirmations(ctx, p.Ref)
 }
 
 func (l *BatchSubmitter) handleReceipt(r txmgr.TxReceipt[txData]) {
 	// Record TX Status
 	if r.Err != nil {
 		l.log.Warn("unable to publish tx", "err", r.Err, "data_size", r.ID.Len())
+		if errors.Is(r.Err, txmgr.ErrStoreOnDA) {
+			l.daFallback.DAFailed()
+		}
 		l.recordFailedTx(r.ID.ID(), r.Err)
+	} else if r.DAInclusion != nil && r.DAInclusion.Anchor != nil {
+		l.recordAnchoredTx(r.ID.ID(), r.DAInclusion.Anchor)
+	} else if r.DAInclusion != nil {
+		l.daFallback.DASucceeded()
+		l.log.Info("tx data successfully stored on DA", "ref", hexutil.Bytes(r.DAInclusion.Ref), "data_size", r.ID.Len())
+		l.recordStoredOnDATx(r.ID.ID(), r.DAInclusion)
 	} else {
 		l.log.Info("tx successfully published", "tx_hash", r.Receipt.TxHash, "data_size", r.ID.Len())
 		l.recordConfirmedTx(r.ID.ID(), r.Receipt)
@@ -456,6 +631,17 @@ func (l *BatchSubmitter) recordFailedTx(id txID, err error) {
 	l.state.TxFailed(id)
 }
 
+func (l *BatchSubmitter) recordStoredOnDATx(id txID, inclusion *txmgr.DAInclusion) {
+	l.log.Info("Transaction data stored on DA", "ref", hexutil.Bytes(inclusion.Ref))
+	l.state.TxStoredOnDA(id)
+}
+
+func (l *BatchSubmitter) recordAnchoredTx(id txID, receipt *types.Receipt) {
+	l.log.Info("Transaction data anchored", "tx_hash", receipt.TxHash, "block_hash", receipt.BlockHash, "block_number", receipt.BlockNumber)
+	l1block := eth.BlockID{Number: receipt.BlockNumber.Uint64(), Hash: receipt.BlockHash}
+	l.state.TxAnchored(id, l1block)
+}
+
 func (l *BatchSubmitter) recordConfirmedTx(id txID, receipt *types.Receipt) {
 	l.log.Info("Transaction confirmed", "tx_hash", receipt.TxHash, "status", receipt.Status, "block_hash", receipt.BlockHash, "block_number", receipt.BlockNumber)
 	l1block := eth.BlockID{Number: receipt.BlockNumber.Uint64(), Hash: receipt.BlockHash}
@@ -3,6 +3,7 @@ package compressor
 import (
 	"strings"
 
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
 	opservice "github.com/ethereum-optimism/optimism/op-service"
 	"github.com/urfave/cli"
 )
@@ -12,6 +13,7 @@ const (
 	TargetNumFramesFlagName     = "target-num-frames"
 	ApproxComprRatioFlagName    = "approx-compr-ratio"
 	KindFlagName                = "compressor"
+	CompressionAlgoFlagName     = "compression-algo"
 )
 
 func CLIFlags(envPrefix string) []cli.Flag {
@@ -40,7 +42,22 @@ func CLIFlags(envPrefix string) []cli.Flag {
 			EnvVar: opservice.PrefixEnvVar(envPrefix, "COMPRESSOR"),
 			Value:  RatioKind,
 		},
+		cli.StringFlag{
+			Name: CompressionAlgoFlagName,
+			Usage: "The compression algorithm of the channel data. Valid options: " + algoKeys() +
+				". Algorithms other than zlib are only used once the channel compression upgrade is active.",
+			EnvVar: opservice.PrefixEnvVar(envPrefix, "COMPRESSION_ALGO"),
+			Value:  derive.Zlib.String(),
+		},
+	}
+}
+
+func algoKeys() string {
+	keys := make([]string, 0, len(derive.CompressionAlgos))
+	for _, algo := range derive.CompressionAlgos {
+		keys = append(keys, algo.String())
 	}
+	return strings.Join(keys, ", ")
 }
 
 type CLIConfig struct {
@@ -57,6 +74,16 @@ type CLIConfig struct {
 	ApproxComprRatio float64
 	// Type of compressor to use. Must be one of KindKeys.
 	Kind string
+	// CompressionAlgo is the compression algorithm of the channel data. Must be one of derive.CompressionAlgos,
+	// or empty for zlib.
+	CompressionAlgo string
+}
+
+func (c *CLIConfig) Check() error {
+	if c.CompressionAlgo == "" {
+		return nil
+	}
+	return derive.CompressionAlgo(c.CompressionAlgo).Check()
 }
 
 func (c *CLIConfig) Config() Config {
@@ -65,12 +92,14 @@ func (c *CLIConfig) Config() Config {
 		TargetNumFrames:  c.TargetNumFrames,
 		ApproxComprRatio: c.ApproxComprRatio,
 		Kind:             c.Kind,
+		Algo:             derive.CompressionAlgo(c.CompressionAlgo),
 	}
 }
 
 func ReadCLIConfig(ctx *cli.Context) CLIConfig {
 	return CLIConfig{
 		Kind:                ctx.GlobalString(KindFlagName),
+		CompressionAlgo:     ctx.GlobalString(CompressionAlgoFlagName),
 		TargetL1TxSizeBytes: ctx.GlobalUint64(TargetL1TxSizeBytesFlagName),
 		TargetNumFrames:     ctx.GlobalInt(TargetNumFramesFlagName),
 		ApproxComprRatio:    ctx.GlobalFloat64(ApproxComprRatioFlagName),
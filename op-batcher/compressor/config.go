@@ -18,6 +18,17 @@ type Config struct {
 	ApproxComprRatio float64
 	// Kind of compressor to use. Must
 	Kind string
+	// Algo is the algorithm the channel data is compressed with. Defaults to zlib if empty.
+	// Algorithms other than zlib must only be used once the channel compression upgrade is active.
+	Algo derive.CompressionAlgo
+}
+
+// CompressionAlgo returns the configured compression algorithm, or zlib if none is set.
+func (c Config) CompressionAlgo() derive.CompressionAlgo {
+	if c.Algo == "" {
+		return derive.Zlib
+	}
+	return c.Algo
 }
 
 func (c Config) NewCompressor() (derive.Compressor, error) {
@@ -2,6 +2,7 @@ package flags
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/urfave/cli"
@@ -9,6 +10,7 @@ import (
 	"github.com/ethereum-optimism/optimism/op-batcher/compressor"
 	"github.com/ethereum-optimism/optimism/op-batcher/rpc"
 	opservice "github.com/ethereum-optimism/optimism/op-service"
+	"github.com/ethereum-optimism/optimism/op-service/da"
 	oplog "github.com/ethereum-optimism/optimism/op-service/log"
 	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
 	oppprof "github.com/ethereum-optimism/optimism/op-service/pprof"
@@ -62,12 +64,68 @@ var (
 		Value:  0,
 		EnvVar: opservice.PrefixEnvVar(EnvVarPrefix, "MAX_CHANNEL_DURATION"),
 	}
+	MaxPendingChannelsFlag = cli.Uint64Flag{
+		Name:   "max-pending-channels",
+		Usage:  "The maximum number of channels to keep in flight at once, each timing out independently. Channels stored on DA are in flight until their L1 anchors are included.",
+		Value:  1,
+		EnvVar: opservice.PrefixEnvVar(EnvVarPrefix, "MAX_PENDING_CHANNELS"),
+	}
 	MaxL1TxSizeBytesFlag = cli.Uint64Flag{
 		Name:   "max-l1-tx-size-bytes",
 		Usage:  "The maximum size of a batch tx submitted to L1.",
 		Value:  120_000,
 		EnvVar: opservice.PrefixEnvVar(EnvVarPrefix, "MAX_L1_TX_SIZE_BYTES"),
 	}
+	MaxDATxSizeBytesFlag = cli.Uint64Flag{
+		Name:   "max-da-tx-size-bytes",
+		Usage:  "The maximum size of a batch tx stored on the DA backend, which can hold multiple frames. 0 for the default of the DA backend.",
+		Value:  0,
+		EnvVar: opservice.PrefixEnvVar(EnvVarPrefix, "MAX_DA_TX_SIZE_BYTES"),
+	}
+	DAJournalFlag = cli.StringFlag{
+		Name: "da-journal",
+		Usage: "Path of the journal that references to data stored on the DA backend are kept in until they are sent to L1. " +
+			"The journal is kept in memory only if empty, and references are lost on restart.",
+		EnvVar: opservice.PrefixEnvVar(EnvVarPrefix, "DA_JOURNAL"),
+	}
+	DAAnchorMinBatchFlag = cli.IntFlag{
+		Name:   "da-anchor-min-batch",
+		Usage:  "Number of L1 anchors of data stored on the DA backend to wait for, so that they are sent to L1 together.",
+		Value:  3,
+		EnvVar: opservice.PrefixEnvVar(EnvVarPrefix, "DA_ANCHOR_MIN_BATCH"),
+	}
+	DAAnchorMaxWaitFlag = cli.DurationFlag{
+		Name:   "da-anchor-max-wait",
+		Usage:  "Maximum time that L1 anchors are held back while waiting for da-anchor-min-batch anchors.",
+		Value:  10 * time.Minute,
+		EnvVar: opservice.PrefixEnvVar(EnvVarPrefix, "DA_ANCHOR_MAX_WAIT"),
+	}
+	DAAnchorMaxBatchFlag = cli.IntFlag{
+		Name: "da-anchor-max-batch",
+		Usage: "Maximum number of L1 anchors of data stored on the DA backend that are aggregated into a single L1 transaction. " +
+			"Anchors are sent in separate transactions if less than 2.",
+		Value:  100,
+		EnvVar: opservice.PrefixEnvVar(EnvVarPrefix, "DA_ANCHOR_MAX_BATCH"),
+	}
+	DAFinalityDepthFlag = cli.Uint64Flag{
+		Name: "da-finality-depth",
+		Usage: "Number of DA-blocks that data stored on the DA backend must be buried under before it is anchored on L1. " +
+			"0 to use the default of the DA backend.",
+		EnvVar: opservice.PrefixEnvVar(EnvVarPrefix, "DA_FINALITY_DEPTH"),
+	}
+	DAFallbackThresholdFlag = cli.IntFlag{
+		Name: "da-fallback-threshold",
+		Usage: "Number of consecutive failures to store batch data on the DA backend after which it is posted as L1 calldata instead, " +
+			"until the DA backend recovers. Frames must fit in max-l1-tx-size-bytes. 0 to disable.",
+		Value:  0,
+		EnvVar: opservice.PrefixEnvVar(EnvVarPrefix, "DA_FALLBACK_THRESHOLD"),
+	}
+	DAFallbackProbeIntervalFlag = cli.DurationFlag{
+		Name:   "da-fallback-probe-interval",
+		Usage:  "Interval at which the DA backend is tried again while falling back to L1 calldata.",
+		Value:  time.Minute,
+		EnvVar: opservice.PrefixEnvVar(EnvVarPrefix, "DA_FALLBACK_PROBE_INTERVAL"),
+	}
 	StoppedFlag = cli.BoolFlag{
 		Name:   "stopped",
 		Usage:  "Initialize the batcher in a stopped state. The batcher can be started using the admin_startBatcher RPC",
@@ -76,15 +134,9 @@ var (
 	// Legacy Flags
 	SequencerHDPathFlag = txmgr.SequencerHDPathFlag
 
-	L1EthDARpcFlag = cli.StringFlag{
-		Name:   "l1-da-rpc",
-		Usage:  "HTTP DA provider URL for L1",
-		EnvVar: opservice.PrefixEnvVar(EnvVarPrefix, "L1_DA_RPC"),
-	}
-
 	L1EthDATypeFlag = cli.StringFlag{
 		Name:   "l1-da-type",
-		Usage:  "HTTP DA type for L1",
+		Usage:  "Data availability backend that batch data is stored on, must match the da_type of the rollup config. Valid options: " + strings.Join(da.TypeKeys, ", "),
 		EnvVar: opservice.PrefixEnvVar(EnvVarPrefix, "L1_DA_TYPE"),
 	}
 )
@@ -93,8 +145,6 @@ var requiredFlags = []cli.Flag{
 	L1EthRpcFlag,
 	L2EthRpcFlag,
 	RollupRpcFlag,
-	L1EthDARpcFlag,
-	L1EthDATypeFlag,
 }
 
 var optionalFlags = []cli.Flag{
@@ -102,9 +152,19 @@ var optionalFlags = []cli.Flag{
 	PollIntervalFlag,
 	MaxPendingTransactionsFlag,
 	MaxChannelDurationFlag,
+	MaxPendingChannelsFlag,
 	MaxL1TxSizeBytesFlag,
+	MaxDATxSizeBytesFlag,
+	DAJournalFlag,
+	DAAnchorMinBatchFlag,
+	DAAnchorMaxWaitFlag,
+	DAAnchorMaxBatchFlag,
+	DAFinalityDepthFlag,
+	DAFallbackThresholdFlag,
+	DAFallbackProbeIntervalFlag,
 	StoppedFlag,
 	SequencerHDPathFlag,
+	L1EthDATypeFlag,
 }
 
 func init() {
@@ -115,6 +175,7 @@ func init() {
 	optionalFlags = append(optionalFlags, rpc.CLIFlags(EnvVarPrefix)...)
 	optionalFlags = append(optionalFlags, txmgr.CLIFlags(EnvVarPrefix)...)
 	optionalFlags = append(optionalFlags, compressor.CLIFlags(EnvVarPrefix)...)
+	optionalFlags = append(optionalFlags, da.CLIFlags(EnvVarPrefix)...)
 
 	Flags = append(requiredFlags, optionalFlags...)
 }
@@ -41,6 +41,8 @@ type Metricer interface {
 	RecordBatchTxSuccess()
 	RecordBatchTxFailed()
 
+	RecordDAFallback(active bool)
+
 	Document() []opmetrics.DocumentedMetric
 }
 
@@ -73,6 +75,9 @@ type Metrics struct {
 	channelOutputBytesTotal prometheus.Counter
 
 	batcherTxEvs opmetrics.EventVec
+
+	daFallbackActive prometheus.Gauge
+	daFallbackEvs    opmetrics.EventVec
 }
 
 var _ Metricer = (*Metrics)(nil)
@@ -173,6 +178,13 @@ func NewMetrics(procName string) *Metrics {
 		}),
 
 		batcherTxEvs: opmetrics.NewEventVec(factory, ns, "", "batcher_tx", "BatcherTx", []string{"stage"}),
+
+		daFallbackActive: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace: ns,
+			Name:      "da_fallback_active",
+			Help:      "1 if batch data is posted as calldata because the DA backend is unavailable, 0 otherwise.",
+		}),
+		daFallbackEvs: opmetrics.NewEventVec(factory, ns, "", "da_fallback", "DA fallback", []string{"stage"}),
 	}
 }
 
@@ -212,6 +224,9 @@ const (
 	TxStageSubmitted = "submitted"
 	TxStageSuccess   = "success"
 	TxStageFailed    = "failed"
+
+	DAFallbackStageActivated   = "activated"
+	DAFallbackStageDeactivated = "deactivated"
 )
 
 func (m *Metrics) RecordLatestL1Block(l1ref eth.L1BlockRef) {
@@ -295,6 +310,17 @@ func (m *Metrics) RecordBatchTxFailed() {
 	m.batcherTxEvs.Record(TxStageFailed)
 }
 
+// RecordDAFallback records a switch from the DA backend to calldata, if active, or back.
+func (m *Metrics) RecordDAFallback(active bool) {
+	if active {
+		m.daFallbackActive.Set(1)
+		m.daFallbackEvs.Record(DAFallbackStageActivated)
+	} else {
+		m.daFallbackActive.Set(0)
+		m.daFallbackEvs.Record(DAFallbackStageDeactivated)
+	}
+}
+
 // estimateBatchSize estimates the size of the batch
 func estimateBatchSize(block *types.Block) uint64 {
 	size := uint64(70) // estimated overhead of batch metadata
@@ -2,11 +2,63 @@ package rpc
 
 import (
 	"context"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
 )
 
+// ChannelState is the state of a channel in flight.
+type ChannelState struct {
+	ID derive.ChannelID `json:"id"`
+	// StartBlock and EndBlock are the first and last L2 block in the channel.
+	// They are empty if no block was added to the channel yet.
+	StartBlock eth.BlockID `json:"startBlock"`
+	EndBlock   eth.BlockID `json:"endBlock"`
+	// Full is set once no more blocks can be added to the channel, for the FullReason.
+	Full       bool   `json:"full"`
+	FullReason string `json:"fullReason,omitempty"`
+	// FramesReady is the number of frames that are yet to be sent.
+	FramesReady int `json:"framesReady"`
+	// FramesPending is the number of frames in transactions that are sent but not yet confirmed.
+	FramesPending int `json:"framesPending"`
+	// FramesConfirmed is the number of frames in transactions that are confirmed on L1.
+	FramesConfirmed int `json:"framesConfirmed"`
+	// FramesStoredOnDA is the number of frames in transactions that are stored on the DA backend.
+	FramesStoredOnDA int `json:"framesStoredOnDA"`
+}
+
+// DAAnchor is a reference to data stored on the DA backend that awaits being anchored on L1.
+type DAAnchor struct {
+	Seq uint64 `json:"seq"`
+	// Ref is the reference to the stored data, e.g. the blob key, without the DA prefix byte.
+	Ref hexutil.Bytes `json:"ref"`
+	// Confirmed is set once the stored data is deep enough on the DA backend to be anchored.
+	Confirmed bool `json:"confirmed"`
+}
+
+// BatcherState is the in-flight state of the batcher.
+type BatcherState struct {
+	Running bool `json:"running"`
+	// CurrentChannel is the channel new blocks are added to, if any.
+	CurrentChannel *derive.ChannelID `json:"currentChannel"`
+	// Channels are the channels in flight, oldest first.
+	Channels []ChannelState `json:"channels"`
+	// BlocksPending is the number of L2 blocks that are not yet added to a channel.
+	BlocksPending int `json:"blocksPending"`
+	// LastStoredBlock is the last L2 block loaded into the batcher.
+	LastStoredBlock eth.BlockID `json:"lastStoredBlock"`
+	// DAAnchors are the references to data stored on the DA backend that await being anchored on L1.
+	DAAnchors []DAAnchor `json:"daAnchors"`
+}
+
 type batcherClient interface {
 	Start() error
 	Stop(ctx context.Context) error
+	State() (*BatcherState, error)
+	ForceCloseChannel() (derive.ChannelID, error)
+	FlushAnchors(ctx context.Context) (int, error)
 }
 
 type adminAPI struct {
@@ -26,3 +78,20 @@ func (a *adminAPI) StartBatcher(_ context.Context) error {
 func (a *adminAPI) StopBatcher(ctx context.Context) error {
 	return a.b.Stop(ctx)
 }
+
+// BatcherState returns the in-flight state of the batcher.
+func (a *adminAPI) BatcherState(_ context.Context) (*BatcherState, error) {
+	return a.b.State()
+}
+
+// ForceCloseChannel closes the channel new blocks are added to, so that its remaining frames are sent,
+// and returns its ID. Later blocks are added to a new channel.
+func (a *adminAPI) ForceCloseChannel(_ context.Context) (derive.ChannelID, error) {
+	return a.b.ForceCloseChannel()
+}
+
+// FlushAnchors sends the L1 anchors of the data on the DA backend that is confirmed deep enough right away,
+// without waiting for the anchor batch to fill. It returns the number of anchors that were sent.
+func (a *adminAPI) FlushAnchors(ctx context.Context) (int, error) {
+	return a.b.FlushAnchors(ctx)
+}
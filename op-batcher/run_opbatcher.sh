@@ -24,6 +24,12 @@ echo "CELESTIA" $CELESTIA
 echo "EIGEN" $EIGEN
 echo "NEARDA" $NEARDA
 
+# blob server of the CELESTIA, EIGEN or NEARDA DA types
+if [ "$DA_SERVER" == "" ]; then
+    DA_SERVER=${CELESTIA:-${EIGEN:-$NEARDA}}
+fi
+echo "DA_SERVER" $DA_SERVER
+
 
 ./bin/op-batcher \
       --l2-eth-rpc=$GETH_HOST \
@@ -41,6 +47,8 @@ echo "NEARDA" $NEARDA
       --l1-eth-rpc=$TCHOST \
       --log.level=debug \
       --l1-da-rpc=$POLYGON \
+      --da.server=$DA_SERVER \
       --l1-da-type=$DA_TYPE \
       --num-confirmations-da=$DACONFIRM \
+      --da-journal=$PWD/resources/da-journal \
       --private-key=$BatcherPriv 2>&1 | cronolog $PWD/resources/logs/%Y-%m-%d.log
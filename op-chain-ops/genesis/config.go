@@ -71,6 +71,15 @@ type DeployConfig struct {
 
 	// Seconds after genesis block that Regolith hard fork activates. 0 to activate at genesis. Nil to disable regolith
 	L2GenesisRegolithTimeOffset *hexutil.Uint64 `json:"l2GenesisRegolithTimeOffset,omitempty"`
+	// Seconds after genesis block that the channel compression upgrade activates, by L1 block time.
+	// 0 to activate at genesis. Nil to only allow zlib channel compression.
+	ChannelCompressionTimeOffset *hexutil.Uint64 `json:"channelCompressionTimeOffset,omitempty"`
+	// Seconds after genesis block that the DA commitment upgrade activates, by L1 block time.
+	// 0 to activate at genesis. Nil to keep deriving DA payloads without commitment.
+	DACommitmentTimeOffset *hexutil.Uint64 `json:"daCommitmentTimeOffset,omitempty"`
+
+	// Data availability backend that batch data is posted to. Empty to post batch data as L1 calldata.
+	DAType string `json:"daType,omitempty"`
 
 	// Owner of the ProxyAdmin predeploy
 	ProxyAdminOwner common.Address `json:"proxyAdminOwner"`
@@ -308,6 +317,28 @@ func (d *DeployConfig) RegolithTime(genesisTime uint64) *uint64 {
 	return &v
 }
 
+func (d *DeployConfig) ChannelCompressionTime(genesisTime uint64) *uint64 {
+	if d.ChannelCompressionTimeOffset == nil {
+		return nil
+	}
+	v := uint64(0)
+	if offset := *d.ChannelCompressionTimeOffset; offset > 0 {
+		v = genesisTime + uint64(offset)
+	}
+	return &v
+}
+
+func (d *DeployConfig) DACommitmentTime(genesisTime uint64) *uint64 {
+	if d.DACommitmentTimeOffset == nil {
+		return nil
+	}
+	v := uint64(0)
+	if offset := *d.DACommitmentTimeOffset; offset > 0 {
+		v = genesisTime + uint64(offset)
+	}
+	return &v
+}
+
 // RollupConfig converts a DeployConfig to a rollup.Config
 func (d *DeployConfig) RollupConfig(l1StartBlock *types.Block, l2GenesisBlockHash common.Hash, l2GenesisBlockNumber uint64) (*rollup.Config, error) {
 	if d.OptimismPortalProxy == (common.Address{}) {
@@ -345,6 +376,9 @@ func (d *DeployConfig) RollupConfig(l1StartBlock *types.Block, l2GenesisBlockHas
 		DepositContractAddress: d.OptimismPortalProxy,
 		L1SystemConfigAddress:  d.SystemConfigProxy,
 		RegolithTime:           d.RegolithTime(l1StartBlock.Time()),
+		ChannelCompressionTime: d.ChannelCompressionTime(l1StartBlock.Time()),
+		DACommitmentTime:       d.DACommitmentTime(l1StartBlock.Time()),
+		DAType:                 d.DAType,
 	}, nil
 }
 
@@ -0,0 +1,53 @@
+package alert
+
+import (
+	"context"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+)
+
+// Type identifies what an alert reports.
+type Type string
+
+const (
+	// InvalidOutput is raised when a proposed output does not match the output computed by the rollup node.
+	InvalidOutput Type = "invalid_output"
+	// OutputDeleted is raised when a previously reported invalid output is deleted from the L2OutputOracle.
+	OutputDeleted Type = "output_deleted"
+)
+
+// Alert is a structured notification about an output proposal.
+type Alert struct {
+	Type Type `json:"type"`
+	// L2OutputOracle is the address of the contract the output was proposed to.
+	L2OutputOracle common.Address `json:"l2OutputOracle"`
+	L2OutputIndex  uint64         `json:"l2OutputIndex"`
+	L2BlockNumber  uint64         `json:"l2BlockNumber"`
+	// ProposedOutputRoot is the output root proposed to the L2OutputOracle.
+	ProposedOutputRoot eth.Bytes32 `json:"proposedOutputRoot"`
+	// ExpectedOutputRoot is the output root computed by the rollup node.
+	ExpectedOutputRoot eth.Bytes32 `json:"expectedOutputRoot"`
+	Time               time.Time   `json:"time"`
+}
+
+// Notifier is a sink for alerts.
+type Notifier interface {
+	Notify(ctx context.Context, a Alert) error
+}
+
+// Notifiers fans out alerts to multiple sinks. Every sink is notified, even if another sink fails.
+type Notifiers []Notifier
+
+func (ns Notifiers) Notify(ctx context.Context, a Alert) error {
+	var result error
+	for _, n := range ns {
+		if err := n.Notify(ctx, a); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+	return result
+}
@@ -0,0 +1,77 @@
+package alert
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type recordingNotifier struct {
+	alerts []Alert
+	err    error
+}
+
+func (n *recordingNotifier) Notify(_ context.Context, a Alert) error {
+	n.alerts = append(n.alerts, a)
+	return n.err
+}
+
+func TestNotifiersNotifyAllSinks(t *testing.T) {
+	failing := &recordingNotifier{err: errors.New("boom")}
+	ok := &recordingNotifier{}
+	ns := Notifiers{failing, ok}
+
+	err := ns.Notify(context.Background(), testAlert())
+	require.ErrorContains(t, err, "boom")
+	require.Len(t, failing.alerts, 1)
+	require.Len(t, ok.alerts, 1, "sinks must be notified after a failing sink")
+}
+
+type outstandingMetrics struct {
+	n int
+}
+
+func (m *outstandingMetrics) RecordInvalidOutputsOutstanding(n int) {
+	m.n = n
+}
+
+func TestMetricsNotifier(t *testing.T) {
+	m := &outstandingMetrics{}
+	n := NewMetricsNotifier(m)
+
+	a := testAlert()
+	require.NoError(t, n.Notify(context.Background(), a))
+	require.Equal(t, 1, m.n)
+	// repeated alerts of the same output are only counted once
+	require.NoError(t, n.Notify(context.Background(), a))
+	require.Equal(t, 1, m.n)
+
+	b := testAlert()
+	b.L2OutputIndex++
+	require.NoError(t, n.Notify(context.Background(), b))
+	require.Equal(t, 2, m.n)
+
+	a.Type = OutputDeleted
+	require.NoError(t, n.Notify(context.Background(), a))
+	require.Equal(t, 1, m.n)
+}
+
+func TestConfigCheck(t *testing.T) {
+	require.NoError(t, Config{}.Check())
+	require.NoError(t, Config{Sinks: ParseSinks("log, metrics")}.Check())
+	require.ErrorIs(t, Config{Sinks: []string{WebhookSink}}.Check(), ErrMissingWebhookURL)
+	require.NoError(t, Config{Sinks: []string{WebhookSink}, WebhookURL: "https://example.com/hook"}.Check())
+	require.ErrorContains(t, Config{Sinks: []string{WebhookSink}, WebhookURL: "ftp://example.com"}.Check(), "must be http or https")
+	require.ErrorContains(t, Config{Sinks: []string{"pager"}}.Check(), "unknown alert sink")
+}
+
+func TestNewNotifier(t *testing.T) {
+	n := NewNotifier(Config{Sinks: Sinks, WebhookURL: "http://localhost"}, nil, &outstandingMetrics{})
+	ns := n.(Notifiers)
+	require.Len(t, ns, 3)
+	require.IsType(t, &LogNotifier{}, ns[0])
+	require.IsType(t, &MetricsNotifier{}, ns[1])
+	require.IsType(t, &WebhookNotifier{}, ns[2])
+}
@@ -0,0 +1,84 @@
+package alert
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	LogSink     = "log"
+	MetricsSink = "metrics"
+	WebhookSink = "webhook"
+)
+
+// Sinks lists the supported alert sinks.
+var Sinks = []string{LogSink, MetricsSink, WebhookSink}
+
+var ErrMissingWebhookURL = errors.New("missing alert webhook url")
+
+// Config selects the sinks that alerts are delivered to.
+type Config struct {
+	// Sinks is the list of enabled sinks, see Sinks.
+	Sinks []string
+	// WebhookURL is the URL alerts are posted to by the webhook sink.
+	WebhookURL string
+	// WebhookTimeout bounds the delivery of an alert to the webhook.
+	WebhookTimeout time.Duration
+}
+
+// ParseSinks parses a comma separated list of sink names.
+func ParseSinks(s string) []string {
+	var sinks []string
+	for _, sink := range strings.Split(s, ",") {
+		if sink = strings.TrimSpace(sink); sink != "" {
+			sinks = append(sinks, sink)
+		}
+	}
+	return sinks
+}
+
+func (c Config) Check() error {
+	for _, sink := range c.Sinks {
+		switch sink {
+		case LogSink, MetricsSink:
+		case WebhookSink:
+			if c.WebhookURL == "" {
+				return ErrMissingWebhookURL
+			}
+			u, err := url.Parse(c.WebhookURL)
+			if err != nil {
+				return fmt.Errorf("invalid alert webhook url: %w", err)
+			}
+			if u.Scheme != "http" && u.Scheme != "https" {
+				return fmt.Errorf("alert webhook url must be http or https: %q", c.WebhookURL)
+			}
+		default:
+			return fmt.Errorf("unknown alert sink %q, valid options: %s", sink, strings.Join(Sinks, ", "))
+		}
+	}
+	if c.WebhookTimeout < 0 {
+		return errors.New("alert webhook timeout cannot be negative")
+	}
+	return nil
+}
+
+// NewNotifier creates a notifier that delivers alerts to all configured sinks.
+func NewNotifier(cfg Config, l log.Logger, m OutstandingMetrics) Notifier {
+	var ns Notifiers
+	for _, sink := range cfg.Sinks {
+		switch sink {
+		case LogSink:
+			ns = append(ns, NewLogNotifier(l))
+		case MetricsSink:
+			ns = append(ns, NewMetricsNotifier(m))
+		case WebhookSink:
+			ns = append(ns, NewWebhookNotifier(cfg.WebhookURL, cfg.WebhookTimeout))
+		}
+	}
+	return ns
+}
@@ -0,0 +1,27 @@
+package alert
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// LogNotifier writes alerts to a logger.
+type LogNotifier struct {
+	log log.Logger
+}
+
+func NewLogNotifier(log log.Logger) *LogNotifier {
+	return &LogNotifier{log: log}
+}
+
+func (n *LogNotifier) Notify(_ context.Context, a Alert) error {
+	ctx := []any{"type", a.Type, "l2oo", a.L2OutputOracle, "index", a.L2OutputIndex, "l2_block", a.L2BlockNumber,
+		"proposed", a.ProposedOutputRoot, "expected", a.ExpectedOutputRoot}
+	if a.Type == InvalidOutput {
+		n.log.Error("ALERT: invalid output proposed", ctx...)
+	} else {
+		n.log.Info("ALERT: invalid output deleted", ctx...)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package alert
+
+import (
+	"context"
+	"sync"
+)
+
+// OutstandingMetrics tracks the number of invalid outputs that are still part of the L2OutputOracle.
+type OutstandingMetrics interface {
+	RecordInvalidOutputsOutstanding(n int)
+}
+
+// MetricsNotifier exposes the number of outstanding invalid outputs as a metric:
+// it is raised by InvalidOutput alerts, and lowered again by OutputDeleted alerts.
+type MetricsNotifier struct {
+	m OutstandingMetrics
+
+	mu          sync.Mutex
+	outstanding map[uint64]struct{}
+}
+
+func NewMetricsNotifier(m OutstandingMetrics) *MetricsNotifier {
+	return &MetricsNotifier{m: m, outstanding: make(map[uint64]struct{})}
+}
+
+func (n *MetricsNotifier) Notify(_ context.Context, a Alert) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	switch a.Type {
+	case InvalidOutput:
+		n.outstanding[a.L2OutputIndex] = struct{}{}
+	case OutputDeleted:
+		delete(n.outstanding, a.L2OutputIndex)
+	}
+	n.m.RecordInvalidOutputsOutstanding(len(n.outstanding))
+	return nil
+}
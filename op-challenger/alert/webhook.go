@@ -0,0 +1,54 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// DefaultWebhookTimeout bounds the delivery of an alert to a webhook.
+const DefaultWebhookTimeout = 10 * time.Second
+
+// WebhookNotifier delivers alerts as JSON POST requests to a URL.
+type WebhookNotifier struct {
+	url     string
+	client  *http.Client
+	timeout time.Duration
+}
+
+// NewWebhookNotifier creates a notifier posting to the given URL.
+// The DefaultWebhookTimeout is used if the timeout is zero.
+func NewWebhookNotifier(url string, timeout time.Duration) *WebhookNotifier {
+	if timeout == 0 {
+		timeout = DefaultWebhookTimeout
+	}
+	return &WebhookNotifier{url: url, client: &http.Client{}, timeout: timeout}
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, a Alert) error {
+	body, err := json.Marshal(a)
+	if err != nil {
+		return fmt.Errorf("failed to encode alert: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, n.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to deliver alert to webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+)
+
+func testAlert() Alert {
+	return Alert{
+		Type:               InvalidOutput,
+		L2OutputOracle:     common.Address{0x01},
+		L2OutputIndex:      3,
+		L2BlockNumber:      120,
+		ProposedOutputRoot: eth.Bytes32{0xba, 0xd0},
+		ExpectedOutputRoot: eth.Bytes32{0x90, 0x0d},
+		Time:               time.Unix(1000, 0).UTC(),
+	}
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var received []Alert
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		var a Alert
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&a))
+		received = append(received, a)
+	}))
+	defer srv.Close()
+
+	n := NewWebhookNotifier(srv.URL, 0)
+	a := testAlert()
+	require.NoError(t, n.Notify(context.Background(), a))
+	require.Equal(t, []Alert{a}, received)
+}
+
+func TestWebhookNotifierJSON(t *testing.T) {
+	var body map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+	}))
+	defer srv.Close()
+
+	require.NoError(t, NewWebhookNotifier(srv.URL, 0).Notify(context.Background(), testAlert()))
+	require.Equal(t, "invalid_output", body["type"])
+	require.Equal(t, float64(3), body["l2OutputIndex"])
+	require.Equal(t, float64(120), body["l2BlockNumber"])
+	require.Equal(t, eth.Bytes32{0xba, 0xd0}.String(), body["proposedOutputRoot"])
+	require.Equal(t, eth.Bytes32{0x90, 0x0d}.String(), body["expectedOutputRoot"])
+}
+
+func TestWebhookNotifierErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	err := NewWebhookNotifier(srv.URL, 0).Notify(context.Background(), testAlert())
+	require.ErrorContains(t, err, "status 502")
+}
+
+func TestWebhookNotifierTimeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	err := NewWebhookNotifier(srv.URL, 50*time.Millisecond).Notify(context.Background(), testAlert())
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
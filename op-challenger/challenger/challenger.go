@@ -2,7 +2,9 @@ package challenger
 
 import (
 	"context"
+	"fmt"
 	_ "net/http/pprof"
+	"strings"
 	"sync"
 	"time"
 
@@ -12,11 +14,11 @@ import (
 	ethclient "github.com/ethereum/go-ethereum/ethclient"
 	log "github.com/ethereum/go-ethereum/log"
 
+	alert "github.com/ethereum-optimism/optimism/op-challenger/alert"
 	config "github.com/ethereum-optimism/optimism/op-challenger/config"
 	metrics "github.com/ethereum-optimism/optimism/op-challenger/metrics"
 
 	bindings "github.com/ethereum-optimism/optimism/op-bindings/bindings"
-	sources "github.com/ethereum-optimism/optimism/op-node/sources"
 	opclient "github.com/ethereum-optimism/optimism/op-service/client"
 	txmgr "github.com/ethereum-optimism/optimism/op-service/txmgr"
 )
@@ -35,19 +37,35 @@ type Challenger struct {
 
 	l1Client *ethclient.Client
 
-	rollupClient *sources.RollupClient
+	rollupClient RollupClient
 
 	// l2 Output Oracle contract
-	l2ooContract     *bindings.L2OutputOracleCaller
+	l2ooContract     OutputOracle
+	l2ooFilterer     *bindings.L2OutputOracleFilterer
 	l2ooContractAddr common.Address
 	l2ooABI          *abi.ABI
 
 	// dispute game factory contract
-	// dgfContract     *bindings.DisputeGameFactoryCaller
+	dgfContract     *bind.BoundContract
 	dgfContractAddr common.Address
-	// dgfABI          *abi.ABI
+	dgfABI          *abi.ABI
 
 	networkTimeout time.Duration
+	pollInterval   time.Duration
+	dryRun         bool
+
+	notifier alert.Notifier
+	// alerts holds the alerts that are yet to be delivered to the notifier
+	alerts chan alert.Alert
+	// invalid holds the alerts of invalid outputs that are still part of the L2OutputOracle, by output index
+	invalid map[uint64]alert.Alert
+
+	// nextL1Block is the next L1 block to search for output proposals
+	nextL1Block uint64
+	// pending holds the proposed outputs that are yet to be validated, in order of proposal
+	pending []*bindings.L2OutputOracleOutputProposed
+	// retries holds the invalid output proposals whose challenge failed, by output index
+	retries map[uint64]*challengeRetry
 }
 
 // NewChallenger creates a new Challenger
@@ -88,12 +106,24 @@ func NewChallenger(cfg config.Config, l log.Logger, m metrics.Metricer) (*Challe
 	}
 	l.Info("Connected to L2OutputOracle", "address", cfg.L2OOAddress, "version", version)
 
+	l2ooFilterer, err := bindings.NewL2OutputOracleFilterer(cfg.L2OOAddress, l1Client)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
 	parsed, err := bindings.L2OutputOracleMetaData.GetAbi()
 	if err != nil {
 		cancel()
 		return nil, err
 	}
 
+	dgfABI, err := abi.JSON(strings.NewReader(disputeGameFactoryABI))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
 	return &Challenger{
 		txMgr: txManager,
 		done:  make(chan struct{}),
@@ -109,18 +139,50 @@ func NewChallenger(cfg config.Config, l log.Logger, m metrics.Metricer) (*Challe
 		l1Client: l1Client,
 
 		l2ooContract:     l2ooContract,
+		l2ooFilterer:     l2ooFilterer,
 		l2ooContractAddr: cfg.L2OOAddress,
 		l2ooABI:          parsed,
 
+		dgfContract:     bind.NewBoundContract(cfg.DGFAddress, dgfABI, l1Client, nil, nil),
 		dgfContractAddr: cfg.DGFAddress,
+		dgfABI:          &dgfABI,
 
 		networkTimeout: cfg.NetworkTimeout,
+		pollInterval:   cfg.PollInterval,
+		dryRun:         cfg.DryRun,
+
+		notifier: alert.NewNotifier(cfg.AlertConfig, l, m),
+		alerts:   make(chan alert.Alert, alertQueueSize),
+		invalid:  make(map[uint64]alert.Alert),
+		retries:  make(map[uint64]*challengeRetry),
 	}, nil
 }
 
 // Start runs the challenger in a goroutine.
+// Outputs proposed from the current L1 head onwards are validated, as well as the latest output proposed before.
 func (c *Challenger) Start() error {
-	c.log.Error("challenger not implemented.")
+	cCtx, cCancel := context.WithTimeout(c.ctx, c.networkTimeout)
+	defer cCancel()
+	head, err := c.l1Client.BlockNumber(cCtx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch L1 head: %w", err)
+	}
+	c.nextL1Block = head + 1
+
+	latest, err := c.latestOutput(c.ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch latest output: %w", err)
+	}
+	if latest != nil {
+		c.pending = append(c.pending, latest)
+	}
+	if c.dryRun {
+		c.log.Warn("Running in dry-run mode, invalid outputs will not be challenged")
+	}
+
+	c.wg.Add(2)
+	go c.loop()
+	go c.notifyLoop()
 	return nil
 }
 
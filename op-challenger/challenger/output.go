@@ -0,0 +1,453 @@
+package challenger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-challenger/alert"
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
+)
+
+// maxLogRange bounds the number of L1 blocks that are searched for output proposals at once.
+const maxLogRange = 1000
+
+// attestationGameType is the GameType of the attestation dispute game, which contests an output proposal.
+const attestationGameType uint8 = 2
+
+// disputeGameFactoryABI is the part of the DisputeGameFactory ABI used to create and look up dispute games.
+const disputeGameFactoryABI = `[{"inputs":[{"internalType":"enum GameType","name":"gameType","type":"uint8"},{"internalType":"Claim","name":"rootClaim","type":"bytes32"},{"internalType":"bytes","name":"extraData","type":"bytes"}],"name":"create","outputs":[{"internalType":"contract IDisputeGame","name":"proxy","type":"address"}],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"enum GameType","name":"gameType","type":"uint8"},{"internalType":"Claim","name":"rootClaim","type":"bytes32"},{"internalType":"bytes","name":"extraData","type":"bytes"}],"name":"games","outputs":[{"internalType":"contract IDisputeGame","name":"proxy","type":"address"}],"stateMutability":"view","type":"function"}]`
+
+// ErrChallengeReverted is returned when the dispute game creation tx reverted, and no game exists for the output.
+var ErrChallengeReverted = errors.New("dispute game creation reverted")
+
+var supportedL2OutputVersion = eth.Bytes32{}
+
+// alertQueueSize bounds the number of alerts that are queued for delivery.
+const alertQueueSize = 64
+
+// maxRetryInterval bounds the backoff between attempts to challenge an invalid output.
+const maxRetryInterval = 10 * time.Minute
+
+// challengeRetry is an invalid output proposal whose challenge failed, to be attempted again.
+type challengeRetry struct {
+	proposal *bindings.L2OutputOracleOutputProposed
+	blockRef eth.L2BlockRef
+	attempts int
+	next     time.Time
+}
+
+// OutputOracle is the part of the L2OutputOracle API used to read proposed outputs.
+type OutputOracle interface {
+	NextOutputIndex(opts *bind.CallOpts) (*big.Int, error)
+	GetL2Output(opts *bind.CallOpts, l2OutputIndex *big.Int) (bindings.TypesOutputProposal, error)
+}
+
+// RollupClient is the part of the rollup node API used to recompute output proposals.
+type RollupClient interface {
+	OutputAtBlock(ctx context.Context, blockNum uint64) (*eth.OutputResponse, error)
+	SyncStatus(ctx context.Context) (*eth.SyncStatus, error)
+}
+
+// loop is responsible for picking up new output proposals and validating them
+func (c *Challenger) loop() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := c.fetchOutputProposals(c.ctx); err != nil {
+				c.log.Error("Failed to fetch output proposals", "err", err)
+			}
+			c.processPending(c.ctx)
+			c.retryChallenges(c.ctx)
+			if err := c.checkDeletedOutputs(c.ctx); err != nil {
+				c.log.Error("Failed to check for deleted outputs", "err", err)
+			}
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// latestOutput returns the latest output proposal, or nil if no output was proposed yet.
+func (c *Challenger) latestOutput(ctx context.Context) (*bindings.L2OutputOracleOutputProposed, error) {
+	cCtx, cCancel := context.WithTimeout(ctx, c.networkTimeout)
+	defer cCancel()
+	callOpts := &bind.CallOpts{Context: cCtx}
+	next, err := c.l2ooContract.NextOutputIndex(callOpts)
+	if err != nil {
+		return nil, err
+	}
+	if next.Sign() == 0 {
+		return nil, nil
+	}
+	index := new(big.Int).Sub(next, common.Big1)
+	output, err := c.l2ooContract.GetL2Output(callOpts, index)
+	if err != nil {
+		return nil, err
+	}
+	return &bindings.L2OutputOracleOutputProposed{
+		OutputRoot:    output.OutputRoot,
+		L2OutputIndex: index,
+		L2BlockNumber: output.L2BlockNumber,
+		L1Timestamp:   output.Timestamp,
+	}, nil
+}
+
+// fetchOutputProposals queues the OutputProposed events emitted since the last call for validation.
+func (c *Challenger) fetchOutputProposals(ctx context.Context) error {
+	cCtx, cCancel := context.WithTimeout(ctx, c.networkTimeout)
+	defer cCancel()
+	head, err := c.l1Client.BlockNumber(cCtx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch L1 head: %w", err)
+	}
+	for c.nextL1Block <= head {
+		end := c.nextL1Block + maxLogRange - 1
+		if end > head {
+			end = head
+		}
+		cCtx, cCancel := context.WithTimeout(ctx, c.networkTimeout)
+		iter, err := c.l2ooFilterer.FilterOutputProposed(&bind.FilterOpts{
+			Start:   c.nextL1Block,
+			End:     &end,
+			Context: cCtx,
+		}, nil, nil, nil)
+		if err != nil {
+			cCancel()
+			return fmt.Errorf("failed to filter output proposals in L1 blocks %d-%d: %w", c.nextL1Block, end, err)
+		}
+		var proposals []*bindings.L2OutputOracleOutputProposed
+		for iter.Next() {
+			proposals = append(proposals, iter.Event)
+		}
+		err = iter.Error()
+		_ = iter.Close()
+		cCancel()
+		if err != nil {
+			return fmt.Errorf("failed to read output proposals in L1 blocks %d-%d: %w", c.nextL1Block, end, err)
+		}
+		for _, p := range proposals {
+			c.log.Info("Output proposed", "index", p.L2OutputIndex, "l2_block", p.L2BlockNumber,
+				"output_root", common.Hash(p.OutputRoot), "l1_block", p.Raw.BlockNumber)
+		}
+		c.pending = append(c.pending, proposals...)
+		c.nextL1Block = end + 1
+	}
+	return nil
+}
+
+// processPending validates the pending output proposals in order, and challenges those that are invalid.
+// Proposals of L2 blocks that are not yet safe stay pending, as do proposals that failed to be validated.
+// Failed challenges do not block the queue, they are retried by retryChallenges.
+func (c *Challenger) processPending(ctx context.Context) {
+	for len(c.pending) > 0 {
+		proposal := c.pending[0]
+		done, err := c.processOutput(ctx, proposal)
+		if err != nil {
+			c.log.Error("Failed to process output proposal", "index", proposal.L2OutputIndex,
+				"l2_block", proposal.L2BlockNumber, "err", err)
+			return
+		}
+		if !done {
+			return
+		}
+		c.pending = c.pending[1:]
+	}
+}
+
+// processOutput validates the output proposal, and challenges it if it is invalid.
+// It returns false if the proposal cannot be validated yet.
+func (c *Challenger) processOutput(ctx context.Context, proposal *bindings.L2OutputOracleOutputProposed) (bool, error) {
+	output, ok, err := c.fetchOutput(ctx, proposal.L2BlockNumber)
+	if err != nil || !ok {
+		return false, err
+	}
+	proposed := eth.Bytes32(proposal.OutputRoot)
+	index := proposal.L2OutputIndex.Uint64()
+	if a, ok := c.invalid[index]; ok && a.ProposedOutputRoot != proposed {
+		// the invalid output was deleted, and another output was proposed in its place
+		c.resolveDeletedOutput(index)
+	}
+	if output.OutputRoot == proposed {
+		c.log.Info("Validated output proposal", "index", proposal.L2OutputIndex, "l2_block", output.BlockRef,
+			"output_root", proposed)
+		c.metr.RecordValidOutput(output.BlockRef)
+		return true, nil
+	}
+
+	// The OutputProposed event is read from the unconfirmed L1 chain: do not act on a proposal
+	// that was reorged out since, or deleted.
+	if ok, err := c.isProposed(ctx, proposal); err != nil {
+		return false, fmt.Errorf("failed to check output proposal: %w", err)
+	} else if !ok {
+		c.log.Warn("Invalid output is no longer proposed, skipping", "index", proposal.L2OutputIndex,
+			"l2_block", output.BlockRef, "proposed", proposed)
+		return true, nil
+	}
+
+	c.log.Error("Invalid output proposal", "index", proposal.L2OutputIndex, "l2_block", output.BlockRef,
+		"proposed", proposed, "expected", output.OutputRoot)
+	c.metr.RecordInvalidOutput(output.BlockRef)
+	if _, ok := c.invalid[index]; !ok {
+		a := alert.Alert{
+			Type:               alert.InvalidOutput,
+			L2OutputOracle:     c.l2ooContractAddr,
+			L2OutputIndex:      proposal.L2OutputIndex.Uint64(),
+			L2BlockNumber:      output.BlockRef.Number,
+			ProposedOutputRoot: proposed,
+			ExpectedOutputRoot: output.OutputRoot,
+			Time:               time.Now(),
+		}
+		c.invalid[a.L2OutputIndex] = a
+		c.notify(a)
+	}
+	if c.dryRun {
+		c.log.Warn("Not challenging invalid output in dry-run mode", "index", proposal.L2OutputIndex)
+		return true, nil
+	}
+	if err := c.challenge(ctx, proposal); err != nil {
+		c.metr.RecordChallengeFailed()
+		c.scheduleRetry(proposal, output.BlockRef, 0, err)
+		return true, nil
+	}
+	c.metr.RecordOutputChallenged(output.BlockRef)
+	return true, nil
+}
+
+// scheduleRetry schedules another attempt to challenge the invalid output proposal,
+// backing off exponentially from the poll interval with the number of failed attempts.
+func (c *Challenger) scheduleRetry(proposal *bindings.L2OutputOracleOutputProposed, blockRef eth.L2BlockRef, attempts int, err error) {
+	backoff := c.pollInterval
+	for i := 0; i < attempts && backoff < maxRetryInterval; i++ {
+		backoff *= 2
+	}
+	if backoff > maxRetryInterval {
+		backoff = maxRetryInterval
+	}
+	c.log.Error("Failed to challenge output, retrying later", "index", proposal.L2OutputIndex, "l2_block", blockRef,
+		"attempts", attempts+1, "backoff", backoff, "err", err)
+	c.retries[proposal.L2OutputIndex.Uint64()] = &challengeRetry{
+		proposal: proposal,
+		blockRef: blockRef,
+		attempts: attempts + 1,
+		next:     time.Now().Add(backoff),
+	}
+}
+
+// retryChallenges attempts again the failed challenges that are due.
+// Outputs that are no longer proposed are not challenged anymore.
+func (c *Challenger) retryChallenges(ctx context.Context) {
+	now := time.Now()
+	for index, r := range c.retries {
+		if now.Before(r.next) {
+			continue
+		}
+		if ok, err := c.isProposed(ctx, r.proposal); err != nil {
+			c.log.Error("Failed to check output proposal", "index", index, "err", err)
+			continue
+		} else if !ok {
+			c.log.Warn("Invalid output is no longer proposed, not retrying challenge", "index", index,
+				"l2_block", r.blockRef)
+			delete(c.retries, index)
+			continue
+		}
+		if err := c.challenge(ctx, r.proposal); err != nil {
+			c.metr.RecordChallengeFailed()
+			c.scheduleRetry(r.proposal, r.blockRef, r.attempts, err)
+			continue
+		}
+		c.metr.RecordOutputChallenged(r.blockRef)
+		delete(c.retries, index)
+	}
+}
+
+// isProposed returns whether the output proposal is part of the L2OutputOracle.
+func (c *Challenger) isProposed(ctx context.Context, proposal *bindings.L2OutputOracleOutputProposed) (bool, error) {
+	cCtx, cCancel := context.WithTimeout(ctx, c.networkTimeout)
+	defer cCancel()
+	callOpts := &bind.CallOpts{Context: cCtx}
+	next, err := c.l2ooContract.NextOutputIndex(callOpts)
+	if err != nil {
+		return false, err
+	}
+	if proposal.L2OutputIndex.Cmp(next) >= 0 {
+		return false, nil
+	}
+	output, err := c.l2ooContract.GetL2Output(callOpts, proposal.L2OutputIndex)
+	if err != nil {
+		return false, err
+	}
+	return output.OutputRoot == proposal.OutputRoot, nil
+}
+
+// checkDeletedOutputs resolves the alerts of invalid outputs that were deleted from the L2OutputOracle.
+// An output counts as deleted if its index is beyond the latest output,
+// or if a different output was proposed at its index since.
+func (c *Challenger) checkDeletedOutputs(ctx context.Context) error {
+	if len(c.invalid) == 0 {
+		return nil
+	}
+	cCtx, cCancel := context.WithTimeout(ctx, c.networkTimeout)
+	defer cCancel()
+	callOpts := &bind.CallOpts{Context: cCtx}
+	next, err := c.l2ooContract.NextOutputIndex(callOpts)
+	if err != nil {
+		return err
+	}
+	for index, a := range c.invalid {
+		if index < next.Uint64() {
+			output, err := c.l2ooContract.GetL2Output(callOpts, new(big.Int).SetUint64(index))
+			if err != nil {
+				return fmt.Errorf("failed to fetch output %d: %w", index, err)
+			}
+			if eth.Bytes32(output.OutputRoot) == a.ProposedOutputRoot {
+				continue
+			}
+		}
+		c.resolveDeletedOutput(index)
+	}
+	return nil
+}
+
+// resolveDeletedOutput raises an OutputDeleted alert for the invalid output at the given index.
+func (c *Challenger) resolveDeletedOutput(index uint64) {
+	a, ok := c.invalid[index]
+	if !ok {
+		return
+	}
+	delete(c.invalid, index)
+	a.Type = alert.OutputDeleted
+	a.Time = time.Now()
+	c.notify(a)
+}
+
+// notify queues the alert for delivery, without blocking on slow sinks.
+// The alert is dropped if the queue is full.
+func (c *Challenger) notify(a alert.Alert) {
+	select {
+	case c.alerts <- a:
+	default:
+		c.log.Error("Alert queue is full, dropping alert", "type", a.Type, "index", a.L2OutputIndex)
+	}
+}
+
+// notifyLoop delivers the queued alerts to all configured sinks, in order.
+// Delivery failures are logged, but not retried.
+func (c *Challenger) notifyLoop() {
+	defer c.wg.Done()
+
+	for {
+		select {
+		case a := <-c.alerts:
+			if err := c.notifier.Notify(c.ctx, a); err != nil {
+				c.log.Error("Failed to deliver alert", "type", a.Type, "index", a.L2OutputIndex, "err", err)
+			}
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// fetchOutput recomputes the output at the given L2 block with the rollup node.
+// It returns false if the block is not part of the safe L2 chain yet.
+func (c *Challenger) fetchOutput(ctx context.Context, block *big.Int) (*eth.OutputResponse, bool, error) {
+	cCtx, cCancel := context.WithTimeout(ctx, c.networkTimeout)
+	defer cCancel()
+	// The rollup node does not serve outputs of blocks it did not sync yet:
+	// check the safe head first, to tell those apart from failures of the rollup node.
+	status, err := c.rollupClient.SyncStatus(cCtx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to fetch sync status: %w", err)
+	}
+	if block.Uint64() > status.SafeL2.Number {
+		c.log.Debug("Output not safe yet", "l2_block", block, "safe_l2", status.SafeL2)
+		return nil, false, nil
+	}
+	output, err := c.rollupClient.OutputAtBlock(cCtx, block.Uint64())
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to fetch output at block %v: %w", block, err)
+	}
+	if output.Version != supportedL2OutputVersion {
+		return nil, false, fmt.Errorf("unsupported l2 output version: %s", output.Version)
+	}
+	if output.BlockRef.Number != block.Uint64() { // sanity check, e.g. in case of bad RPC caching
+		return nil, false, fmt.Errorf("invalid block number: requested %v, got %v", block, output.BlockRef.Number)
+	}
+	if output.Status == nil || output.BlockRef.Number > output.Status.SafeL2.Number {
+		c.log.Debug("Output not safe yet", "l2_block", output.BlockRef)
+		return nil, false, nil
+	}
+	return output, true, nil
+}
+
+// challenge creates an attestation dispute game for the output proposal through the DisputeGameFactory.
+func (c *Challenger) challenge(ctx context.Context, proposal *bindings.L2OutputOracleOutputProposed) error {
+	data, err := createDisputeGameTxData(c.dgfABI, proposal)
+	if err != nil {
+		return err
+	}
+	receipt, err := c.txMgr.Send(ctx, txmgr.TxCandidate{
+		TxData:   data,
+		To:       &c.dgfContractAddr,
+		GasLimit: 0,
+	})
+	if err != nil {
+		return err
+	}
+	if receipt.Status == types.ReceiptStatusFailed {
+		// The game may already have been created by another challenger, there is no point in retrying then.
+		game, err := c.disputeGame(ctx, proposal)
+		if err != nil {
+			return fmt.Errorf("%w: tx %s, and failed to look up existing game: %v", ErrChallengeReverted, receipt.TxHash, err)
+		}
+		if game == (common.Address{}) {
+			return fmt.Errorf("%w: tx %s", ErrChallengeReverted, receipt.TxHash)
+		}
+		c.log.Warn("Dispute game creation tx reverted, but the game already exists", "index", proposal.L2OutputIndex,
+			"tx_hash", receipt.TxHash, "game", game)
+		return nil
+	}
+	c.log.Info("Created dispute game", "index", proposal.L2OutputIndex, "l2_block", proposal.L2BlockNumber,
+		"tx_hash", receipt.TxHash)
+	return nil
+}
+
+// disputeGame returns the address of the attestation dispute game of the output proposal,
+// or the zero address if there is none.
+func (c *Challenger) disputeGame(ctx context.Context, proposal *bindings.L2OutputOracleOutputProposed) (common.Address, error) {
+	cCtx, cCancel := context.WithTimeout(ctx, c.networkTimeout)
+	defer cCancel()
+	var out []interface{}
+	err := c.dgfContract.Call(&bind.CallOpts{Context: cCtx}, &out, "games",
+		attestationGameType, proposal.OutputRoot, common.LeftPadBytes(proposal.L2BlockNumber.Bytes(), 32))
+	if err != nil {
+		return common.Address{}, err
+	}
+	return *abi.ConvertType(out[0], new(common.Address)).(*common.Address), nil
+}
+
+// createDisputeGameTxData creates the transaction data for the DisputeGameFactory create function,
+// disputing the proposed output root at the L2 block number that is passed as extra data.
+func createDisputeGameTxData(dgfABI *abi.ABI, proposal *bindings.L2OutputOracleOutputProposed) ([]byte, error) {
+	if proposal.L2BlockNumber == nil {
+		return nil, errors.New("missing l2 block number")
+	}
+	return dgfABI.Pack(
+		"create",
+		attestationGameType,
+		proposal.OutputRoot,
+		common.LeftPadBytes(proposal.L2BlockNumber.Bytes(), 32))
+}
@@ -0,0 +1,480 @@
+package challenger
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-challenger/alert"
+	"github.com/ethereum-optimism/optimism/op-challenger/metrics"
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
+)
+
+type stubRollupClient struct {
+	outputs map[uint64]eth.Bytes32
+	safe    uint64
+}
+
+func (s *stubRollupClient) OutputAtBlock(_ context.Context, blockNum uint64) (*eth.OutputResponse, error) {
+	root, ok := s.outputs[blockNum]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return &eth.OutputResponse{
+		OutputRoot: root,
+		BlockRef:   eth.L2BlockRef{Number: blockNum},
+		Status:     &eth.SyncStatus{SafeL2: eth.L2BlockRef{Number: s.safe}},
+	}, nil
+}
+
+func (s *stubRollupClient) SyncStatus(context.Context) (*eth.SyncStatus, error) {
+	return &eth.SyncStatus{SafeL2: eth.L2BlockRef{Number: s.safe}}, nil
+}
+
+type stubTxMgr struct {
+	sent     []txmgr.TxCandidate
+	err      error
+	reverted bool
+}
+
+func (s *stubTxMgr) Send(_ context.Context, candidate txmgr.TxCandidate) (*types.Receipt, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.sent = append(s.sent, candidate)
+	if s.reverted {
+		return &types.Receipt{Status: types.ReceiptStatusFailed}, nil
+	}
+	return &types.Receipt{Status: types.ReceiptStatusSuccessful}, nil
+}
+
+func (s *stubTxMgr) From() common.Address {
+	return common.Address{}
+}
+
+// stubDGFCaller answers DisputeGameFactory games calls with a fixed game address.
+type stubDGFCaller struct {
+	abi  *abi.ABI
+	game common.Address
+}
+
+func (s *stubDGFCaller) CodeAt(context.Context, common.Address, *big.Int) ([]byte, error) {
+	return []byte{0x00}, nil
+}
+
+func (s *stubDGFCaller) CallContract(_ context.Context, call ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	return s.abi.Methods["games"].Outputs.Pack(s.game)
+}
+
+// stubOutputOracle holds the output roots of the L2OutputOracle, by output index.
+type stubOutputOracle struct {
+	outputs []eth.Bytes32
+}
+
+func (s *stubOutputOracle) NextOutputIndex(*bind.CallOpts) (*big.Int, error) {
+	return big.NewInt(int64(len(s.outputs))), nil
+}
+
+func (s *stubOutputOracle) GetL2Output(_ *bind.CallOpts, index *big.Int) (bindings.TypesOutputProposal, error) {
+	return bindings.TypesOutputProposal{OutputRoot: s.outputs[index.Uint64()]}, nil
+}
+
+type recordingNotifier struct {
+	alerts chan alert.Alert
+}
+
+func (n *recordingNotifier) Notify(_ context.Context, a alert.Alert) error {
+	n.alerts <- a
+	return nil
+}
+
+// queuedAlerts returns the alerts that were queued for delivery since the last call.
+func queuedAlerts(c *Challenger) []alert.Alert {
+	var alerts []alert.Alert
+	for {
+		select {
+		case a := <-c.alerts:
+			alerts = append(alerts, a)
+		default:
+			return alerts
+		}
+	}
+}
+
+type testMetrics struct {
+	metrics.Metricer
+	valid, invalid, challenged, failed int
+}
+
+func (m *testMetrics) RecordValidOutput(eth.L2BlockRef)      { m.valid++ }
+func (m *testMetrics) RecordInvalidOutput(eth.L2BlockRef)    { m.invalid++ }
+func (m *testMetrics) RecordOutputChallenged(eth.L2BlockRef) { m.challenged++ }
+func (m *testMetrics) RecordChallengeFailed()                { m.failed++ }
+
+func newTestChallenger(t *testing.T, dryRun bool) (*Challenger, *stubRollupClient, *stubTxMgr, *testMetrics) {
+	c, rollupClient, txMgr, m, _ := newTestChallengerWithDGF(t, dryRun)
+	return c, rollupClient, txMgr, m
+}
+
+func newTestChallengerWithDGF(t *testing.T, dryRun bool) (*Challenger, *stubRollupClient, *stubTxMgr, *testMetrics, *stubDGFCaller) {
+	dgfABI, err := abi.JSON(strings.NewReader(disputeGameFactoryABI))
+	require.NoError(t, err)
+	rollupClient := &stubRollupClient{outputs: make(map[uint64]eth.Bytes32)}
+	txMgr := &stubTxMgr{}
+	m := &testMetrics{}
+	dgf := &stubDGFCaller{abi: &dgfABI}
+	c := &Challenger{
+		txMgr:           txMgr,
+		log:             testlog.Logger(t, log.LvlDebug),
+		metr:            m,
+		rollupClient:    rollupClient,
+		dgfContract:     bind.NewBoundContract(common.Address{0xdf}, dgfABI, dgf, nil, nil),
+		dgfContractAddr: common.Address{0xdf},
+		dgfABI:          &dgfABI,
+		networkTimeout:  time.Second,
+		dryRun:          dryRun,
+		l2ooContract:    &stubOutputOracle{},
+		notifier:        &recordingNotifier{alerts: make(chan alert.Alert, alertQueueSize)},
+		alerts:          make(chan alert.Alert, alertQueueSize),
+		invalid:         make(map[uint64]alert.Alert),
+		retries:         make(map[uint64]*challengeRetry),
+	}
+	return c, rollupClient, txMgr, m, dgf
+}
+
+func proposal(index uint64, l2Block uint64, root eth.Bytes32) *bindings.L2OutputOracleOutputProposed {
+	return &bindings.L2OutputOracleOutputProposed{
+		OutputRoot:    root,
+		L2OutputIndex: new(big.Int).SetUint64(index),
+		L2BlockNumber: new(big.Int).SetUint64(l2Block),
+	}
+}
+
+// propose adds the output proposal to the L2OutputOracle, and queues it for validation.
+func propose(c *Challenger, index uint64, l2Block uint64, root eth.Bytes32) {
+	l2oo := c.l2ooContract.(*stubOutputOracle)
+	for uint64(len(l2oo.outputs)) <= index {
+		l2oo.outputs = append(l2oo.outputs, eth.Bytes32{})
+	}
+	l2oo.outputs[index] = root
+	c.pending = append(c.pending, proposal(index, l2Block, root))
+}
+
+func TestValidOutput(t *testing.T) {
+	c, rollupClient, txMgr, m := newTestChallenger(t, false)
+	rollupClient.outputs[10] = eth.Bytes32{0x01}
+	rollupClient.safe = 10
+	propose(c, 0, 10, eth.Bytes32{0x01})
+
+	c.processPending(context.Background())
+	require.Empty(t, c.pending)
+	require.Empty(t, txMgr.sent)
+	require.Equal(t, 1, m.valid)
+	require.Zero(t, m.invalid)
+}
+
+func TestInvalidOutputIsChallenged(t *testing.T) {
+	c, rollupClient, txMgr, m := newTestChallenger(t, false)
+	rollupClient.outputs[10] = eth.Bytes32{0x01}
+	rollupClient.outputs[20] = eth.Bytes32{0x02}
+	rollupClient.safe = 20
+	propose(c, 0, 10, eth.Bytes32{0x01})
+	propose(c, 1, 20, eth.Bytes32{0xba, 0xd0})
+
+	c.processPending(context.Background())
+	require.Empty(t, c.pending)
+	require.Equal(t, 1, m.valid)
+	require.Equal(t, 1, m.invalid)
+	require.Equal(t, 1, m.challenged)
+
+	require.Len(t, txMgr.sent, 1)
+	require.Equal(t, c.dgfContractAddr, *txMgr.sent[0].To)
+	args, err := c.dgfABI.Methods["create"].Inputs.Unpack(txMgr.sent[0].TxData[4:])
+	require.NoError(t, err)
+	require.Equal(t, attestationGameType, args[0])
+	require.Equal(t, [32]byte{0xba, 0xd0}, args[1], "must dispute the proposed output root")
+	require.Equal(t, common.LeftPadBytes([]byte{20}, 32), args[2], "extra data must be the l2 block number")
+}
+
+func TestInvalidOutputDryRun(t *testing.T) {
+	c, rollupClient, txMgr, m := newTestChallenger(t, true)
+	rollupClient.outputs[10] = eth.Bytes32{0x01}
+	rollupClient.safe = 10
+	propose(c, 0, 10, eth.Bytes32{0xba, 0xd0})
+
+	c.processPending(context.Background())
+	require.Empty(t, c.pending)
+	require.Empty(t, txMgr.sent)
+	require.Equal(t, 1, m.invalid)
+	require.Zero(t, m.challenged)
+}
+
+func TestInvalidOutputNoLongerProposed(t *testing.T) {
+	c, rollupClient, txMgr, m := newTestChallenger(t, false)
+	rollupClient.outputs[10] = eth.Bytes32{0x01}
+	rollupClient.outputs[20] = eth.Bytes32{0x02}
+	rollupClient.safe = 20
+	propose(c, 0, 10, eth.Bytes32{0xba, 0xd0})
+	propose(c, 1, 20, eth.Bytes32{0xba, 0xd1})
+
+	// the first proposal was reorged out and replaced, the second one was reorged out entirely
+	l2oo := c.l2ooContract.(*stubOutputOracle)
+	l2oo.outputs = []eth.Bytes32{{0x01}}
+
+	c.processPending(context.Background())
+	require.Empty(t, c.pending)
+	require.Empty(t, txMgr.sent, "must not challenge outputs that are no longer proposed")
+	require.Zero(t, m.invalid)
+	require.Empty(t, queuedAlerts(c))
+}
+
+func TestOutputNotSafeYet(t *testing.T) {
+	c, rollupClient, txMgr, m := newTestChallenger(t, false)
+	rollupClient.outputs[10] = eth.Bytes32{0x01}
+	rollupClient.safe = 9
+	propose(c, 0, 10, eth.Bytes32{0xba, 0xd0})
+	propose(c, 1, 20, eth.Bytes32{0x02})
+
+	c.processPending(context.Background())
+	require.Len(t, c.pending, 2, "proposals must stay pending until the L2 block is safe")
+	require.Empty(t, txMgr.sent)
+	require.Zero(t, m.invalid)
+
+	rollupClient.safe = 10
+	c.processPending(context.Background())
+	require.Len(t, c.pending, 1, "later proposals must stay pending until their L2 block is available")
+	require.Len(t, txMgr.sent, 1)
+}
+
+func TestOutputUnavailable(t *testing.T) {
+	c, rollupClient, txMgr, m := newTestChallenger(t, false)
+	rollupClient.safe = 10
+	p := proposal(0, 10, eth.Bytes32{0xba, 0xd0})
+	c.pending = append(c.pending, p)
+
+	// the block is safe, the rollup node failing to serve its output is an error, rather than "not safe yet"
+	_, err := c.processOutput(context.Background(), p)
+	require.ErrorContains(t, err, "failed to fetch output")
+	c.processPending(context.Background())
+	require.Len(t, c.pending, 1)
+	require.Empty(t, txMgr.sent)
+	require.Zero(t, m.invalid)
+}
+
+func TestChallengeRetriedOnFailure(t *testing.T) {
+	c, rollupClient, txMgr, m := newTestChallenger(t, false)
+	rollupClient.outputs[10] = eth.Bytes32{0x01}
+	rollupClient.safe = 10
+	propose(c, 0, 10, eth.Bytes32{0xba, 0xd0})
+
+	txMgr.err = errors.New("boom")
+	c.processPending(context.Background())
+	require.Empty(t, c.pending)
+	require.Contains(t, c.retries, uint64(0))
+	require.Equal(t, 1, m.failed)
+
+	c.retryChallenges(context.Background())
+	require.Equal(t, 2, m.failed)
+	require.Equal(t, 2, c.retries[0].attempts)
+
+	txMgr.err = nil
+	c.retryChallenges(context.Background())
+	require.Empty(t, c.retries)
+	require.Len(t, txMgr.sent, 1)
+	require.Equal(t, 1, m.challenged)
+}
+
+func TestChallengeFailureDoesNotBlockQueue(t *testing.T) {
+	c, rollupClient, txMgr, m := newTestChallenger(t, false)
+	c.pollInterval = time.Hour
+	rollupClient.outputs[10] = eth.Bytes32{0x01}
+	rollupClient.outputs[20] = eth.Bytes32{0x02}
+	rollupClient.outputs[30] = eth.Bytes32{0x03}
+	rollupClient.safe = 30
+	propose(c, 0, 10, eth.Bytes32{0xba, 0xd0})
+	propose(c, 1, 20, eth.Bytes32{0x02})
+	propose(c, 2, 30, eth.Bytes32{0xba, 0xd2})
+
+	txMgr.err = errors.New("boom")
+	c.processPending(context.Background())
+	require.Empty(t, c.pending, "later proposals must be validated after a failed challenge")
+	require.Equal(t, 1, m.valid)
+	require.Equal(t, 2, m.invalid)
+	require.Equal(t, 2, m.failed)
+	require.Len(t, c.retries, 2)
+
+	// retries back off from the poll interval
+	txMgr.err = nil
+	c.retryChallenges(context.Background())
+	require.Empty(t, txMgr.sent)
+	require.Len(t, c.retries, 2)
+
+	c.retries[2].next = time.Now()
+	c.retryChallenges(context.Background())
+	require.Len(t, txMgr.sent, 1)
+	require.Equal(t, 1, m.challenged)
+	require.Contains(t, c.retries, uint64(0))
+}
+
+func TestChallengeRetryDroppedWhenNoLongerProposed(t *testing.T) {
+	c, rollupClient, txMgr, m := newTestChallenger(t, false)
+	rollupClient.outputs[10] = eth.Bytes32{0x01}
+	rollupClient.safe = 10
+	propose(c, 0, 10, eth.Bytes32{0xba, 0xd0})
+
+	txMgr.err = errors.New("boom")
+	c.processPending(context.Background())
+	require.Len(t, c.retries, 1)
+
+	// the invalid output was deleted
+	c.l2ooContract.(*stubOutputOracle).outputs = nil
+	txMgr.err = nil
+	c.retryChallenges(context.Background())
+	require.Empty(t, c.retries)
+	require.Empty(t, txMgr.sent)
+	require.Zero(t, m.challenged)
+}
+
+func TestChallengeRevertedIsRetried(t *testing.T) {
+	c, rollupClient, txMgr, m, dgf := newTestChallengerWithDGF(t, false)
+	rollupClient.outputs[10] = eth.Bytes32{0x01}
+	rollupClient.safe = 10
+	propose(c, 0, 10, eth.Bytes32{0xba, 0xd0})
+
+	txMgr.reverted = true
+	c.processPending(context.Background())
+	require.Empty(t, c.pending)
+	require.Len(t, c.retries, 1, "challenge must be retried if no game was created")
+	require.Equal(t, 1, m.failed)
+	require.Zero(t, m.challenged)
+
+	// another challenger created the game in the meantime
+	dgf.game = common.Address{0x9a}
+	c.retryChallenges(context.Background())
+	require.Empty(t, c.retries)
+	require.Len(t, txMgr.sent, 2)
+	require.Equal(t, 1, m.challenged)
+}
+
+func TestInvalidOutputAlerts(t *testing.T) {
+	c, rollupClient, _, _ := newTestChallenger(t, true)
+	l2oo := c.l2ooContract.(*stubOutputOracle)
+	rollupClient.outputs[10] = eth.Bytes32{0x01}
+	rollupClient.outputs[20] = eth.Bytes32{0x02}
+	rollupClient.safe = 20
+	l2oo.outputs = []eth.Bytes32{{0x01}, {0xba, 0xd0}}
+	propose(c, 0, 10, eth.Bytes32{0x01})
+	propose(c, 1, 20, eth.Bytes32{0xba, 0xd0})
+
+	c.processPending(context.Background())
+	alerts := queuedAlerts(c)
+	require.Len(t, alerts, 1, "only invalid outputs must be alerted")
+	a := alerts[0]
+	require.Equal(t, alert.InvalidOutput, a.Type)
+	require.Equal(t, uint64(1), a.L2OutputIndex)
+	require.Equal(t, uint64(20), a.L2BlockNumber)
+	require.Equal(t, eth.Bytes32{0xba, 0xd0}, a.ProposedOutputRoot)
+	require.Equal(t, eth.Bytes32{0x02}, a.ExpectedOutputRoot)
+
+	// the output is still part of the L2OutputOracle
+	require.NoError(t, c.checkDeletedOutputs(context.Background()))
+	require.Empty(t, queuedAlerts(c))
+
+	// the output was deleted
+	l2oo.outputs = l2oo.outputs[:1]
+	require.NoError(t, c.checkDeletedOutputs(context.Background()))
+	alerts = queuedAlerts(c)
+	require.Len(t, alerts, 1)
+	require.Equal(t, alert.OutputDeleted, alerts[0].Type)
+	require.Equal(t, uint64(1), alerts[0].L2OutputIndex)
+	require.Empty(t, c.invalid)
+}
+
+func TestReplacedOutputAlerts(t *testing.T) {
+	c, rollupClient, _, _ := newTestChallenger(t, true)
+	l2oo := c.l2ooContract.(*stubOutputOracle)
+	rollupClient.outputs[10] = eth.Bytes32{0x01}
+	rollupClient.safe = 10
+	l2oo.outputs = []eth.Bytes32{{0xba, 0xd0}}
+	propose(c, 0, 10, eth.Bytes32{0xba, 0xd0})
+	c.processPending(context.Background())
+	require.Len(t, queuedAlerts(c), 1)
+
+	// the output was deleted, and another invalid output was proposed at its index before the next check
+	l2oo.outputs = []eth.Bytes32{{0xba, 0xd1}}
+	require.NoError(t, c.checkDeletedOutputs(context.Background()))
+	alerts := queuedAlerts(c)
+	require.Len(t, alerts, 1)
+	require.Equal(t, alert.OutputDeleted, alerts[0].Type)
+	require.Empty(t, c.invalid)
+
+	propose(c, 0, 10, eth.Bytes32{0xba, 0xd1})
+	c.processPending(context.Background())
+	alerts = queuedAlerts(c)
+	require.Len(t, alerts, 1, "the replacement output must be alerted")
+	require.Equal(t, alert.InvalidOutput, alerts[0].Type)
+	require.Equal(t, eth.Bytes32{0xba, 0xd1}, alerts[0].ProposedOutputRoot)
+
+	// the replacement was validated before the deletion was checked
+	propose(c, 0, 10, eth.Bytes32{0x01})
+	c.processPending(context.Background())
+	alerts = queuedAlerts(c)
+	require.Len(t, alerts, 1)
+	require.Equal(t, alert.OutputDeleted, alerts[0].Type)
+	require.Equal(t, eth.Bytes32{0xba, 0xd1}, alerts[0].ProposedOutputRoot)
+	require.Empty(t, c.invalid)
+}
+
+func TestAlertsDeliveredInBackground(t *testing.T) {
+	c, _, _, _ := newTestChallenger(t, true)
+	notifier := c.notifier.(*recordingNotifier)
+	c.ctx, c.cancel = context.WithCancel(context.Background())
+	c.done = make(chan struct{})
+	c.wg.Add(1)
+	go c.notifyLoop()
+	defer func() {
+		c.cancel()
+		close(c.done)
+		c.wg.Wait()
+	}()
+
+	c.notify(alert.Alert{Type: alert.InvalidOutput, L2OutputIndex: 1})
+	c.notify(alert.Alert{Type: alert.OutputDeleted, L2OutputIndex: 1})
+	for _, typ := range []alert.Type{alert.InvalidOutput, alert.OutputDeleted} {
+		select {
+		case a := <-notifier.alerts:
+			require.Equal(t, typ, a.Type, "alerts must be delivered in order")
+		case <-time.After(5 * time.Second):
+			t.Fatal("alert not delivered")
+		}
+	}
+}
+
+func TestInvalidOutputAlertedOnce(t *testing.T) {
+	c, rollupClient, txMgr, _ := newTestChallenger(t, false)
+	rollupClient.outputs[10] = eth.Bytes32{0x01}
+	rollupClient.safe = 10
+	propose(c, 0, 10, eth.Bytes32{0xba, 0xd0})
+
+	// the challenge is retried, but the output is alerted only once
+	txMgr.err = errors.New("boom")
+	c.processPending(context.Background())
+	txMgr.err = nil
+	c.retryChallenges(context.Background())
+	require.Len(t, txMgr.sent, 1)
+	require.Len(t, queuedAlerts(c), 1)
+}
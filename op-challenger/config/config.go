@@ -7,6 +7,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/urfave/cli"
 
+	alert "github.com/ethereum-optimism/optimism/op-challenger/alert"
 	flags "github.com/ethereum-optimism/optimism/op-challenger/flags"
 
 	oplog "github.com/ethereum-optimism/optimism/op-service/log"
@@ -22,6 +23,7 @@ var (
 	ErrMissingL2OOAddress    = errors.New("missing l2 output oracle contract address")
 	ErrMissingDGFAddress     = errors.New("missing dispute game factory contract address")
 	ErrInvalidNetworkTimeout = errors.New("invalid network timeout")
+	ErrInvalidPollInterval   = errors.New("invalid poll interval")
 	ErrMissingTxMgrConfig    = errors.New("missing tx manager config")
 	ErrMissingRPCConfig      = errors.New("missing rpc config")
 	ErrMissingLogConfig      = errors.New("missing log config")
@@ -48,6 +50,15 @@ type Config struct {
 	// NetworkTimeout is the timeout for network requests.
 	NetworkTimeout time.Duration
 
+	// PollInterval is the delay between querying L1 for new output proposals.
+	PollInterval time.Duration
+
+	// DryRun only alerts on invalid outputs, instead of creating dispute games.
+	DryRun bool
+
+	// AlertConfig selects the sinks that invalid output alerts are delivered to.
+	AlertConfig alert.Config
+
 	TxMgrConfig *txmgr.CLIConfig
 
 	RPCConfig *oprpc.CLIConfig
@@ -75,6 +86,12 @@ func (c Config) Check() error {
 	if c.NetworkTimeout == 0 {
 		return ErrInvalidNetworkTimeout
 	}
+	if c.PollInterval == 0 {
+		return ErrInvalidPollInterval
+	}
+	if err := c.AlertConfig.Check(); err != nil {
+		return err
+	}
 	if c.TxMgrConfig == nil {
 		return ErrMissingTxMgrConfig
 	}
@@ -127,11 +144,16 @@ func NewConfig(
 		L2OOAddress:    L2OOAddress,
 		DGFAddress:     DGFAddress,
 		NetworkTimeout: NetworkTimeout,
-		TxMgrConfig:    TxMgrConfig,
-		RPCConfig:      RPCConfig,
-		LogConfig:      LogConfig,
-		MetricsConfig:  MetricsConfig,
-		PprofConfig:    PprofConfig,
+		PollInterval:   flags.PollIntervalFlag.Value,
+		AlertConfig: alert.Config{
+			Sinks:          alert.ParseSinks(flags.AlertSinksFlag.Value),
+			WebhookTimeout: flags.AlertWebhookTimeoutFlag.Value,
+		},
+		TxMgrConfig:   TxMgrConfig,
+		RPCConfig:     RPCConfig,
+		LogConfig:     LogConfig,
+		MetricsConfig: MetricsConfig,
+		PprofConfig:   PprofConfig,
 	}
 }
 
@@ -171,6 +193,14 @@ func NewConfigFromCLI(ctx *cli.Context) (*Config, error) {
 		DGFAddress:  dgfAddress,
 		TxMgrConfig: &txMgrConfig,
 		// Optional Flags
+		NetworkTimeout: txMgrConfig.NetworkTimeout,
+		PollInterval:   ctx.GlobalDuration(flags.PollIntervalFlag.Name),
+		DryRun:         ctx.GlobalBool(flags.DryRunFlag.Name),
+		AlertConfig: alert.Config{
+			Sinks:          alert.ParseSinks(ctx.GlobalString(flags.AlertSinksFlag.Name)),
+			WebhookURL:     ctx.GlobalString(flags.AlertWebhookURLFlag.Name),
+			WebhookTimeout: ctx.GlobalDuration(flags.AlertWebhookTimeoutFlag.Name),
+		},
 		RPCConfig:     &rpcConfig,
 		LogConfig:     &logConfig,
 		MetricsConfig: &metricsConfig,
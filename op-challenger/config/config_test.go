@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	alert "github.com/ethereum-optimism/optimism/op-challenger/alert"
 	oplog "github.com/ethereum-optimism/optimism/op-service/log"
 	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
 	oppprof "github.com/ethereum-optimism/optimism/op-service/pprof"
@@ -125,3 +126,27 @@ func TestNetworkTimeoutRequired(t *testing.T) {
 	err := config.Check()
 	require.ErrorIs(t, err, ErrInvalidNetworkTimeout)
 }
+
+func TestPollIntervalRequired(t *testing.T) {
+	config := validConfig()
+	config.PollInterval = 0
+	err := config.Check()
+	require.ErrorIs(t, err, ErrInvalidPollInterval)
+}
+
+func TestAlertConfig(t *testing.T) {
+	t.Run("DefaultSinks", func(t *testing.T) {
+		config := validConfig()
+		require.Equal(t, []string{alert.LogSink, alert.MetricsSink}, config.AlertConfig.Sinks)
+	})
+
+	t.Run("WebhookRequiresURL", func(t *testing.T) {
+		config := validConfig()
+		config.AlertConfig.Sinks = append(config.AlertConfig.Sinks, alert.WebhookSink)
+		err := config.Check()
+		require.ErrorIs(t, err, alert.ErrMissingWebhookURL)
+
+		config.AlertConfig.WebhookURL = "https://example.com/alerts"
+		require.NoError(t, config.Check())
+	})
+}
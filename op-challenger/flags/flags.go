@@ -2,9 +2,12 @@ package flags
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/urfave/cli"
 
+	"github.com/ethereum-optimism/optimism/op-challenger/alert"
 	opservice "github.com/ethereum-optimism/optimism/op-service"
 	oplog "github.com/ethereum-optimism/optimism/op-service/log"
 	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
@@ -39,6 +42,38 @@ var (
 	}
 )
 
+// Optional Flags
+var (
+	PollIntervalFlag = cli.DurationFlag{
+		Name:   "poll-interval",
+		Usage:  "Delay between querying L1 for new output proposals.",
+		Value:  6 * time.Second,
+		EnvVar: opservice.PrefixEnvVar(envVarPrefix, "POLL_INTERVAL"),
+	}
+	DryRunFlag = cli.BoolFlag{
+		Name:   "dry-run",
+		Usage:  "Only alert on invalid output proposals, without creating dispute games.",
+		EnvVar: opservice.PrefixEnvVar(envVarPrefix, "DRY_RUN"),
+	}
+	AlertSinksFlag = cli.StringFlag{
+		Name:   "alert.sinks",
+		Usage:  "Comma separated list of sinks to deliver invalid output alerts to. Valid options: " + strings.Join(alert.Sinks, ", "),
+		Value:  alert.LogSink + "," + alert.MetricsSink,
+		EnvVar: opservice.PrefixEnvVar(envVarPrefix, "ALERT_SINKS"),
+	}
+	AlertWebhookURLFlag = cli.StringFlag{
+		Name:   "alert.webhook-url",
+		Usage:  "URL that alerts are posted to as JSON by the webhook sink.",
+		EnvVar: opservice.PrefixEnvVar(envVarPrefix, "ALERT_WEBHOOK_URL"),
+	}
+	AlertWebhookTimeoutFlag = cli.DurationFlag{
+		Name:   "alert.webhook-timeout",
+		Usage:  "Timeout for delivering an alert to the webhook.",
+		Value:  alert.DefaultWebhookTimeout,
+		EnvVar: opservice.PrefixEnvVar(envVarPrefix, "ALERT_WEBHOOK_TIMEOUT"),
+	}
+)
+
 // requiredFlags are checked by [CheckRequired]
 var requiredFlags = []cli.Flag{
 	L1EthRpcFlag,
@@ -48,7 +83,13 @@ var requiredFlags = []cli.Flag{
 }
 
 // optionalFlags is a list of unchecked cli flags
-var optionalFlags = []cli.Flag{}
+var optionalFlags = []cli.Flag{
+	PollIntervalFlag,
+	DryRunFlag,
+	AlertSinksFlag,
+	AlertWebhookURLFlag,
+	AlertWebhookTimeoutFlag,
+}
 
 func init() {
 	optionalFlags = append(optionalFlags, oprpc.CLIFlags(envVarPrefix)...)
@@ -29,6 +29,8 @@ type Metricer interface {
 	RecordValidOutput(l2ref eth.L2BlockRef)
 	RecordInvalidOutput(l2ref eth.L2BlockRef)
 	RecordOutputChallenged(l2ref eth.L2BlockRef)
+	RecordChallengeFailed()
+	RecordInvalidOutputsOutstanding(n int)
 }
 
 type Metrics struct {
@@ -41,6 +43,10 @@ type Metrics struct {
 
 	info prometheus.GaugeVec
 	up   prometheus.Gauge
+
+	outputs            *prometheus.CounterVec
+	challengeFailure   prometheus.Counter
+	invalidOutstanding prometheus.Gauge
 }
 
 var _ Metricer = (*Metrics)(nil)
@@ -74,6 +80,23 @@ func NewMetrics(procName string) *Metrics {
 			Name:      "up",
 			Help:      "1 if the op-proposer has finished starting up",
 		}),
+		outputs: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: ns,
+			Name:      "outputs_total",
+			Help:      "Number of output proposals that were validated, by result",
+		}, []string{
+			"result",
+		}),
+		challengeFailure: factory.NewCounter(prometheus.CounterOpts{
+			Namespace: ns,
+			Name:      "challenge_failures_total",
+			Help:      "Number of dispute games that failed to be created for invalid outputs",
+		}),
+		invalidOutstanding: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace: ns,
+			Name:      "invalid_outputs_outstanding",
+			Help:      "Number of invalid outputs that have not been deleted from the L2OutputOracle",
+		}),
 	}
 }
 
@@ -107,16 +130,29 @@ const (
 // RecordValidOutput should be called when a valid output is found
 func (m *Metrics) RecordValidOutput(l2ref eth.L2BlockRef) {
 	m.RecordL2Ref(ValidOutput, l2ref)
+	m.outputs.WithLabelValues(ValidOutput).Inc()
 }
 
 // RecordInvalidOutput should be called when an invalid output is found
 func (m *Metrics) RecordInvalidOutput(l2ref eth.L2BlockRef) {
 	m.RecordL2Ref(InvalidOutput, l2ref)
+	m.outputs.WithLabelValues(InvalidOutput).Inc()
 }
 
 // RecordOutputChallenged should be called when an output is challenged
 func (m *Metrics) RecordOutputChallenged(l2ref eth.L2BlockRef) {
 	m.RecordL2Ref(OutputChallenged, l2ref)
+	m.outputs.WithLabelValues(OutputChallenged).Inc()
+}
+
+// RecordChallengeFailed should be called when a dispute game for an invalid output could not be created
+func (m *Metrics) RecordChallengeFailed() {
+	m.challengeFailure.Inc()
+}
+
+// RecordInvalidOutputsOutstanding sets the number of invalid outputs that are still part of the L2OutputOracle
+func (m *Metrics) RecordInvalidOutputsOutstanding(n int) {
+	m.invalidOutstanding.Set(float64(n))
 }
 
 func (m *Metrics) Document() []opmetrics.DocumentedMetric {
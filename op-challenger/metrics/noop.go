@@ -19,3 +19,5 @@ func (*noopMetrics) RecordUp()                 {}
 func (*noopMetrics) RecordValidOutput(l2ref eth.L2BlockRef)      {}
 func (*noopMetrics) RecordInvalidOutput(l2ref eth.L2BlockRef)    {}
 func (*noopMetrics) RecordOutputChallenged(l2ref eth.L2BlockRef) {}
+func (*noopMetrics) RecordChallengeFailed()                      {}
+func (*noopMetrics) RecordInvalidOutputsOutstanding(n int)       {}
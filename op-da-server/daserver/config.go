@@ -0,0 +1,53 @@
+package daserver
+
+import (
+	"errors"
+	"time"
+
+	"github.com/urfave/cli"
+
+	"github.com/ethereum-optimism/optimism/op-da-server/flags"
+	oplog "github.com/ethereum-optimism/optimism/op-service/log"
+)
+
+type Config struct {
+	ListenAddr string
+	ListenPort int
+
+	// DataDir is the directory blobs are stored in. Blobs are kept in memory if empty.
+	DataDir   string
+	AuthToken string
+
+	Latency     time.Duration
+	FailureRate float64
+
+	Log oplog.CLIConfig
+}
+
+func (c Config) Check() error {
+	if c.ListenAddr == "" {
+		return errors.New("must specify a valid HTTP address")
+	}
+	if c.ListenPort < 0 {
+		return errors.New("must specify a valid HTTP port")
+	}
+	if c.Latency < 0 {
+		return errors.New("latency must not be negative")
+	}
+	if c.FailureRate < 0 || c.FailureRate > 1 {
+		return errors.New("failure rate must be between 0 and 1")
+	}
+	return c.Log.Check()
+}
+
+func NewConfig(ctx *cli.Context) Config {
+	return Config{
+		ListenAddr:  ctx.GlobalString(flags.HTTPAddrFlag.Name),
+		ListenPort:  ctx.GlobalInt(flags.HTTPPortFlag.Name),
+		DataDir:     ctx.GlobalString(flags.DataDirFlag.Name),
+		AuthToken:   ctx.GlobalString(flags.AuthTokenFlag.Name),
+		Latency:     ctx.GlobalDuration(flags.LatencyFlag.Name),
+		FailureRate: ctx.GlobalFloat64(flags.FailureRateFlag.Name),
+		Log:         oplog.ReadCLIConfig(ctx),
+	}
+}
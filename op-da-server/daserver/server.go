@@ -0,0 +1,249 @@
+package daserver
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// maxBlobSize bounds the size of a store request body.
+const maxBlobSize = 32 * 1024 * 1024
+
+type storeRequest struct {
+	Data string `json:"data"`
+}
+
+// Server is a mock DA backend speaking the HTTP blob protocol of the Celestia, Eigen and NEAR DA
+// backends: POST /store with a JSON body {"data": <base64>} returns the key of the blob,
+// and GET /get<key> returns the raw data. Keys are the hex keccak256 hash of the data.
+//
+// Latency, random failures and withholding of blobs can be injected to exercise the
+// error handling of the batcher and the derivation pipeline.
+type Server struct {
+	log       log.Logger
+	store     Store
+	authToken string
+
+	mu          sync.Mutex
+	latency     time.Duration
+	failureRate float64
+	rng         *rand.Rand
+	withholdAll bool
+	withheld    map[string]struct{}
+
+	listenAddr string
+	listener   net.Listener
+	httpServer *http.Server
+}
+
+// NewServer creates a server backed by the given store. Requests must carry the auth token as
+// bearer token, unless it is empty.
+func NewServer(log log.Logger, store Store, authToken string) *Server {
+	return &Server{
+		log:       log,
+		store:     store,
+		authToken: authToken,
+		rng:       rand.New(rand.NewSource(time.Now().UnixNano())),
+		withheld:  make(map[string]struct{}),
+	}
+}
+
+// NewServerFromConfig creates a server listening on the configured address,
+// with blobs stored in the configured directory, or in memory if there is none.
+func NewServerFromConfig(log log.Logger, cfg *Config) (*Server, error) {
+	var store Store = NewMemoryStore()
+	if cfg.DataDir != "" {
+		fileStore, err := NewFileStore(cfg.DataDir)
+		if err != nil {
+			return nil, err
+		}
+		store = fileStore
+	}
+	srv := NewServer(log, store, cfg.AuthToken)
+	srv.listenAddr = net.JoinHostPort(cfg.ListenAddr, fmt.Sprint(cfg.ListenPort))
+	srv.SetLatency(cfg.Latency)
+	srv.SetFailureRate(cfg.FailureRate)
+	return srv, nil
+}
+
+// Key returns the key under which the server stores the given data.
+func Key(data []byte) string {
+	return hex.EncodeToString(crypto.Keccak256(data))
+}
+
+// validKey returns whether the key is formatted like the keys returned by Key,
+// so that it can be used as a file name by the store.
+func validKey(key string) bool {
+	b, err := hex.DecodeString(key)
+	return err == nil && len(b) == 32 && hex.EncodeToString(b) == key
+}
+
+// SetLatency delays every response by the given duration.
+func (s *Server) SetLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = d
+}
+
+// SetFailureRate makes the given fraction of requests fail with an internal server error.
+// A rate of 1 makes every request fail.
+func (s *Server) SetFailureRate(rate float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failureRate = rate
+}
+
+// Withhold stops serving the blob with the given key, while still accepting it.
+func (s *Server) Withhold(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.withheld[key] = struct{}{}
+}
+
+// WithholdAll stops serving any blob, while still accepting new ones.
+func (s *Server) WithholdAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.withholdAll = true
+}
+
+// Release serves all withheld blobs again.
+func (s *Server) Release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.withholdAll = false
+	s.withheld = make(map[string]struct{})
+}
+
+func (s *Server) isWithheld(key string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.withheld[key]
+	return s.withholdAll || ok
+}
+
+// injectFaults applies the configured latency, and returns whether the request should fail.
+func (s *Server) injectFaults(ctx context.Context) bool {
+	s.mu.Lock()
+	latency := s.latency
+	fail := s.failureRate > 0 && s.rng.Float64() < s.failureRate
+	s.mu.Unlock()
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-ctx.Done():
+		}
+	}
+	return fail
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.authToken != "" && r.Header.Get("Authorization") != "Bearer "+s.authToken {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if s.injectFaults(r.Context()) {
+		s.log.Debug("Injecting DA request failure", "method", r.Method, "path", r.URL.Path)
+		http.Error(w, "injected failure", http.StatusInternalServerError)
+		return
+	}
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == "/store":
+		s.handleStore(w, r)
+	case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/get"):
+		key := strings.TrimPrefix(r.URL.Path, "/get")
+		if !validKey(key) {
+			http.Error(w, "invalid blob key", http.StatusBadRequest)
+			return
+		}
+		s.handleGet(w, key)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) handleStore(w http.ResponseWriter, r *http.Request) {
+	var req storeRequest
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxBlobSize)).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("invalid store request: %v", err), http.StatusBadRequest)
+		return
+	}
+	data, err := base64.StdEncoding.DecodeString(req.Data)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid blob data: %v", err), http.StatusBadRequest)
+		return
+	}
+	key := Key(data)
+	if err := s.store.Put(key, data); err != nil {
+		s.log.Error("Failed to store blob", "key", key, "err", err)
+		http.Error(w, "failed to store blob", http.StatusInternalServerError)
+		return
+	}
+	s.log.Debug("Stored blob", "key", key, "size", len(data))
+	_, _ = w.Write([]byte(key))
+}
+
+func (s *Server) handleGet(w http.ResponseWriter, key string) {
+	if s.isWithheld(key) {
+		s.log.Debug("Withholding blob", "key", key)
+		http.Error(w, "blob not found", http.StatusNotFound)
+		return
+	}
+	data, err := s.store.Get(key)
+	if errors.Is(err, ErrNotFound) {
+		http.Error(w, "blob not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		s.log.Error("Failed to read blob", "key", key, "err", err)
+		http.Error(w, "failed to read blob", http.StatusInternalServerError)
+		return
+	}
+	_, _ = w.Write(data)
+}
+
+// Start starts serving on the configured address, or on a random local port if there is none.
+func (s *Server) Start() error {
+	addr := s.listenAddr
+	if addr == "" {
+		addr = "127.0.0.1:0"
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	s.listener = listener
+	s.httpServer = &http.Server{Handler: s}
+	go func() {
+		if err := s.httpServer.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.log.Error("DA server stopped", "err", err)
+		}
+	}()
+	s.log.Info("Started DA server", "endpoint", s.Endpoint())
+	return nil
+}
+
+// Endpoint returns the URL of the server, once started.
+func (s *Server) Endpoint() string {
+	return "http://" + s.listener.Addr().String()
+}
+
+// Stop stops serving, waiting for in-flight requests until the context is done.
+func (s *Server) Stop(ctx context.Context) error {
+	if s.httpServer == nil {
+		return nil
+	}
+	return s.httpServer.Shutdown(ctx)
+}
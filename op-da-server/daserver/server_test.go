@@ -0,0 +1,169 @@
+package daserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+	"github.com/ethereum-optimism/optimism/op-service/da"
+)
+
+func newTestServer(t *testing.T, store Store, authToken string) (*Server, *da.HTTPBlob) {
+	srv := NewServer(testlog.Logger(t, log.LvlInfo), store, authToken)
+	ts := httptest.NewServer(srv)
+	t.Cleanup(ts.Close)
+	return srv, da.NewHTTPBlob(da.CelestiaPrefix, da.HTTPBlobConfig{URL: ts.URL, AuthToken: authToken})
+}
+
+func TestServerRoundTrip(t *testing.T) {
+	stores := map[string]func(t *testing.T) Store{
+		"memory": func(t *testing.T) Store { return NewMemoryStore() },
+		"file": func(t *testing.T) Store {
+			s, err := NewFileStore(t.TempDir())
+			require.NoError(t, err)
+			return s
+		},
+	}
+	for name, newStore := range stores {
+		newStore := newStore
+		t.Run(name, func(t *testing.T) {
+			_, blob := newTestServer(t, newStore(t), "")
+			ctx := context.Background()
+
+			data := []byte("batch data")
+			ref, err := blob.Store(ctx, data)
+			require.NoError(t, err)
+			require.Equal(t, Key(data), string(ref))
+
+			got, err := blob.Retrieve(ctx, ref)
+			require.NoError(t, err)
+			require.Equal(t, data, got)
+
+			_, err = blob.Retrieve(ctx, []byte(Key([]byte("unknown"))))
+			require.ErrorContains(t, err, "404")
+		})
+	}
+}
+
+func TestServerFileStorePersists(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileStore(dir)
+	require.NoError(t, err)
+	_, blob := newTestServer(t, store, "")
+	ref, err := blob.Store(context.Background(), []byte("persisted"))
+	require.NoError(t, err)
+
+	store, err = NewFileStore(dir)
+	require.NoError(t, err)
+	_, blob = newTestServer(t, store, "")
+	got, err := blob.Retrieve(context.Background(), ref)
+	require.NoError(t, err)
+	require.Equal(t, []byte("persisted"), got)
+}
+
+func TestServerAuth(t *testing.T) {
+	srv, blob := newTestServer(t, NewMemoryStore(), "secret")
+	ref, err := blob.Store(context.Background(), []byte("data"))
+	require.NoError(t, err)
+
+	ts := httptest.NewServer(srv)
+	t.Cleanup(ts.Close)
+	unauthenticated := da.NewHTTPBlob(da.CelestiaPrefix, da.HTTPBlobConfig{URL: ts.URL})
+	_, err = unauthenticated.Retrieve(context.Background(), ref)
+	require.ErrorContains(t, err, "401")
+}
+
+func TestServerFailureInjection(t *testing.T) {
+	srv, blob := newTestServer(t, NewMemoryStore(), "")
+	ctx := context.Background()
+	ref, err := blob.Store(ctx, []byte("data"))
+	require.NoError(t, err)
+
+	srv.SetFailureRate(1)
+	_, err = blob.Store(ctx, []byte("more data"))
+	require.ErrorContains(t, err, "500")
+	_, err = blob.Retrieve(ctx, ref)
+	require.ErrorContains(t, err, "500")
+
+	srv.SetFailureRate(0)
+	_, err = blob.Retrieve(ctx, ref)
+	require.NoError(t, err)
+}
+
+func TestServerWithholding(t *testing.T) {
+	srv, blob := newTestServer(t, NewMemoryStore(), "")
+	ctx := context.Background()
+	refA, err := blob.Store(ctx, []byte("a"))
+	require.NoError(t, err)
+	refB, err := blob.Store(ctx, []byte("b"))
+	require.NoError(t, err)
+
+	srv.Withhold(string(refA))
+	_, err = blob.Retrieve(ctx, refA)
+	require.ErrorContains(t, err, "404")
+	_, err = blob.Retrieve(ctx, refB)
+	require.NoError(t, err)
+
+	srv.WithholdAll()
+	_, err = blob.Retrieve(ctx, refB)
+	require.ErrorContains(t, err, "404")
+	// Blobs are still accepted while withholding.
+	refC, err := blob.Store(ctx, []byte("c"))
+	require.NoError(t, err)
+
+	srv.Release()
+	for _, ref := range [][]byte{refA, refB, refC} {
+		_, err = blob.Retrieve(ctx, ref)
+		require.NoError(t, err)
+	}
+}
+
+func TestServerInvalidKey(t *testing.T) {
+	dir := t.TempDir()
+	blobDir := filepath.Join(dir, "blobs")
+	store, err := NewFileStore(blobDir)
+	require.NoError(t, err)
+	secret := filepath.Join(dir, "secret")
+	require.NoError(t, os.WriteFile(secret, []byte("secret"), 0o644))
+	srv := NewServer(testlog.Logger(t, log.LvlInfo), store, "")
+
+	key := Key([]byte("data"))
+	for _, path := range []string{
+		"/get../secret",
+		"/get/../secret",
+		"/get" + secret,
+		"/get" + strings.ToUpper(key),
+		"/get" + key[:62],
+		"/get" + key + "00",
+	} {
+		rec := httptest.NewRecorder()
+		srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://da"+path, nil))
+		require.Equal(t, http.StatusBadRequest, rec.Code, path)
+		require.NotContains(t, rec.Body.String(), "secret", path)
+	}
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://da/get"+key, nil))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestServerStartStop(t *testing.T) {
+	cfg := &Config{ListenAddr: "127.0.0.1", ListenPort: 0}
+	srv, err := NewServerFromConfig(testlog.Logger(t, log.LvlInfo), cfg)
+	require.NoError(t, err)
+	require.NoError(t, srv.Start())
+	blob := da.NewHTTPBlob(da.EigenPrefix, da.HTTPBlobConfig{URL: srv.Endpoint()})
+	ref, err := blob.Store(context.Background(), []byte("data"))
+	require.NoError(t, err)
+	got, err := blob.Retrieve(context.Background(), ref)
+	require.NoError(t, err)
+	require.Equal(t, []byte("data"), got)
+	require.NoError(t, srv.Stop(context.Background()))
+}
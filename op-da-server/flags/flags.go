@@ -0,0 +1,68 @@
+package flags
+
+import (
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+	oplog "github.com/ethereum-optimism/optimism/op-service/log"
+	"github.com/urfave/cli"
+)
+
+const envPrefix = "OP_DA_SERVER"
+
+const (
+	HTTPAddrFlagName    = "http.addr"
+	HTTPPortFlagName    = "http.port"
+	DataDirFlagName     = "data-dir"
+	AuthTokenFlagName   = "auth-token"
+	LatencyFlagName     = "latency"
+	FailureRateFlagName = "failure-rate"
+)
+
+var (
+	HTTPAddrFlag = cli.StringFlag{
+		Name:   HTTPAddrFlagName,
+		Usage:  "Address the server should listen on",
+		Value:  "0.0.0.0",
+		EnvVar: opservice.PrefixEnvVar(envPrefix, "HTTP_ADDR"),
+	}
+	HTTPPortFlag = cli.IntFlag{
+		Name:   HTTPPortFlagName,
+		Usage:  "Port the server should listen on",
+		Value:  26658,
+		EnvVar: opservice.PrefixEnvVar(envPrefix, "HTTP_PORT"),
+	}
+	DataDirFlag = cli.StringFlag{
+		Name:   DataDirFlagName,
+		Usage:  "Directory to store blobs in. Blobs are kept in memory if empty",
+		EnvVar: opservice.PrefixEnvVar(envPrefix, "DATA_DIR"),
+	}
+	AuthTokenFlag = cli.StringFlag{
+		Name:   AuthTokenFlagName,
+		Usage:  "Bearer token required on every request. No authentication if empty",
+		EnvVar: opservice.PrefixEnvVar(envPrefix, "AUTH_TOKEN"),
+	}
+	LatencyFlag = cli.DurationFlag{
+		Name:   LatencyFlagName,
+		Usage:  "Latency added to every response",
+		EnvVar: opservice.PrefixEnvVar(envPrefix, "LATENCY"),
+	}
+	FailureRateFlag = cli.Float64Flag{
+		Name:   FailureRateFlagName,
+		Usage:  "Fraction of requests, between 0 and 1, that fail with an internal server error",
+		EnvVar: opservice.PrefixEnvVar(envPrefix, "FAILURE_RATE"),
+	}
+)
+
+var Flags []cli.Flag
+
+func init() {
+	Flags = []cli.Flag{
+		HTTPAddrFlag,
+		HTTPPortFlag,
+		DataDirFlag,
+		AuthTokenFlag,
+		LatencyFlag,
+		FailureRateFlag,
+	}
+
+	Flags = append(Flags, oplog.CLIFlags(envPrefix)...)
+}
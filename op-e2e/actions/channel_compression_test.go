@@ -0,0 +1,71 @@
+package actions
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-e2e/e2eutils"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+)
+
+// TestChannelCompression checks that channels compressed with algorithms other than zlib are only
+// derived once they are included on L1 after the channel compression upgrade activation.
+func TestChannelCompression(gt *testing.T) {
+	for _, algo := range derive.CompressionAlgos {
+		algo := algo
+		gt.Run(algo.String(), func(gt *testing.T) {
+			t := NewDefaultTesting(gt)
+			dp := e2eutils.MakeDeployParams(t, defaultRollupTestParams)
+			// activate at the second L1 block
+			offset := hexutil.Uint64(24)
+			dp.DeployConfig.ChannelCompressionTimeOffset = &offset
+			sd := e2eutils.Setup(t, dp, defaultAlloc)
+			log := testlog.Logger(t, log.LvlDebug)
+			miner, seqEngine, sequencer := setupSequencerTest(t, sd, log)
+			_, verifier := setupVerifier(t, sd, log, miner.L1Client(t, sd.RollupCfg))
+			batcherCfg := &BatcherCfg{
+				MinL1TxSize:     0,
+				MaxL1TxSize:     128_000,
+				BatcherKey:      dp.Secrets.Batcher,
+				CompressionAlgo: algo,
+			}
+
+			sequencer.ActL2PipelineFull(t)
+			verifier.ActL2PipelineFull(t)
+
+			sequencer.ActL2StartBlock(t)
+			sequencer.ActL2EndBlock(t)
+
+			submitBatch := func() {
+				batcher := NewL2Batcher(log, sd.RollupCfg, batcherCfg, sequencer.RollupClient(), miner.EthClient(), seqEngine.EthClient())
+				batcher.ActL2BatchBuffer(t)
+				batcher.ActL2ChannelClose(t)
+				batcher.ActL2BatchSubmit(t)
+				miner.ActL1StartBlock(12)(t)
+				miner.ActL1IncludeTx(dp.Addresses.Batcher)(t)
+				miner.ActL1EndBlock(t)
+				verifier.ActL1HeadSignal(t)
+				verifier.ActL2PipelineFull(t)
+			}
+
+			// before the activation, only zlib channels are derived
+			submitBatch()
+			require.False(t, sd.RollupCfg.IsChannelCompression(miner.l1Chain.CurrentBlock().Time))
+			if algo == derive.Zlib {
+				require.Equal(t, uint64(1), verifier.SyncStatus().SafeL2.Number)
+				return
+			}
+			require.Equal(t, uint64(0), verifier.SyncStatus().SafeL2.Number, "channel must be dropped before the activation")
+
+			// after the activation, channels are derived with the algorithm of their version byte
+			submitBatch()
+			require.True(t, sd.RollupCfg.IsChannelCompression(miner.l1Chain.CurrentBlock().Time))
+			require.Equal(t, uint64(1), verifier.SyncStatus().SafeL2.Number)
+			require.Equal(t, sequencer.L2Unsafe().Hash, verifier.SyncStatus().SafeL2.Hash)
+		})
+	}
+}
@@ -0,0 +1,382 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-da-server/daserver"
+	"github.com/ethereum-optimism/optimism/op-service/da"
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
+)
+
+var errDAWithheld = errors.New("DA data withheld")
+
+// DAFaults injects faults into the retrieval of data from a fake DA backend, per reference to the data.
+// It is embedded in the fake DA chain, so tests can withhold, delay or corrupt the data the batcher stored,
+// and applied to the blobs of the fake blob store.
+type DAFaults struct {
+	mu        sync.Mutex
+	withheld  map[string]bool
+	delayed   map[string]int
+	corrupted map[string]bool
+}
+
+func newDAFaults() DAFaults {
+	return DAFaults{
+		withheld:  make(map[string]bool),
+		delayed:   make(map[string]int),
+		corrupted: make(map[string]bool),
+	}
+}
+
+// Withhold makes the referenced data unavailable, until it is released.
+func (f *DAFaults) Withhold(ref []byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.withheld[string(ref)] = true
+}
+
+// Release makes withheld data available again.
+func (f *DAFaults) Release(ref []byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.withheld, string(ref))
+}
+
+// Delay makes the next n retrievals of the referenced data fail, as if the data has not propagated yet.
+func (f *DAFaults) Delay(ref []byte, n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.delayed[string(ref)] = n
+}
+
+// Corrupt makes retrievals of the referenced data return data that does not match what was stored,
+// until it is repaired.
+func (f *DAFaults) Corrupt(ref []byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.corrupted[string(ref)] = true
+}
+
+// Repair makes retrievals of corrupted data return the stored data again.
+func (f *DAFaults) Repair(ref []byte) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.corrupted, string(ref))
+}
+
+// apply applies the faults of the referenced data to a retrieval of it.
+func (f *DAFaults) apply(ref []byte, data []byte) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := string(ref)
+	if f.withheld[key] {
+		return nil, errDAWithheld
+	}
+	if f.delayed[key] > 0 {
+		f.delayed[key]--
+		return nil, fmt.Errorf("DA data delayed: %w", ethereum.NotFound)
+	}
+	if f.corrupted[key] {
+		out := make([]byte, len(data)+1)
+		copy(out, data)
+		out[len(data)] = 0xff
+		return out, nil
+	}
+	return data, nil
+}
+
+// FakeDAChain is an in-memory EVM chain used for data availability, like the Polygon chain.
+// It backs the real Polygon DA backend: it is both the chain that the backend reads from,
+// and the transaction manager that the backend sends data transactions with.
+// Every data transaction is included in a new DA block right away. Tests add confirmations by mining
+// more DA blocks, and can rewind the DA chain to drop or re-include data transactions.
+type FakeDAChain struct {
+	DAFaults
+
+	log   log.Logger
+	inbox common.Address
+
+	mu     sync.Mutex
+	nonce  uint64
+	blocks [][]common.Hash // transactions by DA block number, block 0 is genesis
+	txs    map[common.Hash]*types.Transaction
+	incl   map[common.Hash]uint64 // block number that includes each canonical transaction
+	refs   [][]byte
+	// rewound are the transactions that were dropped by the latest rewind, in order.
+	rewound []common.Hash
+}
+
+var (
+	_ da.DAChain      = (*FakeDAChain)(nil)
+	_ txmgr.TxManager = (*FakeDAChain)(nil)
+)
+
+func NewFakeDAChain(log log.Logger) *FakeDAChain {
+	return &FakeDAChain{
+		DAFaults: newDAFaults(),
+		log:      log,
+		inbox:    common.Address{'D', 'A'},
+		blocks:   [][]common.Hash{nil},
+		txs:      make(map[common.Hash]*types.Transaction),
+		incl:     make(map[common.Hash]uint64),
+	}
+}
+
+// Backend returns the Polygon DA backend, storing data on and retrieving it from this chain.
+func (c *FakeDAChain) Backend() *da.Polygon {
+	return da.NewPolygon(c, c.inbox, c)
+}
+
+// Refs returns the references to all data that was stored on the chain, in order.
+func (c *FakeDAChain) Refs() [][]byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([][]byte(nil), c.refs...)
+}
+
+// LastRef returns the reference to the data that was stored last.
+func (c *FakeDAChain) LastRef(t Testing) []byte {
+	refs := c.Refs()
+	require.NotEmpty(t, refs, "no data stored on DA chain")
+	return refs[len(refs)-1]
+}
+
+// Head returns the number of the latest DA block.
+func (c *FakeDAChain) Head() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return uint64(len(c.blocks) - 1)
+}
+
+func (c *FakeDAChain) From() common.Address {
+	return common.Address{'D', 'A', 'S', 'E', 'N', 'D', 'E', 'R'}
+}
+
+// Send includes the data transaction in a new DA block.
+func (c *FakeDAChain) Send(_ context.Context, candidate txmgr.TxCandidate) (*types.Receipt, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	tx := types.NewTx(&types.LegacyTx{
+		Nonce: c.nonce,
+		To:    candidate.To,
+		Gas:   candidate.GasLimit,
+		Data:  candidate.TxData,
+	})
+	c.nonce++
+	c.txs[tx.Hash()] = tx
+	c.refs = append(c.refs, tx.Hash().Bytes())
+	c.includeLocked([]common.Hash{tx.Hash()})
+	c.log.Info("included DA transaction", "tx", tx.Hash(), "block", len(c.blocks)-1)
+	return c.receiptLocked(tx.Hash()), nil
+}
+
+func (c *FakeDAChain) includeLocked(txs []common.Hash) {
+	num := uint64(len(c.blocks))
+	c.blocks = append(c.blocks, txs)
+	for _, h := range txs {
+		c.incl[h] = num
+	}
+}
+
+func (c *FakeDAChain) receiptLocked(h common.Hash) *types.Receipt {
+	num, ok := c.incl[h]
+	if !ok {
+		return nil
+	}
+	return &types.Receipt{
+		Status:      types.ReceiptStatusSuccessful,
+		TxHash:      h,
+		BlockHash:   crypto.Keccak256Hash(big.NewInt(int64(num)).Bytes()),
+		BlockNumber: new(big.Int).SetUint64(num),
+	}
+}
+
+func (c *FakeDAChain) TransactionByHash(_ context.Context, hash common.Hash) (*types.Transaction, error) {
+	c.mu.Lock()
+	tx, ok := c.txs[hash]
+	_, canonical := c.incl[hash]
+	c.mu.Unlock()
+	if !ok || !canonical {
+		return nil, ethereum.NotFound
+	}
+	data, err := c.apply(hash.Bytes(), tx.Data())
+	if err != nil {
+		return nil, err
+	}
+	return types.NewTx(&types.LegacyTx{Nonce: tx.Nonce(), To: tx.To(), Gas: tx.Gas(), Data: data}), nil
+}
+
+func (c *FakeDAChain) TransactionReceipt(_ context.Context, hash common.Hash) (*types.Receipt, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.receiptLocked(hash), nil
+}
+
+func (c *FakeDAChain) BlockNumber(_ context.Context) (uint64, error) {
+	return c.Head(), nil
+}
+
+// ActDABlock mines an empty DA block, adding a confirmation to all included data.
+func (c *FakeDAChain) ActDABlock(t Testing) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.includeLocked(nil)
+}
+
+// ActDABlocks mines n empty DA blocks.
+func (c *FakeDAChain) ActDABlocks(n uint64) Action {
+	return func(t Testing) {
+		for i := uint64(0); i < n; i++ {
+			c.ActDABlock(t)
+		}
+	}
+}
+
+// ActDARewindDepth rewinds the DA chain by the given number of blocks. The data transactions of the
+// rewound blocks are dropped: they cannot be retrieved, until they are re-included with ActDAReincludeRewound.
+func (c *FakeDAChain) ActDARewindDepth(depth uint64) Action {
+	return func(t Testing) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if depth >= uint64(len(c.blocks)) {
+			t.InvalidAction("cannot rewind DA chain by %d blocks, head is %d", depth, len(c.blocks)-1)
+			return
+		}
+		keep := uint64(len(c.blocks)) - depth
+		c.rewound = nil
+		for _, txs := range c.blocks[keep:] {
+			for _, h := range txs {
+				delete(c.incl, h)
+				c.rewound = append(c.rewound, h)
+			}
+		}
+		c.blocks = c.blocks[:keep]
+		c.log.Info("rewound DA chain", "head", keep-1, "dropped_txs", len(c.rewound))
+	}
+}
+
+// ActDAReincludeRewound includes the data transactions that were dropped by the latest rewind in a new DA block.
+func (c *FakeDAChain) ActDAReincludeRewound(t Testing) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.rewound) == 0 {
+		t.InvalidAction("no rewound DA transactions to re-include")
+		return
+	}
+	c.includeLocked(c.rewound)
+	c.rewound = nil
+}
+
+// FakeBlobStore is an in-memory blob server, like a Celestia, EigenDA or NEAR DA gateway:
+// the mock DA server of op-da-server, over a memory store. On top of the faults the server injects,
+// like withholding blobs, retrievals of stored blobs can be delayed or corrupted.
+// Stored blobs are final right away: blob stores have no notion of DA-layer blocks.
+type FakeBlobStore struct {
+	*daserver.Server
+	store *faultyBlobStore
+}
+
+func NewFakeBlobStore(t Testing, log log.Logger) *FakeBlobStore {
+	store := &faultyBlobStore{
+		MemoryStore: daserver.NewMemoryStore(),
+		faults:      newDAFaults(),
+		retrievals:  make(map[string]int),
+	}
+	server := daserver.NewServer(log, store, "")
+	require.NoError(t, server.Start())
+	t.Cleanup(func() {
+		_ = server.Stop(context.Background())
+	})
+	return &FakeBlobStore{Server: server, store: store}
+}
+
+// Backend returns the blob server DA backend with the given prefix, storing data on and retrieving it from this store.
+func (s *FakeBlobStore) Backend(prefix byte) *da.HTTPBlob {
+	return da.NewHTTPBlob(prefix, da.HTTPBlobConfig{URL: s.Endpoint()})
+}
+
+// Refs returns the references to all blobs that were stored, in order.
+func (s *FakeBlobStore) Refs() [][]byte {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	return append([][]byte(nil), s.store.refs...)
+}
+
+// LastRef returns the reference to the blob that was stored last.
+func (s *FakeBlobStore) LastRef(t Testing) []byte {
+	refs := s.Refs()
+	require.NotEmpty(t, refs, "no blob stored")
+	return refs[len(refs)-1]
+}
+
+// Retrievals returns the number of reads of the referenced blob from the store, including delayed ones.
+// Blobs withheld by the server are not read.
+func (s *FakeBlobStore) Retrievals(ref []byte) int {
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	return s.store.retrievals[string(ref)]
+}
+
+// Delay makes the next n retrievals of the referenced blob fail, as if the blob has not propagated yet.
+func (s *FakeBlobStore) Delay(ref []byte, n int) {
+	s.store.faults.Delay(ref, n)
+}
+
+// Corrupt makes retrievals of the referenced blob return data that does not match what was stored,
+// until it is repaired.
+func (s *FakeBlobStore) Corrupt(ref []byte) {
+	s.store.faults.Corrupt(ref)
+}
+
+// Repair makes retrievals of a corrupted blob return the stored data again.
+func (s *FakeBlobStore) Repair(ref []byte) {
+	s.store.faults.Repair(ref)
+}
+
+// faultyBlobStore is the memory store of the FakeBlobStore. It records the stored blobs,
+// and applies the faults of the blobs to their retrieval.
+type faultyBlobStore struct {
+	*daserver.MemoryStore
+	faults DAFaults
+
+	mu         sync.Mutex
+	refs       [][]byte
+	retrievals map[string]int
+}
+
+func (s *faultyBlobStore) Put(key string, data []byte) error {
+	if err := s.MemoryStore.Put(key, data); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.refs = append(s.refs, []byte(key))
+	return nil
+}
+
+func (s *faultyBlobStore) Get(key string) ([]byte, error) {
+	s.mu.Lock()
+	s.retrievals[key]++
+	s.mu.Unlock()
+	data, err := s.MemoryStore.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err = s.faults.apply([]byte(key), data)
+	if err != nil {
+		// served as not found, like a blob that the server does not have (yet)
+		return nil, fmt.Errorf("%w: %v", daserver.ErrNotFound, err)
+	}
+	return data, nil
+}
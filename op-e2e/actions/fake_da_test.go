@@ -0,0 +1,130 @@
+package actions
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-e2e/e2eutils"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+	"github.com/ethereum-optimism/optimism/op-service/da"
+)
+
+// setupDATest sets up a sequencer, and a verifier that resolves batch data on the given DA backend,
+// and returns a function that batches one new L2 block through the DA backend, and anchors it on L1.
+func setupDATest(t Testing, backend da.DataAvailability) (*L2Sequencer, *L2Verifier, func()) {
+	dp := e2eutils.MakeDeployParams(t, defaultRollupTestParams)
+	sd := e2eutils.Setup(t, dp, defaultAlloc)
+	log := testlog.Logger(t, log.LvlDebug)
+	miner, seqEngine, sequencer := setupSequencerTest(t, sd, log)
+	_, verifier := setupVerifier(t, sd, log, miner.L1Client(t, sd.RollupCfg), backend)
+	batcher := NewL2Batcher(log, sd.RollupCfg, &BatcherCfg{
+		MinL1TxSize: 0,
+		MaxL1TxSize: 128_000,
+		BatcherKey:  dp.Secrets.Batcher,
+		DA:          backend,
+	}, sequencer.RollupClient(), miner.EthClient(), seqEngine.EthClient())
+
+	sequencer.ActL2PipelineFull(t)
+	verifier.ActL2PipelineFull(t)
+
+	submitBatch := func() {
+		sequencer.ActL2StartBlock(t)
+		sequencer.ActL2EndBlock(t)
+		batcher.ActSubmitAll(t)
+		miner.ActL1StartBlock(12)(t)
+		miner.ActL1IncludeTx(dp.Addresses.Batcher)(t)
+		miner.ActL1EndBlock(t)
+		verifier.ActL1HeadSignal(t)
+	}
+	return sequencer, verifier, submitBatch
+}
+
+// TestDAChainConfirmations checks that batch data on a DA chain is only derived
+// once it is confirmed deep enough on the DA chain.
+func TestDAChainConfirmations(gt *testing.T) {
+	t := NewDefaultTesting(gt)
+	daChain := NewFakeDAChain(testlog.Logger(t, log.LvlInfo))
+	sequencer, verifier, submitBatch := setupDATest(t, daChain.Backend())
+
+	submitBatch()
+	daChain.ActDABlocks(derive.NumConfirmationsDA - 1)(t)
+	verifier.ActL2PipelineFull(t)
+	require.Equal(t, uint64(0), verifier.SyncStatus().SafeL2.Number, "DA data is not confirmed deep enough yet")
+
+	daChain.ActDABlock(t)
+	verifier.ActL2PipelineFull(t)
+	require.Equal(t, sequencer.L2Unsafe(), verifier.SyncStatus().SafeL2)
+}
+
+// TestDAChainReorg checks that the verifier waits for DA data that was dropped by a reorg of the DA chain,
+// and derives it once it is re-included and confirmed deep enough again.
+func TestDAChainReorg(gt *testing.T) {
+	t := NewDefaultTesting(gt)
+	daChain := NewFakeDAChain(testlog.Logger(t, log.LvlInfo))
+	sequencer, verifier, submitBatch := setupDATest(t, daChain.Backend())
+
+	submitBatch()
+	daChain.ActDABlocks(derive.NumConfirmationsDA)(t)
+	// the DA block with the data is reorged out, before the verifier derives from it
+	daChain.ActDARewindDepth(derive.NumConfirmationsDA + 1)(t)
+	verifier.ActL2PipelineFull(t)
+	require.Equal(t, uint64(0), verifier.SyncStatus().SafeL2.Number, "DA data was reorged out")
+
+	daChain.ActDAReincludeRewound(t)
+	daChain.ActDABlocks(derive.NumConfirmationsDA - 1)(t)
+	verifier.ActL2PipelineFull(t)
+	require.Equal(t, uint64(0), verifier.SyncStatus().SafeL2.Number, "re-included DA data is not confirmed deep enough yet")
+
+	daChain.ActDABlock(t)
+	verifier.ActL2PipelineFull(t)
+	require.Equal(t, sequencer.L2Unsafe(), verifier.SyncStatus().SafeL2)
+}
+
+// TestBlobStoreWithholding checks that the verifier waits for withheld blobs.
+func TestBlobStoreWithholding(gt *testing.T) {
+	t := NewDefaultTesting(gt)
+	store := NewFakeBlobStore(t, testlog.Logger(t, log.LvlInfo))
+	sequencer, verifier, submitBatch := setupDATest(t, store.Backend(da.CelestiaPrefix))
+
+	submitBatch()
+	store.Withhold(string(store.LastRef(t)))
+	verifier.ActL2PipelineFull(t)
+	require.Equal(t, uint64(0), verifier.SyncStatus().SafeL2.Number, "blob is withheld")
+
+	store.Release()
+	verifier.ActL2PipelineFull(t)
+	require.Equal(t, sequencer.L2Unsafe(), verifier.SyncStatus().SafeL2)
+}
+
+// TestBlobStoreDelay checks that the verifier retries the retrieval of blobs that are not available yet.
+func TestBlobStoreDelay(gt *testing.T) {
+	t := NewDefaultTesting(gt)
+	store := NewFakeBlobStore(t, testlog.Logger(t, log.LvlInfo))
+	sequencer, verifier, submitBatch := setupDATest(t, store.Backend(da.CelestiaPrefix))
+
+	submitBatch()
+	store.Delay(store.LastRef(t), 4)
+	verifier.ActL2PipelineFull(t)
+	require.Equal(t, sequencer.L2Unsafe(), verifier.SyncStatus().SafeL2)
+	require.Equal(t, 5, store.Retrievals(store.LastRef(t)), "blob must be retrieved until it is available")
+}
+
+// TestBlobStoreCorruption checks that blobs that do not match the commitment posted to L1 are rejected,
+// and that the verifier derives the blob once the blob store serves the committed data.
+func TestBlobStoreCorruption(gt *testing.T) {
+	t := NewDefaultTesting(gt)
+	store := NewFakeBlobStore(t, testlog.Logger(t, log.LvlInfo))
+	sequencer, verifier, submitBatch := setupDATest(t, store.Backend(da.EigenPrefix))
+
+	submitBatch()
+	store.Corrupt(store.LastRef(t))
+	verifier.ActL2PipelineFull(t)
+	require.Equal(t, uint64(0), verifier.SyncStatus().SafeL2.Number, "corrupted blob must be rejected")
+
+	store.Repair(store.LastRef(t))
+	verifier.ActL2PipelineFull(t)
+	require.Equal(t, sequencer.L2Unsafe(), verifier.SyncStatus().SafeL2)
+}
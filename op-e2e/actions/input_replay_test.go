@@ -0,0 +1,84 @@
+package actions
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-e2e/e2eutils"
+	"github.com/ethereum-optimism/optimism/op-node/cmd/replay"
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+	"github.com/ethereum-optimism/optimism/op-service/da"
+)
+
+// TestInputArchiveReplay checks that the derivation inputs recorded by a verifier reproduce the same safe heads
+// when replayed, both against a fresh engine and against the in-memory engine of the fault proof program.
+func TestInputArchiveReplay(gt *testing.T) {
+	t := NewDefaultTesting(gt)
+	dp := e2eutils.MakeDeployParams(t, defaultRollupTestParams)
+	sd := e2eutils.Setup(t, dp, defaultAlloc)
+	sd.RollupCfg.DAType = da.CelestiaType
+	log := testlog.Logger(t, log.LvlDebug)
+	miner, seqEngine, sequencer := setupSequencerTest(t, sd, log)
+	store := NewFakeBlobStore(t, log)
+
+	archive := derive.NewMemoryInputArchive(log)
+	require.NoError(t, archive.WriteRollupConfig(sd.RollupCfg))
+	_, verifier := setupVerifier(t, sd, log, archive.RecordL1(miner.L1Client(t, sd.RollupCfg)),
+		archive.RecordDA(store.Backend(da.CelestiaPrefix)))
+	batcher := NewL2Batcher(log, sd.RollupCfg, &BatcherCfg{
+		MinL1TxSize: 0,
+		MaxL1TxSize: 128_000,
+		BatcherKey:  dp.Secrets.Batcher,
+		DA:          store.Backend(da.CelestiaPrefix),
+	}, sequencer.RollupClient(), miner.EthClient(), seqEngine.EthClient())
+
+	sequencer.ActL2PipelineFull(t)
+	verifier.ActL2PipelineFull(t)
+	for i := 0; i < 3; i++ {
+		miner.ActEmptyBlock(t)
+		sequencer.ActL1HeadSignal(t)
+		sequencer.ActBuildToL1Head(t)
+		batcher.ActSubmitAll(t)
+		miner.ActL1StartBlock(12)(t)
+		miner.ActL1IncludeTx(dp.Addresses.Batcher)(t)
+		miner.ActL1EndBlock(t)
+		verifier.ActL1HeadSignal(t)
+		verifier.ActL2PipelineFull(t)
+	}
+	safeHead := verifier.SyncStatus().SafeL2
+	require.Equal(t, sequencer.L2Unsafe(), safeHead)
+	require.NotZero(t, safeHead.Number)
+
+	// every replay must write the same safe heads, ending at the safe head of the verifier
+	var expected []byte
+	checkReplay := func(engine derive.Engine) {
+		var out bytes.Buffer
+		replayed, err := replay.Replay(t.Ctx(), log, sd.RollupCfg, archive, engine, &out)
+		require.NoError(t, err)
+		require.Equal(t, safeHead, replayed)
+
+		lines := bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n"))
+		var last eth.SafeHeadResponse
+		require.NoError(t, json.Unmarshal(lines[len(lines)-1], &last))
+		require.Equal(t, safeHead.ID(), last.SafeHead)
+		require.Equal(t, verifier.SyncStatus().CurrentL1.ID(), last.L1Block)
+		if expected == nil {
+			expected = out.Bytes()
+		} else {
+			require.Equal(t, string(expected), out.String(), "replays must be deterministic")
+		}
+	}
+
+	freshEngine := NewL2Engine(t, log, sd.L2Cfg, sd.RollupCfg.Genesis.L1, e2eutils.WriteDefaultJWT(t))
+	checkReplay(freshEngine.EngineClient(t, sd.RollupCfg))
+
+	memEngine, err := replay.NewMemoryEngine(log, sd.RollupCfg, sd.L2Cfg)
+	require.NoError(t, err)
+	checkReplay(memEngine)
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/ethereum-optimism/optimism/op-node/eth"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum-optimism/optimism/op-service/da"
 )
 
 type SyncStatusAPI interface {
@@ -44,6 +45,15 @@ type BatcherCfg struct {
 	BatcherKey *ecdsa.PrivateKey
 
 	GarbageCfg *GarbageChannelCfg
+
+	// CompressionAlgo of the channel data, defaults to zlib.
+	// It is used regardless of the channel compression upgrade activation.
+	CompressionAlgo derive.CompressionAlgo
+
+	// DA is the data availability backend to store the channel frames on.
+	// The L1 batch inbox transactions then only carry the reference to the data.
+	// If nil, the frames are posted inline as calldata.
+	DA da.DataAvailability
 }
 
 // L2Batcher buffers and submits L2 batches to L1.
@@ -138,6 +148,7 @@ func (s *L2Batcher) Buffer(t Testing) error {
 				TargetFrameSize:  s.l2BatcherCfg.MaxL1TxSize,
 				TargetNumFrames:  1,
 				ApproxComprRatio: 1,
+				Algo:             s.l2BatcherCfg.CompressionAlgo,
 			})
 			require.NoError(t, e, "failed to create compressor")
 			ch, err = derive.NewChannelOut(c)
@@ -179,8 +190,8 @@ func (s *L2Batcher) ActL2BatchSubmit(t Testing, txOpts ...func(tx *types.Dynamic
 	// Collect the output frame
 	data := new(bytes.Buffer)
 	data.WriteByte(derive.DerivationVersion0)
-	// subtract one, to account for the version byte
-	if _, err := s.l2ChannelOut.OutputFrame(data, s.l2BatcherCfg.MaxL1TxSize-1); err == io.EOF {
+	// subtract two, to account for the DA prefix and version byte
+	if _, err := s.l2ChannelOut.OutputFrame(data, s.l2BatcherCfg.MaxL1TxSize-2); err == io.EOF {
 		s.l2ChannelOut = nil
 		s.l2Submitting = false
 	} else if err != nil {
@@ -188,6 +199,16 @@ func (s *L2Batcher) ActL2BatchSubmit(t Testing, txOpts ...func(tx *types.Dynamic
 		t.Fatalf("failed to output channel data to frame: %v", err)
 	}
 
+	// Post the frame inline as calldata, or store it on the DA backend and post the reference to it
+	var inboxData []byte
+	if backend := s.l2BatcherCfg.DA; backend != nil && !da.IsInline(backend) {
+		ref, err := backend.Store(t.Ctx(), data.Bytes())
+		require.NoError(t, err, "need to store frame on DA backend")
+		inboxData = da.EncodePayload(backend.Prefix(), data.Bytes(), ref)
+	} else {
+		inboxData = da.EncodePayload(da.CalldataPrefix, data.Bytes(), data.Bytes())
+	}
+
 	nonce, err := s.l1.PendingNonceAt(t.Ctx(), s.batcherAddr)
 	require.NoError(t, err, "need batcher nonce")
 
@@ -202,7 +223,7 @@ func (s *L2Batcher) ActL2BatchSubmit(t Testing, txOpts ...func(tx *types.Dynamic
 		To:        &s.rollupCfg.BatchInboxAddress,
 		GasTipCap: gasTipCap,
 		GasFeeCap: gasFeeCap,
-		Data:      data.Bytes(),
+		Data:      inboxData,
 	}
 	for _, opt := range txOpts {
 		opt(rawTx)
@@ -232,8 +253,8 @@ func (s *L2Batcher) ActL2BatchSubmitGarbage(t Testing, kind GarbageKind) {
 	data := new(bytes.Buffer)
 	data.WriteByte(derive.DerivationVersion0)
 
-	// subtract one, to account for the version byte
-	if _, err := s.l2ChannelOut.OutputFrame(data, s.l2BatcherCfg.MaxL1TxSize-1); err == io.EOF {
+	// subtract two, to account for the DA prefix and version byte
+	if _, err := s.l2ChannelOut.OutputFrame(data, s.l2BatcherCfg.MaxL1TxSize-2); err == io.EOF {
 		s.l2ChannelOut = nil
 		s.l2Submitting = false
 	} else if err != nil {
@@ -288,7 +309,7 @@ func (s *L2Batcher) ActL2BatchSubmitGarbage(t Testing, kind GarbageKind) {
 		To:        &s.rollupCfg.BatchInboxAddress,
 		GasTipCap: gasTipCap,
 		GasFeeCap: gasFeeCap,
-		Data:      outputFrame,
+		Data:      append([]byte{da.CalldataPrefix}, outputFrame...),
 	}
 	gas, err := core.IntrinsicGas(rawTx.Data, nil, false, true, true, false)
 	require.NoError(t, err, "need to compute intrinsic gas")
@@ -14,11 +14,13 @@ import (
 	"github.com/ethereum-optimism/optimism/op-node/client"
 	"github.com/ethereum-optimism/optimism/op-node/eth"
 	"github.com/ethereum-optimism/optimism/op-node/node"
+	"github.com/ethereum-optimism/optimism/op-node/node/safedb"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/driver"
 	"github.com/ethereum-optimism/optimism/op-node/sources"
 	"github.com/ethereum-optimism/optimism/op-node/testutils"
+	"github.com/ethereum-optimism/optimism/op-service/da"
 )
 
 // L2Verifier is an actor that functions like a rollup node,
@@ -39,6 +41,8 @@ type L2Verifier struct {
 
 	l2PipelineIdle bool
 	l2Building     bool
+	// l2PipelineTempErrs counts the consecutive pipeline steps that failed with a temporary error.
+	l2PipelineTempErrs int
 
 	rollupCfg *rollup.Config
 
@@ -55,9 +59,14 @@ type L2API interface {
 	GetProof(ctx context.Context, address common.Address, storage []common.Hash, blockTag string) (*eth.AccountResult, error)
 }
 
-func NewL2Verifier(t Testing, log log.Logger, l1 derive.L1Fetcher, eng L2API, cfg *rollup.Config) *L2Verifier {
+// NewL2Verifier creates a verifier that derives the L2 chain from the data posted to L1,
+// resolving references to data on the given DA backends, next to calldata.
+func NewL2Verifier(t Testing, log log.Logger, l1 derive.L1Fetcher, eng L2API, cfg *rollup.Config, daBackends ...da.DataAvailability) *L2Verifier {
 	metrics := &testutils.TestDerivationMetrics{}
-	pipeline := derive.NewDerivationPipeline(log, cfg, l1, eng, metrics)
+	daSources, err := da.NewRegistry(append([]da.DataAvailability{da.NewCalldata()}, daBackends...)...)
+	require.NoError(t, err)
+	safeHeads := safedb.NewMemorySafeDB(log)
+	pipeline := derive.NewDerivationPipeline(log, cfg, l1, daSources, eng, metrics, safeHeads)
 	pipeline.Reset()
 
 	rollupNode := &L2Verifier{
@@ -79,7 +88,7 @@ func NewL2Verifier(t Testing, log log.Logger, l1 derive.L1Fetcher, eng L2API, cf
 	apis := []rpc.API{
 		{
 			Namespace:     "optimism",
-			Service:       node.NewNodeAPI(cfg, eng, backend, log, m),
+			Service:       node.NewNodeAPI(cfg, eng, backend, derive.NewDAConfirmationsChecker(log, cfg, l1, daSources), safeHeads, log, m),
 			Public:        true,
 			Authenticated: false,
 		},
@@ -191,6 +200,9 @@ func (s *L2Verifier) ActL1FinalizedSignal(t Testing) {
 	s.derivation.Finalize(finalized)
 }
 
+// maxL2PipelineTempErrs is the number of consecutive temporary errors after which the pipeline is considered stuck.
+const maxL2PipelineTempErrs = 100
+
 // ActL2PipelineStep runs one iteration of the L2 derivation pipeline
 func (s *L2Verifier) ActL2PipelineStep(t Testing) {
 	if s.l2Building {
@@ -200,6 +212,11 @@ func (s *L2Verifier) ActL2PipelineStep(t Testing) {
 
 	s.l2PipelineIdle = false
 	err := s.derivation.Step(t.Ctx())
+	if err != nil && errors.Is(err, derive.ErrTemporary) {
+		s.l2PipelineTempErrs++
+	} else {
+		s.l2PipelineTempErrs = 0
+	}
 	if err == io.EOF {
 		s.l2PipelineIdle = true
 		return
@@ -211,6 +228,12 @@ func (s *L2Verifier) ActL2PipelineStep(t Testing) {
 		return
 	} else if err != nil && errors.Is(err, derive.ErrTemporary) {
 		s.log.Warn("Derivation process temporary error", "err", err)
+		// The pipeline is stuck if the error persists, e.g. when the data it derives from is not available:
+		// consider it idle, so the test can change the conditions before running it again.
+		if s.l2PipelineTempErrs >= maxL2PipelineTempErrs {
+			s.l2PipelineTempErrs = 0
+			s.l2PipelineIdle = true
+		}
 		return
 	} else if err != nil && errors.Is(err, derive.ErrCritical) {
 		t.Fatalf("derivation failed critically: %v", err)
@@ -10,13 +10,14 @@ import (
 	"github.com/ethereum-optimism/optimism/op-e2e/e2eutils"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
 	"github.com/ethereum-optimism/optimism/op-node/testlog"
+	"github.com/ethereum-optimism/optimism/op-service/da"
 )
 
-func setupVerifier(t Testing, sd *e2eutils.SetupData, log log.Logger, l1F derive.L1Fetcher) (*L2Engine, *L2Verifier) {
+func setupVerifier(t Testing, sd *e2eutils.SetupData, log log.Logger, l1F derive.L1Fetcher, daBackends ...da.DataAvailability) (*L2Engine, *L2Verifier) {
 	jwtPath := e2eutils.WriteDefaultJWT(t)
 	engine := NewL2Engine(t, log, sd.L2Cfg, sd.RollupCfg.Genesis.L1, jwtPath)
 	engCl := engine.EngineClient(t, sd.RollupCfg)
-	verifier := NewL2Verifier(t, log, l1F, engCl, sd.RollupCfg)
+	verifier := NewL2Verifier(t, log, l1F, engCl, sd.RollupCfg, daBackends...)
 	return engine, verifier
 }
 
@@ -90,3 +91,54 @@ func TestL2Verifier_SequenceWindow(gt *testing.T) {
 	got := miner.l1Chain.GetBlockByHash(miner.l1Chain.GetBlockByHash(verifier.SyncStatus().SafeL2.L1Origin.Hash).Hash())
 	require.Equal(t, reorgL1Block.Hash(), got.Hash(), "must have reorged L2 chain to the new L1 chain")
 }
+
+// TestL2Verifier_SafeHeadDB checks that the safe head derived from each L1 block is recorded,
+// and that the recorded safe heads are replaced after an L1 reorg.
+func TestL2Verifier_SafeHeadDB(gt *testing.T) {
+	t := NewDefaultTesting(gt)
+	p := &e2eutils.TestParams{
+		MaxSequencerDrift:   10,
+		SequencerWindowSize: 24,
+		ChannelTimeout:      10,
+	}
+	dp := e2eutils.MakeDeployParams(t, p)
+	sd := e2eutils.Setup(t, dp, defaultAlloc)
+	log := testlog.Logger(t, log.LvlDebug)
+	miner, _, verifier := setupVerifierOnlyTest(t, sd, log)
+	miner.ActL1SetFeeRecipient(common.Address{'A'})
+	rollupClient := verifier.RollupClient()
+
+	requireSafeHeadAtL1Head := func() {
+		l1Head := miner.l1Chain.CurrentBlock().Number.Uint64()
+		out, err := rollupClient.SafeHeadAtL1Block(t.Ctx(), l1Head)
+		require.NoError(t, err)
+		require.Equal(t, verifier.SyncStatus().SafeL2.ID(), out.SafeHead)
+		require.LessOrEqual(t, out.L1Block.Number, l1Head)
+	}
+
+	// the genesis safe head is derived from the L1 genesis block
+	verifier.ActL2PipelineFull(t)
+	out, err := rollupClient.SafeHeadAtL1Block(t.Ctx(), 0)
+	require.NoError(t, err)
+	require.Equal(t, sd.RollupCfg.Genesis.L2, out.SafeHead)
+	require.Equal(t, sd.RollupCfg.Genesis.L1, out.L1Block)
+
+	for miner.l1Chain.CurrentBlock().Number.Uint64() < sd.RollupCfg.SeqWindowSize*2 {
+		miner.ActL1StartBlock(10)(t)
+		miner.ActL1EndBlock(t)
+		verifier.ActL2PipelineFull(t)
+		requireSafeHeadAtL1Head()
+	}
+	preReorg := verifier.SyncStatus().SafeL2
+
+	// reorg L1 as deep as a sequence window, this reorgs the safe L2 chain
+	miner.ActL1RewindDepth(sd.RollupCfg.SeqWindowSize)(t)
+	miner.ActL1SetFeeRecipient(common.Address{'B'})
+	for miner.l1Chain.CurrentBlock().Number.Uint64() < sd.RollupCfg.SeqWindowSize*2+1 {
+		miner.ActL1StartBlock(10)(t)
+		miner.ActL1EndBlock(t)
+	}
+	verifier.ActL2PipelineFull(t)
+	require.NotEqual(t, preReorg.Hash, verifier.SyncStatus().SafeL2.Hash, "must have reorged the safe L2 chain")
+	requireSafeHeadAtL1Head()
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/ethereum-optimism/optimism/op-node/sources"
 	proposermetrics "github.com/ethereum-optimism/optimism/op-proposer/metrics"
 	l2os "github.com/ethereum-optimism/optimism/op-proposer/proposer"
+	"github.com/ethereum-optimism/optimism/op-service/da"
 	oplog "github.com/ethereum-optimism/optimism/op-service/log"
 
 	"github.com/docker/docker/api/types"
@@ -334,8 +335,10 @@ func TestMigration(t *testing.T) {
 		L1EthRpc:           forkedL1URL,
 		L2EthRpc:           gethNode.WSEndpoint(),
 		RollupRpc:          rollupNode.HTTPEndpoint(),
+		L1EthDAType:        da.CalldataType,
 		MaxChannelDuration: 1,
 		MaxL1TxSize:        120_000,
+		DAAnchorMinBatch:   1,
 		CompressorConfig: compressor.CLIConfig{
 			TargetL1TxSizeBytes: 100_000,
 			TargetNumFrames:     1,
@@ -28,6 +28,7 @@ import (
 	batchermetrics "github.com/ethereum-optimism/optimism/op-batcher/metrics"
 	"github.com/ethereum-optimism/optimism/op-bindings/predeploys"
 	"github.com/ethereum-optimism/optimism/op-chain-ops/genesis"
+	"github.com/ethereum-optimism/optimism/op-da-server/daserver"
 	"github.com/ethereum-optimism/optimism/op-e2e/e2eutils"
 	"github.com/ethereum-optimism/optimism/op-node/chaincfg"
 	"github.com/ethereum-optimism/optimism/op-node/eth"
@@ -40,6 +41,7 @@ import (
 	"github.com/ethereum-optimism/optimism/op-node/testlog"
 	proposermetrics "github.com/ethereum-optimism/optimism/op-proposer/metrics"
 	l2os "github.com/ethereum-optimism/optimism/op-proposer/proposer"
+	"github.com/ethereum-optimism/optimism/op-service/da"
 	oplog "github.com/ethereum-optimism/optimism/op-service/log"
 	"github.com/ethereum-optimism/optimism/op-service/txmgr"
 )
@@ -165,6 +167,7 @@ func DefaultSystemConfig(t *testing.T) SystemConfig {
 			"sequencer": testlog.Logger(t, log.LvlInfo).New("role", "sequencer"),
 			"batcher":   testlog.Logger(t, log.LvlInfo).New("role", "batcher"),
 			"proposer":  testlog.Logger(t, log.LvlCrit).New("role", "proposer"),
+			"da":        testlog.Logger(t, log.LvlInfo).New("role", "da"),
 		},
 		GethOptions:           map[string][]GethOption{},
 		P2PTopology:           nil, // no P2P connectivity by default
@@ -215,6 +218,9 @@ type SystemConfig struct {
 
 	// Explicitly disable batcher, for tests that rely on unsafe L2 payloads
 	DisableBatcher bool
+
+	// Explicitly disable proposer, for tests that propose outputs themselves
+	DisableProposer bool
 }
 
 type System struct {
@@ -232,6 +238,8 @@ type System struct {
 	L2OutputSubmitter *l2os.L2OutputSubmitter
 	BatchSubmitter    *bss.BatchSubmitter
 	Mocknet           mocknet.Mocknet
+	// DAServer is the mock DA blob server, if the rollup uses a DA backend served over HTTP.
+	DAServer *daserver.Server
 }
 
 func (sys *System) NodeEndpoint(name string) string {
@@ -255,6 +263,11 @@ func (sys *System) Close() {
 		node.Close()
 	}
 	sys.Mocknet.Close()
+	if sys.DAServer != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = sys.DAServer.Stop(ctx)
+	}
 }
 
 type systemConfigHook func(sCfg *SystemConfig, s *System)
@@ -310,6 +323,9 @@ func (cfg SystemConfig) Start(_opts ...SystemConfigOption) (*System, error) {
 			for _, node := range sys.Nodes {
 				node.Close()
 			}
+			if sys.DAServer != nil {
+				_ = sys.DAServer.Stop(context.Background())
+			}
 		}
 	}()
 
@@ -380,6 +396,9 @@ func (cfg SystemConfig) Start(_opts ...SystemConfigOption) (*System, error) {
 			DepositContractAddress: predeploys.DevOptimismPortalAddr,
 			L1SystemConfigAddress:  predeploys.DevSystemConfigAddr,
 			RegolithTime:           cfg.DeployConfig.RegolithTime(uint64(cfg.DeployConfig.L1GenesisBlockTimestamp)),
+			ChannelCompressionTime: cfg.DeployConfig.ChannelCompressionTime(uint64(cfg.DeployConfig.L1GenesisBlockTimestamp)),
+			DACommitmentTime:       cfg.DeployConfig.DACommitmentTime(uint64(cfg.DeployConfig.L1GenesisBlockTimestamp)),
+			DAType:                 cfg.DeployConfig.DAType,
 		}
 	}
 	defaultConfig := makeRollupConfig()
@@ -508,6 +527,18 @@ func (cfg SystemConfig) Start(_opts ...SystemConfigOption) (*System, error) {
 	snapLog := log.New()
 	snapLog.SetHandler(log.DiscardHandler())
 
+	// DA server
+	var daConfig da.CLIConfig
+	switch da.TypeOrDefault(cfg.DeployConfig.DAType) {
+	case da.CelestiaType, da.EigenType, da.NearDAType:
+		sys.DAServer = daserver.NewServer(cfg.Loggers["da"], daserver.NewMemoryStore(), "")
+		if err := sys.DAServer.Start(); err != nil {
+			didErrAfterStart = true
+			return nil, fmt.Errorf("unable to start DA server: %w", err)
+		}
+		daConfig.Server = sys.DAServer.Endpoint()
+	}
+
 	// Rollup nodes
 
 	// Ensure we are looping through the nodes in alphabetical order
@@ -522,6 +553,7 @@ func (cfg SystemConfig) Start(_opts ...SystemConfigOption) (*System, error) {
 		nodeConfig := cfg.Nodes[name]
 		c := *nodeConfig // copy
 		c.Rollup = makeRollupConfig()
+		c.DA = daConfig
 
 		if p, ok := p2pNodes[name]; ok {
 			c.P2P = p
@@ -572,24 +604,26 @@ func (cfg SystemConfig) Start(_opts ...SystemConfigOption) (*System, error) {
 	}
 
 	// L2Output Submitter
-	sys.L2OutputSubmitter, err = l2os.NewL2OutputSubmitterFromCLIConfig(l2os.CLIConfig{
-		L1EthRpc:          sys.Nodes["l1"].WSEndpoint(),
-		RollupRpc:         sys.RollupNodes["sequencer"].HTTPEndpoint(),
-		L2OOAddress:       predeploys.DevL2OutputOracleAddr.String(),
-		PollInterval:      50 * time.Millisecond,
-		TxMgrConfig:       newTxMgrConfig(sys.Nodes["l1"].WSEndpoint(), cfg.Secrets.Proposer),
-		AllowNonFinalized: cfg.NonFinalizedProposals,
-		LogConfig: oplog.CLIConfig{
-			Level:  "info",
-			Format: "text",
-		},
-	}, sys.cfg.Loggers["proposer"], proposermetrics.NoopMetrics)
-	if err != nil {
-		return nil, fmt.Errorf("unable to setup l2 output submitter: %w", err)
-	}
+	if !sys.cfg.DisableProposer {
+		sys.L2OutputSubmitter, err = l2os.NewL2OutputSubmitterFromCLIConfig(l2os.CLIConfig{
+			L1EthRpc:          sys.Nodes["l1"].WSEndpoint(),
+			RollupRpc:         sys.RollupNodes["sequencer"].HTTPEndpoint(),
+			L2OOAddress:       predeploys.DevL2OutputOracleAddr.String(),
+			PollInterval:      50 * time.Millisecond,
+			TxMgrConfig:       newTxMgrConfig(sys.Nodes["l1"].WSEndpoint(), cfg.Secrets.Proposer),
+			AllowNonFinalized: cfg.NonFinalizedProposals,
+			LogConfig: oplog.CLIConfig{
+				Level:  "info",
+				Format: "text",
+			},
+		}, sys.cfg.Loggers["proposer"], proposermetrics.NoopMetrics)
+		if err != nil {
+			return nil, fmt.Errorf("unable to setup l2 output submitter: %w", err)
+		}
 
-	if err := sys.L2OutputSubmitter.Start(); err != nil {
-		return nil, fmt.Errorf("unable to start l2 output submitter: %w", err)
+		if err := sys.L2OutputSubmitter.Start(); err != nil {
+			return nil, fmt.Errorf("unable to start l2 output submitter: %w", err)
+		}
 	}
 
 	// Batch Submitter
@@ -597,9 +631,12 @@ func (cfg SystemConfig) Start(_opts ...SystemConfigOption) (*System, error) {
 		L1EthRpc:               sys.Nodes["l1"].WSEndpoint(),
 		L2EthRpc:               sys.Nodes["sequencer"].WSEndpoint(),
 		RollupRpc:              sys.RollupNodes["sequencer"].HTTPEndpoint(),
+		L1EthDAType:            da.TypeOrDefault(cfg.DeployConfig.DAType),
+		DAConfig:               daConfig,
 		MaxPendingTransactions: 1,
 		MaxChannelDuration:     1,
 		MaxL1TxSize:            120_000,
+		DAAnchorMinBatch:       1,
 		CompressorConfig: compressor.CLIConfig{
 			TargetL1TxSizeBytes: 100_000,
 			TargetNumFrames:     1,
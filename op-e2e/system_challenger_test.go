@@ -0,0 +1,164 @@
+package op_e2e
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-bindings/bindings"
+	"github.com/ethereum-optimism/optimism/op-bindings/predeploys"
+	"github.com/ethereum-optimism/optimism/op-challenger/challenger"
+	challengerconfig "github.com/ethereum-optimism/optimism/op-challenger/config"
+	challengermetrics "github.com/ethereum-optimism/optimism/op-challenger/metrics"
+	"github.com/ethereum-optimism/optimism/op-e2e/e2eutils"
+	"github.com/ethereum-optimism/optimism/op-node/client"
+	"github.com/ethereum-optimism/optimism/op-node/sources"
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+	oplog "github.com/ethereum-optimism/optimism/op-service/log"
+	opmetrics "github.com/ethereum-optimism/optimism/op-service/metrics"
+	oppprof "github.com/ethereum-optimism/optimism/op-service/pprof"
+	oprpc "github.com/ethereum-optimism/optimism/op-service/rpc"
+)
+
+// TestChallengerChallengesInvalidOutput proposes a valid and an invalid output,
+// and checks that the challenger creates a dispute game for the invalid output only.
+func TestChallengerChallengesInvalidOutput(t *testing.T) {
+	InitParallel(t)
+
+	cfg := DefaultSystemConfig(t)
+	cfg.DisableProposer = true
+
+	sys, err := cfg.Start()
+	require.Nil(t, err, "Error starting up system")
+	defer sys.Close()
+
+	l1Client := sys.Clients["l1"]
+	rollupRPCClient, err := rpc.DialContext(context.Background(), sys.RollupNodes["verifier"].HTTPEndpoint())
+	require.Nil(t, err)
+	rollupClient := sources.NewRollupClient(client.NewBaseRPCClient(rollupRPCClient))
+
+	// The dispute game factory is not deployed in the devnet. Deploy a stand-in that accepts any call,
+	// and logs the calldata, so the dispute games the challenger creates can be inspected through its logs.
+	dgfAddr := deployCalldataLogger(t, l1Client, cfg)
+	txMgrCfg := newTxMgrConfig(sys.Nodes["l1"].WSEndpoint(), cfg.Secrets.Bob)
+	challengerCfg := challengerconfig.NewConfig(
+		sys.Nodes["l1"].WSEndpoint(),
+		sys.RollupNodes["verifier"].HTTPEndpoint(),
+		predeploys.DevL2OutputOracleAddr,
+		dgfAddr,
+		2*time.Second,
+		&txMgrCfg,
+		&oprpc.CLIConfig{},
+		&oplog.CLIConfig{},
+		&opmetrics.CLIConfig{},
+		&oppprof.CLIConfig{},
+	)
+	challengerCfg.PollInterval = 100 * time.Millisecond
+	logger := testlog.Logger(t, log.LvlInfo).New("role", "challenger")
+	c, err := challenger.NewChallenger(*challengerCfg, logger, challengermetrics.NoopMetrics)
+	require.Nil(t, err)
+	require.Nil(t, c.Start())
+	defer c.Stop()
+
+	l2oo, err := bindings.NewL2OutputOracle(predeploys.DevL2OutputOracleAddr, l1Client)
+	require.Nil(t, err)
+	opts, err := bind.NewKeyedTransactorWithChainID(cfg.Secrets.Proposer, cfg.L1ChainIDBig())
+	require.Nil(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	propose := func(valid bool) (*big.Int, [32]byte, *types.Receipt) {
+		next, err := l2oo.NextBlockNumber(&bind.CallOpts{Context: ctx})
+		require.Nil(t, err)
+		require.Nil(t, waitForSafeHead(ctx, next.Uint64(), rollupClient))
+		output, err := rollupClient.OutputAtBlock(ctx, next.Uint64())
+		require.Nil(t, err)
+		root := [32]byte(output.OutputRoot)
+		if !valid {
+			root = [32]byte{0xba, 0xd0}
+		}
+		tx, err := l2oo.ProposeL2Output(opts, root, next, [32]byte{}, common.Big0)
+		require.Nil(t, err)
+		receipt, err := waitForTransaction(tx.Hash(), l1Client, 10*time.Duration(cfg.DeployConfig.L1BlockTime)*time.Second)
+		require.Nil(t, err)
+		require.Equal(t, types.ReceiptStatusSuccessful, receipt.Status, "proposal must succeed")
+		return next, root, receipt
+	}
+	_, _, validReceipt := propose(true)
+	invalidBlock, invalidRoot, _ := propose(false)
+
+	// Find the first dispute game the challenger created after the proposals.
+	var challenge *types.Transaction
+	var challengeBlock uint64
+	nextL1Block := validReceipt.BlockNumber.Uint64()
+	err = e2eutils.WaitFor(ctx, 500*time.Millisecond, func() (bool, error) {
+		head, err := l1Client.BlockNumber(ctx)
+		if err != nil {
+			return false, err
+		}
+		for ; nextL1Block <= head; nextL1Block++ {
+			block, err := l1Client.BlockByNumber(ctx, new(big.Int).SetUint64(nextL1Block))
+			if err != nil {
+				return false, err
+			}
+			for _, tx := range block.Transactions() {
+				if to := tx.To(); to != nil && *to == dgfAddr {
+					challenge = tx
+					challengeBlock = nextL1Block
+					return true, nil
+				}
+			}
+		}
+		return false, nil
+	})
+	require.Nil(t, err, "invalid output must be challenged")
+
+	data := challenge.Data()
+	require.Equal(t, crypto.Keccak256([]byte("create(uint8,bytes32,bytes)"))[:4], data[:4])
+	require.Equal(t, common.LeftPadBytes([]byte{2}, 32), data[4:36], "must create an attestation game")
+	require.Equal(t, invalidRoot[:], data[36:68], "must dispute the invalid output, not the valid one")
+	require.Equal(t, common.LeftPadBytes(invalidBlock.Bytes(), 32), data[len(data)-32:], "must dispute the output at its L2 block")
+
+	receipt, err := l1Client.TransactionReceipt(ctx, challenge.Hash())
+	require.Nil(t, err)
+	require.Equal(t, types.ReceiptStatusSuccessful, receipt.Status, "dispute game creation must succeed")
+	require.Equal(t, challengeBlock, receipt.BlockNumber.Uint64())
+	require.Len(t, receipt.Logs, 1)
+	require.Equal(t, dgfAddr, receipt.Logs[0].Address)
+	require.Equal(t, data, receipt.Logs[0].Data, "factory must have been called with the dispute game")
+}
+
+// deployCalldataLogger deploys a contract to L1 that emits the calldata of every call as an anonymous log.
+func deployCalldataLogger(t *testing.T, l1Client *ethclient.Client, cfg SystemConfig) common.Address {
+	// CODECOPY the 11 byte runtime and RETURN it. The runtime CALLDATACOPYs the calldata to memory and LOG0s it.
+	initCode := common.FromHex("0x600b600c600039600b6000f3" + "366000600037366000a000")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	from := crypto.PubkeyToAddress(cfg.Secrets.Alice.PublicKey)
+	nonce, err := l1Client.PendingNonceAt(ctx, from)
+	require.Nil(t, err)
+	tx := types.MustSignNewTx(cfg.Secrets.Alice, types.LatestSignerForChainID(cfg.L1ChainIDBig()), &types.DynamicFeeTx{
+		ChainID:   cfg.L1ChainIDBig(),
+		Nonce:     nonce,
+		GasTipCap: big.NewInt(10),
+		GasFeeCap: big.NewInt(200_000_000_000),
+		Gas:       100_000,
+		Data:      initCode,
+	})
+	require.Nil(t, l1Client.SendTransaction(ctx, tx))
+	receipt, err := waitForTransaction(tx.Hash(), l1Client, 10*time.Duration(cfg.DeployConfig.L1BlockTime)*time.Second)
+	require.Nil(t, err)
+	require.Equal(t, types.ReceiptStatusSuccessful, receipt.Status, "deployment must succeed")
+	return receipt.ContractAddress
+}
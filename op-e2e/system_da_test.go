@@ -0,0 +1,104 @@
+package op_e2e
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/da"
+)
+
+// daTypes are the DA backends served by the mock DA server. Polygon is not covered,
+// because it requires a DA chain rather than a blob server.
+var daTypes = []string{da.CelestiaType, da.EigenType, da.NearDAType}
+
+// TestSystemDA runs the batcher and the verifier against the mock DA server for every blob server DA type,
+// and checks that the verifier derives the L2 chain from the batch data referenced on L1.
+func TestSystemDA(t *testing.T) {
+	for _, typ := range daTypes {
+		typ := typ
+		t.Run(typ, func(t *testing.T) {
+			InitParallel(t)
+
+			cfg := DefaultSystemConfig(t)
+			cfg.DeployConfig.DAType = typ
+
+			sys, err := cfg.Start()
+			require.Nil(t, err, "Error starting up system")
+			defer sys.Close()
+			require.NotNil(t, sys.DAServer, "DA server must run for the %s DA type", typ)
+
+			l2Seq := sys.Clients["sequencer"]
+			l2Verif := sys.Clients["verifier"]
+
+			SendL2Tx(t, cfg, l2Seq, cfg.Secrets.Alice, func(opts *TxOpts) {
+				opts.ToAddr = &common.Address{0xff, 0xff}
+				opts.Value = big.NewInt(1_000_000_000)
+				opts.VerifyOnClients(l2Verif)
+			})
+
+			payloads := inboxPayloads(t, sys.Clients["l1"], cfg.DeployConfig.BatchInboxAddress)
+			require.NotEmpty(t, payloads, "batcher must reference batch data on L1")
+			for _, payload := range payloads {
+				require.Equal(t, da.Types[typ], payload.Prefix, "batch data must be stored on the %s DA backend", typ)
+			}
+		})
+	}
+}
+
+// TestSystemDAWithholding checks that the verifier stalls while the DA server withholds batch data,
+// and derives the L2 chain once the data is served again.
+func TestSystemDAWithholding(t *testing.T) {
+	InitParallel(t)
+
+	cfg := DefaultSystemConfig(t)
+	cfg.DeployConfig.DAType = da.CelestiaType
+
+	sys, err := cfg.Start()
+	require.Nil(t, err, "Error starting up system")
+	defer sys.Close()
+
+	l2Seq := sys.Clients["sequencer"]
+	l2Verif := sys.Clients["verifier"]
+
+	sys.DAServer.WithholdAll()
+	receipt := SendL2Tx(t, cfg, l2Seq, cfg.Secrets.Alice, func(opts *TxOpts) {
+		opts.ToAddr = &common.Address{0xff, 0xff}
+		opts.Value = big.NewInt(1_000_000_000)
+	})
+
+	_, err = waitForTransaction(receipt.TxHash, l2Verif, 6*time.Duration(cfg.DeployConfig.L1BlockTime)*time.Second)
+	require.Error(t, err, "verifier must not derive batch data that is withheld")
+
+	sys.DAServer.Release()
+	receiptVerif, err := waitForTransaction(receipt.TxHash, l2Verif, 10*time.Duration(cfg.DeployConfig.L1BlockTime)*time.Second)
+	require.Nil(t, err, "verifier must derive batch data once it is released")
+	require.Equal(t, receipt, receiptVerif)
+}
+
+// inboxPayloads returns the DA payloads of all transactions sent to the batch inbox on L1.
+func inboxPayloads(t *testing.T, l1Client *ethclient.Client, inbox common.Address) []da.Payload {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	head, err := l1Client.BlockNumber(ctx)
+	require.Nil(t, err)
+	var out []da.Payload
+	for n := uint64(0); n <= head; n++ {
+		block, err := l1Client.BlockByNumber(ctx, new(big.Int).SetUint64(n))
+		require.Nil(t, err)
+		for _, tx := range block.Transactions() {
+			if to := tx.To(); to == nil || *to != inbox {
+				continue
+			}
+			payloads, err := da.DecodePayloads(tx.Data())
+			require.Nil(t, err)
+			out = append(out, payloads...)
+		}
+	}
+	return out
+}
@@ -0,0 +1,433 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// FailoverMetrics tracks the requests and health of the endpoints of a FailoverRPC.
+type FailoverMetrics interface {
+	RecordRPCEndpointRequest(endpoint string, duration time.Duration, err error)
+	RecordRPCEndpointHealth(endpoint string, healthy bool)
+	RecordRPCEndpointActive(endpoint string)
+	RecordRPCCrossCheckMismatch(endpoint string)
+}
+
+type FailoverConfig struct {
+	// HealthCheckInterval is the interval at which the latest block of all endpoints is requested,
+	// to detect endpoints that are down or lagging behind, and endpoints that recovered. 0 disables health checks.
+	HealthCheckInterval time.Duration
+	// HealthCheckTimeout bounds the request of the latest block of an endpoint by a health check.
+	// An endpoint that does not respond in time fails the health check. 0 uses the HealthCheckInterval.
+	HealthCheckTimeout time.Duration
+	// MaxErrorRate is the moving average of the rate of failed requests above which an endpoint is unhealthy.
+	MaxErrorRate float64
+	// MaxHeadLag is the number of blocks an endpoint may trail behind the highest block seen on any endpoint,
+	// before it is unhealthy. 0 disables the check.
+	MaxHeadLag uint64
+	// CrossCheck enables the verification of blocks fetched by number against another healthy endpoint,
+	// before they are returned.
+	CrossCheck bool
+}
+
+func DefaultFailoverConfig() FailoverConfig {
+	return FailoverConfig{
+		HealthCheckInterval: 10 * time.Second,
+		HealthCheckTimeout:  5 * time.Second,
+		MaxErrorRate:        0.5,
+		MaxHeadLag:          5,
+		CrossCheck:          false,
+	}
+}
+
+// failoverAlpha is the weight of a new sample in the moving averages of latency and error rate.
+const failoverAlpha = 0.2
+
+type failoverEndpoint struct {
+	name string
+	rpc  RPC
+
+	// guarded by FailoverRPC.mu
+	latency     time.Duration // moving average of the latency of successful requests
+	errRate     float64       // moving average of the rate of failed requests
+	head        uint64        // latest block number seen by the last health check
+	checkFailed bool          // whether the last health check failed
+}
+
+// FailoverRPC is an RPC that spreads the risk of a faulty provider over multiple endpoints of the same chain.
+// Requests are served by the active endpoint, and retried on the other endpoints if it fails. The active endpoint
+// is replaced by the healthy endpoint with the lowest latency once its error rate, health checks or head lag
+// show it is unhealthy. Blocks fetched by number can be cross-checked against another endpoint, to not trust
+// a single provider for the canonical chain.
+type FailoverRPC struct {
+	log log.Logger
+	cfg FailoverConfig
+	m   FailoverMetrics
+
+	mu        sync.Mutex
+	endpoints []*failoverEndpoint
+	active    int
+	bestHead  uint64
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+var _ RPC = (*FailoverRPC)(nil)
+
+// NewFailoverRPC creates an RPC that fails over between the given RPCs, in the given order of preference.
+// Metrics are optional: no metrics are tracked if m is nil.
+func NewFailoverRPC(log log.Logger, rpcs []RPC, cfg FailoverConfig, m FailoverMetrics) (*FailoverRPC, error) {
+	if len(rpcs) == 0 {
+		return nil, errors.New("no RPC endpoints")
+	}
+	if cfg.HealthCheckTimeout == 0 {
+		cfg.HealthCheckTimeout = cfg.HealthCheckInterval
+	}
+	f := &FailoverRPC{
+		log: log,
+		cfg: cfg,
+		m:   m,
+	}
+	for i, cl := range rpcs {
+		f.endpoints = append(f.endpoints, &failoverEndpoint{name: fmt.Sprintf("%d", i), rpc: cl})
+	}
+	if f.m != nil {
+		f.m.RecordRPCEndpointActive(f.endpoints[0].name)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	f.cancel = cancel
+	if cfg.HealthCheckInterval > 0 {
+		f.wg.Add(1)
+		go f.healthCheckLoop(ctx)
+	}
+	return f, nil
+}
+
+func (f *FailoverRPC) Close() {
+	f.cancel()
+	f.wg.Wait()
+	for _, e := range f.endpoints {
+		e.rpc.Close()
+	}
+}
+
+func (f *FailoverRPC) CallContext(ctx context.Context, result any, method string, args ...any) error {
+	var err error
+	for _, e := range f.order() {
+		err = f.request(ctx, e, func(ctx context.Context) error {
+			return e.rpc.CallContext(ctx, result, method, args...)
+		})
+		if err == nil {
+			if f.cfg.CrossCheck && isBlockByNumberCall(method, args) {
+				return f.crossCheck(ctx, e, result, method, args...)
+			}
+			return nil
+		}
+		if !isEndpointError(ctx, err) {
+			return err
+		}
+		f.log.Warn("RPC endpoint failed, trying next endpoint", "endpoint", e.name, "method", method, "err", err)
+	}
+	return err
+}
+
+func (f *FailoverRPC) BatchCallContext(ctx context.Context, b []rpc.BatchElem) error {
+	var err error
+	for _, e := range f.order() {
+		err = f.request(ctx, e, func(ctx context.Context) error {
+			return e.rpc.BatchCallContext(ctx, b)
+		})
+		if err == nil || !isEndpointError(ctx, err) {
+			return err
+		}
+		f.log.Warn("RPC endpoint failed batch request, trying next endpoint", "endpoint", e.name, "size", len(b), "err", err)
+	}
+	return err
+}
+
+// EthSubscribe subscribes on the first endpoint that accepts the subscription. The subscription stays pinned to
+// that endpoint: it does not fail over when the endpoint becomes unhealthy, but ends with an error if the endpoint
+// drops it. Callers should resubscribe on error, e.g. with event.ResubscribeErr, to subscribe on the endpoint that
+// is active by then.
+func (f *FailoverRPC) EthSubscribe(ctx context.Context, channel any, args ...any) (ethereum.Subscription, error) {
+	var err error
+	for _, e := range f.order() {
+		var sub ethereum.Subscription
+		err = f.request(ctx, e, func(ctx context.Context) (subErr error) {
+			sub, subErr = e.rpc.EthSubscribe(ctx, channel, args...)
+			return subErr
+		})
+		if err == nil {
+			return sub, nil
+		}
+		if !isEndpointError(ctx, err) {
+			return nil, err
+		}
+		f.log.Warn("RPC endpoint failed to subscribe, trying next endpoint", "endpoint", e.name, "err", err)
+	}
+	return nil, err
+}
+
+// Active returns the name of the active endpoint, i.e. its index in the list of endpoints.
+func (f *FailoverRPC) Active() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.endpoints[f.active].name
+}
+
+// request runs the request against the endpoint, and records its latency and whether it failed.
+func (f *FailoverRPC) request(ctx context.Context, e *failoverEndpoint, fn func(ctx context.Context) error) error {
+	start := time.Now()
+	err := fn(ctx)
+	dur := time.Since(start)
+	endpointErr := err != nil && isEndpointError(ctx, err)
+	if err != nil && !endpointErr && ctx.Err() != nil {
+		return err // the request was canceled, it says nothing about the endpoint
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if endpointErr {
+		e.errRate = (1-failoverAlpha)*e.errRate + failoverAlpha
+	} else {
+		e.errRate = (1 - failoverAlpha) * e.errRate
+		if e.latency == 0 {
+			e.latency = dur
+		} else {
+			e.latency = time.Duration((1-failoverAlpha)*float64(e.latency) + failoverAlpha*float64(dur))
+		}
+	}
+	if f.m != nil {
+		var recordErr error
+		if endpointErr {
+			recordErr = err
+		}
+		f.m.RecordRPCEndpointRequest(e.name, dur, recordErr)
+	}
+	f.updateActive()
+	return err
+}
+
+// healthy returns whether the endpoint is healthy. The lock must be held.
+func (f *FailoverRPC) healthy(e *failoverEndpoint) bool {
+	if e.checkFailed || e.errRate > f.cfg.MaxErrorRate {
+		return false
+	}
+	return f.cfg.MaxHeadLag == 0 || e.head+f.cfg.MaxHeadLag >= f.bestHead
+}
+
+// updateActive replaces the active endpoint if it is unhealthy. The lock must be held.
+func (f *FailoverRPC) updateActive() {
+	active := f.endpoints[f.active]
+	if f.healthy(active) {
+		return
+	}
+	next := f.orderLocked()[0]
+	if next == active {
+		return
+	}
+	for i, e := range f.endpoints {
+		if e == next {
+			f.active = i
+		}
+	}
+	f.log.Warn("Failing over to another RPC endpoint", "from", active.name, "to", next.name,
+		"err_rate", active.errRate, "head", active.head, "best_head", f.bestHead, "check_failed", active.checkFailed)
+	if f.m != nil {
+		f.m.RecordRPCEndpointActive(next.name)
+	}
+}
+
+// order returns the endpoints in the order to try them in.
+func (f *FailoverRPC) order() []*failoverEndpoint {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.orderLocked()
+}
+
+// orderLocked orders the endpoints to try: the active endpoint if it is healthy, then the other healthy endpoints
+// by latency, and then the unhealthy endpoints by error rate, as last resort. The lock must be held.
+func (f *FailoverRPC) orderLocked() []*failoverEndpoint {
+	active := f.endpoints[f.active]
+	out := make([]*failoverEndpoint, len(f.endpoints))
+	copy(out, f.endpoints)
+	sort.SliceStable(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if ha, hb := f.healthy(a), f.healthy(b); ha != hb {
+			return ha
+		} else if !ha {
+			return a.errRate < b.errRate
+		}
+		if a == active || b == active {
+			return a == active
+		}
+		return a.latency < b.latency
+	})
+	return out
+}
+
+// crossCheck verifies the block that was fetched from the given endpoint against another healthy endpoint.
+// Endpoints whose latest block is known to be at or past the block are preferred, as other endpoints may not have
+// the block yet. If the other endpoint does not have the block, it cannot be verified, and is trusted:
+// only a different block is a mismatch.
+func (f *FailoverRPC) crossCheck(ctx context.Context, used *failoverEndpoint, result any, method string, args ...any) error {
+	expected, err := blockHash(result)
+	if err != nil {
+		return err
+	}
+	if expected == nil { // not found, nothing to check
+		return nil
+	}
+	num, err := hexutil.DecodeUint64(args[0].(string))
+	if err != nil {
+		return fmt.Errorf("invalid block number %v: %w", args[0], err)
+	}
+	var other *failoverEndpoint
+	f.mu.Lock()
+	for _, e := range f.orderLocked() {
+		if e == used || !f.healthy(e) {
+			continue
+		}
+		if e.head >= num {
+			other = e
+			break
+		}
+		if other == nil {
+			other = e
+		}
+	}
+	f.mu.Unlock()
+	if other == nil {
+		f.log.Debug("No healthy endpoint to cross-check block with", "endpoint", used.name, "block", args[0])
+		return nil
+	}
+	check := reflect.New(reflect.TypeOf(result).Elem()).Interface()
+	if err := f.request(ctx, other, func(ctx context.Context) error {
+		return other.rpc.CallContext(ctx, check, method, args...)
+	}); err != nil {
+		return fmt.Errorf("failed to cross-check block %v with endpoint %s: %w", args[0], other.name, err)
+	}
+	got, err := blockHash(check)
+	if err != nil {
+		return err
+	}
+	if got == nil {
+		f.log.Debug("Endpoint to cross-check block with does not have the block yet", "endpoint", used.name,
+			"other", other.name, "block", args[0])
+		return nil
+	}
+	if *got != *expected {
+		if f.m != nil {
+			f.m.RecordRPCCrossCheckMismatch(used.name)
+		}
+		return fmt.Errorf("block %v of endpoint %s (%s) does not match block of endpoint %s (%s)", args[0], used.name, *expected, other.name, *got)
+	}
+	return nil
+}
+
+func (f *FailoverRPC) healthCheckLoop(ctx context.Context) {
+	defer f.wg.Done()
+	ticker := time.NewTicker(f.cfg.HealthCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			f.healthCheck(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// healthCheck requests the latest block of all endpoints, and updates their health.
+func (f *FailoverRPC) healthCheck(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, e := range f.endpoints {
+		wg.Add(1)
+		go func(e *failoverEndpoint) {
+			defer wg.Done()
+			var header *types.Header
+			err := f.request(ctx, e, func(ctx context.Context) error {
+				ctx, cancel := context.WithTimeout(ctx, f.cfg.HealthCheckTimeout)
+				defer cancel()
+				return e.rpc.CallContext(ctx, &header, "eth_getBlockByNumber", "latest", false)
+			})
+			if ctx.Err() != nil {
+				return
+			}
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			e.checkFailed = err != nil || header == nil
+			if !e.checkFailed && header.Number != nil {
+				e.head = header.Number.Uint64()
+				if e.head > f.bestHead {
+					f.bestHead = e.head
+				}
+			}
+		}(e)
+	}
+	wg.Wait()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, e := range f.endpoints {
+		if f.m != nil {
+			f.m.RecordRPCEndpointHealth(e.name, f.healthy(e))
+		}
+	}
+	f.updateActive()
+}
+
+// isEndpointError returns whether the error is a failure of the endpoint, rather than an error response to the
+// request, or the cancellation of the request by the caller.
+func isEndpointError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var rpcErr rpc.Error
+	return !errors.As(err, &rpcErr)
+}
+
+// isBlockByNumberCall returns whether the call fetches a block by number, rather than by a label like "latest",
+// which may legitimately differ between endpoints.
+func isBlockByNumberCall(method string, args []any) bool {
+	if method != "eth_getBlockByNumber" || len(args) == 0 {
+		return false
+	}
+	num, ok := args[0].(string)
+	return ok && strings.HasPrefix(num, "0x")
+}
+
+// blockHash returns the hash of the block or header result, or nil if the block was not found.
+func blockHash(result any) (*common.Hash, error) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode block: %w", err)
+	}
+	var block *struct {
+		Hash *common.Hash `json:"hash"`
+	}
+	if err := json.Unmarshal(data, &block); err != nil {
+		return nil, fmt.Errorf("failed to decode block hash: %w", err)
+	}
+	if block == nil {
+		return nil, nil
+	}
+	if block.Hash == nil {
+		return nil, errors.New("block has no hash")
+	}
+	return block.Hash, nil
+}
@@ -0,0 +1,292 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+)
+
+type testFailoverMetrics struct {
+	requests   map[string]int
+	errors     map[string]int
+	healthy    map[string]bool
+	active     string
+	mismatches map[string]int
+}
+
+func newTestFailoverMetrics() *testFailoverMetrics {
+	return &testFailoverMetrics{
+		requests:   make(map[string]int),
+		errors:     make(map[string]int),
+		healthy:    make(map[string]bool),
+		mismatches: make(map[string]int),
+	}
+}
+
+func (m *testFailoverMetrics) RecordRPCEndpointRequest(endpoint string, _ time.Duration, err error) {
+	m.requests[endpoint]++
+	if err != nil {
+		m.errors[endpoint]++
+	}
+}
+
+func (m *testFailoverMetrics) RecordRPCEndpointHealth(endpoint string, healthy bool) {
+	m.healthy[endpoint] = healthy
+}
+
+func (m *testFailoverMetrics) RecordRPCEndpointActive(endpoint string) {
+	m.active = endpoint
+}
+
+func (m *testFailoverMetrics) RecordRPCCrossCheckMismatch(endpoint string) {
+	m.mismatches[endpoint]++
+}
+
+// testRPCError is an error response of the RPC server
+type testRPCError struct{}
+
+func (testRPCError) Error() string  { return "execution reverted" }
+func (testRPCError) ErrorCode() int { return 3 }
+
+// blockRPC serves headers by block argument, and nil for unknown blocks. It blocks until the request is
+// canceled if it hangs.
+type blockRPC struct {
+	RPC
+	mu      sync.Mutex
+	headers map[string]*types.Header
+	hang    bool
+	calls   map[string]int
+}
+
+func newBlockRPC() *blockRPC {
+	return &blockRPC{headers: make(map[string]*types.Header), calls: make(map[string]int)}
+}
+
+func (r *blockRPC) CallContext(ctx context.Context, result any, method string, args ...any) error {
+	r.mu.Lock()
+	hang := r.hang
+	header := r.headers[args[0].(string)]
+	r.calls[args[0].(string)]++
+	r.mu.Unlock()
+	if hang {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	*result.(**types.Header) = header
+	return nil
+}
+
+func (r *blockRPC) Close() {}
+
+func latestHeader(f *FailoverRPC) (*types.Header, error) {
+	var header *types.Header
+	err := f.CallContext(context.Background(), &header, "eth_getBlockByNumber", "latest")
+	return header, err
+}
+
+func testFailoverConfig() FailoverConfig {
+	cfg := DefaultFailoverConfig()
+	cfg.HealthCheckInterval = 0 // health checks are run by the tests
+	return cfg
+}
+
+func TestFailoverRPC_Failover(t *testing.T) {
+	rootA, rootB := common.Hash{0xaa}, common.Hash{0xbb}
+	a := &MockRPC{t: t, callResults: []*callResult{{root: rootA, error: errors.New("connection refused")}}}
+	b := &MockRPC{t: t, callResults: []*callResult{{root: rootB}}}
+	m := newTestFailoverMetrics()
+	f, err := NewFailoverRPC(testlog.Logger(t, log.LvlError), []RPC{a, b}, testFailoverConfig(), m)
+	require.NoError(t, err)
+	defer f.Close()
+	require.Equal(t, "0", m.active)
+
+	// each failed request is retried on the next endpoint
+	for i := 1; i <= 3; i++ {
+		header, err := latestHeader(f)
+		require.NoError(t, err)
+		require.Equal(t, rootB, header.Root)
+		require.Equal(t, i, a.callCount)
+		require.Equal(t, "0", f.Active(), "error rate is not high enough yet to fail over")
+	}
+	// the endpoint is replaced once its error rate is too high
+	header, err := latestHeader(f)
+	require.NoError(t, err)
+	require.Equal(t, rootB, header.Root)
+	require.Equal(t, "1", f.Active())
+	require.Equal(t, "1", m.active)
+	require.Equal(t, 4, m.errors["0"])
+	require.Equal(t, 0, m.errors["1"])
+
+	// the healthy endpoint is tried first
+	_, err = latestHeader(f)
+	require.NoError(t, err)
+	require.Equal(t, 4, a.callCount)
+	require.Equal(t, 5, b.callCount)
+
+	// if all endpoints fail, the error is returned
+	b.callResults = []*callResult{{error: errors.New("connection reset")}}
+	_, err = latestHeader(f)
+	require.ErrorContains(t, err, "connection")
+	require.Equal(t, 5, a.callCount)
+	require.Equal(t, 6, b.callCount)
+
+	f.Close()
+	require.True(t, a.closed)
+	require.True(t, b.closed)
+}
+
+func TestFailoverRPC_ErrorResponse(t *testing.T) {
+	a := &MockRPC{t: t, callResults: []*callResult{{error: testRPCError{}}}}
+	b := &MockRPC{t: t, callResults: []*callResult{{root: common.Hash{0xbb}}}}
+	m := newTestFailoverMetrics()
+	f, err := NewFailoverRPC(testlog.Logger(t, log.LvlError), []RPC{a, b}, testFailoverConfig(), m)
+	require.NoError(t, err)
+	defer f.Close()
+
+	// error responses are returned as-is, the endpoint is not at fault
+	for i := 0; i < 10; i++ {
+		_, err = latestHeader(f)
+		require.ErrorIs(t, err, testRPCError{})
+	}
+	require.Equal(t, 0, b.callCount)
+	require.Equal(t, "0", f.Active())
+	require.Equal(t, 10, m.requests["0"])
+	require.Equal(t, 0, m.errors["0"])
+
+	// canceled requests do not count against the endpoint either
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	a.callResults = []*callResult{{error: context.Canceled}}
+	var header *types.Header
+	require.ErrorIs(t, f.CallContext(ctx, &header, "eth_getBlockByNumber", "latest"), context.Canceled)
+	require.Equal(t, 0, b.callCount)
+	require.Equal(t, 10, m.requests["0"])
+}
+
+func TestFailoverRPC_HealthCheck(t *testing.T) {
+	rootA, rootB := common.Hash{0xaa}, common.Hash{0xbb}
+	a := &MockRPC{t: t, callResults: []*callResult{{error: errors.New("connection refused")}, {root: rootA}}, autopop: false}
+	b := &MockRPC{t: t, callResults: []*callResult{{root: rootB}, {error: errors.New("connection refused")}}, autopop: false}
+	m := newTestFailoverMetrics()
+	f, err := NewFailoverRPC(testlog.Logger(t, log.LvlError), []RPC{a, b}, testFailoverConfig(), m)
+	require.NoError(t, err)
+	defer f.Close()
+
+	// a failed health check makes the endpoint unhealthy immediately
+	f.healthCheck(context.Background())
+	require.Equal(t, "1", f.Active())
+	require.False(t, m.healthy["0"])
+	require.True(t, m.healthy["1"])
+	header, err := latestHeader(f)
+	require.NoError(t, err)
+	require.Equal(t, rootB, header.Root)
+
+	// the recovered endpoint becomes healthy again, but the active endpoint is kept while it is healthy
+	a.popResult()
+	f.healthCheck(context.Background())
+	require.True(t, m.healthy["0"])
+	require.Equal(t, "1", f.Active())
+
+	// and it fails over back once the active endpoint is unhealthy
+	b.popResult()
+	f.healthCheck(context.Background())
+	require.False(t, m.healthy["1"])
+	require.Equal(t, "0", f.Active())
+	require.Equal(t, "0", m.active)
+	header, err = latestHeader(f)
+	require.NoError(t, err)
+	require.Equal(t, rootA, header.Root)
+}
+
+func TestFailoverRPC_CrossCheck(t *testing.T) {
+	root := common.Hash{0xaa}
+	a := &MockRPC{t: t, callResults: []*callResult{{root: root}}, blockArg: "0x10"}
+	b := &MockRPC{t: t, callResults: []*callResult{{root: root}}, blockArg: "0x10"}
+	m := newTestFailoverMetrics()
+	cfg := testFailoverConfig()
+	cfg.CrossCheck = true
+	f, err := NewFailoverRPC(testlog.Logger(t, log.LvlError), []RPC{a, b}, cfg, m)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var header *types.Header
+	require.NoError(t, f.CallContext(context.Background(), &header, "eth_getBlockByNumber", "0x10"))
+	require.Equal(t, root, header.Root)
+	require.Equal(t, 1, a.callCount)
+	require.Equal(t, 1, b.callCount, "block must be cross-checked")
+
+	// a different block on the other endpoint is not trusted
+	b.callResults = []*callResult{{root: common.Hash{0xbb}}}
+	err = f.CallContext(context.Background(), &header, "eth_getBlockByNumber", "0x10")
+	require.ErrorContains(t, err, "does not match")
+	require.Equal(t, 1, m.mismatches["0"])
+
+	// blocks by label are not cross-checked, as endpoints may legitimately differ
+	a.blockArg, b.blockArg = "latest", "latest"
+	_, err = latestHeader(f)
+	require.NoError(t, err)
+	require.Equal(t, 3, a.callCount)
+	require.Equal(t, 2, b.callCount)
+}
+
+func TestFailoverRPC_CrossCheckLaggingEndpoint(t *testing.T) {
+	block := &types.Header{Number: big.NewInt(0x10), Root: common.Hash{0xaa}}
+	a, b, c := newBlockRPC(), newBlockRPC(), newBlockRPC()
+	a.headers["0x10"], a.headers["latest"] = block, block
+	b.headers["latest"] = &types.Header{Number: big.NewInt(0xf)}
+	c.headers["0x10"], c.headers["latest"] = block, block
+	m := newTestFailoverMetrics()
+	cfg := testFailoverConfig()
+	cfg.CrossCheck = true
+	cfg.MaxHeadLag = 0
+	f, err := NewFailoverRPC(testlog.Logger(t, log.LvlError), []RPC{a, b, c}, cfg, m)
+	require.NoError(t, err)
+	defer f.Close()
+
+	// the block is cross-checked with an endpoint that has it, rather than the lagging one
+	f.healthCheck(context.Background())
+	var header *types.Header
+	require.NoError(t, f.CallContext(context.Background(), &header, "eth_getBlockByNumber", "0x10"))
+	require.Equal(t, block.Root, header.Root)
+	require.Zero(t, b.calls["0x10"])
+	require.Equal(t, 1, c.calls["0x10"])
+
+	// a block that the other endpoint does not have cannot be verified, and is not a mismatch
+	f.mu.Lock()
+	for _, e := range f.endpoints {
+		e.head = 0
+	}
+	f.mu.Unlock()
+	delete(c.headers, "0x10")
+	require.NoError(t, f.CallContext(context.Background(), &header, "eth_getBlockByNumber", "0x10"))
+	require.Equal(t, block.Root, header.Root)
+	require.Zero(t, m.mismatches["0"])
+}
+
+func TestFailoverRPC_HealthCheckTimeout(t *testing.T) {
+	a, b := newBlockRPC(), newBlockRPC()
+	a.hang = true
+	b.headers["latest"] = &types.Header{Number: big.NewInt(1)}
+	m := newTestFailoverMetrics()
+	cfg := testFailoverConfig()
+	cfg.HealthCheckTimeout = 10 * time.Millisecond
+	f, err := NewFailoverRPC(testlog.Logger(t, log.LvlError), []RPC{a, b}, cfg, m)
+	require.NoError(t, err)
+	defer f.Close()
+
+	// a hanging endpoint fails the health check, rather than stalling it
+	f.healthCheck(context.Background())
+	require.False(t, m.healthy["0"])
+	require.True(t, m.healthy["1"])
+	require.Equal(t, "1", f.Active())
+}
@@ -22,6 +22,8 @@ type MockRPC struct {
 	callCount   int
 	autopop     bool
 	closed      bool
+	// blockArg is the expected block argument, "latest" if nil
+	blockArg any
 }
 
 type callResult struct {
@@ -40,7 +42,11 @@ func (m *MockRPC) CallContext(ctx context.Context, result any, method string, ar
 	if method != "eth_getBlockByNumber" {
 		m.t.Fatalf("invalid method %s", method)
 	}
-	if args[0] != "latest" {
+	blockArg := m.blockArg
+	if blockArg == nil {
+		blockArg = "latest"
+	}
+	if args[0] != blockArg {
 		m.t.Fatalf("invalid arg %v", args[0])
 	}
 
@@ -11,7 +11,9 @@ import (
 	"time"
 
 	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum-optimism/optimism/op-service/da"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 )
@@ -28,15 +30,29 @@ type TransactionWithMetadata struct {
 	Frames      []derive.Frame     `json:"frames"`
 	FrameErr    string             `json:"frame_parse_error"`
 	ValidFrames bool               `json:"valid_data"`
+	DA          []DALocation       `json:"da"`
 	Tx          *types.Transaction `json:"tx"`
 }
 
+// DALocation records where the data of a payload of an inbox transaction is stored.
+// The frames of the transaction are the frames of all its payloads, in order.
+type DALocation struct {
+	Type string `json:"type"`
+	// Ref is the reference to the data on the DA backend. It is omitted for calldata, which is the data itself.
+	Ref        hexutil.Bytes `json:"ref,omitempty"`
+	Commitment *common.Hash  `json:"commitment,omitempty"`
+	NumFrames  int           `json:"num_frames"`
+}
+
 type Config struct {
 	Start, End   uint64
 	ChainID      *big.Int
 	BatchInbox   common.Address
 	BatchSenders map[common.Address]struct{}
 	OutDirectory string
+	// DASources are the DA backends that inbox transactions are resolved through.
+	// Data on backends that are not configured cannot be decoded.
+	DASources *da.Registry
 }
 
 // Batches fetches & stores all transactions sent to the batch inbox address in
@@ -81,7 +97,7 @@ func fetchBatchesPerBlock(client *ethclient.Client, number *big.Int, signer type
 
 			validFrames := true
 			frameError := ""
-			frames, err := derive.ParseFrames(tx.Data())
+			frames, locations, err := resolveFrames(config.DASources, tx.Data())
 			if err != nil {
 				fmt.Printf("Found a transaction (%s) with invalid data: %v\n", tx.Hash().String(), err)
 				validFrames = false
@@ -107,6 +123,7 @@ func fetchBatchesPerBlock(client *ethclient.Client, number *big.Int, signer type
 				Frames:      frames,
 				FrameErr:    frameError,
 				ValidFrames: validFrames,
+				DA:          locations,
 			}
 			filename := path.Join(config.OutDirectory, fmt.Sprintf("%s.json", tx.Hash().String()))
 			file, err := os.Create(filename)
@@ -122,3 +139,43 @@ func fetchBatchesPerBlock(client *ethclient.Client, number *big.Int, signer type
 	}
 	return
 }
+
+// resolveFrames resolves the inbox data through the DA backends it refers to, and parses the frames
+// of the resolved data. The locations of all payloads of the inbox data are returned, even if one
+// of them cannot be resolved.
+func resolveFrames(daSources *da.Registry, data []byte) ([]derive.Frame, []DALocation, error) {
+	payloads, err := da.DecodePayloads(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	locations := make([]DALocation, len(payloads))
+	for i, payload := range payloads {
+		locations[i] = DALocation{Type: da.TypeName(payload.Prefix), Commitment: payload.Commitment}
+		if payload.Prefix != da.CalldataPrefix {
+			locations[i].Ref = payload.Ref
+		}
+	}
+	var frames []derive.Frame
+	for i, payload := range payloads {
+		backend, ok := daSources.Get(payload.Prefix)
+		if !ok {
+			return frames, locations, fmt.Errorf("no DA backend configured for %s data", locations[i].Type)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), da.DefaultRetrieveTimeout)
+		resolved, err := backend.Retrieve(ctx, payload.Ref)
+		cancel()
+		if err != nil {
+			return frames, locations, fmt.Errorf("failed to retrieve %s data: %w", locations[i].Type, err)
+		}
+		if err := payload.Verify(resolved); err != nil {
+			return frames, locations, err
+		}
+		payloadFrames, err := derive.ParseFrames(resolved)
+		if err != nil {
+			return frames, locations, err
+		}
+		locations[i].NumFrames = len(payloadFrames)
+		frames = append(frames, payloadFrames...)
+	}
+	return frames, locations, nil
+}
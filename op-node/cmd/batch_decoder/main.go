@@ -10,6 +10,7 @@ import (
 	"github.com/ethereum-optimism/optimism/op-node/cmd/batch_decoder/fetch"
 	"github.com/ethereum-optimism/optimism/op-node/cmd/batch_decoder/reassemble"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum-optimism/optimism/op-service/da"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/urfave/cli"
@@ -55,6 +56,26 @@ func main() {
 					Usage:    "L1 RPC URL",
 					EnvVar:   "L1_RPC",
 				},
+				cli.StringFlag{
+					Name:  "polygon-rpc",
+					Usage: "RPC URL of the Polygon DA chain, to resolve Polygon DA references",
+				},
+				cli.StringFlag{
+					Name:  "celestia-server",
+					Usage: "URL of the Celestia DA blob server, to resolve Celestia DA references",
+				},
+				cli.StringFlag{
+					Name:  "eigen-server",
+					Usage: "URL of the EigenDA blob server, to resolve EigenDA references",
+				},
+				cli.StringFlag{
+					Name:  "nearda-server",
+					Usage: "URL of the NEAR DA blob server, to resolve NEAR DA references",
+				},
+				cli.StringFlag{
+					Name:  "da-auth-token",
+					Usage: "Bearer token sent to the DA blob servers",
+				},
 			},
 			Action: func(cliCtx *cli.Context) error {
 				client, err := ethclient.Dial(cliCtx.String("l1"))
@@ -67,6 +88,10 @@ func main() {
 				if err != nil {
 					log.Fatal(err)
 				}
+				daSources, err := newDASources(cliCtx)
+				if err != nil {
+					log.Fatal(err)
+				}
 				config := fetch.Config{
 					Start:   uint64(cliCtx.Int("start")),
 					End:     uint64(cliCtx.Int("end")),
@@ -76,6 +101,7 @@ func main() {
 					},
 					BatchInbox:   common.HexToAddress(cliCtx.String("inbox")),
 					OutDirectory: cliCtx.String("out"),
+					DASources:    daSources,
 				}
 				totalValid, totalInvalid := fetch.Batches(client, config)
 				fmt.Printf("Fetched batches in range [%v,%v). Found %v valid & %v invalid batches\n", config.Start, config.End, totalValid, totalInvalid)
@@ -160,3 +186,29 @@ func main() {
 		log.Fatal(err)
 	}
 }
+
+// newDASources creates the DA backends that inbox transactions are resolved through:
+// calldata, and every backend whose endpoint is configured.
+func newDASources(cliCtx *cli.Context) (*da.Registry, error) {
+	backends := []da.DataAvailability{da.NewCalldata()}
+	if rpc := cliCtx.String("polygon-rpc"); rpc != "" {
+		client, err := ethclient.Dial(rpc)
+		if err != nil {
+			return nil, fmt.Errorf("failed to dial Polygon DA chain: %w", err)
+		}
+		backends = append(backends, da.NewPolygon(nil, common.Address{}, da.NewEthClientChain(client)))
+	}
+	for flag, prefix := range map[string]byte{
+		"celestia-server": da.CelestiaPrefix,
+		"eigen-server":    da.EigenPrefix,
+		"nearda-server":   da.NearDAPrefix,
+	} {
+		if url := cliCtx.String(flag); url != "" {
+			backends = append(backends, da.NewHTTPBlob(prefix, da.HTTPBlobConfig{
+				URL:       url,
+				AuthToken: cliCtx.String("da-auth-token"),
+			}))
+		}
+	}
+	return da.NewRegistry(backends...)
+}
@@ -30,6 +30,8 @@ type FrameWithMetadata struct {
 	Timestamp      uint64       `json:"timestamp"`
 	BlockHash      common.Hash  `json:"block_hash"`
 	Frame          derive.Frame `json:"frame"`
+	// DA is where the data of the frame is stored.
+	DA *fetch.DALocation `json:"da,omitempty"`
 }
 
 type Config struct {
@@ -103,7 +105,7 @@ func processFrames(id derive.ChannelID, frames []FrameWithMetadata) ChannelWithM
 	var batches []derive.BatchV1
 	invalidBatches := false
 	if ch.IsReady() {
-		br, err := derive.BatchReader(ch.Reader(), eth.L1BlockRef{})
+		br, err := derive.BatchReader(ch.Reader(), eth.L1BlockRef{}, true)
 		if err == nil {
 			for batch, err := br(); err != io.EOF; batch, err = br() {
 				if err != nil {
@@ -133,6 +135,7 @@ func processFrames(id derive.ChannelID, frames []FrameWithMetadata) ChannelWithM
 func transactionsToFrames(txns []fetch.TransactionWithMetadata) []FrameWithMetadata {
 	var out []FrameWithMetadata
 	for _, tx := range txns {
+		loc, locFrames := 0, 0
 		for _, frame := range tx.Frames {
 			fm := FrameWithMetadata{
 				TxHash:         tx.Tx.Hash(),
@@ -141,6 +144,14 @@ func transactionsToFrames(txns []fetch.TransactionWithMetadata) []FrameWithMetad
 				Timestamp:      tx.BlockTime,
 				Frame:          frame,
 			}
+			// the frames of the transaction are the frames of all its DA payloads, in order
+			for loc < len(tx.DA) && locFrames == tx.DA[loc].NumFrames {
+				loc, locFrames = loc+1, 0
+			}
+			if loc < len(tx.DA) {
+				fm.DA = &tx.DA[loc]
+				locFrames++
+			}
 			out = append(out, fm)
 		}
 	}
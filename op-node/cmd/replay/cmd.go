@@ -0,0 +1,294 @@
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/trie"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/metrics"
+	"github.com/ethereum-optimism/optimism/op-node/node"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
+	"github.com/ethereum-optimism/optimism/op-node/sources"
+	"github.com/ethereum-optimism/optimism/op-program/client/l2"
+	"github.com/ethereum-optimism/optimism/op-service/da"
+	oplog "github.com/ethereum-optimism/optimism/op-service/log"
+)
+
+// maxTemporaryErrors is the number of consecutive temporary derivation errors after which the replay is aborted.
+// The recorded inputs do not change, so a temporary error that persists will not resolve by retrying.
+const maxTemporaryErrors = 10
+
+var (
+	ArchiveFlag = cli.StringFlag{
+		Name:  "archive",
+		Usage: "Path of the derivation input archive, as recorded with --derivation-record.path",
+	}
+	L2EngineAddrFlag = cli.StringFlag{
+		Name:  "l2",
+		Usage: "Address of a fresh L2 Engine JSON-RPC endpoint to replay the derivation against",
+	}
+	L2EngineJWTSecretFlag = cli.StringFlag{
+		Name:  "l2.jwt-secret",
+		Usage: "Path to JWT secret key of the L2 Engine. Keys are 32 bytes, hex encoded in a file.",
+	}
+	L2GenesisFlag = cli.StringFlag{
+		Name:  "l2.genesis",
+		Usage: "Path to the L2 genesis file, to replay the derivation against an in-memory engine instead of an L2 Engine endpoint",
+	}
+	OutputFlag = cli.StringFlag{
+		Name:  "output",
+		Usage: "Path to write the safe head derived from each L1 block to, as JSON lines. Written to stdout if not set.",
+	}
+)
+
+var Command = cli.Command{
+	Name:  "replay",
+	Usage: "Replay the derivation from a recorded derivation input archive",
+	Description: "Runs the derivation pipeline over the L1 and DA inputs that were recorded by a rollup node, " +
+		"against a fresh L2 engine that starts from the same L2 chain as the recording node, " +
+		"and writes the safe head derived from each L1 block until the recorded inputs are exhausted.",
+	Flags:  append([]cli.Flag{ArchiveFlag, L2EngineAddrFlag, L2EngineJWTSecretFlag, L2GenesisFlag, OutputFlag}, oplog.CLIFlags("OP_NODE_REPLAY")...),
+	Action: Main,
+}
+
+func Main(ctx *cli.Context) error {
+	logCfg := oplog.ReadLocalCLIConfig(ctx)
+	if err := logCfg.Check(); err != nil {
+		return err
+	}
+	// log to stderr, the safe heads may be written to stdout
+	logger := log.New()
+	logger.SetHandler(log.LvlFilterHandler(oplog.Level(logCfg.Level),
+		log.StreamHandler(os.Stderr, oplog.Format(logCfg.Format, logCfg.Color))))
+
+	archivePath := ctx.String(ArchiveFlag.Name)
+	if archivePath == "" {
+		return errors.New("no derivation input archive specified")
+	}
+	if (ctx.String(L2EngineAddrFlag.Name) == "") == (ctx.String(L2GenesisFlag.Name) == "") {
+		return errors.New("exactly one of an L2 engine address or an L2 genesis file must be specified")
+	}
+	archive, err := derive.OpenInputArchive(logger, archivePath)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+	cfg, err := archive.RollupConfig()
+	if err != nil {
+		return err
+	}
+
+	var engine derive.Engine
+	if genesisPath := ctx.String(L2GenesisFlag.Name); genesisPath != "" {
+		genesis, err := readGenesis(genesisPath)
+		if err != nil {
+			return err
+		}
+		engine, err = NewMemoryEngine(logger, cfg, genesis)
+		if err != nil {
+			return err
+		}
+	} else {
+		secret, err := readJWTSecret(ctx.String(L2EngineJWTSecretFlag.Name))
+		if err != nil {
+			return err
+		}
+		engine, err = newEngineClient(context.Background(), logger, cfg, &node.L2EndpointConfig{
+			L2EngineAddr:      ctx.String(L2EngineAddrFlag.Name),
+			L2EngineJWTSecret: secret,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	out := io.Writer(os.Stdout)
+	if outputPath := ctx.String(OutputFlag.Name); outputPath != "" {
+		f, err := os.Create(outputPath)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	safeHead, err := Replay(context.Background(), logger, cfg, archive, engine, out)
+	if err != nil {
+		return err
+	}
+	logger.Info("Replayed derivation", "safe_head", safeHead)
+	if mem, ok := engine.(*l2.OracleEngine); ok {
+		output, err := mem.L2OutputRoot()
+		if err != nil {
+			return err
+		}
+		logger.Info("Computed output root of the safe head", "safe_head", safeHead, "output_root", output)
+	}
+	return nil
+}
+
+// Replay runs the derivation pipeline over the inputs recorded in the archive, until the recorded L1 chain is exhausted,
+// and returns the final safe head. The safe head derived from each L1 block is written to out, as JSON lines.
+// If the derivation is reset, the safe heads that are written from there on replace those of the same L1 blocks.
+func Replay(ctx context.Context, logger log.Logger, cfg *rollup.Config, archive *derive.InputArchive, engine derive.Engine, out io.Writer) (eth.L2BlockRef, error) {
+	daSources, err := replayDASources(cfg, archive)
+	if err != nil {
+		return eth.L2BlockRef{}, err
+	}
+	pipeline := derive.NewDerivationPipeline(logger, cfg, derive.NewReplayL1Fetcher(archive), daSources, engine,
+		metrics.NoopMetrics, &safeHeadWriter{enc: json.NewEncoder(out)})
+	pipeline.Reset()
+
+	temporaryErrors := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return eth.L2BlockRef{}, err
+		}
+		err := pipeline.Step(ctx)
+		if errors.Is(err, io.EOF) {
+			logger.Info("Derivation complete: reached the end of the recorded L1 chain", "origin", pipeline.Origin())
+			return pipeline.SafeL2Head(), nil
+		} else if errors.Is(err, derive.ErrReset) {
+			logger.Warn("Derivation pipeline is reset", "err", err)
+			pipeline.Reset()
+		} else if errors.Is(err, derive.ErrTemporary) {
+			temporaryErrors++
+			if temporaryErrors >= maxTemporaryErrors {
+				return eth.L2BlockRef{}, fmt.Errorf("derivation keeps failing with temporary errors: %w", err)
+			}
+			logger.Warn("Derivation temporary error", "attempts", temporaryErrors, "err", err)
+			continue
+		} else if err != nil && !errors.Is(err, derive.NotEnoughData) {
+			return eth.L2BlockRef{}, fmt.Errorf("derivation failed: %w", err)
+		}
+		temporaryErrors = 0
+	}
+}
+
+// replayDASources creates the DA backends of the rollup, serving the recorded DA data,
+// next to L1 calldata which can always be decoded.
+func replayDASources(cfg *rollup.Config, archive *derive.InputArchive) (*da.Registry, error) {
+	backends := []da.DataAvailability{da.NewCalldata()}
+	if prefix, ok := da.Types[da.TypeOrDefault(cfg.DAType)]; !ok {
+		return nil, fmt.Errorf("unknown data availability type %q", cfg.DAType)
+	} else if prefix != da.CalldataPrefix {
+		backends = append(backends, archive.ReplayDA(prefix))
+	}
+	return da.NewRegistry(backends...)
+}
+
+// safeHeadWriter writes the safe head derived from each L1 block as a JSON line.
+type safeHeadWriter struct {
+	enc *json.Encoder
+}
+
+func (w *safeHeadWriter) SafeHeadUpdated(safeHead eth.L2BlockRef, l1Head eth.BlockID) error {
+	return w.enc.Encode(eth.SafeHeadResponse{L1Block: l1Head, SafeHead: safeHead.ID()})
+}
+
+func (w *safeHeadWriter) SafeHeadReset(eth.L2BlockRef) error {
+	return nil
+}
+
+func newEngineClient(ctx context.Context, logger log.Logger, cfg *rollup.Config, endpoint *node.L2EndpointConfig) (*sources.EngineClient, error) {
+	rpc, rpcCfg, err := endpoint.Setup(ctx, logger, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup L2 execution-engine RPC client: %w", err)
+	}
+	engine, err := sources.NewEngineClient(rpc, logger, nil, rpcCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Engine client: %w", err)
+	}
+	if err := cfg.ValidateL2Config(ctx, engine); err != nil {
+		return nil, err
+	}
+	return engine, nil
+}
+
+// NewMemoryEngine creates an in-memory L2 engine, as used by the fault proof program, that starts from the L2 genesis.
+func NewMemoryEngine(logger log.Logger, cfg *rollup.Config, genesis *core.Genesis) (*l2.OracleEngine, error) {
+	db := rawdb.NewMemoryDatabase()
+	genesisBlock, err := genesis.Commit(db, trie.NewDatabase(db))
+	if err != nil {
+		return nil, fmt.Errorf("failed to commit L2 genesis: %w", err)
+	}
+	if genesisBlock.Hash() != cfg.Genesis.L2.Hash {
+		return nil, fmt.Errorf("L2 genesis block %s does not match the rollup config genesis %s", genesisBlock.Hash(), cfg.Genesis.L2.Hash)
+	}
+	chain, err := l2.NewOracleBackedL2Chain(logger, &genesisOracle{db: db}, genesis.Config, genesisBlock.Hash())
+	if err != nil {
+		return nil, err
+	}
+	return l2.NewOracleEngine(cfg, logger, chain), nil
+}
+
+// genesisOracle serves the L2 chain data that was committed to the database, i.e. the genesis block and state,
+// to the in-memory engine. The engine keeps the blocks and state it processes itself.
+type genesisOracle struct {
+	db ethdb.Database
+}
+
+var _ l2.Oracle = (*genesisOracle)(nil)
+
+func (o *genesisOracle) NodeByHash(nodeHash common.Hash) []byte {
+	data, _ := o.db.Get(nodeHash.Bytes())
+	return data
+}
+
+func (o *genesisOracle) CodeByHash(codeHash common.Hash) []byte {
+	return rawdb.ReadCode(o.db, codeHash)
+}
+
+func (o *genesisOracle) BlockByHash(blockHash common.Hash) *types.Block {
+	num := rawdb.ReadHeaderNumber(o.db, blockHash)
+	if num == nil {
+		return nil
+	}
+	return rawdb.ReadBlock(o.db, blockHash, *num)
+}
+
+func readGenesis(path string) (*core.Genesis, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open L2 genesis file: %w", err)
+	}
+	defer f.Close()
+	var genesis core.Genesis
+	if err := json.NewDecoder(f).Decode(&genesis); err != nil {
+		return nil, fmt.Errorf("failed to decode L2 genesis file: %w", err)
+	}
+	return &genesis, nil
+}
+
+func readJWTSecret(path string) ([32]byte, error) {
+	var secret [32]byte
+	if path == "" {
+		return secret, errors.New("no JWT secret of the L2 engine specified")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return secret, fmt.Errorf("failed to read JWT secret: %w", err)
+	}
+	jwtSecret := common.FromHex(strings.TrimSpace(string(data)))
+	if len(jwtSecret) != 32 {
+		return secret, fmt.Errorf("invalid JWT secret of %d bytes, expected 32", len(jwtSecret))
+	}
+	copy(secret[:], jwtSecret)
+	return secret, nil
+}
@@ -12,3 +12,22 @@ type OutputResponse struct {
 	StateRoot             common.Hash `json:"stateRoot"`
 	Status                *SyncStatus `json:"syncStatus"`
 }
+
+// DAConfirmations summarizes the data availability confirmations of the batch data
+// that was posted to L1 within a range of L1 blocks.
+type DAConfirmations struct {
+	// L1Start and L1End are the first and last L1 block of the range, inclusive.
+	L1Start BlockID `json:"l1Start"`
+	L1End   BlockID `json:"l1End"`
+	// Refs is the number of references to data on a DA backend other than L1 in the range.
+	Refs uint64 `json:"refs"`
+	// MinConfirmations is the lowest number of DA-layer confirmations of the referenced data,
+	// or the maximum uint64 value if there are no references, or only to backends without DA-layer blocks.
+	MinConfirmations uint64 `json:"minConfirmations"`
+}
+
+// SafeHeadResponse is the safe L2 head that was fully derived from the L1 chain up to and including L1Block.
+type SafeHeadResponse struct {
+	L1Block  BlockID `json:"l1Block"`
+	SafeHead BlockID `json:"safeHead"`
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/ethereum-optimism/optimism/op-node/chaincfg"
 	"github.com/ethereum-optimism/optimism/op-node/sources"
+	"github.com/ethereum-optimism/optimism/op-service/da"
 	oplog "github.com/ethereum-optimism/optimism/op-service/log"
 
 	"github.com/urfave/cli"
@@ -24,7 +25,7 @@ var (
 	/* Required Flags */
 	L1NodeAddr = cli.StringFlag{
 		Name:   "l1",
-		Usage:  "Address of L1 User JSON-RPC endpoint to use (eth namespace required)",
+		Usage:  "Address of L1 User JSON-RPC endpoint to use (eth namespace required). Multiple endpoints may be specified as a comma-separated list, in order of preference, to fail over between.",
 		Value:  "http://127.0.0.1:8545",
 		EnvVar: prefixEnvVar("L1_ETH_RPC"),
 	}
@@ -93,6 +94,17 @@ var (
 		EnvVar: prefixEnvVar("L1_HTTP_POLL_INTERVAL"),
 		Value:  time.Second * 12,
 	}
+	L1HealthCheckInterval = cli.DurationFlag{
+		Name:   "l1.health-check-interval",
+		Usage:  "Interval between health checks of the L1 endpoints, if multiple endpoints are configured. Set to 0 to disable health checks.",
+		EnvVar: prefixEnvVar("L1_HEALTH_CHECK_INTERVAL"),
+		Value:  time.Second * 10,
+	}
+	L1CrossCheck = cli.BoolFlag{
+		Name:   "l1.cross-check",
+		Usage:  "Verify L1 blocks fetched by number against another L1 endpoint, if multiple endpoints are configured.",
+		EnvVar: prefixEnvVar("L1_CROSS_CHECK"),
+	}
 	L2EngineJWTSecret = cli.StringFlag{
 		Name:        "l2.jwt-secret",
 		Usage:       "Path to JWT secret key. Keys are 32 bytes, hex encoded in a file. A new key will be generated if left empty.",
@@ -178,6 +190,33 @@ var (
 		Usage:  "Path to the snapshot log file",
 		EnvVar: prefixEnvVar("SNAPSHOT_LOG"),
 	}
+	SafeDBPath = cli.StringFlag{
+		Name:   "safedb.path",
+		Usage:  "File path used to persist the safe head derived from each L1 block. Disabled if not set.",
+		EnvVar: prefixEnvVar("SAFEDB_PATH"),
+	}
+	UnsafeJournalPath = cli.StringFlag{
+		Name:   "unsafe-journal.path",
+		Usage:  "File path used to persist buffered unsafe L2 payloads across restarts. Disabled if not set.",
+		EnvVar: prefixEnvVar("UNSAFE_JOURNAL_PATH"),
+	}
+	UnsafeJournalMaxSize = cli.Uint64Flag{
+		Name:   "unsafe-journal.max-size",
+		Usage:  "Maximum size of the unsafe payloads journal, in bytes. The lowest payloads are dropped first.",
+		EnvVar: prefixEnvVar("UNSAFE_JOURNAL_MAX_SIZE"),
+		Value:  256 * 1024 * 1024,
+	}
+	UnsafeJournalMaxAge = cli.DurationFlag{
+		Name:   "unsafe-journal.max-age",
+		Usage:  "Maximum age of payloads in the unsafe payloads journal, based on the payload timestamp. 0 to disable.",
+		EnvVar: prefixEnvVar("UNSAFE_JOURNAL_MAX_AGE"),
+		Value:  12 * time.Hour,
+	}
+	DerivationRecordPath = cli.StringFlag{
+		Name:   "derivation-record.path",
+		Usage:  "File path used to record the L1 and DA inputs of the derivation, to replay them with the replay subcommand. Disabled if not set.",
+		EnvVar: prefixEnvVar("DERIVATION_RECORD_PATH"),
+	}
 	HeartbeatEnabledFlag = cli.BoolFlag{
 		Name:   "heartbeat.enabled",
 		Usage:  "Enables or disables heartbeating",
@@ -207,12 +246,6 @@ var (
 		EnvVar:   prefixEnvVar("L2_BACKUP_UNSAFE_SYNC_RPC_TRUST_RPC"),
 		Required: false,
 	}
-	L1DAAddr = cli.StringFlag{
-		Name:   "l1-da-rpc",
-		Usage:  "Address of L1 DA JSON-RPC endpoint to use (eth namespace required)",
-		Value:  "https://polygon-mumbai.g.alchemy.com/v2/BGuEX3heS4SK2IYlKAVDEWhaVvALzO9N",
-		EnvVar: prefixEnvVar("L1_ETH_RPC"),
-	}
 )
 
 var requiredFlags = []cli.Flag{
@@ -230,6 +263,8 @@ var optionalFlags = []cli.Flag{
 	L1RPCRateLimit,
 	L1RPCMaxBatchSize,
 	L1HTTPPollInterval,
+	L1HealthCheckInterval,
+	L1CrossCheck,
 	L2EngineJWTSecret,
 	VerifierL1Confs,
 	SequencerEnabledFlag,
@@ -245,12 +280,16 @@ var optionalFlags = []cli.Flag{
 	PprofAddrFlag,
 	PprofPortFlag,
 	SnapshotLog,
+	SafeDBPath,
+	UnsafeJournalPath,
+	UnsafeJournalMaxSize,
+	UnsafeJournalMaxAge,
+	DerivationRecordPath,
 	HeartbeatEnabledFlag,
 	HeartbeatMonikerFlag,
 	HeartbeatURLFlag,
 	BackupL2UnsafeSyncRPC,
 	BackupL2UnsafeSyncRPCTrustRPC,
-	L1DAAddr,
 }
 
 // Flags contains the list of configuration options available to the binary.
@@ -259,6 +298,7 @@ var Flags []cli.Flag
 func init() {
 	optionalFlags = append(optionalFlags, p2pFlags...)
 	optionalFlags = append(optionalFlags, oplog.CLIFlags(EnvVarPrefix)...)
+	optionalFlags = append(optionalFlags, da.CLIFlags(EnvVarPrefix)...)
 	Flags = append(requiredFlags, optionalFlags...)
 }
 
@@ -65,6 +65,11 @@ type Metricer interface {
 	RecordSequencerSealingTime(duration time.Duration)
 	Document() []metrics.DocumentedMetric
 	RecordChannelInputBytes(num int)
+	RecordDAFetch(backend string, size int, duration time.Duration, err error)
+	RecordRPCEndpointRequest(endpoint string, duration time.Duration, err error)
+	RecordRPCEndpointHealth(endpoint string, healthy bool)
+	RecordRPCEndpointActive(endpoint string)
+	RecordRPCCrossCheckMismatch(endpoint string)
 	// P2P Metrics
 	SetPeerScores(scores map[string]float64)
 	ClientPayloadByNumberEvent(num uint64, resultCode byte, duration time.Duration)
@@ -136,6 +141,16 @@ type Metrics struct {
 
 	ChannelInputBytes prometheus.Counter
 
+	DAFetchDurationSeconds *prometheus.HistogramVec
+	DAFetchBytesTotal      *prometheus.CounterVec
+	DAFetchErrorsTotal     *prometheus.CounterVec
+
+	L1EndpointRequestDurationSeconds *prometheus.HistogramVec
+	L1EndpointErrorsTotal            *prometheus.CounterVec
+	L1EndpointHealthy                *prometheus.GaugeVec
+	L1EndpointActive                 *prometheus.GaugeVec
+	L1CrossCheckMismatchesTotal      *prometheus.CounterVec
+
 	registry *prometheus.Registry
 	factory  metrics.Factory
 }
@@ -342,6 +357,74 @@ func NewMetrics(procName string) *Metrics {
 			Help:      "Number of compressed bytes added to the channel",
 		}),
 
+		DAFetchDurationSeconds: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: ns,
+			Subsystem: "da",
+			Name:      "fetch_duration_seconds",
+			Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60},
+			Help:      "Histogram of the time to retrieve batch data from the data availability backend",
+		}, []string{
+			"backend",
+		}),
+		DAFetchBytesTotal: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: ns,
+			Subsystem: "da",
+			Name:      "fetch_bytes_total",
+			Help:      "Number of bytes of batch data retrieved from the data availability backend",
+		}, []string{
+			"backend",
+		}),
+		DAFetchErrorsTotal: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: ns,
+			Subsystem: "da",
+			Name:      "fetch_errors_total",
+			Help:      "Number of failures to retrieve batch data from the data availability backend",
+		}, []string{
+			"backend",
+		}),
+
+		L1EndpointRequestDurationSeconds: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: ns,
+			Subsystem: "l1_endpoint",
+			Name:      "request_duration_seconds",
+			Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+			Help:      "Histogram of the time to serve requests, per L1 RPC endpoint",
+		}, []string{
+			"endpoint",
+		}),
+		L1EndpointErrorsTotal: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: ns,
+			Subsystem: "l1_endpoint",
+			Name:      "errors_total",
+			Help:      "Number of failed requests, per L1 RPC endpoint",
+		}, []string{
+			"endpoint",
+		}),
+		L1EndpointHealthy: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: ns,
+			Subsystem: "l1_endpoint",
+			Name:      "healthy",
+			Help:      "1 if the L1 RPC endpoint passed the last health check, 0 otherwise",
+		}, []string{
+			"endpoint",
+		}),
+		L1EndpointActive: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: ns,
+			Subsystem: "l1_endpoint",
+			Name:      "active",
+			Help:      "1 for the L1 RPC endpoint that serves requests, 0 for the other endpoints",
+		}, []string{
+			"endpoint",
+		}),
+		L1CrossCheckMismatchesTotal: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: ns,
+			Subsystem: "l1_endpoint",
+			Name:      "cross_check_mismatches_total",
+			Help:      "Number of blocks of the L1 RPC endpoint that did not match the block of another endpoint",
+		}, []string{
+			"endpoint",
+		}),
+
 		P2PReqDurationSeconds: factory.NewHistogramVec(prometheus.HistogramOpts{
 			Namespace: ns,
 			Subsystem: "p2p",
@@ -663,6 +746,41 @@ func (m *Metrics) RecordChannelInputBytes(inputCompressedBytes int) {
 	m.ChannelInputBytes.Add(float64(inputCompressedBytes))
 }
 
+// RecordDAFetch records the retrieval of batch data from the data availability backend.
+func (m *Metrics) RecordDAFetch(backend string, size int, duration time.Duration, err error) {
+	m.DAFetchDurationSeconds.WithLabelValues(backend).Observe(duration.Seconds())
+	if err != nil {
+		m.DAFetchErrorsTotal.WithLabelValues(backend).Inc()
+		return
+	}
+	m.DAFetchBytesTotal.WithLabelValues(backend).Add(float64(size))
+}
+
+// RecordRPCEndpointRequest records a request to one of the L1 RPC endpoints.
+func (m *Metrics) RecordRPCEndpointRequest(endpoint string, duration time.Duration, err error) {
+	m.L1EndpointRequestDurationSeconds.WithLabelValues(endpoint).Observe(duration.Seconds())
+	if err != nil {
+		m.L1EndpointErrorsTotal.WithLabelValues(endpoint).Inc()
+	}
+}
+
+func (m *Metrics) RecordRPCEndpointHealth(endpoint string, healthy bool) {
+	if healthy {
+		m.L1EndpointHealthy.WithLabelValues(endpoint).Set(1)
+	} else {
+		m.L1EndpointHealthy.WithLabelValues(endpoint).Set(0)
+	}
+}
+
+func (m *Metrics) RecordRPCEndpointActive(endpoint string) {
+	m.L1EndpointActive.Reset()
+	m.L1EndpointActive.WithLabelValues(endpoint).Set(1)
+}
+
+func (m *Metrics) RecordRPCCrossCheckMismatch(endpoint string) {
+	m.L1CrossCheckMismatchesTotal.WithLabelValues(endpoint).Inc()
+}
+
 type noopMetricer struct{}
 
 var NoopMetrics Metricer = new(noopMetricer)
@@ -768,3 +886,18 @@ func (n *noopMetricer) PayloadsQuarantineSize(int) {
 
 func (n *noopMetricer) RecordChannelInputBytes(int) {
 }
+
+func (n *noopMetricer) RecordDAFetch(string, int, time.Duration, error) {
+}
+
+func (n *noopMetricer) RecordRPCEndpointRequest(string, time.Duration, error) {
+}
+
+func (n *noopMetricer) RecordRPCEndpointHealth(string, bool) {
+}
+
+func (n *noopMetricer) RecordRPCEndpointActive(string) {
+}
+
+func (n *noopMetricer) RecordRPCCrossCheckMismatch(string) {
+}
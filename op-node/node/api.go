@@ -20,6 +20,19 @@ type l2EthClient interface {
 	// GetProof returns a proof of the account, it may return a nil result without error if the address was not found.
 	// Optionally keys of the account storage trie can be specified to include with corresponding values in the proof.
 	GetProof(ctx context.Context, address common.Address, storage []common.Hash, blockTag string) (*eth.AccountResult, error)
+	SystemConfigByL2Hash(ctx context.Context, hash common.Hash) (eth.SystemConfig, error)
+}
+
+type daConfirmationsSource interface {
+	// DAConfirmations returns the lowest DA-layer confirmations of the data referenced by the batch inbox transactions
+	// sent by batcherAddr in the L1 blocks l1Start to l1End, inclusive.
+	DAConfirmations(ctx context.Context, batcherAddr common.Address, l1Start, l1End uint64) (*eth.DAConfirmations, error)
+}
+
+type safeDBReader interface {
+	// SafeHeadAtL1 returns the safe head that was derived from the L1 chain up to and including the given L1 block,
+	// and the L1 block at which that safe head was recorded.
+	SafeHeadAtL1(ctx context.Context, l1BlockNum uint64) (l1Block eth.BlockID, safeHead eth.BlockID, err error)
 }
 
 type driverClient interface {
@@ -69,15 +82,19 @@ type nodeAPI struct {
 	config *rollup.Config
 	client l2EthClient
 	dr     driverClient
+	da     daConfirmationsSource
+	safeDB safeDBReader
 	log    log.Logger
 	m      rpcMetrics
 }
 
-func NewNodeAPI(config *rollup.Config, l2Client l2EthClient, dr driverClient, log log.Logger, m rpcMetrics) *nodeAPI {
+func NewNodeAPI(config *rollup.Config, l2Client l2EthClient, dr driverClient, da daConfirmationsSource, safeDB safeDBReader, log log.Logger, m rpcMetrics) *nodeAPI {
 	return &nodeAPI{
 		config: config,
 		client: l2Client,
 		dr:     dr,
+		da:     da,
+		safeDB: safeDB,
 		log:    log,
 		m:      m,
 	}
@@ -130,6 +147,106 @@ func (n *nodeAPI) OutputAtBlock(ctx context.Context, number hexutil.Uint64) (*et
 	}, nil
 }
 
+// DaConfirmations returns the lowest DA-layer confirmations of the batch data that the L2 blocks start to end,
+// inclusive, were derived from. The L2 blocks must be safe.
+// If the safe head database is enabled, only the L1 blocks up to the one that the last L2 block was derived from
+// are scanned, rather than the full sequencing window.
+// It is served as optimism_daConfirmations: the RPC method name is derived from the Go method name.
+func (n *nodeAPI) DaConfirmations(ctx context.Context, start, end hexutil.Uint64) (*eth.DAConfirmations, error) {
+	recordDur := n.m.RecordRPCServerRequest("optimism_daConfirmations")
+	defer recordDur()
+
+	if end < start {
+		return nil, fmt.Errorf("invalid L2 range: end %d is before start %d", end, start)
+	}
+	startRef, _, err := n.dr.BlockRefWithStatus(ctx, uint64(start))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get L2 block ref %d: %w", start, err)
+	}
+	endRef, status, err := n.dr.BlockRefWithStatus(ctx, uint64(end))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get L2 block ref with sync status: %w", err)
+	}
+	if endRef.Number > status.SafeL2.Number {
+		return nil, fmt.Errorf("L2 block %s is not safe yet, the safe head is %s", endRef, status.SafeL2)
+	}
+
+	// The batches of the L2 blocks are included on L1 no earlier than the L1 origin of the first block,
+	// and no later than the end of the sequencing window of the last block. The blocks are safe,
+	// so their batches were also included no later than the current L1 block of the derivation.
+	l1Start := startRef.L1Origin.Number
+	l1End := endRef.L1Origin.Number + n.config.SeqWindowSize
+	if status.CurrentL1.Number < l1End {
+		l1End = status.CurrentL1.Number
+	}
+	l1End = n.safeL1End(ctx, endRef.Number, l1Start, l1End)
+
+	// The batcher may have been changed within the range, so the batch data of both batchers is checked.
+	startCfg, err := n.client.SystemConfigByL2Hash(ctx, startRef.Hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get system config of L2 block %s: %w", startRef, err)
+	}
+	endCfg, err := n.client.SystemConfigByL2Hash(ctx, endRef.Hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get system config of L2 block %s: %w", endRef, err)
+	}
+	confs, err := n.da.DAConfirmations(ctx, startCfg.BatcherAddr, l1Start, l1End)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get DA confirmations of L1 blocks %d to %d: %w", l1Start, l1End, err)
+	}
+	if endCfg.BatcherAddr != startCfg.BatcherAddr {
+		endConfs, err := n.da.DAConfirmations(ctx, endCfg.BatcherAddr, l1Start, l1End)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get DA confirmations of L1 blocks %d to %d: %w", l1Start, l1End, err)
+		}
+		confs.Refs += endConfs.Refs
+		if endConfs.MinConfirmations < confs.MinConfirmations {
+			confs.MinConfirmations = endConfs.MinConfirmations
+		}
+	}
+	return confs, nil
+}
+
+// safeL1End narrows the L1 range l1Start to l1End, inclusive, down to the first L1 block that the L2 block
+// with the given number was derived from, as recorded by the safe head database. The batch data of the L2 block
+// cannot be included any later. The range is returned unchanged if the safe head database does not know the L2 block.
+func (n *nodeAPI) safeL1End(ctx context.Context, l2Num uint64, l1Start, l1End uint64) uint64 {
+	derived := func(l1Num uint64) bool {
+		_, safeHead, err := n.safeDB.SafeHeadAtL1(ctx, l1Num)
+		return err == nil && safeHead.Number >= l2Num
+	}
+	if !derived(l1End) {
+		n.log.Debug("Safe head database does not know the L1 block that the L2 block was derived from", "l2_block", l2Num)
+		return l1End
+	}
+	// binary search for the first L1 block at which the L2 block was safe: safe heads only grow with the L1 chain
+	lo, hi := l1Start, l1End
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		if derived(mid) {
+			hi = mid
+		} else {
+			lo = mid + 1
+		}
+	}
+	return lo
+}
+
+// SafeHeadAtL1Block returns the safe head that was derived from the L1 chain up to and including the given L1 block.
+// It requires the safe head database to be enabled, and only knows the safe heads since it was enabled.
+func (n *nodeAPI) SafeHeadAtL1Block(ctx context.Context, number hexutil.Uint64) (*eth.SafeHeadResponse, error) {
+	recordDur := n.m.RecordRPCServerRequest("optimism_safeHeadAtL1Block")
+	defer recordDur()
+	l1Block, safeHead, err := n.safeDB.SafeHeadAtL1(ctx, uint64(number))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get safe head at L1 block %d: %w", number, err)
+	}
+	return &eth.SafeHeadResponse{
+		L1Block:  l1Block,
+		SafeHead: safeHead,
+	}, nil
+}
+
 func (n *nodeAPI) SyncStatus(ctx context.Context) (*eth.SyncStatus, error) {
 	recordDur := n.m.RecordRPCServerRequest("optimism_syncStatus")
 	defer recordDur()
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/ethereum-optimism/optimism/op-node/client"
@@ -32,8 +33,8 @@ type L1EndpointSetup interface {
 	// Setup a RPC client to a L1 node to pull rollup input-data from.
 	// The results of the RPC client may be trusted for faster processing, or strictly validated.
 	// The kind of the RPC may be non-basic, to optimize RPC usage.
-	Setup(ctx context.Context, log log.Logger, rollupCfg *rollup.Config) (cl client.RPC, rpcCfg *sources.L1ClientConfig, err error)
-	SetupDA(ctx context.Context, log log.Logger, rollupCfg *rollup.Config) (cl client.RPC, rpcCfg *sources.L1ClientConfig, err error)
+	// The metrics track the health of the endpoints, if the RPC client fails over between multiple endpoints.
+	Setup(ctx context.Context, log log.Logger, rollupCfg *rollup.Config, m client.FailoverMetrics) (cl client.RPC, rpcCfg *sources.L1ClientConfig, err error)
 	Check() error
 }
 
@@ -131,8 +132,9 @@ func (cfg *PreparedL2SyncEndpoint) Check() error {
 }
 
 type L1EndpointConfig struct {
-	L1NodeAddr string // Address of L1 User JSON-RPC endpoint to use (eth namespace required)
-	L1DAAddr   string
+	// Address of L1 User JSON-RPC endpoint to use (eth namespace required).
+	// Multiple endpoints may be specified as a comma-separated list, in order of preference, to fail over between.
+	L1NodeAddr string
 
 	// L1TrustRPC: if we trust the L1 RPC we do not have to validate L1 response contents like headers
 	// against block hashes, or cached transaction sender addresses.
@@ -154,10 +156,29 @@ type L1EndpointConfig struct {
 	// It is recommended to use websockets or IPC for efficient following of the changing block.
 	// Setting this to 0 disables polling.
 	HttpPollInterval time.Duration
+
+	// HealthCheckInterval specifies the interval between health checks of the L1 endpoints,
+	// if multiple endpoints are configured. Setting this to 0 disables health checks.
+	HealthCheckInterval time.Duration
+
+	// CrossCheck enables the verification of L1 blocks fetched by number against another endpoint,
+	// if multiple endpoints are configured.
+	CrossCheck bool
 }
 
 var _ L1EndpointSetup = (*L1EndpointConfig)(nil)
 
+// Addrs returns the addresses of the L1 endpoints, in order of preference.
+func (cfg *L1EndpointConfig) Addrs() []string {
+	var addrs []string
+	for _, addr := range strings.Split(cfg.L1NodeAddr, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
+
 func (cfg *L1EndpointConfig) Check() error {
 	if cfg.BatchSize < 1 || cfg.BatchSize > 500 {
 		return fmt.Errorf("batch size is invalid or unreasonable: %d", cfg.BatchSize)
@@ -165,10 +186,13 @@ func (cfg *L1EndpointConfig) Check() error {
 	if cfg.RateLimit < 0 {
 		return fmt.Errorf("rate limit cannot be negative")
 	}
+	if len(cfg.Addrs()) == 0 {
+		return errors.New("empty L1 Address")
+	}
 	return nil
 }
 
-func (cfg *L1EndpointConfig) Setup(ctx context.Context, log log.Logger, rollupCfg *rollup.Config) (client.RPC, *sources.L1ClientConfig, error) {
+func (cfg *L1EndpointConfig) Setup(ctx context.Context, log log.Logger, rollupCfg *rollup.Config, m client.FailoverMetrics) (client.RPC, *sources.L1ClientConfig, error) {
 	opts := []client.RPCOption{
 		client.WithHttpPollInterval(cfg.HttpPollInterval),
 		client.WithDialBackoff(10),
@@ -177,37 +201,34 @@ func (cfg *L1EndpointConfig) Setup(ctx context.Context, log log.Logger, rollupCf
 		opts = append(opts, client.WithRateLimit(cfg.RateLimit, cfg.BatchSize))
 	}
 
-	l1Node, err := client.NewRPC(ctx, log, cfg.L1NodeAddr, opts...)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to dial L1 address (%s): %w", cfg.L1NodeAddr, err)
-	}
-	rpcCfg := sources.L1ClientDefaultConfig(rollupCfg, cfg.L1TrustRPC, cfg.L1RPCKind)
-	rpcCfg.MaxRequestsPerBatch = cfg.BatchSize
-	return l1Node, rpcCfg, nil
-}
-
-func (cfg *L1EndpointConfig) SetupDA(ctx context.Context, log log.Logger, rollupCfg *rollup.Config) (client.RPC, *sources.L1ClientConfig, error) {
-	opts := []client.RPCOption{
-		client.WithHttpPollInterval(cfg.HttpPollInterval),
-		client.WithDialBackoff(10),
-	}
-	if cfg.RateLimit != 0 {
-		opts = append(opts, client.WithRateLimit(cfg.RateLimit, cfg.BatchSize))
+	addrs := cfg.Addrs()
+	var rpcs []client.RPC
+	for _, addr := range addrs {
+		rpc, err := client.NewRPC(ctx, log, addr, opts...)
+		if err != nil {
+			for _, rpc := range rpcs {
+				rpc.Close()
+			}
+			return nil, nil, fmt.Errorf("failed to dial L1 address (%s): %w", addr, err)
+		}
+		rpcs = append(rpcs, rpc)
 	}
-
-	l1Node, err := client.NewRPC(ctx, log, cfg.L1DAAddr, opts...)
-	if err != nil {
-		return nil, nil, fmt.Errorf("failed to dial L1 address (%s): %w", cfg.L1DAAddr, err)
+	var l1Node client.RPC = rpcs[0]
+	if len(rpcs) > 1 {
+		failoverCfg := client.DefaultFailoverConfig()
+		failoverCfg.HealthCheckInterval = cfg.HealthCheckInterval
+		failoverCfg.CrossCheck = cfg.CrossCheck
+		failover, err := client.NewFailoverRPC(log, rpcs, failoverCfg, m)
+		if err != nil {
+			return nil, nil, err
+		}
+		l1Node = failover
 	}
 	rpcCfg := sources.L1ClientDefaultConfig(rollupCfg, cfg.L1TrustRPC, cfg.L1RPCKind)
 	rpcCfg.MaxRequestsPerBatch = cfg.BatchSize
 	return l1Node, rpcCfg, nil
 }
 
-func (p *PreparedL1Endpoint) SetupDA(ctx context.Context, log log.Logger, rollupCfg *rollup.Config) (client.RPC, *sources.L1ClientConfig, error) {
-	return p.Client, sources.L1ClientDefaultConfig(rollupCfg, p.TrustRPC, p.RPCProviderKind), nil
-}
-
 // PreparedL1Endpoint enables testing with an in-process pre-setup RPC connection to L1
 type PreparedL1Endpoint struct {
 	Client          client.RPC
@@ -217,7 +238,7 @@ type PreparedL1Endpoint struct {
 
 var _ L1EndpointSetup = (*PreparedL1Endpoint)(nil)
 
-func (p *PreparedL1Endpoint) Setup(ctx context.Context, log log.Logger, rollupCfg *rollup.Config) (client.RPC, *sources.L1ClientConfig, error) {
+func (p *PreparedL1Endpoint) Setup(ctx context.Context, log log.Logger, rollupCfg *rollup.Config, _ client.FailoverMetrics) (client.RPC, *sources.L1ClientConfig, error) {
 	return p.Client, sources.L1ClientDefaultConfig(rollupCfg, p.TrustRPC, p.RPCProviderKind), nil
 }
 
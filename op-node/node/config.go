@@ -9,6 +9,7 @@ import (
 	"github.com/ethereum-optimism/optimism/op-node/p2p"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/driver"
+	"github.com/ethereum-optimism/optimism/op-service/da"
 	oppprof "github.com/ethereum-optimism/optimism/op-service/pprof"
 )
 
@@ -21,6 +22,9 @@ type Config struct {
 
 	Rollup rollup.Config
 
+	// DA configures access to the data availability backend of the rollup, see Rollup.DAType.
+	DA da.CLIConfig
+
 	// P2PSigner will be used for signing off on published content
 	// if the node is sequencing and if the p2p stack is enabled
 	P2PSigner p2p.SignerSetup
@@ -36,11 +40,38 @@ type Config struct {
 	// Used to poll the L1 for new finalized or safe blocks
 	L1EpochPollInterval time.Duration
 
+	// SafeDBPath is the path of the database that records the safe head derived from each L1 block.
+	// The database is disabled if empty.
+	SafeDBPath string
+
+	// UnsafePayloadsJournal configures the journal that persists buffered unsafe payloads across restarts.
+	UnsafePayloadsJournal UnsafePayloadsJournalConfig
+
+	// DerivationRecordPath is the path of the archive that records all inputs of the derivation,
+	// to replay the derivation offline. Recording is disabled if empty.
+	DerivationRecordPath string
+
 	// Optional
 	Tracer    Tracer
 	Heartbeat HeartbeatConfig
 }
 
+type UnsafePayloadsJournalConfig struct {
+	// Path of the journal file. The journal is disabled if empty.
+	Path string
+	// MaxSize of the journal, in bytes.
+	MaxSize uint64
+	// MaxAge of journaled payloads, based on the payload timestamp. Payloads of any age are kept if 0.
+	MaxAge time.Duration
+}
+
+func (cfg *UnsafePayloadsJournalConfig) Check() error {
+	if cfg.Path != "" && cfg.MaxSize == 0 {
+		return errors.New("unsafe payloads journal max size must be positive")
+	}
+	return nil
+}
+
 type RPCConfig struct {
 	ListenAddr  string
 	ListenPort  int
@@ -86,6 +117,15 @@ func (cfg *Config) Check() error {
 	if err := cfg.Rollup.Check(); err != nil {
 		return fmt.Errorf("rollup config error: %w", err)
 	}
+	if err := cfg.DA.Check(); err != nil {
+		return fmt.Errorf("da config error: %w", err)
+	}
+	if err := cfg.DA.CheckType(da.TypeOrDefault(cfg.Rollup.DAType)); err != nil {
+		return fmt.Errorf("da config cannot decode batches of the rollup: %w", err)
+	}
+	if err := cfg.UnsafePayloadsJournal.Check(); err != nil {
+		return fmt.Errorf("unsafe payloads journal config error: %w", err)
+	}
 	if err := cfg.Metrics.Check(); err != nil {
 		return fmt.Errorf("metrics config error: %w", err)
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/hashicorp/go-multierror"
@@ -16,9 +17,12 @@ import (
 	"github.com/ethereum-optimism/optimism/op-node/client"
 	"github.com/ethereum-optimism/optimism/op-node/eth"
 	"github.com/ethereum-optimism/optimism/op-node/metrics"
+	"github.com/ethereum-optimism/optimism/op-node/node/safedb"
 	"github.com/ethereum-optimism/optimism/op-node/p2p"
+	"github.com/ethereum-optimism/optimism/op-node/rollup/derive"
 	"github.com/ethereum-optimism/optimism/op-node/rollup/driver"
 	"github.com/ethereum-optimism/optimism/op-node/sources"
+	"github.com/ethereum-optimism/optimism/op-service/da"
 )
 
 type OpNode struct {
@@ -31,21 +35,59 @@ type OpNode struct {
 	l1FinalizedSub ethereum.Subscription // Subscription to get L1 safe blocks, a.k.a. justified data (polling)
 
 	l1Source  *sources.L1Client     // L1 Client to fetch data from
+	daChain   *sources.EthClient    // DA chain client to fetch Polygon batch data from
+	daSources *da.Registry          // Data availability backends that L1 batch data may refer to
 	l2Driver  *driver.Driver        // L2 Engine to Sync
 	l2Source  *sources.EngineClient // L2 Execution Engine RPC bindings
 	rpcSync   *sources.SyncClient   // Alt-sync RPC client, optional (may be nil)
+	safeDB    closableSafeDB        // Safe head database, records the safe head derived from each L1 block
 	server    *rpcServer            // RPC server hosting the rollup-node API
 	p2pNode   *p2p.NodeP2P          // P2P node functionality
 	p2pSigner p2p.Signer            // p2p gogssip application messages will be signed with this signer
 	tracer    Tracer                // tracer to get events for testing/debugging
 	runCfg    *RuntimeConfig        // runtime configurables
 
+	journal *derive.PayloadsJournal // Unsafe payloads journal, optional (may be nil)
+	// journaled payloads of a previous run, to process again once the driver is started
+	journaledPayloads []*eth.ExecutionPayload
+
+	inputArchive *derive.InputArchive // Records the inputs of the derivation, optional (may be nil)
+
 	// some resources cannot be stopped directly, like the p2p gossipsub router (not our design),
 	// and depend on this ctx to be closed.
 	resourcesCtx   context.Context
 	resourcesClose context.CancelFunc
 }
 
+type closableSafeDB interface {
+	derive.SafeHeadListener
+	safeDBReader
+	io.Closer
+}
+
+// safeHeadListeners notifies all of the listeners of safe head changes.
+type safeHeadListeners []derive.SafeHeadListener
+
+func (ls safeHeadListeners) SafeHeadUpdated(safeHead eth.L2BlockRef, l1Head eth.BlockID) error {
+	var result *multierror.Error
+	for _, l := range ls {
+		if err := l.SafeHeadUpdated(safeHead, l1Head); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+	return result.ErrorOrNil()
+}
+
+func (ls safeHeadListeners) SafeHeadReset(resetSafeHead eth.L2BlockRef) error {
+	var result *multierror.Error
+	for _, l := range ls {
+		if err := l.SafeHeadReset(resetSafeHead); err != nil {
+			result = multierror.Append(result, err)
+		}
+	}
+	return result.ErrorOrNil()
+}
+
 // The OpNode handles incoming gossip
 var _ p2p.GossipIn = (*OpNode)(nil)
 
@@ -78,6 +120,9 @@ func (n *OpNode) init(ctx context.Context, cfg *Config, snapshotLog log.Logger)
 	if err := n.initTracer(ctx, cfg); err != nil {
 		return err
 	}
+	if err := n.initInputRecorder(ctx, cfg); err != nil {
+		return err
+	}
 	if err := n.initL1(ctx, cfg); err != nil {
 		return err
 	}
@@ -115,19 +160,69 @@ func (n *OpNode) initTracer(ctx context.Context, cfg *Config) error {
 	return nil
 }
 
-func (n *OpNode) initL1(ctx context.Context, cfg *Config) error {
-	l1Node, rpcCfg, err := cfg.L1.Setup(ctx, n.log, &cfg.Rollup)
+// initInputRecorder opens the archive that the L1 and DA inputs of the derivation are recorded into, if enabled.
+func (n *OpNode) initInputRecorder(ctx context.Context, cfg *Config) error {
+	if cfg.DerivationRecordPath == "" {
+		return nil
+	}
+	n.log.Info("Recording derivation inputs", "path", cfg.DerivationRecordPath)
+	archive, err := derive.OpenInputArchive(n.log, cfg.DerivationRecordPath)
 	if err != nil {
-		return fmt.Errorf("failed to get L1 RPC client: %w", err)
+		return err
+	}
+	if err := archive.WriteRollupConfig(&cfg.Rollup); err != nil {
+		_ = archive.Close()
+		return fmt.Errorf("failed to record rollup config: %w", err)
+	}
+	n.inputArchive = archive
+	return nil
+}
+
+// initDA sets up the data availability backend of the rollup, next to L1 calldata which can always be decoded.
+func (n *OpNode) initDA(ctx context.Context, cfg *Config) error {
+	typ := da.TypeOrDefault(cfg.Rollup.DAType)
+	n.log.Info("Initializing data availability", "type", typ, "rpc", cfg.DA.RPC, "server", cfg.DA.Server,
+		"tls", cfg.DA.TLSConfig.TLSEnabled())
+	backends := []da.DataAvailability{da.NewCalldata()}
+	switch typ {
+	case da.CalldataType:
+	case da.PolygonType:
+		daNode, err := client.NewRPC(ctx, n.log, cfg.DA.RPC, client.WithDialBackoff(10))
+		if err != nil {
+			return fmt.Errorf("failed to dial DA chain address (%s): %w", cfg.DA.RPC, err)
+		}
+		rpcCfg := sources.L1ClientDefaultConfig(&cfg.Rollup, false, sources.RPCKindBasic)
+		n.daChain, err = sources.NewEthClient(
+			client.NewInstrumentedRPC(daNode, n.metrics), n.log, n.metrics.L1SourceCache, &rpcCfg.EthClientConfig)
+		if err != nil {
+			return fmt.Errorf("failed to create DA chain source: %w", err)
+		}
+		backends = append(backends, da.NewPolygon(nil, cfg.Rollup.BatchInboxAddress, n.daChain))
+	default:
+		blob, err := cfg.DA.NewHTTPBlob(n.log, typ)
+		if err != nil {
+			return err
+		}
+		backends = append(backends, blob)
 	}
+	if n.inputArchive != nil {
+		for i, b := range backends {
+			backends[i] = n.inputArchive.RecordDA(b)
+		}
+	}
+	var err error
+	n.daSources, err = da.NewRegistry(backends...)
+	return err
+}
 
-	l1DA, _, err := cfg.L1.SetupDA(ctx, n.log, &cfg.Rollup)
+func (n *OpNode) initL1(ctx context.Context, cfg *Config) error {
+	l1Node, rpcCfg, err := cfg.L1.Setup(ctx, n.log, &cfg.Rollup, n.metrics)
 	if err != nil {
 		return fmt.Errorf("failed to get L1 RPC client: %w", err)
 	}
 
 	n.l1Source, err = sources.NewL1Client(
-		client.NewInstrumentedRPC(l1Node, n.metrics), l1DA, n.log, n.metrics.L1SourceCache, rpcCfg)
+		client.NewInstrumentedRPC(l1Node, n.metrics), n.log, n.metrics.L1SourceCache, rpcCfg)
 	if err != nil {
 		return fmt.Errorf("failed to create L1 source: %w", err)
 	}
@@ -136,6 +231,10 @@ func (n *OpNode) initL1(ctx context.Context, cfg *Config) error {
 		return err
 	}
 
+	if err := n.initDA(ctx, cfg); err != nil {
+		return fmt.Errorf("failed to init data availability sources: %w", err)
+	}
+
 	// Keep subscribed to the L1 heads, which keeps the L1 maintainer pointing to the best headers to sync
 	n.l1HeadsSub = event.ResubscribeErr(time.Second*10, func(ctx context.Context, err error) (event.Subscription, error) {
 		if err != nil {
@@ -204,7 +303,32 @@ func (n *OpNode) initL2(ctx context.Context, cfg *Config, snapshotLog log.Logger
 		return err
 	}
 
-	n.l2Driver = driver.NewDriver(&cfg.Driver, &cfg.Rollup, n.l2Source, n.l1Source, n, n, n.log, snapshotLog, n.metrics)
+	if cfg.SafeDBPath != "" {
+		n.log.Info("Safe head database enabled", "path", cfg.SafeDBPath)
+		n.safeDB, err = safedb.OpenSafeDB(n.log, cfg.SafeDBPath)
+		if err != nil {
+			return err
+		}
+	} else {
+		n.safeDB = safedb.Disabled
+	}
+
+	var safeHeadListener derive.SafeHeadListener = n.safeDB
+	if jcfg := cfg.UnsafePayloadsJournal; jcfg.Path != "" {
+		n.log.Info("Unsafe payloads journal enabled", "path", jcfg.Path, "max_size", jcfg.MaxSize, "max_age", jcfg.MaxAge)
+		n.journal, n.journaledPayloads, err = derive.OpenPayloadsJournal(n.log, jcfg.Path, jcfg.MaxSize, jcfg.MaxAge)
+		if err != nil {
+			return fmt.Errorf("failed to open unsafe payloads journal: %w", err)
+		}
+		// prune the journal as the safe head progresses
+		safeHeadListener = safeHeadListeners{n.safeDB, n.journal}
+	}
+
+	var l1Source driver.L1Chain = n.l1Source
+	if n.inputArchive != nil {
+		l1Source = n.inputArchive.RecordL1(n.l1Source)
+	}
+	n.l2Driver = driver.NewDriver(&cfg.Driver, &cfg.Rollup, n.l2Source, l1Source, n.daSources, safeHeadListener, n, n, n.log, snapshotLog, n.metrics)
 
 	return nil
 }
@@ -226,7 +350,8 @@ func (n *OpNode) initRPCSync(ctx context.Context, cfg *Config) error {
 }
 
 func (n *OpNode) initRPCServer(ctx context.Context, cfg *Config) error {
-	server, err := newRPCServer(ctx, &cfg.RPC, &cfg.Rollup, n.l2Source.L2Client, n.l2Driver, n.log, n.appVersion, n.metrics)
+	server, err := newRPCServer(ctx, &cfg.RPC, &cfg.Rollup, n.l2Source.L2Client, n.l2Driver,
+		derive.NewDAConfirmationsChecker(n.log, &cfg.Rollup, n.l1Source, n.daSources), n.safeDB, n.log, n.appVersion, n.metrics)
 	if err != nil {
 		return err
 	}
@@ -302,6 +427,18 @@ func (n *OpNode) Start(ctx context.Context) error {
 		n.log.Info("Started L2-RPC sync service")
 	}
 
+	// Process the unsafe payloads that were journaled before the node was restarted.
+	if len(n.journaledPayloads) > 0 {
+		n.log.Info("Processing journaled unsafe payloads", "count", len(n.journaledPayloads))
+		for _, payload := range n.journaledPayloads {
+			if err := n.l2Driver.OnUnsafeL2Payload(ctx, payload); err != nil {
+				n.log.Warn("failed to process journaled unsafe payload", "id", payload.ID(), "err", err)
+				break
+			}
+		}
+		n.journaledPayloads = nil
+	}
+
 	return nil
 }
 
@@ -368,6 +505,12 @@ func (n *OpNode) OnUnsafeL2Payload(ctx context.Context, from peer.ID, payload *e
 
 	n.log.Info("Received signed execution payload from p2p", "id", payload.ID(), "peer", from)
 
+	if n.journal != nil {
+		if err := n.journal.Insert(payload); err != nil {
+			n.log.Warn("failed to journal unsafe payload", "id", payload.ID(), "err", err)
+		}
+	}
+
 	// Pass on the event to the L2 Engine
 	ctx, cancel := context.WithTimeout(ctx, time.Second*30)
 	defer cancel()
@@ -443,12 +586,36 @@ func (n *OpNode) Close() error {
 		}
 	}
 
+	// close the unsafe payloads journal, after the driver stopped pruning it
+	if n.journal != nil {
+		if err := n.journal.Close(); err != nil {
+			result = multierror.Append(result, fmt.Errorf("failed to close unsafe payloads journal: %w", err))
+		}
+	}
+
+	// close the derivation input archive, after the driver stopped recording into it
+	if n.inputArchive != nil {
+		if err := n.inputArchive.Close(); err != nil {
+			result = multierror.Append(result, fmt.Errorf("failed to close derivation input archive: %w", err))
+		}
+	}
+
+	// close the safe head database, after the driver stopped updating it
+	if n.safeDB != nil {
+		if err := n.safeDB.Close(); err != nil {
+			result = multierror.Append(result, fmt.Errorf("failed to close safe head database: %w", err))
+		}
+	}
+
 	// close L2 engine RPC client
 	if n.l2Source != nil {
 		n.l2Source.Close()
 	}
 
 	// close L1 data source
+	if n.daChain != nil {
+		n.daChain.Close()
+	}
 	if n.l1Source != nil {
 		n.l1Source.Close()
 	}
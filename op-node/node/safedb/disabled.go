@@ -0,0 +1,28 @@
+package safedb
+
+import (
+	"context"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+)
+
+type DisabledDB struct{}
+
+// Disabled is used when the safe head database is not enabled: updates are ignored, and lookups fail.
+var Disabled = &DisabledDB{}
+
+func (d *DisabledDB) SafeHeadUpdated(_ eth.L2BlockRef, _ eth.BlockID) error {
+	return nil
+}
+
+func (d *DisabledDB) SafeHeadReset(_ eth.L2BlockRef) error {
+	return nil
+}
+
+func (d *DisabledDB) SafeHeadAtL1(_ context.Context, _ uint64) (eth.BlockID, eth.BlockID, error) {
+	return eth.BlockID{}, eth.BlockID{}, ErrNotEnabled
+}
+
+func (d *DisabledDB) Close() error {
+	return nil
+}
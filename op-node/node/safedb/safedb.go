@@ -0,0 +1,166 @@
+package safedb
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/leveldb"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+)
+
+var (
+	ErrNotFound   = errors.New("safe head not found")
+	ErrNotEnabled = errors.New("safe head database not enabled")
+)
+
+// safeHeadPrefix + inverted L1 block number (uint64 big-endian) -> safe head after processing the L1 block.
+// The block number is inverted to iterate from the latest L1 block backwards.
+var safeHeadPrefix = []byte("s")
+
+const (
+	safeDBCache   = 16 // MB
+	safeDBHandles = 16
+)
+
+type safeHeadJSON struct {
+	L1Hash     common.Hash    `json:"l1Hash"`
+	SafeHash   common.Hash    `json:"safeHash"`
+	SafeNumber hexutil.Uint64 `json:"safeNumber"`
+}
+
+// SafeDB persists the L2 safe head that was derived from each L1 block,
+// so that the safe head at any past L1 block can be looked up.
+// Only L1 blocks at which the safe head changed are recorded:
+// the safe head at an L1 block is the one recorded at the last L1 block at, or before, it.
+type SafeDB struct {
+	mu  sync.Mutex
+	log log.Logger
+	db  ethdb.KeyValueStore
+}
+
+// OpenSafeDB opens the safe head database stored at the given path, or creates a new one.
+func OpenSafeDB(log log.Logger, path string) (*SafeDB, error) {
+	db, err := leveldb.New(path, safeDBCache, safeDBHandles, "", false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open safe head database at %s: %w", path, err)
+	}
+	return &SafeDB{log: log, db: db}, nil
+}
+
+// NewMemorySafeDB creates a safe head database that is not persisted.
+func NewMemorySafeDB(log log.Logger) *SafeDB {
+	return &SafeDB{log: log, db: memorydb.New()}
+}
+
+// SafeHeadUpdated records that the given safe head was derived from the L1 chain up to and including l1Head.
+// A safe head recorded earlier for the same L1 block is replaced.
+func (d *SafeDB) SafeHeadUpdated(safeHead eth.L2BlockRef, l1Head eth.BlockID) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	enc, err := json.Marshal(safeHeadJSON{
+		L1Hash:     l1Head.Hash,
+		SafeHash:   safeHead.Hash,
+		SafeNumber: hexutil.Uint64(safeHead.Number),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode safe head: %w", err)
+	}
+	if err := d.db.Put(safeHeadKey(l1Head.Number), enc); err != nil {
+		return fmt.Errorf("failed to record safe head %s at L1 block %s: %w", safeHead, l1Head, err)
+	}
+	d.log.Debug("Recorded safe head", "l1", l1Head, "safe", safeHead)
+	return nil
+}
+
+// SafeHeadReset removes all safe heads after the given safe head, which the derivation is reset to,
+// as well as entries of other blocks at the same height. Derivation records the safe heads again
+// as it progresses from there, on the now canonical chains.
+func (d *SafeDB) SafeHeadReset(safeHead eth.L2BlockRef) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	// Entries are iterated from the latest L1 block backwards, and safe heads are recorded in increasing order:
+	// remove entries until the reset safe head, or the first safe head before it.
+	it := d.db.NewIterator(safeHeadPrefix, nil)
+	defer it.Release()
+	batch := d.db.NewBatch()
+	removed := 0
+	for it.Next() {
+		entry, err := decodeSafeHead(it.Value())
+		if err != nil {
+			return err
+		}
+		if uint64(entry.SafeNumber) < safeHead.Number ||
+			(uint64(entry.SafeNumber) == safeHead.Number && entry.SafeHash == safeHead.Hash) {
+			break
+		}
+		if err := batch.Delete(common.CopyBytes(it.Key())); err != nil {
+			return err
+		}
+		removed++
+	}
+	if err := it.Error(); err != nil {
+		return fmt.Errorf("failed to read safe heads: %w", err)
+	}
+	if err := batch.Write(); err != nil {
+		return fmt.Errorf("failed to truncate safe heads at %s: %w", safeHead, err)
+	}
+	d.log.Debug("Truncated safe heads", "safe", safeHead, "removed", removed)
+	return nil
+}
+
+// SafeHeadAtL1 returns the safe head that was derived from the L1 chain up to and including the given L1 block,
+// and the L1 block at which that safe head was recorded. ErrNotFound is returned if no safe head is known
+// at, or before, the given L1 block.
+func (d *SafeDB) SafeHeadAtL1(ctx context.Context, l1BlockNum uint64) (l1Block eth.BlockID, safeHead eth.BlockID, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	// the first entry from the given L1 block backwards is the last one at, or before, it
+	it := d.db.NewIterator(safeHeadPrefix, safeHeadKey(l1BlockNum)[len(safeHeadPrefix):])
+	defer it.Release()
+	if !it.Next() {
+		if err := it.Error(); err != nil {
+			return eth.BlockID{}, eth.BlockID{}, fmt.Errorf("failed to read safe heads: %w", err)
+		}
+		return eth.BlockID{}, eth.BlockID{}, ErrNotFound
+	}
+	key := it.Key()
+	if len(key) != len(safeHeadPrefix)+8 {
+		return eth.BlockID{}, eth.BlockID{}, fmt.Errorf("invalid safe head key %x", key)
+	}
+	entry, err := decodeSafeHead(it.Value())
+	if err != nil {
+		return eth.BlockID{}, eth.BlockID{}, err
+	}
+	l1Block = eth.BlockID{Hash: entry.L1Hash, Number: ^binary.BigEndian.Uint64(key[len(safeHeadPrefix):])}
+	safeHead = eth.BlockID{Hash: entry.SafeHash, Number: uint64(entry.SafeNumber)}
+	return l1Block, safeHead, nil
+}
+
+func (d *SafeDB) Close() error {
+	return d.db.Close()
+}
+
+func safeHeadKey(l1BlockNum uint64) []byte {
+	key := make([]byte, len(safeHeadPrefix)+8)
+	copy(key, safeHeadPrefix)
+	binary.BigEndian.PutUint64(key[len(safeHeadPrefix):], ^l1BlockNum)
+	return key
+}
+
+func decodeSafeHead(data []byte) (safeHeadJSON, error) {
+	var entry safeHeadJSON
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return safeHeadJSON{}, fmt.Errorf("invalid safe head entry: %w", err)
+	}
+	return entry, nil
+}
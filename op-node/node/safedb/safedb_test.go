@@ -0,0 +1,89 @@
+package safedb
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+)
+
+func l1ID(num uint64) eth.BlockID {
+	return eth.BlockID{Hash: common.Hash{0x01, byte(num)}, Number: num}
+}
+
+func l2Ref(num uint64, origin uint64) eth.L2BlockRef {
+	return eth.L2BlockRef{Hash: common.Hash{0x02, byte(num)}, Number: num, L1Origin: l1ID(origin)}
+}
+
+func requireSafeHead(t *testing.T, db *SafeDB, l1Num uint64, expectedL1 eth.BlockID, expectedSafe eth.L2BlockRef) {
+	l1Block, safeHead, err := db.SafeHeadAtL1(context.Background(), l1Num)
+	require.NoError(t, err)
+	require.Equal(t, expectedL1, l1Block)
+	require.Equal(t, expectedSafe.ID(), safeHead)
+}
+
+func TestSafeHeadAtL1(t *testing.T) {
+	db := NewMemorySafeDB(testlog.Logger(t, log.LvlInfo))
+	_, _, err := db.SafeHeadAtL1(context.Background(), 100)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, db.SafeHeadUpdated(l2Ref(10, 90), l1ID(100)))
+	require.NoError(t, db.SafeHeadUpdated(l2Ref(12, 91), l1ID(100)), "replaces the safe head of the same L1 block")
+	require.NoError(t, db.SafeHeadUpdated(l2Ref(20, 95), l1ID(105)))
+
+	_, _, err = db.SafeHeadAtL1(context.Background(), 99)
+	require.ErrorIs(t, err, ErrNotFound)
+	requireSafeHead(t, db, 100, l1ID(100), l2Ref(12, 91))
+	requireSafeHead(t, db, 104, l1ID(100), l2Ref(12, 91))
+	requireSafeHead(t, db, 105, l1ID(105), l2Ref(20, 95))
+	requireSafeHead(t, db, 1000, l1ID(105), l2Ref(20, 95))
+}
+
+func TestSafeHeadReset(t *testing.T) {
+	db := NewMemorySafeDB(testlog.Logger(t, log.LvlInfo))
+	require.NoError(t, db.SafeHeadUpdated(l2Ref(10, 90), l1ID(100)))
+	require.NoError(t, db.SafeHeadUpdated(l2Ref(20, 95), l1ID(105)))
+	require.NoError(t, db.SafeHeadUpdated(l2Ref(30, 100), l1ID(110)))
+
+	// resetting to a recorded safe head keeps it
+	require.NoError(t, db.SafeHeadReset(l2Ref(20, 95)))
+	requireSafeHead(t, db, 1000, l1ID(105), l2Ref(20, 95))
+
+	// resetting to another block at the same height removes it
+	reorged := l2Ref(20, 95)
+	reorged.Hash = common.Hash{0xff}
+	require.NoError(t, db.SafeHeadReset(reorged))
+	requireSafeHead(t, db, 1000, l1ID(100), l2Ref(10, 90))
+
+	// resetting to an earlier block removes all later safe heads
+	require.NoError(t, db.SafeHeadUpdated(l2Ref(20, 95), l1ID(106)))
+	require.NoError(t, db.SafeHeadReset(l2Ref(5, 85)))
+	_, _, err := db.SafeHeadAtL1(context.Background(), 1000)
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestSafeDBReopen(t *testing.T) {
+	logger := testlog.Logger(t, log.LvlInfo)
+	path := filepath.Join(t.TempDir(), "safedb")
+	db, err := OpenSafeDB(logger, path)
+	require.NoError(t, err)
+	require.NoError(t, db.SafeHeadUpdated(l2Ref(10, 90), l1ID(100)))
+	require.NoError(t, db.Close())
+
+	db, err = OpenSafeDB(logger, path)
+	require.NoError(t, err)
+	defer db.Close()
+	requireSafeHead(t, db, 100, l1ID(100), l2Ref(10, 90))
+}
+
+func TestDisabled(t *testing.T) {
+	require.NoError(t, Disabled.SafeHeadUpdated(l2Ref(10, 90), l1ID(100)))
+	_, _, err := Disabled.SafeHeadAtL1(context.Background(), 100)
+	require.ErrorIs(t, err, ErrNotEnabled)
+}
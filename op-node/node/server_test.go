@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -19,6 +20,7 @@ import (
 	"github.com/ethereum-optimism/optimism/op-bindings/predeploys"
 	"github.com/ethereum-optimism/optimism/op-node/eth"
 	"github.com/ethereum-optimism/optimism/op-node/metrics"
+	"github.com/ethereum-optimism/optimism/op-node/node/safedb"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-node/testlog"
 	"github.com/ethereum-optimism/optimism/op-node/testutils"
@@ -110,7 +112,7 @@ func TestOutputAtBlock(t *testing.T) {
 	status := randomSyncStatus(rand.New(rand.NewSource(123)))
 	drClient.ExpectBlockRefWithStatus(0xdcdc89, ref, status, nil)
 
-	server, err := newRPCServer(context.Background(), rpcCfg, rollupCfg, l2Client, drClient, log, "0.0", metrics.NoopMetrics)
+	server, err := newRPCServer(context.Background(), rpcCfg, rollupCfg, l2Client, drClient, nil, nil, log, "0.0", metrics.NoopMetrics)
 	require.NoError(t, err)
 	require.NoError(t, server.Start())
 	defer server.Stop()
@@ -142,7 +144,7 @@ func TestVersion(t *testing.T) {
 	rollupCfg := &rollup.Config{
 		// ignore other rollup config info in this test
 	}
-	server, err := newRPCServer(context.Background(), rpcCfg, rollupCfg, l2Client, drClient, log, "0.0", metrics.NoopMetrics)
+	server, err := newRPCServer(context.Background(), rpcCfg, rollupCfg, l2Client, drClient, nil, nil, log, "0.0", metrics.NoopMetrics)
 	assert.NoError(t, err)
 	assert.NoError(t, server.Start())
 	defer server.Stop()
@@ -185,7 +187,7 @@ func TestSyncStatus(t *testing.T) {
 	rollupCfg := &rollup.Config{
 		// ignore other rollup config info in this test
 	}
-	server, err := newRPCServer(context.Background(), rpcCfg, rollupCfg, l2Client, drClient, log, "0.0", metrics.NoopMetrics)
+	server, err := newRPCServer(context.Background(), rpcCfg, rollupCfg, l2Client, drClient, nil, nil, log, "0.0", metrics.NoopMetrics)
 	assert.NoError(t, err)
 	assert.NoError(t, server.Start())
 	defer server.Stop()
@@ -199,6 +201,136 @@ func TestSyncStatus(t *testing.T) {
 	assert.Equal(t, status, out)
 }
 
+func TestDAConfirmations(t *testing.T) {
+	log := testlog.Logger(t, log.LvlError)
+	rng := rand.New(rand.NewSource(1234))
+	l2Client := &testutils.MockL2Client{}
+	drClient := &mockDriverClient{}
+	daConfs := &mockDAConfirmationsSource{}
+
+	startRef := testutils.RandomL2BlockRef(rng)
+	startRef.Number = 100
+	startRef.L1Origin.Number = 50
+	endRef := testutils.RandomL2BlockRef(rng)
+	endRef.Number = 120
+	endRef.L1Origin.Number = 60
+	status := randomSyncStatus(rng)
+	status.SafeL2 = endRef
+	status.CurrentL1.Number = 70
+	drClient.ExpectBlockRefWithStatus(startRef.Number, startRef, status, nil)
+	drClient.ExpectBlockRefWithStatus(endRef.Number, endRef, status, nil)
+
+	// the batcher was changed within the range, the batches of both batchers are checked
+	batcherA, batcherB := testutils.RandomAddress(rng), testutils.RandomAddress(rng)
+	l2Client.ExpectSystemConfigByL2Hash(startRef.Hash, eth.SystemConfig{BatcherAddr: batcherA}, nil)
+	l2Client.ExpectSystemConfigByL2Hash(endRef.Hash, eth.SystemConfig{BatcherAddr: batcherB}, nil)
+	l1Start, l1End := testutils.RandomBlockID(rng), testutils.RandomBlockID(rng)
+	// the range ends at the current L1 block, before the end of the sequencing window of the last L2 block
+	daConfs.On("DAConfirmations", batcherA, uint64(50), uint64(70)).
+		Return(&eth.DAConfirmations{L1Start: l1Start, L1End: l1End, Refs: 3, MinConfirmations: 20}, nil)
+	daConfs.On("DAConfirmations", batcherB, uint64(50), uint64(70)).
+		Return(&eth.DAConfirmations{L1Start: l1Start, L1End: l1End, Refs: 2, MinConfirmations: 12}, nil)
+
+	rpcCfg := &RPCConfig{
+		ListenAddr: "localhost",
+		ListenPort: 0,
+	}
+	rollupCfg := &rollup.Config{
+		SeqWindowSize: 100,
+	}
+	db := safedb.NewMemorySafeDB(log)
+	server, err := newRPCServer(context.Background(), rpcCfg, rollupCfg, l2Client, drClient, daConfs, db, log, "0.0", metrics.NoopMetrics)
+	require.NoError(t, err)
+	require.NoError(t, server.Start())
+	defer server.Stop()
+
+	client, err := rpcclient.NewRPC(context.Background(), log, "http://"+server.Addr().String(), rpcclient.WithDialBackoff(3))
+	require.NoError(t, err)
+
+	var out *eth.DAConfirmations
+	err = client.CallContext(context.Background(), &out, "optimism_daConfirmations", hexutil.Uint64(startRef.Number), hexutil.Uint64(endRef.Number))
+	require.NoError(t, err)
+	require.Equal(t, &eth.DAConfirmations{L1Start: l1Start, L1End: l1End, Refs: 5, MinConfirmations: 12}, out)
+
+	// blocks that are not safe yet are rejected
+	unsafeRef := testutils.RandomL2BlockRef(rng)
+	unsafeRef.Number = 121
+	drClient.ExpectBlockRefWithStatus(unsafeRef.Number, unsafeRef, status, nil)
+	err = client.CallContext(context.Background(), &out, "optimism_daConfirmations", hexutil.Uint64(startRef.Number), hexutil.Uint64(unsafeRef.Number))
+	require.ErrorContains(t, err, "not safe yet")
+
+	// once the safe head database knows the L1 block that the last L2 block was derived from,
+	// no later L1 blocks are scanned
+	safeAt := func(l2Num, l1Num uint64) {
+		safeHead := testutils.RandomL2BlockRef(rng)
+		safeHead.Number = l2Num
+		l1Block := testutils.RandomBlockID(rng)
+		l1Block.Number = l1Num
+		require.NoError(t, db.SafeHeadUpdated(safeHead, l1Block))
+	}
+	safeAt(110, 55)
+	safeAt(119, 61)
+	safeAt(120, 63)
+	safeAt(130, 66)
+	drClient.ExpectBlockRefWithStatus(startRef.Number, startRef, status, nil)
+	drClient.ExpectBlockRefWithStatus(endRef.Number, endRef, status, nil)
+	l2Client.ExpectSystemConfigByL2Hash(startRef.Hash, eth.SystemConfig{BatcherAddr: batcherA}, nil)
+	l2Client.ExpectSystemConfigByL2Hash(endRef.Hash, eth.SystemConfig{BatcherAddr: batcherB}, nil)
+	daConfs.On("DAConfirmations", batcherA, uint64(50), uint64(63)).
+		Return(&eth.DAConfirmations{L1Start: l1Start, L1End: l1End, Refs: 1, MinConfirmations: 30}, nil)
+	daConfs.On("DAConfirmations", batcherB, uint64(50), uint64(63)).
+		Return(&eth.DAConfirmations{L1Start: l1Start, L1End: l1End, Refs: 1, MinConfirmations: 40}, nil)
+	err = client.CallContext(context.Background(), &out, "optimism_daConfirmations", hexutil.Uint64(startRef.Number), hexutil.Uint64(endRef.Number))
+	require.NoError(t, err)
+	require.Equal(t, &eth.DAConfirmations{L1Start: l1Start, L1End: l1End, Refs: 2, MinConfirmations: 30}, out)
+
+	l2Client.Mock.AssertExpectations(t)
+	drClient.Mock.AssertExpectations(t)
+	daConfs.Mock.AssertExpectations(t)
+}
+
+func TestSafeHeadAtL1Block(t *testing.T) {
+	log := testlog.Logger(t, log.LvlError)
+	rng := rand.New(rand.NewSource(1234))
+	l2Client := &testutils.MockL2Client{}
+	drClient := &mockDriverClient{}
+	db := safedb.NewMemorySafeDB(log)
+
+	l1Block := testutils.RandomBlockID(rng)
+	safeHead := testutils.RandomL2BlockRef(rng)
+	require.NoError(t, db.SafeHeadUpdated(safeHead, l1Block))
+
+	rpcCfg := &RPCConfig{
+		ListenAddr: "localhost",
+		ListenPort: 0,
+	}
+	rollupCfg := &rollup.Config{}
+	server, err := newRPCServer(context.Background(), rpcCfg, rollupCfg, l2Client, drClient, nil, db, log, "0.0", metrics.NoopMetrics)
+	require.NoError(t, err)
+	require.NoError(t, server.Start())
+	defer server.Stop()
+
+	client, err := rpcclient.NewRPC(context.Background(), log, "http://"+server.Addr().String(), rpcclient.WithDialBackoff(3))
+	require.NoError(t, err)
+
+	var out *eth.SafeHeadResponse
+	err = client.CallContext(context.Background(), &out, "optimism_safeHeadAtL1Block", hexutil.Uint64(l1Block.Number+10))
+	require.NoError(t, err)
+	require.Equal(t, &eth.SafeHeadResponse{L1Block: l1Block, SafeHead: safeHead.ID()}, out)
+
+	err = client.CallContext(context.Background(), &out, "optimism_safeHeadAtL1Block", hexutil.Uint64(l1Block.Number-1))
+	require.ErrorContains(t, err, safedb.ErrNotFound.Error())
+}
+
+type mockDAConfirmationsSource struct {
+	mock.Mock
+}
+
+func (m *mockDAConfirmationsSource) DAConfirmations(ctx context.Context, batcherAddr common.Address, l1Start, l1End uint64) (*eth.DAConfirmations, error) {
+	out := m.Mock.MethodCalled("DAConfirmations", batcherAddr, l1Start, l1End)
+	return out.Get(0).(*eth.DAConfirmations), out.Error(1)
+}
+
 type mockDriverClient struct {
 	mock.Mock
 }
@@ -98,6 +98,9 @@ func (n *NodeP2P) init(resourcesCtx context.Context, rollupCfg *rollup.Config, l
 				// register the sync protocol with libp2p host
 				payloadByNumber := MakeStreamHandler(resourcesCtx, log.New("serve", "payloads_by_number"), n.syncSrv.HandleSyncRequest)
 				n.host.SetStreamHandler(PayloadByNumberProtocolID(rollupCfg.L2ChainID), payloadByNumber)
+				// the v1 protocol is served alongside v0, for backwards compatibility with v0-only clients
+				payloadByNumberV1 := MakeStreamHandler(resourcesCtx, log.New("serve", "payloads_by_range"), n.syncSrv.HandleSyncRangeRequest)
+				n.host.SetStreamHandler(PayloadByNumberV1ProtocolID(rollupCfg.L2ChainID), payloadByNumberV1)
 			}
 		}
 		// notify of any new connections/streams/etc.
@@ -59,12 +59,27 @@ const (
 	// and eventually kick the peer based on degraded scoring if it's really not serving us well.
 	// TODO(CLI-4009): Use a backoff rather than this mechanism.
 	clientErrRateCost = peerServerBlocksBurst
+	// Max number of payloads a server will stream back in response to a single v1 range request.
+	// Requests for more payloads are served partially.
+	maxPayloadsByRangeCount = 64
+	// Number of contiguous blocks the client assigns to a peer as a single range request.
+	// Smaller than the server limit, so the work of a larger range is spread between peers.
+	clientRangeRequestSize = 16
+	// Max number of range requests that are buffered for peers to pick up.
+	// Together with clientRangeRequestSize this bounds the blocks in-flight, relative to the quarantine size.
+	clientRangeRequestsBuffer = 8
 )
 
 func PayloadByNumberProtocolID(l2ChainID *big.Int) protocol.ID {
 	return protocol.ID(fmt.Sprintf("/opstack/req/payload_by_number/%d/0", l2ChainID))
 }
 
+// PayloadByNumberV1ProtocolID is the v1 version of the payload_by_number protocol:
+// a contiguous range of payloads is requested at once, and streamed back in chunks, from high to low.
+func PayloadByNumberV1ProtocolID(l2ChainID *big.Int) protocol.ID {
+	return protocol.ID(fmt.Sprintf("/opstack/req/payload_by_number/%d/1", l2ChainID))
+}
+
 type requestHandlerFn func(ctx context.Context, log log.Logger, stream network.Stream)
 
 func MakeStreamHandler(resourcesCtx context.Context, log log.Logger, fn requestHandlerFn) network.StreamHandler {
@@ -94,8 +109,10 @@ type syncResult struct {
 	peer    peer.ID
 }
 
+// peerRequest is a request for the contiguous range of blocks [start, start+count)
 type peerRequest struct {
-	num uint64
+	start uint64
+	count uint64
 
 	complete *atomic.Bool
 }
@@ -118,6 +135,7 @@ type SyncClientMetrics interface {
 // The sync mechanism is implemented as following:
 // - User sends range request: blocks on sync main loop (with ctx timeout)
 // - Main loop processes range request (from high to low), dividing block requests by number between parallel peers.
+//   - Contiguous block numbers are grouped into smaller ranges, each range is picked up by a single peer.
 //   - The high part of the range has a known block-hash, and is marked as trusted.
 //   - Once there are no more peers available for buffering requests, we stop the range request processing.
 //   - Every request buffered for a peer is tracked as in-flight, by block number.
@@ -126,7 +144,9 @@ type SyncClientMetrics interface {
 //   - Data already in the quarantine that is trusted is attempted to be promoted.
 //
 // - Peers each have their own routine for processing requests.
-//   - They fetch the requested block by number, parse and validate it, and then send it back to the main loop
+//   - They fetch the requested range of blocks, parse and validate them, and then send them back to the main loop
+//   - Peers that support the v1 protocol stream back the full range in a single request,
+//     older v0-only peers are requested block by block.
 //   - If peers fail to fetch or process it, or fail to send it back to the main loop within timeout,
 //     then the doRequest returns an error. It then marks the in-flight request as completed.
 //
@@ -176,8 +196,9 @@ type SyncClient struct {
 
 	metrics SyncClientMetrics
 
-	newStreamFn     newStreamFn
-	payloadByNumber protocol.ID
+	newStreamFn       newStreamFn
+	payloadByNumber   protocol.ID
+	payloadByNumberV1 protocol.ID
 
 	peersLock sync.Mutex
 	// syncing worker per peer
@@ -224,21 +245,22 @@ func NewSyncClient(log log.Logger, cfg *rollup.Config, newStream newStreamFn, rc
 	ctx, cancel := context.WithCancel(context.Background())
 
 	c := &SyncClient{
-		log:             log,
-		cfg:             cfg,
-		metrics:         metrics,
-		newStreamFn:     newStream,
-		payloadByNumber: PayloadByNumberProtocolID(cfg.L2ChainID),
-		peers:           make(map[peer.ID]context.CancelFunc),
-		quarantineByNum: make(map[uint64]common.Hash),
-		inFlight:        make(map[uint64]*atomic.Bool),
-		requests:        make(chan rangeRequest), // blocking
-		peerRequests:    make(chan peerRequest, 128),
-		results:         make(chan syncResult, 128),
-		globalRL:        rate.NewLimiter(globalServerBlocksRateLimit, globalServerBlocksBurst),
-		resCtx:          ctx,
-		resCancel:       cancel,
-		receivePayload:  rcv,
+		log:               log,
+		cfg:               cfg,
+		metrics:           metrics,
+		newStreamFn:       newStream,
+		payloadByNumber:   PayloadByNumberProtocolID(cfg.L2ChainID),
+		payloadByNumberV1: PayloadByNumberV1ProtocolID(cfg.L2ChainID),
+		peers:             make(map[peer.ID]context.CancelFunc),
+		quarantineByNum:   make(map[uint64]common.Hash),
+		inFlight:          make(map[uint64]*atomic.Bool),
+		requests:          make(chan rangeRequest), // blocking
+		peerRequests:      make(chan peerRequest, clientRangeRequestsBuffer),
+		results:           make(chan syncResult, 128),
+		globalRL:          rate.NewLimiter(globalServerBlocksRateLimit, globalServerBlocksBurst),
+		resCtx:            ctx,
+		resCancel:         cancel,
+		receivePayload:    rcv,
 	}
 	// never errors with positive LRU cache size
 	// TODO(CLI-3733): if we had an LRU based on on total payloads size, instead of payload count,
@@ -352,9 +374,14 @@ func (s *SyncClient) onRangeRequest(ctx context.Context, req rangeRequest) {
 	}
 
 	// Now try to fetch lower numbers than current end, to traverse back towards the updated start.
+	// Contiguous numbers that still need to be fetched are grouped into ranges,
+	// each range is picked up by a single peer, so different peers fetch different ranges in parallel.
+	// pending is the current group of numbers, in descending order.
+	pending := make([]uint64, 0, clientRangeRequestSize)
 	for i := uint64(0); ; i++ {
 		num := req.end.Number - 1 - i
 		if num <= req.start {
+			s.schedulePeerRequest(ctx, log, pending)
 			return
 		}
 		// check if we have something in quarantine already
@@ -364,26 +391,52 @@ func (s *SyncClient) onRangeRequest(ctx context.Context, req rangeRequest) {
 			}
 			// Don't fetch things that we have a candidate for already.
 			// We'll evict it from quarantine by finding a conflict, or if we sync enough other blocks
+			if !s.schedulePeerRequest(ctx, log, pending) {
+				return
+			}
+			pending = pending[:0]
 			continue
 		}
 
 		if _, ok := s.inFlight[num]; ok {
-			continue // request still in flight
+			// request still in flight
+			if !s.schedulePeerRequest(ctx, log, pending) {
+				return
+			}
+			pending = pending[:0]
+			continue
+		}
+		pending = append(pending, num)
+		if len(pending) == clientRangeRequestSize {
+			if !s.schedulePeerRequest(ctx, log, pending) {
+				return
+			}
+			pending = pending[:0]
 		}
-		pr := peerRequest{num: num, complete: new(atomic.Bool)}
+	}
+}
 
-		log.Debug("Scheduling P2P block request", "num", num)
-		// schedule number
-		select {
-		case s.peerRequests <- pr:
+// schedulePeerRequest schedules the given contiguous descending block numbers as a single range request for a peer.
+// It returns false if the request could not be scheduled, in which case no more requests should be scheduled.
+func (s *SyncClient) schedulePeerRequest(ctx context.Context, log log.Logger, nums []uint64) bool {
+	if len(nums) == 0 {
+		return true
+	}
+	pr := peerRequest{start: nums[len(nums)-1], count: uint64(len(nums)), complete: new(atomic.Bool)}
+
+	log.Debug("Scheduling P2P blocks request", "start", pr.start, "count", pr.count)
+	select {
+	case s.peerRequests <- pr:
+		for _, num := range nums {
 			s.inFlight[num] = pr.complete
-		case <-ctx.Done():
-			log.Info("did not schedule full P2P sync range", "current", num, "err", ctx.Err())
-			return
-		default: // peers may all be busy processing requests already
-			log.Info("no peers ready to handle block requests for more P2P requests for L2 block history", "current", num)
-			return
 		}
+		return true
+	case <-ctx.Done():
+		log.Info("did not schedule full P2P sync range", "current", nums[0], "err", ctx.Err())
+		return false
+	default: // peers may all be busy processing requests already
+		log.Info("no peers ready to handle block requests for more P2P requests for L2 block history", "current", nums[0])
+		return false
 	}
 }
 
@@ -488,18 +541,20 @@ func (s *SyncClient) peerLoop(ctx context.Context, id peer.ID) {
 			// We already established the peer is available w.r.t. rate-limiting,
 			// and this is the only loop over this peer, so we can request now.
 			start := time.Now()
-			err := s.doRequest(ctx, id, pr.num)
+			err := s.doRequest(ctx, id, rl, pr)
+			// Mark as complete: results have been sent back to the main loop already.
+			// Any blocks of the range we did not get results for, e.g. because of an error,
+			// or because the server returned a shorter range, can be requested again.
+			pr.complete.Store(true)
 			if err != nil {
-				// mark as complete if there's an error: we are not sending any result and can complete immediately.
-				pr.complete.Store(true)
-				log.Warn("failed p2p sync request", "num", pr.num, "err", err)
+				log.Warn("failed p2p sync request", "start", pr.start, "count", pr.count, "err", err)
 				// If we hit an error, then count it as many requests.
 				// We'd like to avoid making more requests for a while, to back off.
 				if err := rl.WaitN(ctx, clientErrRateCost); err != nil {
 					return
 				}
 			} else {
-				log.Debug("completed p2p sync request", "num", pr.num)
+				log.Debug("completed p2p sync request", "start", pr.start, "count", pr.count)
 			}
 			took := time.Since(start)
 			// TODO(CLI-3732): update scores: depending on the speed of the result,
@@ -514,7 +569,7 @@ func (s *SyncClient) peerLoop(ctx context.Context, id peer.ID) {
 					resultCode = 1
 				}
 			}
-			s.metrics.ClientPayloadByNumberEvent(pr.num, resultCode, took)
+			s.metrics.ClientPayloadByNumberEvent(pr.start, resultCode, took)
 		case <-ctx.Done():
 			return
 		}
@@ -531,15 +586,53 @@ func (r requestResultErr) ResultCode() byte {
 	return byte(r)
 }
 
-func (s *SyncClient) doRequest(ctx context.Context, id peer.ID, n uint64) error {
-	// open stream to peer
+// doRequest fetches the requested range of blocks from the peer, and sends the results back to the main loop.
+// The v1 protocol is preferred, to stream the full range with a single request.
+// If the peer only supports v0, then the blocks are requested one by one (from high to low), subject to rate-limits.
+func (s *SyncClient) doRequest(ctx context.Context, id peer.ID, rl *rate.Limiter, pr peerRequest) error {
+	str, err := s.openStream(ctx, id, s.payloadByNumberV1, s.payloadByNumber)
+	if err != nil {
+		return err
+	}
+	if str.Protocol() == s.payloadByNumberV1 {
+		defer str.Close()
+		return s.doRangeRequest(ctx, id, str, pr.start+pr.count-1, pr.count)
+	}
+	for i := uint64(0); i < pr.count; i++ {
+		if i > 0 {
+			// the first block was accounted for by the peer loop already
+			if err := s.globalRL.Wait(ctx); err != nil {
+				return err
+			}
+			if err := rl.Wait(ctx); err != nil {
+				return err
+			}
+			str, err = s.openStream(ctx, id, s.payloadByNumber)
+			if err != nil {
+				return err
+			}
+		}
+		err := s.doNumberRequest(ctx, id, str, pr.start+pr.count-1-i)
+		_ = str.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *SyncClient) openStream(ctx context.Context, id peer.ID, protocolIDs ...protocol.ID) (network.Stream, error) {
 	reqCtx, reqCancel := context.WithTimeout(ctx, streamTimeout)
-	str, err := s.newStreamFn(reqCtx, id, s.payloadByNumber)
-	reqCancel()
+	defer reqCancel()
+	str, err := s.newStreamFn(reqCtx, id, protocolIDs...)
 	if err != nil {
-		return fmt.Errorf("failed to open stream: %w", err)
+		return nil, fmt.Errorf("failed to open stream: %w", err)
 	}
-	defer str.Close()
+	return str, nil
+}
+
+// doNumberRequest requests a single block by number, with the v0 protocol.
+func (s *SyncClient) doNumberRequest(ctx context.Context, id peer.ID, str network.Stream, n uint64) error {
 	// set write timeout (if available)
 	_ = str.SetWriteDeadline(time.Now().Add(clientWriteRequestTimeout))
 	if err := binary.Write(str, binary.LittleEndian, n); err != nil {
@@ -567,35 +660,108 @@ func (s *SyncClient) doRequest(ctx context.Context, id peer.ID, n uint64) error
 	if _, err := io.ReadFull(r, versionData[:]); err != nil {
 		return fmt.Errorf("failed to read version part of response: %w", err)
 	}
-	version := binary.LittleEndian.Uint32(versionData[:])
+	// The server does not prepend the payload size, nor would we trust a claimed length anyway,
+	// so the payload is read till EOF.
+	res, err := decodePayload(binary.LittleEndian.Uint32(versionData[:]), r)
+	if err != nil {
+		return err
+	}
+	if err := str.CloseRead(); err != nil {
+		return fmt.Errorf("failed to close reading side")
+	}
+	if err := verifyBlock(res, n); err != nil {
+		return fmt.Errorf("received execution payload is invalid: %w", err)
+	}
+	select {
+	case s.results <- syncResult{payload: res, peer: id}:
+	case <-ctx.Done():
+		return fmt.Errorf("failed to process response, sync client is too busy: %w", ctx.Err())
+	}
+	return nil
+}
+
+// doRangeRequest requests count blocks, starting at block n and going back, with the v1 protocol,
+// and sends each block back to the main loop as soon as its response chunk is received and verified.
+func (s *SyncClient) doRangeRequest(ctx context.Context, id peer.ID, str network.Stream, n, count uint64) error {
+	// set write timeout (if available)
+	_ = str.SetWriteDeadline(time.Now().Add(clientWriteRequestTimeout))
+	var req [16]byte
+	binary.LittleEndian.PutUint64(req[:8], n)
+	binary.LittleEndian.PutUint64(req[8:], count)
+	if _, err := str.Write(req[:]); err != nil {
+		return fmt.Errorf("failed to write request (%d, %d): %w", n, count, err)
+	}
+	if err := str.CloseWrite(); err != nil {
+		return fmt.Errorf("failed to close writer side while making request: %w", err)
+	}
+
+	var parent common.Hash
+	for i := uint64(0); ; i++ {
+		// set read timeout per chunk (if available)
+		_ = str.SetReadDeadline(time.Now().Add(clientReadResponsetimeout))
+
+		var result [1]byte
+		if _, err := io.ReadFull(str, result[:]); err == io.EOF && i > 0 {
+			// The server may serve less than the full range. The remaining blocks can be requested again.
+			break
+		} else if err != nil {
+			return fmt.Errorf("failed to read result part of response chunk %d: %w", i, err)
+		}
+		if res := result[0]; res != 0 {
+			return requestResultErr(res)
+		}
+		if i >= count {
+			return fmt.Errorf("peer responded with more than the %d requested payloads", count)
+		}
+		var header [8]byte
+		if _, err := io.ReadFull(str, header[:]); err != nil {
+			return fmt.Errorf("failed to read header part of response chunk %d: %w", i, err)
+		}
+		size := binary.LittleEndian.Uint32(header[4:])
+		if size > maxGossipSize {
+			return fmt.Errorf("response chunk %d of %d bytes exceeds the max size", i, size)
+		}
+		res, err := decodePayload(binary.LittleEndian.Uint32(header[:4]), io.LimitReader(str, int64(size)))
+		if err != nil {
+			return err
+		}
+		if err := verifyBlock(res, n-i); err != nil {
+			return fmt.Errorf("received execution payload is invalid: %w", err)
+		}
+		if i > 0 && res.BlockHash != parent {
+			return fmt.Errorf("received execution payload %s is not the parent %s of the previous payload of the range", res.ID(), parent)
+		}
+		parent = res.ParentHash
+		select {
+		case s.results <- syncResult{payload: res, peer: id}:
+		case <-ctx.Done():
+			return fmt.Errorf("failed to process response, sync client is too busy: %w", ctx.Err())
+		}
+	}
+	if err := str.CloseRead(); err != nil {
+		return fmt.Errorf("failed to close reading side")
+	}
+	return nil
+}
+
+// decodePayload decodes an execution payload of the given version, read till EOF of r.
+func decodePayload(version uint32, r io.Reader) (*eth.ExecutionPayload, error) {
 	if version != 0 {
-		return fmt.Errorf("unrecognized ExecutionPayload version: %d", version)
+		return nil, fmt.Errorf("unrecognized ExecutionPayload version: %d", version)
 	}
 	// payload is SSZ encoded with Snappy framed compression
 	r = snappy.NewReader(r)
 	r = io.LimitReader(r, maxGossipSize)
 	// We cannot stream straight into the SSZ decoder, since we need the scope of the SSZ payload.
-	// The server does not prepend it, nor would we trust a claimed length anyway, so we buffer the data we get.
 	data, err := io.ReadAll(r)
 	if err != nil {
-		return fmt.Errorf("failed to read response: %w", err)
+		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
 	var res eth.ExecutionPayload
 	if err := res.UnmarshalSSZ(uint32(len(data)), bytes.NewReader(data)); err != nil {
-		return fmt.Errorf("failed to decode response: %w", err)
-	}
-	if err := str.CloseRead(); err != nil {
-		return fmt.Errorf("failed to close reading side")
-	}
-	if err := verifyBlock(&res, n); err != nil {
-		return fmt.Errorf("received execution payload is invalid: %w", err)
+		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
-	select {
-	case s.results <- syncResult{payload: &res, peer: id}:
-	case <-ctx.Done():
-		return fmt.Errorf("failed to process response, sync client is too busy: %w", err)
-	}
-	return nil
+	return &res, nil
 }
 
 func verifyBlock(payload *eth.ExecutionPayload, expectedNum uint64) error {
@@ -633,6 +799,9 @@ type ReqRespServer struct {
 
 	peerRateLimits *simplelru.LRU[peer.ID, *peerStat]
 	peerStatsLock  sync.Mutex
+	// rate-limit and burst of the per-peer rate-limiters
+	peerRateLimit rate.Limit
+	peerBurst     int
 
 	globalRequestsRL *rate.Limiter
 }
@@ -649,6 +818,8 @@ func NewReqRespServer(cfg *rollup.Config, l2 L2Chain, metrics ReqRespServerMetri
 		l2:               l2,
 		metrics:          metrics,
 		peerRateLimits:   peerRateLimits,
+		peerRateLimit:    peerServerBlocksRateLimit,
+		peerBurst:        peerServerBlocksBurst,
 		globalRequestsRL: globalRequestsRL,
 	}
 }
@@ -689,13 +860,12 @@ func (srv *ReqRespServer) HandleSyncRequest(ctx context.Context, log log.Logger,
 
 var invalidRequestErr = errors.New("invalid request")
 
-func (srv *ReqRespServer) handleSyncRequest(ctx context.Context, stream network.Stream) (uint64, error) {
-	peerId := stream.Conn().RemotePeer()
-
+// waitRateLimits takes a request token from the global and the per-peer rate-limiters.
+func (srv *ReqRespServer) waitRateLimits(ctx context.Context, peerId peer.ID) error {
 	// take a token from the global rate-limiter,
 	// to make sure there's not too much concurrent server work between different peers.
 	if err := srv.globalRequestsRL.Wait(ctx); err != nil {
-		return 0, fmt.Errorf("timed out waiting for global sync rate limit: %w", err)
+		return fmt.Errorf("timed out waiting for global sync rate limit: %w", err)
 	}
 
 	// find rate limiting data of peer, or add otherwise
@@ -703,7 +873,7 @@ func (srv *ReqRespServer) handleSyncRequest(ctx context.Context, stream network.
 	ps, _ := srv.peerRateLimits.Get(peerId)
 	if ps == nil {
 		ps = &peerStat{
-			Requests: rate.NewLimiter(peerServerBlocksRateLimit, peerServerBlocksBurst),
+			Requests: rate.NewLimiter(srv.peerRateLimit, srv.peerBurst),
 		}
 		srv.peerRateLimits.Add(peerId, ps)
 		ps.Requests.Reserve() // count the hit, but make it delay the next request rather than immediately waiting
@@ -714,10 +884,18 @@ func (srv *ReqRespServer) handleSyncRequest(ctx context.Context, stream network.
 		// We'll disconnect ourselves only when failing to read/write,
 		// if the work is invalid (range validation), or when individual sub tasks timeout.
 		if err := ps.Requests.Wait(ctx); err != nil {
-			return 0, fmt.Errorf("timed out waiting for global sync rate limit: %w", err)
+			srv.peerStatsLock.Unlock()
+			return fmt.Errorf("timed out waiting for global sync rate limit: %w", err)
 		}
 	}
 	srv.peerStatsLock.Unlock()
+	return nil
+}
+
+func (srv *ReqRespServer) handleSyncRequest(ctx context.Context, stream network.Stream) (uint64, error) {
+	if err := srv.waitRateLimits(ctx, stream.Conn().RemotePeer()); err != nil {
+		return 0, err
+	}
 
 	// Set read deadline, if available
 	_ = stream.SetReadDeadline(time.Now().Add(serverReadRequestTimeout))
@@ -770,3 +948,136 @@ func (srv *ReqRespServer) handleSyncRequest(ctx context.Context, stream network.
 	}
 	return req, nil
 }
+
+// HandleSyncRangeRequest is a stream handler function to register the v1 L2 unsafe payloads alt-sync protocol.
+// A contiguous range of payloads is requested, and streamed back from high to low, as a response chunk per payload.
+// The highest payload is served first, since the client verifies the payloads back from a trusted block.
+// Every payload of a range takes a token from the rate-limiters, like a single v0 request does,
+// so that payloads are not served faster by range than one by one.
+// See MakeStreamHandler to transform this into a LibP2P handler function.
+//
+// Note that the same peer may open parallel streams.
+//
+// The caller must Close the stream.
+func (srv *ReqRespServer) HandleSyncRangeRequest(ctx context.Context, log log.Logger, stream network.Stream) {
+	// may stay 0 if we fail to decode the request
+	start := time.Now()
+
+	// We wait as long as necessary; we throttle the peer instead of disconnecting,
+	// unless the delay reaches a threshold that is unreasonable to wait for.
+	rlCtx, cancel := context.WithTimeout(ctx, maxThrottleDelay)
+	err := srv.waitRateLimits(rlCtx, stream.Conn().RemotePeer())
+	cancel()
+
+	var req uint64
+	var served uint64
+	if err == nil {
+		req, served, err = srv.handleSyncRangeRequest(ctx, stream)
+	}
+
+	resultCode := byte(0)
+	if err != nil {
+		log.Warn("failed to serve p2p sync range request", "req", req, "served", served, "err", err)
+		if errors.Is(err, ethereum.NotFound) {
+			resultCode = 1
+		} else if errors.Is(err, invalidRequestErr) {
+			resultCode = 2
+		} else {
+			resultCode = 3
+		}
+		// try to write the error code as final chunk, so the other peer can understand the reason for failure.
+		_, _ = stream.Write([]byte{resultCode})
+	} else {
+		log.Debug("successfully served sync range response", "req", req, "served", served)
+	}
+	srv.metrics.ServerPayloadByNumberEvent(req, resultCode, time.Since(start))
+}
+
+// handleSyncRangeRequest serves the payloads of the requested range, and returns the highest requested block number,
+// and the number of payloads that were served.
+func (srv *ReqRespServer) handleSyncRangeRequest(ctx context.Context, stream network.Stream) (uint64, uint64, error) {
+	// Set read deadline, if available
+	_ = stream.SetReadDeadline(time.Now().Add(serverReadRequestTimeout))
+
+	// Read the request
+	var reqData [16]byte
+	if _, err := io.ReadFull(stream, reqData[:]); err != nil {
+		return 0, 0, fmt.Errorf("failed to read requested block range: %w", err)
+	}
+	if err := stream.CloseRead(); err != nil {
+		return 0, 0, fmt.Errorf("failed to close reading-side of a P2P sync range request call: %w", err)
+	}
+	req := binary.LittleEndian.Uint64(reqData[:8])
+	count := binary.LittleEndian.Uint64(reqData[8:])
+
+	// Check the request is within the expected range of blocks
+	if count == 0 {
+		return req, 0, fmt.Errorf("cannot serve empty range request: %w", invalidRequestErr)
+	}
+	if req < srv.cfg.Genesis.L2.Number {
+		return req, 0, fmt.Errorf("cannot serve request for L2 block %d before genesis %d: %w", req, srv.cfg.Genesis.L2.Number, invalidRequestErr)
+	}
+	max, err := srv.cfg.TargetBlockNumber(uint64(time.Now().Unix()))
+	if err != nil {
+		return req, 0, fmt.Errorf("cannot determine max target block number to verify request: %w", invalidRequestErr)
+	}
+	if req > max {
+		return req, 0, fmt.Errorf("cannot serve request for L2 block %d after max expected block (%v): %w", req, max, invalidRequestErr)
+	}
+	// Serve less than requested if the range is too large, or extends past genesis.
+	if count > maxPayloadsByRangeCount {
+		count = maxPayloadsByRangeCount
+	}
+	if count > req-srv.cfg.Genesis.L2.Number+1 {
+		count = req - srv.cfg.Genesis.L2.Number + 1
+	}
+
+	var buf bytes.Buffer
+	for i := uint64(0); i < count; i++ {
+		num := req - i
+		// Every payload costs a rate-limit token, like a request for a single payload does:
+		// the token of the first payload was taken before reading the request.
+		if i > 0 {
+			rlCtx, cancel := context.WithTimeout(ctx, maxThrottleDelay)
+			err := srv.waitRateLimits(rlCtx, stream.Conn().RemotePeer())
+			cancel()
+			if err != nil {
+				return req, i, err
+			}
+		}
+		payload, err := srv.l2.PayloadByNumber(ctx, num)
+		if err != nil {
+			if errors.Is(err, ethereum.NotFound) {
+				return req, i, fmt.Errorf("peer requested unknown block %d by number: %w", num, err)
+			} else {
+				return req, i, fmt.Errorf("failed to retrieve payload %d to serve to peer: %w", num, err)
+			}
+		}
+
+		// Compress the payload first, the chunk is prefixed with the size of the compressed data.
+		buf.Reset()
+		w := snappy.NewBufferedWriter(&buf)
+		if _, err := payload.MarshalSSZ(w); err != nil {
+			return req, i, fmt.Errorf("failed to encode payload %d for sync response: %w", num, err)
+		}
+		if err := w.Close(); err != nil {
+			return req, i, fmt.Errorf("failed to finish encoding payload %d for sync response: %w", num, err)
+		}
+
+		// We set write deadline per chunk, if available, to safely write without blocking on a throttling peer connection
+		_ = stream.SetWriteDeadline(time.Now().Add(serverWriteChunkTimeout))
+
+		// 0 - resultCode: success = 0
+		// 1:5 - version: 0
+		// 5:9 - size of the compressed payload
+		var header [9]byte
+		binary.LittleEndian.PutUint32(header[5:], uint32(buf.Len()))
+		if _, err := stream.Write(header[:]); err != nil {
+			return req, i, fmt.Errorf("failed to write response chunk header data: %w", err)
+		}
+		if _, err := stream.Write(buf.Bytes()); err != nil {
+			return req, i, fmt.Errorf("failed to write payload %d to sync response: %w", num, err)
+		}
+	}
+	return req, count, nil
+}
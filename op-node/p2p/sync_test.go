@@ -1,15 +1,20 @@
 package p2p
 
 import (
+	"bytes"
 	"context"
+	"encoding/binary"
+	"io"
 	"math/big"
 	"testing"
+	"time"
 
 	"github.com/libp2p/go-libp2p/core/host"
 	"github.com/libp2p/go-libp2p/core/network"
 	"github.com/libp2p/go-libp2p/core/peer"
 	mocknet "github.com/libp2p/go-libp2p/p2p/net/mock"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
@@ -70,7 +75,17 @@ func setupSyncTestData(length uint64) (*rollup.Config, map[uint64]*eth.Execution
 
 func TestSinglePeerSync(t *testing.T) {
 	t.Parallel() // Takes a while, but can run in parallel
+	t.Run("v0", func(t *testing.T) {
+		t.Parallel()
+		testSinglePeerSync(t, false)
+	})
+	t.Run("v1", func(t *testing.T) {
+		t.Parallel()
+		testSinglePeerSync(t, true)
+	})
+}
 
+func testSinglePeerSync(t *testing.T, serveV1 bool) {
 	log := testlog.Logger(t, log.LvlError)
 
 	cfg, payloads, l2Ref := setupSyncTestData(25)
@@ -106,6 +121,10 @@ func TestSinglePeerSync(t *testing.T) {
 	srv := NewReqRespServer(cfg, servePayload, metrics.NoopMetrics)
 	payloadByNumber := MakeStreamHandler(ctx, log.New("role", "server"), srv.HandleSyncRequest)
 	hostA.SetStreamHandler(PayloadByNumberProtocolID(cfg.L2ChainID), payloadByNumber)
+	if serveV1 {
+		payloadsByRange := MakeStreamHandler(ctx, log.New("role", "server"), srv.HandleSyncRangeRequest)
+		hostA.SetStreamHandler(PayloadByNumberV1ProtocolID(cfg.L2ChainID), payloadsByRange)
+	}
 
 	// Setup host B as the client
 	cl := NewSyncClient(log.New("role", "client"), cfg, hostB.NewStream, receivePayload, metrics.NoopMetrics)
@@ -135,7 +154,7 @@ func TestMultiPeerSync(t *testing.T) {
 
 	cfg, payloads, l2Ref := setupSyncTestData(100)
 
-	setupPeer := func(ctx context.Context, h host.Host) (*SyncClient, chan *eth.ExecutionPayload) {
+	setupPeer := func(ctx context.Context, h host.Host, serveV1 bool) (*SyncClient, chan *eth.ExecutionPayload) {
 		// Serving payloads: just load them from the map, if they exist
 		servePayload := mockPayloadFn(func(n uint64) (*eth.ExecutionPayload, error) {
 			p, ok := payloads[n]
@@ -156,6 +175,10 @@ func TestMultiPeerSync(t *testing.T) {
 		srv := NewReqRespServer(cfg, servePayload, metrics.NoopMetrics)
 		payloadByNumber := MakeStreamHandler(ctx, log.New("serve", "payloads_by_number"), srv.HandleSyncRequest)
 		h.SetStreamHandler(PayloadByNumberProtocolID(cfg.L2ChainID), payloadByNumber)
+		if serveV1 {
+			payloadsByRange := MakeStreamHandler(ctx, log.New("serve", "payloads_by_range"), srv.HandleSyncRangeRequest)
+			h.SetStreamHandler(PayloadByNumberV1ProtocolID(cfg.L2ChainID), payloadsByRange)
+		}
 
 		cl := NewSyncClient(log.New("role", "client"), cfg, h.NewStream, receivePayload, metrics.NoopMetrics)
 		return cl, received
@@ -172,9 +195,10 @@ func TestMultiPeerSync(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	clA, recvA := setupPeer(ctx, hostA)
-	clB, recvB := setupPeer(ctx, hostB)
-	clC, _ := setupPeer(ctx, hostC)
+	// C only serves the v0 protocol, A and B serve both v0 and v1
+	clA, recvA := setupPeer(ctx, hostA, true)
+	clB, recvB := setupPeer(ctx, hostB, true)
+	clC, _ := setupPeer(ctx, hostC, false)
 
 	// Make them all sync from each other
 	clA.AddPeer(hostB.ID())
@@ -219,10 +243,126 @@ func TestMultiPeerSync(t *testing.T) {
 	// And request a range again, 25 is there now, and 21-24 should follow quickly (some may already have been fetched and wait in quarantine)
 	require.NoError(t, clB.RequestL2Range(ctx, l2Ref(20), l2Ref(26)))
 
-	for i := uint64(25); i > 20; i-- {
-		p := <-recvB
-		exp, ok := payloads[uint64(p.BlockNumber)]
-		require.True(t, ok, "expecting known payload")
-		require.Equal(t, exp.BlockHash, p.BlockHash, "expecting the correct payload")
+	// The failed range request may not have been marked as completed yet when we re-requested,
+	// so repeat the range request, towards the last received block, like the driver does.
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for i := uint64(25); i > 20; {
+		select {
+		case p := <-recvB:
+			exp, ok := payloads[uint64(p.BlockNumber)]
+			require.True(t, ok, "expecting known payload")
+			require.Equal(t, exp.BlockHash, p.BlockHash, "expecting the correct payload")
+			i = uint64(p.BlockNumber) - 1
+		case <-ticker.C:
+			require.NoError(t, clB.RequestL2Range(ctx, l2Ref(20), l2Ref(i+1)))
+		}
+	}
+}
+
+func TestSyncRangeRequest(t *testing.T) {
+	log := testlog.Logger(t, log.LvlError)
+
+	cfg, payloads, _ := setupSyncTestData(100)
+	delete(payloads, 80) // create a gap
+
+	servePayload := mockPayloadFn(func(n uint64) (*eth.ExecutionPayload, error) {
+		p, ok := payloads[n]
+		if !ok {
+			return nil, ethereum.NotFound
+		}
+		return p, nil
+	})
+
+	mnet, err := mocknet.FullMeshConnected(2)
+	require.NoError(t, err, "failed to setup mocknet")
+	defer mnet.Close()
+	hosts := mnet.Hosts()
+	hostA, hostB := hosts[0], hosts[1]
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	srv := NewReqRespServer(cfg, servePayload, metrics.NoopMetrics)
+	// every payload takes a rate-limit token, the test requests more payloads than the limits allow quickly
+	srv.globalRequestsRL = rate.NewLimiter(rate.Inf, 1)
+	srv.peerRateLimit = rate.Inf
+	hostA.SetStreamHandler(PayloadByNumberV1ProtocolID(cfg.L2ChainID),
+		MakeStreamHandler(ctx, log.New("role", "server"), srv.HandleSyncRangeRequest))
+
+	// request sends a range request, and returns the decoded payloads and the final result code of the response
+	request := func(num, count uint64) (out []*eth.ExecutionPayload, resultCode byte) {
+		str, err := hostB.NewStream(ctx, hostA.ID(), PayloadByNumberV1ProtocolID(cfg.L2ChainID))
+		require.NoError(t, err)
+		defer str.Close()
+		var req [16]byte
+		binary.LittleEndian.PutUint64(req[:8], num)
+		binary.LittleEndian.PutUint64(req[8:], count)
+		_, err = str.Write(req[:])
+		require.NoError(t, err)
+		require.NoError(t, str.CloseWrite())
+		data, err := io.ReadAll(str)
+		require.NoError(t, err)
+		r := bytes.NewReader(data)
+		for r.Len() > 0 {
+			res, _ := r.ReadByte()
+			if res != 0 {
+				require.Zero(t, r.Len(), "error code must be the final chunk")
+				return out, res
+			}
+			var header [8]byte
+			_, err := io.ReadFull(r, header[:])
+			require.NoError(t, err)
+			require.Zero(t, binary.LittleEndian.Uint32(header[:4]), "version")
+			p, err := decodePayload(0, io.LimitReader(r, int64(binary.LittleEndian.Uint32(header[4:]))))
+			require.NoError(t, err)
+			out = append(out, p)
+		}
+		return out, 0
 	}
+
+	t.Run("range", func(t *testing.T) {
+		out, res := request(29, 20)
+		require.Zero(t, res)
+		require.Len(t, out, 20)
+		for i, p := range out {
+			require.Equal(t, payloads[29-uint64(i)].BlockHash, p.BlockHash, "expecting payloads from high to low")
+		}
+	})
+	t.Run("max count", func(t *testing.T) {
+		out, res := request(70, 1000)
+		require.Zero(t, res)
+		require.Len(t, out, maxPayloadsByRangeCount, "server should cap the range")
+		require.Equal(t, payloads[70-maxPayloadsByRangeCount+1].BlockHash, out[len(out)-1].BlockHash)
+	})
+	t.Run("genesis", func(t *testing.T) {
+		out, res := request(5, 10)
+		require.Zero(t, res)
+		require.Len(t, out, 6, "server should not serve past genesis")
+		require.Equal(t, payloads[0].BlockHash, out[len(out)-1].BlockHash)
+	})
+	t.Run("gap", func(t *testing.T) {
+		out, res := request(84, 10)
+		require.Equal(t, byte(1), res, "expecting not-found result after the served part of the range")
+		require.Len(t, out, 4)
+		require.Equal(t, payloads[81].BlockHash, out[len(out)-1].BlockHash)
+	})
+	t.Run("empty", func(t *testing.T) {
+		out, res := request(10, 0)
+		require.Equal(t, byte(2), res)
+		require.Empty(t, out)
+	})
+	t.Run("rate limit", func(t *testing.T) {
+		// limits that do not refill during the test, so the taken tokens can be counted
+		srv.globalRequestsRL = rate.NewLimiter(rate.Every(time.Hour), 20)
+		srv.peerRateLimit, srv.peerBurst = rate.Every(time.Hour), 10
+		srv.peerRateLimits.Purge()
+		out, res := request(50, 6)
+		require.Zero(t, res)
+		require.Len(t, out, 6)
+		ps, ok := srv.peerRateLimits.Get(hostB.ID())
+		require.True(t, ok)
+		require.InDelta(t, 4, ps.Requests.Tokens(), 0.01, "every payload must take a token from the peer")
+		require.InDelta(t, 14, srv.globalRequestsRL.Tokens(), 0.01, "every payload must take a global token")
+	})
 }
@@ -4,9 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"github.com/ethereum-optimism/optimism/op-service/txmgr"
 	"io"
-	"os"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
@@ -17,10 +15,9 @@ import (
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 )
 
-const (
-	HashLength         = 32
-	NumConfirmationsDA = 30
-)
+// NumConfirmationsDA is the number of DA-layer blocks that data referenced from L1 must be buried under
+// before it is used for derivation.
+const NumConfirmationsDA = 30
 
 type DataIter interface {
 	Next(ctx context.Context) (eth.Data, error)
@@ -28,25 +25,25 @@ type DataIter interface {
 
 type L1TransactionFetcher interface {
 	InfoAndTxsByHash(ctx context.Context, hash common.Hash) (eth.BlockInfo, types.Transactions, error)
-	DADataByTxHash(ctx context.Context, hash common.Hash) (eth.Data, uint64, error)
 }
 
 // DataSourceFactory readers raw transactions from a given block & then filters for
 // batch submitter transactions.
 // This is not a stage in the pipeline, but a wrapper for another stage in the pipeline
 type DataSourceFactory struct {
-	log     log.Logger
-	cfg     *rollup.Config
-	fetcher L1TransactionFetcher
+	log       log.Logger
+	cfg       *rollup.Config
+	fetcher   L1TransactionFetcher
+	daFetcher *DAFetcher
 }
 
-func NewDataSourceFactory(log log.Logger, cfg *rollup.Config, fetcher L1TransactionFetcher) *DataSourceFactory {
-	return &DataSourceFactory{log: log, cfg: cfg, fetcher: fetcher}
+func NewDataSourceFactory(log log.Logger, cfg *rollup.Config, fetcher L1TransactionFetcher, daFetcher *DAFetcher) *DataSourceFactory {
+	return &DataSourceFactory{log: log, cfg: cfg, fetcher: fetcher, daFetcher: daFetcher}
 }
 
 // OpenData returns a DataIter. This struct implements the `Next` function.
 func (ds *DataSourceFactory) OpenData(ctx context.Context, id eth.BlockID, batcherAddr common.Address) DataIter {
-	return NewDataSource(ctx, ds.log, ds.cfg, ds.fetcher, id, batcherAddr)
+	return NewDataSource(ctx, ds.log, ds.cfg, ds.fetcher, ds.daFetcher, id, batcherAddr)
 }
 
 // DataSource is a fault tolerant approach to fetching data.
@@ -57,212 +54,57 @@ type DataSource struct {
 	open bool
 	data []eth.Data
 	// Required to re-attempt fetching
-	id      eth.BlockID
-	cfg     *rollup.Config // TODO: `DataFromEVMTransactions` should probably not take the full config
-	fetcher L1TransactionFetcher
-	log     log.Logger
+	id        eth.BlockID
+	cfg       *rollup.Config // TODO: `DataFromEVMTransactions` should probably not take the full config
+	fetcher   L1TransactionFetcher
+	daFetcher *DAFetcher
+	log       log.Logger
 
 	batcherAddr common.Address
 }
 
-// NewDataSource creates a new calldata source. It suppresses errors in fetching the L1 block if they occur.
+// NewDataSource creates a new calldata source. It suppresses errors in fetching the L1 block
+// or resolving the referenced data availability data if they occur.
 // If there is an error, it will attempt to fetch the result on the next call to `Next`.
-func NewDataSource(ctx context.Context, log log.Logger, cfg *rollup.Config, fetcher L1TransactionFetcher, block eth.BlockID, batcherAddr common.Address) DataIter {
-	_, txs, err := fetcher.InfoAndTxsByHash(ctx, block.Hash)
+func NewDataSource(ctx context.Context, log log.Logger, cfg *rollup.Config, fetcher L1TransactionFetcher, daFetcher *DAFetcher, block eth.BlockID, batcherAddr common.Address) DataIter {
+	ds := &DataSource{
+		open:        false,
+		id:          block,
+		cfg:         cfg,
+		fetcher:     fetcher,
+		daFetcher:   daFetcher,
+		log:         log,
+		batcherAddr: batcherAddr,
+	}
+	info, txs, err := fetcher.InfoAndTxsByHash(ctx, block.Hash)
 	if err != nil {
-		return &DataSource{
-			open:        false,
-			id:          block,
-			cfg:         cfg,
-			fetcher:     fetcher,
-			log:         log,
-			batcherAddr: batcherAddr,
-		}
-	} else {
-		var resultData []eth.Data
-		l1data := DataFromEVMTransactions(cfg, batcherAddr, txs, log.New("origin", block))
-		for _, data := range l1data {
-			txHash := data[1:]
-			if data[0] == 0x00 {
-				resultData = append(resultData, data[1:])
-				return &DataSource{
-					open: true,
-					data: resultData,
-				}
-			}
-
-			if data[0] == 0x01 {
-				if len(txHash) != HashLength {
-					return &DataSource{
-						open:        false,
-						id:          block,
-						cfg:         cfg,
-						fetcher:     fetcher,
-						log:         log,
-						batcherAddr: batcherAddr,
-					}
-				}
-				if data, numConfirmations, err := fetcher.DADataByTxHash(ctx, common.BytesToHash(txHash)); err == nil {
-					if numConfirmations < NumConfirmationsDA {
-						return &DataSource{
-							open:        false,
-							id:          block,
-							cfg:         cfg,
-							fetcher:     fetcher,
-							log:         log,
-							batcherAddr: batcherAddr,
-						}
-					}
-					log.Info("retrieved data from calldata source", "data", data, "len", len(data))
-					resultData = append(resultData, data)
-				} else {
-					return &DataSource{
-						open:        false,
-						id:          block,
-						cfg:         cfg,
-						fetcher:     fetcher,
-						log:         log,
-						batcherAddr: batcherAddr,
-					}
-				}
-			}
-
-			if data[0] == 0x02 {
-				blobKey := txHash
-				daServer := os.Getenv("CELESTIA")
-				fmt.Println("CELESTIA 1", string(blobKey), "daServer", daServer)
-				data, err := txmgr.GetBlob(daServer + "/get" + string(blobKey))
-				if err == nil {
-					log.Info("retrieved data from calldata source", "data", data, "len", len(data))
-					resultData = append(resultData, data)
-				} else {
-					return &DataSource{
-						open:        false,
-						id:          block,
-						cfg:         cfg,
-						fetcher:     fetcher,
-						log:         log,
-						batcherAddr: batcherAddr,
-					}
-				}
-			}
-
-			if data[0] == 0x03 {
-				blobKey := txHash
-				daServer := os.Getenv("EIGEN")
-				fmt.Println("EIGEN 1", string(blobKey), "daServer", daServer)
-				data, err := txmgr.GetBlob(daServer + "/get" + string(blobKey))
-				if err == nil {
-					log.Info("retrieved data from calldata source", "data", data, "len", len(data))
-					resultData = append(resultData, data)
-				} else {
-					return &DataSource{
-						open:        false,
-						id:          block,
-						cfg:         cfg,
-						fetcher:     fetcher,
-						log:         log,
-						batcherAddr: batcherAddr,
-					}
-				}
-			}
-			if data[0] == 0x04 {
-				blobKey := txHash
-				daServer := os.Getenv("NEARDA")
-				fmt.Println("NEARDA 1", string(blobKey), "daServer", daServer)
-				data, err := txmgr.GetBlob(daServer + "/get" + string(blobKey))
-				if err == nil {
-					log.Info("retrieved data from calldata source", "data", data, "len", len(data))
-					resultData = append(resultData, data)
-				} else {
-					return &DataSource{
-						open:        false,
-						id:          block,
-						cfg:         cfg,
-						fetcher:     fetcher,
-						log:         log,
-						batcherAddr: batcherAddr,
-					}
-				}
-			}
-
-		}
-
-		return &DataSource{
-			open: true,
-			data: resultData,
-		}
+		return ds
 	}
+	data, err := daFetcher.Resolve(ctx, info.Time(), DataFromEVMTransactions(cfg, batcherAddr, txs, log.New("origin", block)), log)
+	if err != nil {
+		log.Warn("failed to resolve inbox data, retrying later", "origin", block, "err", err)
+		return ds
+	}
+	ds.open = true
+	ds.data = data
+	return ds
 }
 
 // Next returns the next piece of data if it has it. If the constructor failed, this
 // will attempt to reinitialize itself. If it cannot find the block it returns a ResetError
-// otherwise it returns a temporary error if fetching the block returns an error.
+// otherwise it returns a temporary error if fetching the block or the data it references returns an error,
+// or a critical error if the block references data on a backend that is not configured.
 func (ds *DataSource) Next(ctx context.Context) (eth.Data, error) {
 	if !ds.open {
-		if _, txs, err := ds.fetcher.InfoAndTxsByHash(ctx, ds.id.Hash); err == nil {
-			ds.open = true
-			var resultData []eth.Data
-			l1data := DataFromEVMTransactions(ds.cfg, ds.batcherAddr, txs, log.New("origin", ds.id))
-			for _, data := range l1data {
-				txHash := data[1:]
-				if data[0] == 0x00 {
-					resultData = append(resultData, data[1:])
-					continue
-				}
-				if data[0] == 1 {
-					if len(txHash) != HashLength {
-						return nil, NewTemporaryError(fmt.Errorf("invalid hash in calldata source %v", txHash))
-					}
-					if data, numConfirmations, err := ds.fetcher.DADataByTxHash(ctx, common.BytesToHash(txHash)); err == nil {
-						if numConfirmations < NumConfirmationsDA {
-							return nil, NewTemporaryError(fmt.Errorf("not enough confirmations for data tx %v", txHash))
-						}
-
-						log.Info("retrieved data from calldata source", "data", data, "len", len(data))
-						resultData = append(resultData, data)
-					} else {
-						return nil, NewTemporaryError(fmt.Errorf("failed to retrieve data from calldata source: %w", err))
-					}
-				}
-				if data[0] == 2 {
-					blobKey := txHash
-					daServer := os.Getenv("CELESTIA")
-					fmt.Println("CELESTIA 2", string(blobKey), "rpc", daServer)
-					data, err := txmgr.GetBlob(daServer + "/get" + string(blobKey))
-					if err == nil {
-						log.Info("retrieved data from calldata source", "data", data, "len", len(data))
-						resultData = append(resultData, data)
-					} else {
-						return nil, NewTemporaryError(fmt.Errorf("failed to retrieve data from daServer calldata source: %w", err))
-					}
-				}
-				if data[0] == 3 {
-					blobKey := txHash
-					daServer := os.Getenv("EIGEN")
-					fmt.Println("EIGEN 2", string(blobKey), "rpc", daServer)
-					data, err := txmgr.GetBlob(daServer + "/get" + string(blobKey))
-					if err == nil {
-						log.Info("retrieved data from calldata source", "data", data, "len", len(data))
-						resultData = append(resultData, data)
-					} else {
-						return nil, NewTemporaryError(fmt.Errorf("failed to retrieve data from daServer calldata source: %w", err))
-					}
-				}
-				if data[0] == 4 {
-					blobKey := txHash
-					daServer := os.Getenv("NEARDA")
-					fmt.Println("NEARDA 2", string(blobKey), "rpc", daServer)
-					data, err := txmgr.GetBlob(daServer + "/get" + string(blobKey))
-					if err == nil {
-						log.Info("retrieved data from calldata source", "data", data, "len", len(data))
-						resultData = append(resultData, data)
-					} else {
-						return nil, NewTemporaryError(fmt.Errorf("failed to retrieve data from daServer calldata source: %w", err))
-					}
-				}
+		if info, txs, err := ds.fetcher.InfoAndTxsByHash(ctx, ds.id.Hash); err == nil {
+			data, err := ds.daFetcher.Resolve(ctx, info.Time(), DataFromEVMTransactions(ds.cfg, ds.batcherAddr, txs, ds.log.New("origin", ds.id)), ds.log)
+			if errors.Is(err, ErrCritical) {
+				return nil, NewCriticalError(fmt.Errorf("failed to resolve inbox data: %w", err))
+			} else if err != nil {
+				return nil, NewTemporaryError(fmt.Errorf("failed to resolve inbox data: %w", err))
 			}
-			ds.data = resultData
+			ds.open = true
+			ds.data = data
 		} else if errors.Is(err, ethereum.NotFound) {
 			return nil, NewResetError(fmt.Errorf("failed to open calldata source: %w", err))
 		} else {
@@ -1,7 +1,12 @@
 package derive
 
 import (
+	"bytes"
+	"compress/zlib"
+	"context"
 	"crypto/ecdsa"
+	"errors"
+	"io"
 	"math/big"
 	"math/rand"
 	"testing"
@@ -9,15 +14,18 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
 
 	"github.com/ethereum-optimism/optimism/op-node/eth"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-node/testlog"
 	"github.com/ethereum-optimism/optimism/op-node/testutils"
+	"github.com/ethereum-optimism/optimism/op-service/da"
 )
 
 type testTx struct {
@@ -126,3 +134,436 @@ func TestDataFromEVMTransactions(t *testing.T) {
 	}
 
 }
+
+// testDABackend is an in-memory data availability backend with a configurable number of confirmations.
+type testDABackend struct {
+	prefix byte
+	blobs  map[string][]byte
+	confs  uint64
+}
+
+func (b *testDABackend) Prefix() byte { return b.prefix }
+
+func (b *testDABackend) Store(_ context.Context, data []byte) ([]byte, error) {
+	key := []byte{byte(len(b.blobs))}
+	b.blobs[string(key)] = data
+	return key, nil
+}
+
+func (b *testDABackend) Retrieve(_ context.Context, ref []byte) ([]byte, error) {
+	data, ok := b.blobs[string(ref)]
+	if !ok {
+		return nil, errors.New("blob not found")
+	}
+	return data, nil
+}
+
+func (b *testDABackend) Confirmations(_ context.Context, _ []byte) (uint64, error) {
+	return b.confs, nil
+}
+
+// TestDataSourceResolvesDA checks that the data source resolves inbox data through the data availability
+// backend identified by its prefix, in order, and retries until the referenced data is confirmed.
+func TestDataSourceResolvesDA(t *testing.T) {
+	rng := rand.New(rand.NewSource(1234))
+	batcherPriv := testutils.RandomKey()
+	cfg := &rollup.Config{
+		L1ChainID:         big.NewInt(100),
+		BatchInboxAddress: testutils.RandomAddress(rng),
+	}
+	batcherAddr := crypto.PubkeyToAddress(batcherPriv.PublicKey)
+	signer := cfg.L1Signer()
+
+	backend := &testDABackend{prefix: da.CelestiaPrefix, blobs: make(map[string][]byte)}
+	daSources, err := da.NewRegistry(da.NewCalldata(), backend)
+	require.NoError(t, err)
+
+	inline := testutils.RandomData(rng, 100)
+	stored := testutils.RandomData(rng, 200)
+	ref, err := backend.Store(context.Background(), stored)
+	require.NoError(t, err)
+
+	var txs types.Transactions
+	for i, data := range [][]byte{
+		append([]byte{da.CalldataPrefix}, inline...),
+		{0x7f, 0x01}, // unknown prefix is ignored
+		append([]byte{da.CelestiaPrefix}, ref...),
+	} {
+		tx, err := types.SignNewTx(batcherPriv, signer, &types.DynamicFeeTx{
+			ChainID:   signer.ChainID(),
+			Nonce:     uint64(i),
+			GasTipCap: big.NewInt(2 * params.GWei),
+			GasFeeCap: big.NewInt(30 * params.GWei),
+			Gas:       100_000,
+			To:        &cfg.BatchInboxAddress,
+			Data:      data,
+		})
+		require.NoError(t, err)
+		txs = append(txs, tx)
+	}
+
+	block := eth.BlockID{Hash: testutils.RandomHash(rng), Number: 10}
+	l1F := &testutils.MockL1Source{}
+	// the block is fetched again on every attempt to resolve the data it references
+	for i := 0; i < 3; i++ {
+		l1F.ExpectInfoAndTxsByHash(block.Hash, testutils.RandomBlockInfo(rng), txs, nil)
+	}
+
+	src := NewDataSource(context.Background(), testlog.Logger(t, log.LvlError), cfg, l1F, NewDAFetcher(cfg, daSources, nil), block, batcherAddr)
+	_, err = src.Next(context.Background())
+	require.ErrorIs(t, err, ErrTemporary, "DA data is not confirmed yet")
+
+	backend.confs = NumConfirmationsDA
+	data, err := src.Next(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, eth.Data(inline), data)
+	data, err = src.Next(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, eth.Data(stored), data)
+	_, err = src.Next(context.Background())
+	require.ErrorIs(t, err, io.EOF)
+	l1F.AssertExpectations(t)
+}
+
+// TestDataSourceBackendNotConfigured checks that data referring to a supported DA backend that is not configured
+// fails the derivation, rather than being ignored like junk inbox data.
+func TestDataSourceBackendNotConfigured(t *testing.T) {
+	rng := rand.New(rand.NewSource(1234))
+	batcherPriv := testutils.RandomKey()
+	cfg := &rollup.Config{
+		L1ChainID:         big.NewInt(100),
+		BatchInboxAddress: testutils.RandomAddress(rng),
+	}
+	batcherAddr := crypto.PubkeyToAddress(batcherPriv.PublicKey)
+	signer := cfg.L1Signer()
+
+	daSources, err := da.NewRegistry(da.NewCalldata())
+	require.NoError(t, err)
+
+	tx, err := types.SignNewTx(batcherPriv, signer, &types.DynamicFeeTx{
+		ChainID:   signer.ChainID(),
+		Nonce:     0,
+		GasTipCap: big.NewInt(2 * params.GWei),
+		GasFeeCap: big.NewInt(30 * params.GWei),
+		Gas:       100_000,
+		To:        &cfg.BatchInboxAddress,
+		Data:      append([]byte{da.CelestiaPrefix}, testutils.RandomData(rng, 32)...),
+	})
+	require.NoError(t, err)
+
+	block := eth.BlockID{Hash: testutils.RandomHash(rng), Number: 10}
+	l1F := &testutils.MockL1Source{}
+	for i := 0; i < 2; i++ {
+		l1F.ExpectInfoAndTxsByHash(block.Hash, testutils.RandomBlockInfo(rng), types.Transactions{tx}, nil)
+	}
+
+	src := NewDataSource(context.Background(), testlog.Logger(t, log.LvlError), cfg, l1F, NewDAFetcher(cfg, daSources, nil), block, batcherAddr)
+	_, err = src.Next(context.Background())
+	require.ErrorIs(t, err, ErrCritical)
+	require.ErrorIs(t, err, da.ErrNotConfigured)
+	require.NotErrorIs(t, err, io.EOF)
+	l1F.AssertExpectations(t)
+}
+
+// TestDataSourceVerifiesCommitment checks that DA data that does not match the commitment posted to L1
+// is rejected and retried, while legacy inbox data without commitment is still accepted.
+func TestDataSourceVerifiesCommitment(t *testing.T) {
+	rng := rand.New(rand.NewSource(1234))
+	batcherPriv := testutils.RandomKey()
+	cfg := &rollup.Config{
+		L1ChainID:         big.NewInt(100),
+		BatchInboxAddress: testutils.RandomAddress(rng),
+	}
+	batcherAddr := crypto.PubkeyToAddress(batcherPriv.PublicKey)
+	signer := cfg.L1Signer()
+
+	backend := &testDABackend{prefix: da.EigenPrefix, blobs: make(map[string][]byte), confs: da.Finalized}
+	daSources, err := da.NewRegistry(da.NewCalldata(), backend)
+	require.NoError(t, err)
+
+	legacy := testutils.RandomData(rng, 100)
+	legacyRef, err := backend.Store(context.Background(), legacy)
+	require.NoError(t, err)
+	committed := testutils.RandomData(rng, 200)
+	committedRef, err := backend.Store(context.Background(), committed)
+	require.NoError(t, err)
+
+	var txs types.Transactions
+	for i, data := range [][]byte{
+		append([]byte{da.EigenPrefix}, legacyRef...),
+		da.EncodePayload(da.EigenPrefix, committed, committedRef),
+	} {
+		tx, err := types.SignNewTx(batcherPriv, signer, &types.DynamicFeeTx{
+			ChainID:   signer.ChainID(),
+			Nonce:     uint64(i),
+			GasTipCap: big.NewInt(2 * params.GWei),
+			GasFeeCap: big.NewInt(30 * params.GWei),
+			Gas:       100_000,
+			To:        &cfg.BatchInboxAddress,
+			Data:      data,
+		})
+		require.NoError(t, err)
+		txs = append(txs, tx)
+	}
+
+	block := eth.BlockID{Hash: testutils.RandomHash(rng), Number: 10}
+	l1F := &testutils.MockL1Source{}
+	for i := 0; i < 3; i++ {
+		l1F.ExpectInfoAndTxsByHash(block.Hash, testutils.RandomBlockInfo(rng), txs, nil)
+	}
+
+	// the DA server hands out different data than what was committed to
+	backend.blobs[string(committedRef)] = testutils.RandomData(rng, 200)
+	src := NewDataSource(context.Background(), testlog.Logger(t, log.LvlError), cfg, l1F, NewDAFetcher(cfg, daSources, nil), block, batcherAddr)
+	_, err = src.Next(context.Background())
+	require.ErrorIs(t, err, ErrTemporary)
+	require.ErrorIs(t, err, da.ErrCommitmentMismatch)
+
+	backend.blobs[string(committedRef)] = committed
+	data, err := src.Next(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, eth.Data(legacy), data)
+	data, err = src.Next(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, eth.Data(committed), data)
+	_, err = src.Next(context.Background())
+	require.ErrorIs(t, err, io.EOF)
+	l1F.AssertExpectations(t)
+}
+
+// TestDataSourceUnpacksAggregate checks that an aggregate inbox transaction resolves to the data of all
+// the payloads it packs, in order, between the data of the surrounding inbox transactions.
+func TestDataSourceUnpacksAggregate(t *testing.T) {
+	rng := rand.New(rand.NewSource(1234))
+	batcherPriv := testutils.RandomKey()
+	cfg := &rollup.Config{
+		L1ChainID:         big.NewInt(100),
+		BatchInboxAddress: testutils.RandomAddress(rng),
+	}
+	batcherAddr := crypto.PubkeyToAddress(batcherPriv.PublicKey)
+	signer := cfg.L1Signer()
+
+	backend := &testDABackend{prefix: da.NearDAPrefix, blobs: make(map[string][]byte), confs: da.Finalized}
+	daSources, err := da.NewRegistry(da.NewCalldata(), backend)
+	require.NoError(t, err)
+
+	var stored [][]byte
+	var payloads [][]byte
+	for i := 0; i < 4; i++ {
+		data := testutils.RandomData(rng, 100+i)
+		ref, err := backend.Store(context.Background(), data)
+		require.NoError(t, err)
+		stored = append(stored, data)
+		payloads = append(payloads, da.EncodePayload(da.NearDAPrefix, data, ref))
+	}
+	aggregate, err := da.EncodeAggregatePayload(payloads[1:3])
+	require.NoError(t, err)
+
+	var txs types.Transactions
+	for i, data := range [][]byte{payloads[0], aggregate, payloads[3]} {
+		tx, err := types.SignNewTx(batcherPriv, signer, &types.DynamicFeeTx{
+			ChainID:   signer.ChainID(),
+			Nonce:     uint64(i),
+			GasTipCap: big.NewInt(2 * params.GWei),
+			GasFeeCap: big.NewInt(30 * params.GWei),
+			Gas:       100_000,
+			To:        &cfg.BatchInboxAddress,
+			Data:      data,
+		})
+		require.NoError(t, err)
+		txs = append(txs, tx)
+	}
+
+	block := eth.BlockID{Hash: testutils.RandomHash(rng), Number: 10}
+	l1F := &testutils.MockL1Source{}
+	l1F.ExpectInfoAndTxsByHash(block.Hash, testutils.RandomBlockInfo(rng), txs, nil)
+
+	src := NewDataSource(context.Background(), testlog.Logger(t, log.LvlError), cfg, l1F, NewDAFetcher(cfg, daSources, nil), block, batcherAddr)
+	for _, expected := range stored {
+		data, err := src.Next(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, eth.Data(expected), data)
+	}
+	_, err = src.Next(context.Background())
+	require.ErrorIs(t, err, io.EOF)
+	l1F.AssertExpectations(t)
+}
+
+// FuzzAggregatePayloadRoundTrip checks that aggregated payloads are decoded to the payloads they were built from,
+// and that the resolved data of each is verified against its own commitment.
+func FuzzAggregatePayloadRoundTrip(f *testing.F) {
+	f.Add([]byte("data"), []byte("blob-key"), uint8(3))
+	f.Add([]byte{}, []byte{}, uint8(1))
+	f.Fuzz(func(t *testing.T, data []byte, ref []byte, n uint8) {
+		if n == 0 || len(ref) > 1000 {
+			t.Skip()
+		}
+		var payloads [][]byte
+		for i := 0; i < int(n); i++ {
+			d := append([]byte{byte(i)}, data...)
+			payloads = append(payloads, da.EncodePayload(da.EigenPrefix, d, append([]byte{byte(i)}, ref...)))
+		}
+		aggregate, err := da.EncodeAggregatePayload(payloads)
+		require.NoError(t, err)
+		decoded, err := da.DecodePayloads(aggregate)
+		require.NoError(t, err)
+		require.Len(t, decoded, int(n))
+		for i, p := range decoded {
+			require.Equal(t, da.EigenPrefix, p.Prefix)
+			require.Equal(t, append([]byte{byte(i)}, ref...), p.Ref)
+			require.NoError(t, p.Verify(append([]byte{byte(i)}, data...)))
+			if n > 1 {
+				require.ErrorIs(t, p.Verify(append([]byte{byte((i + 1) % int(n))}, data...)), da.ErrCommitmentMismatch)
+			}
+		}
+	})
+}
+
+// FuzzDecodePayloads checks that decoding arbitrary inbox data never panics, and that every successfully decoded
+// aggregate re-encodes to the same bytes.
+func FuzzDecodePayloads(f *testing.F) {
+	payload := da.EncodePayload(da.CelestiaPrefix, []byte("data"), []byte("blob-key"))
+	aggregate, _ := da.EncodeAggregatePayload([][]byte{payload, payload})
+	f.Add(payload)
+	f.Add(aggregate)
+	f.Add([]byte{da.PayloadVersionAggregate, 0x00})
+	f.Fuzz(func(t *testing.T, b []byte) {
+		payloads, err := da.DecodePayloads(b)
+		if err != nil {
+			require.Empty(t, payloads)
+			return
+		}
+		require.NotEmpty(t, payloads)
+		if b[0] != da.PayloadVersionAggregate {
+			require.Len(t, payloads, 1)
+			return
+		}
+		var encoded [][]byte
+		for _, p := range payloads {
+			require.NotNil(t, p.Commitment, "aggregated payloads always commit to their data")
+			encoded = append(encoded, append(append([]byte{da.PayloadVersionCommitment, p.Prefix}, p.Commitment.Bytes()...), p.Ref...))
+		}
+		reencoded, err := da.EncodeAggregatePayload(encoded)
+		require.NoError(t, err)
+		require.Equal(t, b, reencoded)
+	})
+}
+
+// TestDataSourceMixedModeChannel checks that a channel derives correctly when its frames are posted partly as
+// calldata and partly on a DA backend, as the batcher does while falling back to calldata, across L1 blocks.
+func TestDataSourceMixedModeChannel(t *testing.T) {
+	rng := rand.New(rand.NewSource(1234))
+	batcherPriv := testutils.RandomKey()
+	cfg := &rollup.Config{
+		L1ChainID:         big.NewInt(100),
+		BatchInboxAddress: testutils.RandomAddress(rng),
+	}
+	batcherAddr := crypto.PubkeyToAddress(batcherPriv.PublicKey)
+	signer := cfg.L1Signer()
+
+	backend := &testDABackend{prefix: da.CelestiaPrefix, blobs: make(map[string][]byte), confs: da.Finalized}
+	daSources, err := da.NewRegistry(da.NewCalldata(), backend)
+	require.NoError(t, err)
+
+	// build a channel of a few batches, split into frames
+	var batches []*BatchData
+	var rlpBatches bytes.Buffer
+	for i := 0; i < 3; i++ {
+		batch := &BatchData{BatchV1{
+			ParentHash:   testutils.RandomHash(rng),
+			EpochNum:     rollup.Epoch(i),
+			EpochHash:    testutils.RandomHash(rng),
+			Timestamp:    uint64(i * 2),
+			Transactions: []hexutil.Bytes{testutils.RandomData(rng, 100)},
+		}}
+		batches = append(batches, batch)
+		require.NoError(t, rlp.Encode(&rlpBatches, batch))
+	}
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	_, err = zw.Write(rlpBatches.Bytes())
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	chID := ChannelID{0x01}
+	var frames [][]byte
+	var chunks [][]byte
+	for rest := compressed.Bytes(); len(rest) > 0; {
+		n := len(compressed.Bytes())/4 + 1
+		if n > len(rest) {
+			n = len(rest)
+		}
+		chunks = append(chunks, rest[:n])
+		rest = rest[n:]
+	}
+	require.Len(t, chunks, 4)
+	for i, chunk := range chunks {
+		var buf bytes.Buffer
+		buf.WriteByte(DerivationVersion0)
+		require.NoError(t, (&Frame{ID: chID, FrameNumber: uint16(i), Data: chunk, IsLast: i == len(chunks)-1}).MarshalBinary(&buf))
+		frames = append(frames, buf.Bytes())
+	}
+
+	// frames 0 and 3 are posted as calldata, frames 1 and 2 are stored on the DA backend
+	// and referenced from L1, the latter aggregated
+	storeFrame := func(frame []byte) []byte {
+		ref, err := backend.Store(context.Background(), frame)
+		require.NoError(t, err)
+		return da.EncodePayload(da.CelestiaPrefix, frame, ref)
+	}
+	aggregate, err := da.EncodeAggregatePayload([][]byte{storeFrame(frames[2])})
+	require.NoError(t, err)
+	blocks := [][][]byte{
+		{da.EncodePayload(da.CalldataPrefix, frames[0], frames[0]), storeFrame(frames[1])},
+		{aggregate, da.EncodePayload(da.CalldataPrefix, frames[3], frames[3])},
+	}
+
+	l1F := &testutils.MockL1Source{}
+	ch := NewChannel(chID, eth.L1BlockRef{Number: 10})
+	nonce := uint64(0)
+	for i, inboxData := range blocks {
+		var txs types.Transactions
+		for _, data := range inboxData {
+			tx, err := types.SignNewTx(batcherPriv, signer, &types.DynamicFeeTx{
+				ChainID:   signer.ChainID(),
+				Nonce:     nonce,
+				GasTipCap: big.NewInt(2 * params.GWei),
+				GasFeeCap: big.NewInt(30 * params.GWei),
+				Gas:       100_000,
+				To:        &cfg.BatchInboxAddress,
+				Data:      data,
+			})
+			require.NoError(t, err)
+			txs = append(txs, tx)
+			nonce++
+		}
+		ref := eth.L1BlockRef{Hash: testutils.RandomHash(rng), Number: uint64(10 + i)}
+		l1F.ExpectInfoAndTxsByHash(ref.Hash, testutils.RandomBlockInfo(rng), txs, nil)
+
+		src := NewDataSource(context.Background(), testlog.Logger(t, log.LvlError), cfg, l1F, NewDAFetcher(cfg, daSources, nil), ref.ID(), batcherAddr)
+		for {
+			data, err := src.Next(context.Background())
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			parsed, err := ParseFrames(data)
+			require.NoError(t, err)
+			for _, f := range parsed {
+				require.NoError(t, ch.AddFrame(f, ref))
+			}
+		}
+	}
+	l1F.AssertExpectations(t)
+	require.True(t, ch.IsReady())
+
+	next, err := BatchReader(ch.Reader(), eth.L1BlockRef{}, false)
+	require.NoError(t, err)
+	for _, expected := range batches {
+		batch, err := next()
+		require.NoError(t, err)
+		require.Equal(t, expected, batch.Batch)
+	}
+	_, err = next()
+	require.ErrorIs(t, err, io.EOF)
+}
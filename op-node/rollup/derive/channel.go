@@ -2,7 +2,6 @@ package derive
 
 import (
 	"bytes"
-	"compress/zlib"
 	"fmt"
 	"io"
 
@@ -144,9 +143,11 @@ func (ch *Channel) Reader() io.Reader {
 
 // BatchReader provides a function that iteratively consumes batches from the reader.
 // The L1Inclusion block is also provided at creation time.
-func BatchReader(r io.Reader, l1InclusionBlock eth.L1BlockRef) (func() (BatchWithL1InclusionBlock, error), error) {
+// The channel data is read as zlib, unless compressionActive is set, in which case
+// the compression algorithm is selected by the channel version byte.
+func BatchReader(r io.Reader, l1InclusionBlock eth.L1BlockRef, compressionActive bool) (func() (BatchWithL1InclusionBlock, error), error) {
 	// Setup decompressor stage + RLP reader
-	zr, err := zlib.NewReader(r)
+	zr, err := NewDecompressionReader(r, compressionActive)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,168 @@
+package derive
+
+import (
+	"bufio"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// CompressionAlgo is the algorithm that the channel data is compressed with.
+type CompressionAlgo string
+
+const (
+	// Zlib is the original channel compression. Its channel data has no version byte.
+	Zlib   CompressionAlgo = "zlib"
+	Brotli CompressionAlgo = "brotli"
+	Zstd   CompressionAlgo = "zstd"
+)
+
+var CompressionAlgos = []CompressionAlgo{Zlib, Brotli, Zstd}
+
+// Channel version bytes prefix the channel data of all algorithms but zlib, once the channel compression
+// upgrade is active. The first byte of zlib channel data is the zlib CMF header, with the lower 4 bits
+// set to 8 (deflate), so it can't be confused with a channel version byte.
+const (
+	ChannelVersionBrotli byte = 0x01
+	ChannelVersionZstd   byte = 0x02
+)
+
+const zlibCompressionMethod = 8
+
+// zstdWindowSize is the window size of the zstd encoder. It is below the window size limit of
+// the zstd decoder, which is the max channel size.
+const zstdWindowSize = 8 << 20
+
+var ErrUnknownChannelVersion = errors.New("unknown channel version")
+
+func (a CompressionAlgo) String() string {
+	return string(a)
+}
+
+// Check returns an error if the algorithm is not one of the CompressionAlgos.
+func (a CompressionAlgo) Check() error {
+	for _, algo := range CompressionAlgos {
+		if a == algo {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown compression algorithm: %q", a)
+}
+
+// CompressionWriter compresses channel data.
+type CompressionWriter interface {
+	io.WriteCloser
+	// Flush flushes any buffered data to the underlying writer.
+	Flush() error
+	// Reset discards the writer's state and makes it write to w, as if it was newly created.
+	Reset(w io.Writer)
+}
+
+// NewCompressionWriter creates a writer that compresses the channel data with the given algorithm at its
+// best compression level, and writes it to w, prefixed with the channel version byte of the algorithm.
+func NewCompressionWriter(algo CompressionAlgo, w io.Writer) (CompressionWriter, error) {
+	switch algo {
+	case Zlib:
+		zw, err := zlib.NewWriterLevel(w, zlib.BestCompression)
+		if err != nil {
+			return nil, err
+		}
+		return zw, nil
+	case Brotli:
+		return newVersionedWriter(ChannelVersionBrotli, brotli.NewWriterLevel(w, brotli.BestCompression), w), nil
+	case Zstd:
+		zw, err := zstd.NewWriter(w,
+			zstd.WithEncoderLevel(zstd.SpeedBestCompression),
+			zstd.WithEncoderConcurrency(1),
+			zstd.WithWindowSize(zstdWindowSize))
+		if err != nil {
+			return nil, err
+		}
+		return newVersionedWriter(ChannelVersionZstd, zw, w), nil
+	default:
+		return nil, fmt.Errorf("unknown compression algorithm: %q", algo)
+	}
+}
+
+// versionedWriter writes the channel version byte before the compressed data.
+type versionedWriter struct {
+	CompressionWriter
+	version byte
+	// err is the error writing the version byte, returned by the next write
+	err error
+}
+
+func newVersionedWriter(version byte, cw CompressionWriter, w io.Writer) *versionedWriter {
+	vw := &versionedWriter{CompressionWriter: cw, version: version}
+	vw.writeVersion(w)
+	return vw
+}
+
+func (vw *versionedWriter) writeVersion(w io.Writer) {
+	_, vw.err = w.Write([]byte{vw.version})
+}
+
+func (vw *versionedWriter) Write(p []byte) (int, error) {
+	if vw.err != nil {
+		return 0, vw.err
+	}
+	return vw.CompressionWriter.Write(p)
+}
+
+func (vw *versionedWriter) Flush() error {
+	if vw.err != nil {
+		return vw.err
+	}
+	return vw.CompressionWriter.Flush()
+}
+
+func (vw *versionedWriter) Close() error {
+	if vw.err != nil {
+		return vw.err
+	}
+	return vw.CompressionWriter.Close()
+}
+
+func (vw *versionedWriter) Reset(w io.Writer) {
+	vw.writeVersion(w)
+	vw.CompressionWriter.Reset(w)
+}
+
+// NewDecompressionReader returns a reader of the decompressed channel data.
+// Before the channel compression upgrade is active, all channel data is read as zlib.
+// Afterwards, the compression algorithm is selected by the channel version byte.
+func NewDecompressionReader(r io.Reader, compressionActive bool) (io.Reader, error) {
+	if !compressionActive {
+		return zlib.NewReader(r)
+	}
+	br := bufio.NewReader(r)
+	peek, err := br.Peek(1)
+	if err != nil {
+		return nil, fmt.Errorf("reading channel version: %w", err)
+	}
+	version := peek[0]
+	if version&0x0F == zlibCompressionMethod {
+		return zlib.NewReader(br)
+	}
+	// skip the version byte
+	_, _ = br.ReadByte()
+	switch version {
+	case ChannelVersionBrotli:
+		return brotli.NewReader(br), nil
+	case ChannelVersionZstd:
+		// Decode synchronously, and bound the window size by the max channel size to limit memory use.
+		zr, err := zstd.NewReader(br,
+			zstd.WithDecoderConcurrency(1),
+			zstd.WithDecoderMaxWindow(MaxRLPBytesPerChannel))
+		if err != nil {
+			return nil, err
+		}
+		return zr, nil
+	default:
+		return nil, fmt.Errorf("%w: %d", ErrUnknownChannelVersion, version)
+	}
+}
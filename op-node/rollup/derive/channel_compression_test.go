@@ -0,0 +1,220 @@
+package derive
+
+import (
+	"bytes"
+	"io"
+	"math/rand"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-node/testutils"
+)
+
+// algoCompressor is a basic implementation of the Compressor interface that compresses
+// with the given algorithm and is never full.
+type algoCompressor struct {
+	bytes.Buffer
+	w CompressionWriter
+}
+
+func newAlgoCompressor(t require.TestingT, algo CompressionAlgo) *algoCompressor {
+	c := &algoCompressor{}
+	w, err := NewCompressionWriter(algo, &c.Buffer)
+	require.NoError(t, err)
+	c.w = w
+	return c
+}
+
+func (c *algoCompressor) Write(p []byte) (int, error) {
+	return c.w.Write(p)
+}
+
+func (c *algoCompressor) Close() error {
+	return c.w.Close()
+}
+
+func (c *algoCompressor) Reset() {
+	c.Buffer.Reset()
+	c.w.Reset(&c.Buffer)
+}
+
+func (c *algoCompressor) Flush() error {
+	return c.w.Flush()
+}
+
+func (c *algoCompressor) FullErr() error {
+	return nil
+}
+
+func compress(t require.TestingT, algo CompressionAlgo, data ...[]byte) []byte {
+	var buf bytes.Buffer
+	w, err := NewCompressionWriter(algo, &buf)
+	require.NoError(t, err)
+	for _, d := range data {
+		_, err := w.Write(d)
+		require.NoError(t, err)
+		require.NoError(t, w.Flush())
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func decompress(data []byte, compressionActive bool) ([]byte, error) {
+	r, err := NewDecompressionReader(bytes.NewReader(data), compressionActive)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+func TestCompressionRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1234))
+	data := [][]byte{testutils.RandomData(rng, 100), bytes.Repeat([]byte{0x42}, 1000), testutils.RandomData(rng, 10)}
+	concat := bytes.Join(data, nil)
+	for _, algo := range CompressionAlgos {
+		algo := algo
+		t.Run(algo.String(), func(t *testing.T) {
+			compressed := compress(t, algo, data...)
+			out, err := decompress(compressed, true)
+			require.NoError(t, err)
+			require.Equal(t, concat, out)
+
+			switch algo {
+			case Zlib:
+				require.NotEqual(t, ChannelVersionBrotli, compressed[0])
+				require.NotEqual(t, ChannelVersionZstd, compressed[0])
+				out, err := decompress(compressed, false)
+				require.NoError(t, err, "zlib must be read before the activation")
+				require.Equal(t, concat, out)
+			case Brotli:
+				require.Equal(t, ChannelVersionBrotli, compressed[0])
+			case Zstd:
+				require.Equal(t, ChannelVersionZstd, compressed[0])
+			}
+			if algo != Zlib {
+				_, err := decompress(compressed, false)
+				require.Error(t, err, "only zlib must be read before the activation")
+			}
+		})
+	}
+}
+
+func TestCompressionWriterReset(t *testing.T) {
+	for _, algo := range CompressionAlgos {
+		algo := algo
+		t.Run(algo.String(), func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewCompressionWriter(algo, &buf)
+			require.NoError(t, err)
+			_, err = w.Write([]byte("discarded"))
+			require.NoError(t, err)
+			require.NoError(t, w.Flush())
+
+			var buf2 bytes.Buffer
+			w.Reset(&buf2)
+			_, err = w.Write([]byte("hello world"))
+			require.NoError(t, err)
+			require.NoError(t, w.Close())
+
+			out, err := decompress(buf2.Bytes(), true)
+			require.NoError(t, err)
+			require.Equal(t, []byte("hello world"), out)
+		})
+	}
+}
+
+func TestDecompressionReaderInvalid(t *testing.T) {
+	_, err := NewDecompressionReader(bytes.NewReader(nil), true)
+	require.ErrorIs(t, err, io.EOF)
+	_, err = NewDecompressionReader(bytes.NewReader([]byte{0x03, 0x00}), true)
+	require.ErrorIs(t, err, ErrUnknownChannelVersion)
+	_, err = NewDecompressionReader(bytes.NewReader([]byte{0x00}), true)
+	require.ErrorIs(t, err, ErrUnknownChannelVersion)
+}
+
+// TestChannelCompressionRoundTrip checks that batches written to a ChannelOut with each compression
+// algorithm are read back from the channel frames.
+func TestChannelCompressionRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1234))
+	for _, algo := range CompressionAlgos {
+		algo := algo
+		t.Run(algo.String(), func(t *testing.T) {
+			co, err := NewChannelOut(newAlgoCompressor(t, algo))
+			require.NoError(t, err)
+			var batches []*BatchData
+			for i := 0; i < 5; i++ {
+				batch := &BatchData{BatchV1{
+					ParentHash:   testutils.RandomHash(rng),
+					EpochNum:     rollup.Epoch(rng.Uint64()),
+					EpochHash:    testutils.RandomHash(rng),
+					Timestamp:    rng.Uint64(),
+					Transactions: []hexutil.Bytes{testutils.RandomData(rng, 200), testutils.RandomData(rng, 50)},
+				}}
+				_, err := co.AddBatch(batch)
+				require.NoError(t, err)
+				batches = append(batches, batch)
+			}
+			require.NoError(t, co.Close())
+
+			ch := NewChannel(co.ID(), eth.L1BlockRef{})
+			for {
+				var buf bytes.Buffer
+				_, err := co.OutputFrame(&buf, 300)
+				if err != io.EOF {
+					require.NoError(t, err)
+				}
+				var f Frame
+				require.NoError(t, f.UnmarshalBinary(&buf))
+				require.NoError(t, ch.AddFrame(f, eth.L1BlockRef{}))
+				if err == io.EOF {
+					break
+				}
+			}
+			require.True(t, ch.IsReady())
+
+			next, err := BatchReader(ch.Reader(), eth.L1BlockRef{}, true)
+			require.NoError(t, err)
+			for _, expected := range batches {
+				batch, err := next()
+				require.NoError(t, err)
+				require.Equal(t, expected, batch.Batch)
+			}
+			_, err = next()
+			require.ErrorIs(t, err, io.EOF)
+		})
+	}
+}
+
+func FuzzCompressionRoundTrip(f *testing.F) {
+	for i := range CompressionAlgos {
+		f.Add(uint8(i), []byte("hello world"), []byte{})
+	}
+	f.Fuzz(func(t *testing.T, algoIndex uint8, data, data2 []byte) {
+		algo := CompressionAlgos[int(algoIndex)%len(CompressionAlgos)]
+		compressed := compress(t, algo, data, data2)
+		out, err := decompress(compressed, true)
+		require.NoError(t, err)
+		require.Equal(t, append(data, data2...), out)
+	})
+}
+
+// FuzzDecompressionReader checks that arbitrary channel data does not crash the decompression.
+func FuzzDecompressionReader(f *testing.F) {
+	for _, algo := range CompressionAlgos {
+		f.Add(compress(f, algo, []byte("hello world")), true)
+	}
+	f.Add([]byte{ChannelVersionBrotli}, true)
+	f.Add([]byte{ChannelVersionZstd, 0x28, 0xb5, 0x2f, 0xfd}, true)
+	f.Fuzz(func(t *testing.T, data []byte, compressionActive bool) {
+		r, err := NewDecompressionReader(bytes.NewReader(data), compressionActive)
+		if err != nil {
+			return
+		}
+		// bound the output, like the RLP reader does for channel data
+		_, _ = io.ReadAll(io.LimitReader(r, MaxRLPBytesPerChannel))
+	})
+}
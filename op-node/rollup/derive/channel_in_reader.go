@@ -8,6 +8,7 @@ import (
 	"github.com/ethereum/go-ethereum/log"
 
 	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
 )
 
 // Channel In Reader reads a batch from the channel
@@ -17,6 +18,7 @@ import (
 
 type ChannelInReader struct {
 	log log.Logger
+	cfg *rollup.Config
 
 	nextBatchFn func() (BatchWithL1InclusionBlock, error)
 
@@ -28,9 +30,10 @@ type ChannelInReader struct {
 var _ ResetableStage = (*ChannelInReader)(nil)
 
 // NewChannelInReader creates a ChannelInReader, which should be Reset(origin) before use.
-func NewChannelInReader(log log.Logger, prev *ChannelBank, metrics Metrics) *ChannelInReader {
+func NewChannelInReader(log log.Logger, cfg *rollup.Config, prev *ChannelBank, metrics Metrics) *ChannelInReader {
 	return &ChannelInReader{
 		log:     log,
+		cfg:     cfg,
 		prev:    prev,
 		metrics: metrics,
 	}
@@ -42,7 +45,8 @@ func (cr *ChannelInReader) Origin() eth.L1BlockRef {
 
 // TODO: Take full channel for better logging
 func (cr *ChannelInReader) WriteChannel(data []byte) error {
-	if f, err := BatchReader(bytes.NewBuffer(data), cr.Origin()); err == nil {
+	origin := cr.Origin()
+	if f, err := BatchReader(bytes.NewBuffer(data), origin, cr.cfg.IsChannelCompression(origin.Time)); err == nil {
 		cr.nextBatchFn = f
 		cr.metrics.RecordChannelInputBytes(len(data))
 		return nil
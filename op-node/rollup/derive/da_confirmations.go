@@ -0,0 +1,154 @@
+package derive
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	lru "github.com/hashicorp/golang-lru/v2"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-service/da"
+)
+
+// daConfirmationsConcurrency bounds the number of L1 blocks that are scanned concurrently for DA references.
+const daConfirmationsConcurrency = 16
+
+// daFinalizedCacheSize bounds the number of L1 blocks that are remembered to only reference finalized DA data.
+const daFinalizedCacheSize = 10_000
+
+// daBlockKey identifies the batch inbox transactions of a batcher in an L1 block.
+type daBlockKey struct {
+	block   common.Hash
+	batcher common.Address
+}
+
+// Confirmations returns the number of batch inbox payloads that reference data on a backend other than L1,
+// and the lowest number of DA-layer confirmations of the referenced data. If there are no such references,
+// the lowest number of confirmations is da.Finalized. Inbox data that cannot be decoded, has an unknown prefix,
+// or lacks a commitment once the DA commitment upgrade is active at the given L1 block time, is ignored,
+// like it is when the data is resolved. Data of a supported backend that is not configured cannot be checked,
+// and is an error.
+func (f *DAFetcher) Confirmations(ctx context.Context, l1Time uint64, inboxData []eth.Data, log log.Logger) (refs uint64, minConfs uint64, err error) {
+	minConfs = da.Finalized
+	for i, data := range inboxData {
+		payloads, err := da.DecodePayloads(data)
+		if err != nil {
+			log.Debug("ignoring batch inbox data", "index", i, "err", err)
+			continue
+		}
+		for _, payload := range payloads {
+			backend, ok := f.daSources.Get(payload.Prefix)
+			if !ok && da.IsKnownPrefix(payload.Prefix) {
+				return 0, 0, fmt.Errorf("%w: %s (prefix %d)", da.ErrNotConfigured, da.TypeName(payload.Prefix), payload.Prefix)
+			}
+			if !ok || da.IsInline(backend) || f.checkCommitment(backend, payload, l1Time) != nil {
+				continue
+			}
+			confs, err := backend.Confirmations(ctx, payload.Ref)
+			if err != nil {
+				return 0, 0, fmt.Errorf("failed to fetch confirmations of DA data (prefix %d): %w", backend.Prefix(), err)
+			}
+			refs++
+			if confs < minConfs {
+				minConfs = confs
+			}
+		}
+	}
+	return refs, minConfs, nil
+}
+
+// DAConfirmationsL1 is the L1 source that batch inbox transactions are read from.
+type DAConfirmationsL1 interface {
+	L1BlockRefByNumber(ctx context.Context, num uint64) (eth.L1BlockRef, error)
+	L1TransactionFetcher
+}
+
+// DAConfirmationsChecker finds the DA-layer confirmations of the batch data that was posted to L1
+// in a range of L1 blocks, so that the finality of the DA layer can be checked on top of that of L1.
+type DAConfirmationsChecker struct {
+	log     log.Logger
+	cfg     *rollup.Config
+	l1      DAConfirmationsL1
+	fetcher *DAFetcher
+
+	// finalized caches the number of DA references of the L1 blocks whose referenced DA data is all finalized:
+	// their confirmations cannot change anymore, so the blocks do not have to be scanned again.
+	finalized *lru.Cache[daBlockKey, uint64]
+}
+
+// NewDAConfirmationsChecker creates a checker that reads batch inbox transactions from l1,
+// and fetches the confirmations of the data they reference from the given backends.
+func NewDAConfirmationsChecker(log log.Logger, cfg *rollup.Config, l1 DAConfirmationsL1, daSources *da.Registry) *DAConfirmationsChecker {
+	finalized, _ := lru.New[daBlockKey, uint64](daFinalizedCacheSize)
+	return &DAConfirmationsChecker{
+		log:       log,
+		cfg:       cfg,
+		l1:        l1,
+		fetcher:   NewDAFetcher(cfg, daSources, nil),
+		finalized: finalized,
+	}
+}
+
+// DAConfirmations scans the batch inbox transactions sent by batcherAddr in the L1 blocks l1Start to l1End, inclusive,
+// and returns the lowest DA-layer confirmations of the data they reference.
+func (c *DAConfirmationsChecker) DAConfirmations(ctx context.Context, batcherAddr common.Address, l1Start, l1End uint64) (*eth.DAConfirmations, error) {
+	if l1End < l1Start {
+		return nil, fmt.Errorf("invalid L1 range: end %d is before start %d", l1End, l1Start)
+	}
+	blocks := make([]eth.L1BlockRef, l1End-l1Start+1)
+	refs := make([]uint64, len(blocks))
+	minConfs := make([]uint64, len(blocks))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(daConfirmationsConcurrency)
+	for i := range blocks {
+		i := i
+		g.Go(func() error {
+			ref, err := c.l1.L1BlockRefByNumber(gctx, l1Start+uint64(i))
+			if err != nil {
+				return fmt.Errorf("failed to fetch L1 block %d: %w", l1Start+uint64(i), err)
+			}
+			blocks[i] = ref
+			key := daBlockKey{block: ref.Hash, batcher: batcherAddr}
+			if n, ok := c.finalized.Get(key); ok {
+				refs[i], minConfs[i] = n, da.Finalized
+				return nil
+			}
+			_, txs, err := c.l1.InfoAndTxsByHash(gctx, ref.Hash)
+			if err != nil {
+				return fmt.Errorf("failed to fetch transactions of L1 block %s: %w", ref, err)
+			}
+			lgr := c.log.New("origin", ref.ID())
+			refs[i], minConfs[i], err = c.fetcher.Confirmations(gctx, ref.Time, DataFromEVMTransactions(c.cfg, batcherAddr, txs, lgr), lgr)
+			if err != nil {
+				return err
+			}
+			if minConfs[i] == da.Finalized {
+				c.finalized.Add(key, refs[i])
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	out := &eth.DAConfirmations{
+		L1Start:          blocks[0].ID(),
+		L1End:            blocks[len(blocks)-1].ID(),
+		MinConfirmations: da.Finalized,
+	}
+	for i, block := range blocks {
+		if i > 0 && block.ParentHash != blocks[i-1].Hash {
+			return nil, fmt.Errorf("L1 reorg while scanning for DA references: %s does not build on %s", block, blocks[i-1])
+		}
+		out.Refs += refs[i]
+		if minConfs[i] < out.MinConfirmations {
+			out.MinConfirmations = minConfs[i]
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,132 @@
+package derive
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+	"github.com/ethereum-optimism/optimism/op-node/testutils"
+	"github.com/ethereum-optimism/optimism/op-service/da"
+)
+
+// TestDAConfirmationsChecker checks that the lowest confirmations of the DA data referenced by the batcher
+// in a range of L1 blocks is found, ignoring inline data and transactions of other senders.
+func TestDAConfirmationsChecker(t *testing.T) {
+	rng := rand.New(rand.NewSource(1234))
+	batcherPriv := testutils.RandomKey()
+	otherPriv := testutils.RandomKey()
+	cfg := &rollup.Config{
+		L1ChainID:         big.NewInt(100),
+		BatchInboxAddress: testutils.RandomAddress(rng),
+	}
+	batcherAddr := crypto.PubkeyToAddress(batcherPriv.PublicKey)
+	signer := cfg.L1Signer()
+
+	celestia := &testDABackend{prefix: da.CelestiaPrefix, blobs: make(map[string][]byte), confs: 40}
+	eigen := &testDABackend{prefix: da.EigenPrefix, blobs: make(map[string][]byte), confs: 25}
+	daSources, err := da.NewRegistry(da.NewCalldata(), celestia, eigen)
+	require.NoError(t, err)
+
+	var nonce uint64
+	tx := func(priv *ecdsa.PrivateKey, data []byte) *types.Transaction {
+		tx, err := types.SignNewTx(priv, signer, &types.DynamicFeeTx{
+			ChainID:   signer.ChainID(),
+			Nonce:     nonce,
+			GasTipCap: big.NewInt(2 * params.GWei),
+			GasFeeCap: big.NewInt(30 * params.GWei),
+			Gas:       100_000,
+			To:        &cfg.BatchInboxAddress,
+			Data:      data,
+		})
+		require.NoError(t, err)
+		nonce++
+		return tx
+	}
+	celestiaData := testutils.RandomData(rng, 100)
+	celestiaRef, err := celestia.Store(context.Background(), celestiaData)
+	require.NoError(t, err)
+	eigenData := testutils.RandomData(rng, 100)
+	eigenRef, err := eigen.Store(context.Background(), eigenData)
+	require.NoError(t, err)
+
+	blockTxs := []types.Transactions{
+		{tx(batcherPriv, append([]byte{da.CalldataPrefix}, testutils.RandomData(rng, 100)...))},
+		{tx(batcherPriv, da.EncodePayload(da.CelestiaPrefix, celestiaData, celestiaRef))},
+		// DA data referenced by other senders is ignored
+		{tx(otherPriv, da.EncodePayload(da.CelestiaPrefix, celestiaData, celestiaRef))},
+		{tx(batcherPriv, da.EncodePayload(da.EigenPrefix, eigenData, eigenRef))},
+	}
+	l1F := &testutils.MockL1Source{}
+	var blocks []eth.L1BlockRef
+	parent := testutils.RandomBlockRef(rng)
+	for _, txs := range blockTxs {
+		block := testutils.NextRandomRef(rng, parent)
+		blocks = append(blocks, block)
+		l1F.ExpectL1BlockRefByNumber(block.Number, block, nil)
+		l1F.ExpectInfoAndTxsByHash(block.Hash, testutils.RandomBlockInfo(rng), txs, nil)
+		parent = block
+	}
+
+	checker := NewDAConfirmationsChecker(testlog.Logger(t, log.LvlError), cfg, l1F, daSources)
+	l1Start, l1End := blocks[0].Number, blocks[len(blocks)-1].Number
+	confs, err := checker.DAConfirmations(context.Background(), batcherAddr, l1Start, l1End)
+	require.NoError(t, err)
+	require.Equal(t, &eth.DAConfirmations{
+		L1Start:          blocks[0].ID(),
+		L1End:            blocks[len(blocks)-1].ID(),
+		Refs:             2,
+		MinConfirmations: 25,
+	}, confs)
+	l1F.AssertExpectations(t)
+
+	// without DA references, the data is final,
+	// and the block is not scanned again: its transactions were already fetched
+	l1F.ExpectL1BlockRefByNumber(blocks[0].Number, blocks[0], nil)
+	confs, err = checker.DAConfirmations(context.Background(), batcherAddr, l1Start, l1Start)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), confs.Refs)
+	require.Equal(t, da.Finalized, confs.MinConfirmations)
+	l1F.AssertExpectations(t)
+
+	// once the DA data is finalized, the blocks referencing it are not scanned again either
+	celestia.confs, eigen.confs = da.Finalized, da.Finalized
+	for i, block := range blocks {
+		l1F.ExpectL1BlockRefByNumber(block.Number, block, nil)
+		if i == 1 || i == 3 {
+			l1F.ExpectInfoAndTxsByHash(block.Hash, testutils.RandomBlockInfo(rng), blockTxs[i], nil)
+		}
+	}
+	confs, err = checker.DAConfirmations(context.Background(), batcherAddr, l1Start, l1End)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), confs.Refs)
+	require.Equal(t, da.Finalized, confs.MinConfirmations)
+	for _, block := range blocks {
+		l1F.ExpectL1BlockRefByNumber(block.Number, block, nil)
+	}
+	confs, err = checker.DAConfirmations(context.Background(), batcherAddr, l1Start, l1End)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), confs.Refs, "cached blocks must keep their DA references")
+	require.Equal(t, da.Finalized, confs.MinConfirmations)
+	l1F.AssertExpectations(t)
+
+	// a reorg during the scan is detected
+	reorged := testutils.NextRandomRef(rng, testutils.RandomBlockRef(rng))
+	reorged.Number = blocks[1].Number
+	l1F.ExpectL1BlockRefByNumber(blocks[0].Number, blocks[0], nil)
+	l1F.ExpectL1BlockRefByNumber(reorged.Number, reorged, nil)
+	l1F.ExpectInfoAndTxsByHash(reorged.Hash, testutils.RandomBlockInfo(rng), nil, nil)
+	_, err = checker.DAConfirmations(context.Background(), batcherAddr, l1Start, reorged.Number)
+	require.ErrorContains(t, err, "reorg")
+	l1F.AssertExpectations(t)
+}
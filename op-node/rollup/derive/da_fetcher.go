@@ -0,0 +1,170 @@
+package derive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-node/sources/caching"
+	"github.com/ethereum-optimism/optimism/op-service/da"
+)
+
+const (
+	// daCacheSize is the number of resolved DA blobs kept in memory.
+	daCacheSize = 256
+	// daFetchConcurrency bounds the number of DA blobs that are fetched concurrently.
+	daFetchConcurrency = 16
+)
+
+// DAMetrics tracks the retrieval of data referenced from L1 on data availability backends.
+type DAMetrics interface {
+	RecordDAFetch(backend string, size int, duration time.Duration, err error)
+}
+
+type daCacheKey struct {
+	prefix     byte
+	commitment common.Hash
+	ref        string
+}
+
+// DAFetcher resolves L1 batch inbox data through the data availability backends it refers to.
+// All data referenced by a single L1 block is fetched concurrently, and data that was resolved and verified
+// is cached, so that a data source that is reopened after a temporary error does not fetch it again.
+type DAFetcher struct {
+	cfg       *rollup.Config
+	daSources *da.Registry
+	metrics   DAMetrics
+	cache     *caching.LRUCache
+}
+
+// NewDAFetcher creates a fetcher that resolves data through the given backends.
+// Metrics are optional: no metrics will be tracked if m == nil.
+func NewDAFetcher(cfg *rollup.Config, daSources *da.Registry, m DAMetrics) *DAFetcher {
+	return &DAFetcher{
+		cfg:       cfg,
+		daSources: daSources,
+		metrics:   m,
+		cache:     caching.NewLRUCache(nil, "da", daCacheSize),
+	}
+}
+
+type daFetch struct {
+	backend da.DataAvailability
+	payload da.Payload
+}
+
+// Resolve resolves the data of all batch inbox transactions, in order, through the data availability
+// backend identified by the prefix byte of each. Aggregate inbox data resolves to the data of all the payloads
+// it packs, in order. Inbox data that cannot be decoded, or has an unknown prefix, is ignored,
+// as are payloads without commitment once the DA commitment upgrade is active at the given L1 block time.
+// An error is returned if any referenced data is not available, not yet confirmed deep enough,
+// or does not match the commitment posted to L1, so that it is retried later.
+// A critical error is returned for payloads of a supported backend that is not configured:
+// ignoring them would make the derived chain diverge from the chain of the batcher.
+func (f *DAFetcher) Resolve(ctx context.Context, l1Time uint64, inboxData []eth.Data, log log.Logger) ([]eth.Data, error) {
+	var fetches []daFetch
+	for i, data := range inboxData {
+		payloads, err := da.DecodePayloads(data)
+		if err != nil {
+			log.Warn("ignoring batch inbox data", "index", i, "err", err)
+			continue
+		}
+		for j, payload := range payloads {
+			backend, ok := f.daSources.Get(payload.Prefix)
+			if !ok && da.IsKnownPrefix(payload.Prefix) {
+				return nil, NewCriticalError(fmt.Errorf("%w: %s (prefix %d) is referenced by batch inbox data %d, check the da_type of the rollup config",
+					da.ErrNotConfigured, da.TypeName(payload.Prefix), payload.Prefix, i))
+			}
+			if !ok {
+				log.Warn("ignoring batch inbox data", "index", i, "payload", j, "err", fmt.Errorf("%w: %d", da.ErrUnknownPrefix, payload.Prefix))
+				continue
+			}
+			if err := f.checkCommitment(backend, payload, l1Time); err != nil {
+				log.Warn("ignoring batch inbox data", "index", i, "payload", j, "err", err)
+				continue
+			}
+			fetches = append(fetches, daFetch{backend: backend, payload: payload})
+		}
+	}
+
+	out := make([]eth.Data, len(fetches))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(daFetchConcurrency)
+	for i, fetch := range fetches {
+		if da.IsInline(fetch.backend) {
+			out[i] = fetch.payload.Ref
+			continue
+		}
+		i, fetch := i, fetch
+		g.Go(func() error {
+			resolved, err := f.resolvePayload(gctx, fetch.backend, fetch.payload)
+			if err != nil {
+				if errors.Is(err, da.ErrCommitmentMismatch) {
+					log.Warn("rejecting DA data", "prefix", fetch.backend.Prefix(), "ref", fetch.payload.Ref, "err", err)
+				}
+				return err
+			}
+			out[i] = resolved
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// checkCommitment returns an error if the payload refers to data on a backend other than L1 without committing to it,
+// while the DA commitment upgrade is active. The fault proof program can only retrieve DA data by commitment.
+func (f *DAFetcher) checkCommitment(backend da.DataAvailability, payload da.Payload, l1Time uint64) error {
+	if payload.Commitment == nil && !da.IsInline(backend) && f.cfg.IsDACommitment(l1Time) {
+		return fmt.Errorf("%w (prefix %d)", da.ErrNoCommitment, payload.Prefix)
+	}
+	return nil
+}
+
+// resolvePayload retrieves the data referenced by the payload from the backend, once it is confirmed deep enough.
+func (f *DAFetcher) resolvePayload(ctx context.Context, backend da.DataAvailability, payload da.Payload) (eth.Data, error) {
+	key := daCacheKey{prefix: payload.Prefix, ref: string(payload.Ref)}
+	if payload.Commitment != nil {
+		key.commitment = *payload.Commitment
+	}
+	if data, ok := f.cache.Get(key); ok {
+		return data.(eth.Data), nil
+	}
+
+	confs, err := backend.Confirmations(ctx, payload.Ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch confirmations of DA data (prefix %d): %w", backend.Prefix(), err)
+	}
+	if confs < NumConfirmationsDA {
+		return nil, fmt.Errorf("not enough confirmations for DA data (prefix %d): %d < %d", backend.Prefix(), confs, NumConfirmationsDA)
+	}
+	start := time.Now()
+	var resolved []byte
+	if cr, ok := backend.(da.CommitmentRetriever); !ok {
+		resolved, err = backend.Retrieve(ctx, payload.Ref)
+	} else if payload.Commitment == nil {
+		err = da.ErrNoCommitment
+	} else {
+		resolved, err = cr.RetrieveCommitted(ctx, payload.Ref, *payload.Commitment)
+	}
+	if f.metrics != nil {
+		f.metrics.RecordDAFetch(da.TypeName(backend.Prefix()), len(resolved), time.Since(start), err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve DA data (prefix %d): %w", backend.Prefix(), err)
+	}
+	if err := payload.Verify(resolved); err != nil {
+		return nil, fmt.Errorf("failed to verify DA data (prefix %d): %w", backend.Prefix(), err)
+	}
+	f.cache.Add(key, eth.Data(resolved))
+	return resolved, nil
+}
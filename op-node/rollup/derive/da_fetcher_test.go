@@ -0,0 +1,224 @@
+package derive
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+	"github.com/ethereum-optimism/optimism/op-node/testutils"
+	"github.com/ethereum-optimism/optimism/op-service/da"
+)
+
+// countingDABackend counts retrievals, and fails retrievals of the refs in the failing set.
+// If gate is set, every retrieval waits until it is closed.
+type countingDABackend struct {
+	testDABackend
+
+	mu         sync.Mutex
+	retrievals map[string]int
+	failing    map[string]bool
+	gate       chan struct{}
+	inflight   int
+	maxInfl    int
+}
+
+func newCountingDABackend() *countingDABackend {
+	return &countingDABackend{
+		testDABackend: testDABackend{prefix: da.CelestiaPrefix, blobs: make(map[string][]byte), confs: da.Finalized},
+		retrievals:    make(map[string]int),
+		failing:       make(map[string]bool),
+	}
+}
+
+func (b *countingDABackend) Retrieve(ctx context.Context, ref []byte) ([]byte, error) {
+	b.mu.Lock()
+	b.retrievals[string(ref)]++
+	b.inflight++
+	if b.inflight > b.maxInfl {
+		b.maxInfl = b.inflight
+	}
+	fail := b.failing[string(ref)]
+	gate := b.gate
+	b.mu.Unlock()
+	defer func() {
+		b.mu.Lock()
+		b.inflight--
+		b.mu.Unlock()
+	}()
+	if gate != nil {
+		select {
+		case <-gate:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if fail {
+		return nil, errors.New("blob unavailable")
+	}
+	return b.testDABackend.Retrieve(ctx, ref)
+}
+
+func (b *countingDABackend) numRetrievals(ref []byte) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.retrievals[string(ref)]
+}
+
+func TestDAFetcherCachesResolvedData(t *testing.T) {
+	rng := rand.New(rand.NewSource(1234))
+	backend := newCountingDABackend()
+	daSources, err := da.NewRegistry(da.NewCalldata(), backend)
+	require.NoError(t, err)
+
+	var fetches []string
+	var fetchErrs int
+	m := &testutils.TestDerivationMetrics{
+		FnRecordDAFetch: func(backend string, size int, duration time.Duration, err error) {
+			fetches = append(fetches, backend)
+			if err != nil {
+				fetchErrs++
+			}
+		},
+	}
+	f := NewDAFetcher(&rollup.Config{}, daSources, m)
+
+	blobA := testutils.RandomData(rng, 100)
+	refA, err := backend.Store(context.Background(), blobA)
+	require.NoError(t, err)
+	blobB := testutils.RandomData(rng, 100)
+	refB, err := backend.Store(context.Background(), blobB)
+	require.NoError(t, err)
+	inbox := []eth.Data{
+		da.EncodePayload(da.CelestiaPrefix, blobA, refA),
+		da.EncodePayload(da.CelestiaPrefix, blobB, refB),
+	}
+
+	// the first attempt fails on blob B, the data of blob A is kept
+	backend.failing[string(refB)] = true
+	lgr := testlog.Logger(t, log.LvlError)
+	_, err = f.Resolve(context.Background(), 0, inbox, lgr)
+	require.Error(t, err)
+
+	backend.failing[string(refB)] = false
+	out, err := f.Resolve(context.Background(), 0, inbox, lgr)
+	require.NoError(t, err)
+	require.Equal(t, []eth.Data{blobA, blobB}, out)
+	require.Equal(t, 1, backend.numRetrievals(refA), "resolved data must not be fetched again")
+	require.Equal(t, 2, backend.numRetrievals(refB))
+	require.Equal(t, []string{da.CelestiaType, da.CelestiaType, da.CelestiaType}, fetches)
+	require.Equal(t, 1, fetchErrs)
+}
+
+func TestDAFetcherDoesNotCacheRejectedData(t *testing.T) {
+	rng := rand.New(rand.NewSource(1234))
+	backend := newCountingDABackend()
+	daSources, err := da.NewRegistry(da.NewCalldata(), backend)
+	require.NoError(t, err)
+	f := NewDAFetcher(&rollup.Config{}, daSources, nil)
+
+	blob := testutils.RandomData(rng, 100)
+	ref, err := backend.Store(context.Background(), blob)
+	require.NoError(t, err)
+	inbox := []eth.Data{da.EncodePayload(da.CelestiaPrefix, blob, ref)}
+
+	backend.blobs[string(ref)] = testutils.RandomData(rng, 100)
+	lgr := testlog.Logger(t, log.LvlError)
+	_, err = f.Resolve(context.Background(), 0, inbox, lgr)
+	require.ErrorIs(t, err, da.ErrCommitmentMismatch)
+
+	backend.blobs[string(ref)] = blob
+	out, err := f.Resolve(context.Background(), 0, inbox, lgr)
+	require.NoError(t, err)
+	require.Equal(t, []eth.Data{blob}, out)
+	require.Equal(t, 2, backend.numRetrievals(ref))
+}
+
+func TestDAFetcherDACommitment(t *testing.T) {
+	rng := rand.New(rand.NewSource(1234))
+	backend := newCountingDABackend()
+	daSources, err := da.NewRegistry(da.NewCalldata(), backend)
+	require.NoError(t, err)
+	activation := uint64(1000)
+	f := NewDAFetcher(&rollup.Config{DACommitmentTime: &activation}, daSources, nil)
+
+	legacy := testutils.RandomData(rng, 100)
+	legacyRef, err := backend.Store(context.Background(), legacy)
+	require.NoError(t, err)
+	committed := testutils.RandomData(rng, 100)
+	committedRef, err := backend.Store(context.Background(), committed)
+	require.NoError(t, err)
+	calldata := testutils.RandomData(rng, 100)
+	inbox := []eth.Data{
+		append([]byte{da.CelestiaPrefix}, legacyRef...),
+		da.EncodePayload(da.CelestiaPrefix, committed, committedRef),
+		da.EncodePayload(da.CalldataPrefix, calldata, calldata),
+	}
+	lgr := testlog.Logger(t, log.LvlError)
+
+	out, err := f.Resolve(context.Background(), activation-1, inbox, lgr)
+	require.NoError(t, err)
+	require.Equal(t, []eth.Data{legacy, committed, calldata}, out, "legacy payloads are resolved before the upgrade")
+	refs, _, err := f.Confirmations(context.Background(), activation-1, inbox, lgr)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), refs)
+
+	out, err = f.Resolve(context.Background(), activation, inbox, lgr)
+	require.NoError(t, err)
+	require.Equal(t, []eth.Data{committed, calldata}, out, "legacy payloads are ignored once the upgrade is active")
+	refs, _, err = f.Confirmations(context.Background(), activation, inbox, lgr)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), refs)
+}
+
+func TestDAFetcherFetchesConcurrently(t *testing.T) {
+	rng := rand.New(rand.NewSource(1234))
+	backend := newCountingDABackend()
+	backend.gate = make(chan struct{})
+	daSources, err := da.NewRegistry(da.NewCalldata(), backend)
+	require.NoError(t, err)
+	f := NewDAFetcher(&rollup.Config{}, daSources, nil)
+
+	const n = 8
+	var inbox, expected []eth.Data
+	var refs [][]byte
+	for i := 0; i < n; i++ {
+		blob := testutils.RandomData(rng, 100)
+		ref, err := backend.Store(context.Background(), blob)
+		require.NoError(t, err)
+		refs = append(refs, ref)
+		inbox = append(inbox, da.EncodePayload(da.CelestiaPrefix, blob, ref))
+		expected = append(expected, blob)
+	}
+
+	// release the retrievals only once all of them are in flight at the same time
+	go func() {
+		for {
+			backend.mu.Lock()
+			inflight := backend.inflight
+			backend.mu.Unlock()
+			if inflight == n {
+				close(backend.gate)
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	out, err := f.Resolve(ctx, 0, inbox, testlog.Logger(t, log.LvlError))
+	require.NoError(t, err)
+	require.Equal(t, expected, out, "data must be resolved in order")
+	require.Equal(t, n, backend.maxInfl)
+	for _, ref := range refs {
+		require.Equal(t, 1, backend.numRetrievals(ref))
+	}
+}
@@ -63,6 +63,23 @@ type EngineControl interface {
 	BuildingPayload() (onto eth.L2BlockRef, id eth.PayloadID, safe bool)
 }
 
+// SafeHeadListener is notified of the safe head, and the L1 block it was fully derived from, whenever it changes.
+type SafeHeadListener interface {
+	// SafeHeadUpdated records that the safe head was derived from the L1 chain up to and including l1Head.
+	SafeHeadUpdated(safeHead eth.L2BlockRef, l1Head eth.BlockID) error
+	// SafeHeadReset is called when derivation is reset to the given safe head:
+	// safe heads recorded after it may not be canonical anymore.
+	SafeHeadReset(resetSafeHead eth.L2BlockRef) error
+}
+
+type noopSafeHeadListener struct{}
+
+func (noopSafeHeadListener) SafeHeadUpdated(eth.L2BlockRef, eth.BlockID) error { return nil }
+func (noopSafeHeadListener) SafeHeadReset(eth.L2BlockRef) error                { return nil }
+
+// NoopSafeHeadListener ignores all safe head changes.
+var NoopSafeHeadListener SafeHeadListener = noopSafeHeadListener{}
+
 // Max memory used for buffering unsafe payloads
 const maxUnsafePayloadsMemory = 500 * 1024 * 1024
 
@@ -133,12 +150,16 @@ type EngineQueue struct {
 
 	metrics   Metrics
 	l1Fetcher L1Fetcher
+
+	safeHeadNotifs SafeHeadListener
+	// lastNotifiedSafeHead is the last safe head that safeHeadNotifs was notified of.
+	lastNotifiedSafeHead eth.L2BlockRef
 }
 
 var _ EngineControl = (*EngineQueue)(nil)
 
 // NewEngineQueue creates a new EngineQueue, which should be Reset(origin) before use.
-func NewEngineQueue(log log.Logger, cfg *rollup.Config, engine Engine, metrics Metrics, prev NextAttributesProvider, l1Fetcher L1Fetcher) *EngineQueue {
+func NewEngineQueue(log log.Logger, cfg *rollup.Config, engine Engine, metrics Metrics, prev NextAttributesProvider, l1Fetcher L1Fetcher, safeHeadNotifs SafeHeadListener) *EngineQueue {
 	return &EngineQueue{
 		log:            log,
 		cfg:            cfg,
@@ -148,6 +169,7 @@ func NewEngineQueue(log log.Logger, cfg *rollup.Config, engine Engine, metrics M
 		unsafePayloads: NewPayloadsQueue(maxUnsafePayloadsMemory, payloadMemSize),
 		prev:           prev,
 		l1Fetcher:      l1Fetcher,
+		safeHeadNotifs: safeHeadNotifs,
 	}
 }
 
@@ -356,7 +378,9 @@ func (eq *EngineQueue) tryFinalizeL2() {
 
 // postProcessSafeL2 buffers the L1 block the safe head was fully derived from,
 // to finalize it once the L1 block, or later, finalizes.
+// The safe head listener is notified if the safe head changed.
 func (eq *EngineQueue) postProcessSafeL2() {
+	eq.notifySafeHead()
 	// prune finality data if necessary
 	if len(eq.finalityData) >= finalityLookback {
 		eq.log.Debug("prune  finality-data", "len(eq.finalityData)", len(eq.finalityData), "finalityLookback", finalityLookback)
@@ -381,6 +405,20 @@ func (eq *EngineQueue) postProcessSafeL2() {
 	}
 }
 
+// notifySafeHead notifies the safe head listener of the current safe head, if it changed since the last notification.
+// If the listener fails, the notification is retried the next time the safe head is post-processed:
+// the listener is not critical to derivation, and does not stop it.
+func (eq *EngineQueue) notifySafeHead() {
+	if eq.safeHead == eq.lastNotifiedSafeHead {
+		return
+	}
+	if err := eq.safeHeadNotifs.SafeHeadUpdated(eq.safeHead, eq.origin.ID()); err != nil {
+		eq.log.Error("Failed to notify safe head update", "safe_head", eq.safeHead, "l1", eq.origin, "err", err)
+		return
+	}
+	eq.lastNotifiedSafeHead = eq.safeHead
+}
+
 func (eq *EngineQueue) logSyncProgress(reason string) {
 	eq.log.Info("Sync progress",
 		"reason", reason,
@@ -729,9 +767,20 @@ func (eq *EngineQueue) Reset(ctx context.Context, _ eth.L1BlockRef, _ eth.System
 	if err != nil {
 		return NewTemporaryError(fmt.Errorf("failed to fetch L1 config of L2 block %s: %w", pipelineL2.ID(), err))
 	}
+	if err := eq.safeHeadNotifs.SafeHeadReset(safe); err != nil {
+		return NewTemporaryError(fmt.Errorf("failed to reset the safe head listener to %s: %w", safe, err))
+	}
+	// The reset safe head is not derived from the reset pipeline origin, so it is not recorded against it,
+	// except for the L2 genesis block, which is derived from the L1 genesis block.
+	if safe.ID() == eq.cfg.Genesis.L2 {
+		if err := eq.safeHeadNotifs.SafeHeadUpdated(safe, eq.cfg.Genesis.L1); err != nil {
+			return NewTemporaryError(fmt.Errorf("failed to notify the safe head listener of the genesis safe head: %w", err))
+		}
+	}
 	eq.log.Debug("Reset engine queue", "safeHead", safe, "unsafe", unsafe, "safe_timestamp", safe.Time, "unsafe_timestamp", unsafe.Time, "l1Origin", l1Origin)
 	eq.unsafeHead = unsafe
 	eq.safeHead = safe
+	eq.lastNotifiedSafeHead = safe
 	eq.safeAttributes = nil
 	eq.finalized = finalized
 	eq.resetBuildingState()
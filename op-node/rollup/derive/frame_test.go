@@ -195,6 +195,44 @@ func TestParseFramesTruncated(t *testing.T) {
 	require.Empty(t, frames0)
 }
 
+// TestParseFramesMultipleChannels checks that the concatenated frames of multiple channels,
+// as posted by the batcher in a single tx, are parsed in order.
+func TestParseFramesMultipleChannels(t *testing.T) {
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	var frames []Frame
+	for c := 0; c < 3; c++ {
+		id := randomFrame(rng).ID
+		numFrames := rng.Intn(4) + 1
+		for i := 0; i < numFrames; i++ {
+			f := randomFrame(rng, frameWithDataLen(rng.Intn(1000)))
+			f.ID = id
+			f.FrameNumber = uint16(i)
+			f.IsLast = i == numFrames-1
+			frames = append(frames, *f)
+		}
+	}
+	data, err := txMarshalFrames(frames)
+	require.NoError(t, err)
+
+	frames0, err := ParseFrames(data)
+	require.NoError(t, err)
+	require.Equal(t, frames, frames0)
+}
+
+// TestParseFramesTrailingData checks that concatenated frames followed by data
+// that is not a complete frame are rejected as a whole.
+func TestParseFramesTrailingData(t *testing.T) {
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	frames := []Frame{*randomFrame(rng), *randomFrame(rng)}
+	data, err := txMarshalFrames(frames)
+	require.NoError(t, err)
+	data = append(data, DerivationVersion0)
+
+	frames0, err := ParseFrames(data)
+	require.ErrorContains(t, err, "parsing frame 2")
+	require.Empty(t, frames0)
+}
+
 // txMarshalFrames creates the tx payload for the given frames, i.e., it first
 // writes the version byte to a buffer and then appends all binary-marshaled
 // frames.
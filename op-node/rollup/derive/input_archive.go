@@ -0,0 +1,319 @@
+package derive
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/leveldb"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-service/da"
+)
+
+// Keys of the input archive. Block data is immutable and keyed by block hash,
+// only the canonical block hash by number may be replaced, when the L1 chain reorgs while recording.
+var (
+	archiveConfigKey       = []byte("config") // rollup config, JSON
+	archiveHeaderPrefix    = []byte("h")      // h + block hash -> header RLP
+	archiveCanonicalPrefix = []byte("n")      // n + block number (uint64 big-endian) -> block hash
+	archiveTxsPrefix       = []byte("t")      // t + block hash -> transactions RLP
+	archiveReceiptsPrefix  = []byte("r")      // r + block hash -> receipts JSON
+	archiveDAPrefix        = []byte("d")      // d + DA prefix byte + reference -> resolved DA data
+)
+
+const (
+	inputArchiveCache   = 16 // MB
+	inputArchiveHandles = 16
+)
+
+// InputArchive stores the inputs of the derivation: the L1 blocks, transactions and receipts,
+// and the data resolved from DA backends. Inputs are recorded by wrapping the sources of a live node,
+// see RecordL1 and RecordDA, and can be served again to a pipeline to replay the derivation offline,
+// see NewReplayL1Fetcher and ReplayDA.
+//
+// Receipts are stored as JSON rather than by their consensus encoding,
+// as derivation depends on the log metadata that is not part of the consensus encoding.
+type InputArchive struct {
+	log log.Logger
+	db  ethdb.KeyValueStore
+}
+
+// OpenInputArchive opens the input archive stored at the given path, or creates a new one.
+func OpenInputArchive(log log.Logger, path string) (*InputArchive, error) {
+	db, err := leveldb.New(path, inputArchiveCache, inputArchiveHandles, "", false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open input archive at %s: %w", path, err)
+	}
+	return &InputArchive{log: log, db: db}, nil
+}
+
+// NewMemoryInputArchive creates an input archive that is not persisted.
+func NewMemoryInputArchive(log log.Logger) *InputArchive {
+	return &InputArchive{log: log, db: memorydb.New()}
+}
+
+func (a *InputArchive) Close() error {
+	return a.db.Close()
+}
+
+// WriteRollupConfig stores the rollup config of the recorded chain, to replay the derivation with.
+func (a *InputArchive) WriteRollupConfig(cfg *rollup.Config) error {
+	enc, err := json.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to encode rollup config: %w", err)
+	}
+	return a.db.Put(archiveConfigKey, enc)
+}
+
+// RollupConfig returns the rollup config of the recorded chain.
+func (a *InputArchive) RollupConfig() (*rollup.Config, error) {
+	enc, err := a.get(archiveConfigKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rollup config: %w", err)
+	}
+	var cfg rollup.Config
+	if err := json.Unmarshal(enc, &cfg); err != nil {
+		return nil, fmt.Errorf("invalid rollup config: %w", err)
+	}
+	return &cfg, nil
+}
+
+// RecordL1 wraps the L1 source, to record all L1 data that is fetched through it.
+// Block references by label are not recorded, as they only reflect the state of L1 at the time.
+func (a *InputArchive) RecordL1(inner L1Fetcher) L1Fetcher {
+	return &recordingL1Fetcher{inner: inner, archive: a}
+}
+
+// RecordDA wraps the DA backend, to record all data that is retrieved through it.
+// Backends that store data inline, in the L1 transactions, are returned as-is.
+func (a *InputArchive) RecordDA(inner da.DataAvailability) da.DataAvailability {
+	if da.IsInline(inner) {
+		return inner
+	}
+	rec := &recordingDA{DataAvailability: inner, archive: a}
+	if cr, ok := inner.(da.CommitmentRetriever); ok {
+		return &recordingCommittedDA{recordingDA: rec, committed: cr}
+	}
+	return rec
+}
+
+func (a *InputArchive) get(key []byte) ([]byte, error) {
+	if has, err := a.db.Has(key); err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ethereum.NotFound
+	}
+	return a.db.Get(key)
+}
+
+// putOnce writes immutable data, that does not have to be written again if already present.
+func (a *InputArchive) putOnce(key []byte, encode func() ([]byte, error)) error {
+	if has, err := a.db.Has(key); err != nil {
+		return err
+	} else if has {
+		return nil
+	}
+	enc, err := encode()
+	if err != nil {
+		return err
+	}
+	return a.db.Put(key, enc)
+}
+
+func (a *InputArchive) putHeader(info eth.BlockInfo) error {
+	if err := a.putOnce(archiveKey(archiveHeaderPrefix, info.Hash().Bytes()), info.HeaderRLP); err != nil {
+		return fmt.Errorf("failed to record header of L1 block %s: %w", info.Hash(), err)
+	}
+	return nil
+}
+
+func (a *InputArchive) putCanonical(ref eth.L1BlockRef) error {
+	if err := a.db.Put(archiveCanonicalKey(ref.Number), ref.Hash.Bytes()); err != nil {
+		return fmt.Errorf("failed to record canonical L1 block %s: %w", ref, err)
+	}
+	a.log.Debug("Recorded canonical L1 block", "block", ref)
+	return nil
+}
+
+func (a *InputArchive) putTxs(hash common.Hash, txs types.Transactions) error {
+	err := a.putOnce(archiveKey(archiveTxsPrefix, hash.Bytes()), func() ([]byte, error) {
+		return rlp.EncodeToBytes(txs)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record transactions of L1 block %s: %w", hash, err)
+	}
+	return nil
+}
+
+func (a *InputArchive) putReceipts(hash common.Hash, receipts types.Receipts) error {
+	err := a.putOnce(archiveKey(archiveReceiptsPrefix, hash.Bytes()), func() ([]byte, error) {
+		return json.Marshal(receipts)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record receipts of L1 block %s: %w", hash, err)
+	}
+	return nil
+}
+
+func (a *InputArchive) putDA(prefix byte, ref []byte, data []byte) error {
+	err := a.putOnce(archiveDAKey(prefix, ref), func() ([]byte, error) {
+		return data, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record DA data (prefix %d): %w", prefix, err)
+	}
+	return nil
+}
+
+func archiveKey(prefix []byte, id []byte) []byte {
+	key := make([]byte, 0, len(prefix)+len(id))
+	key = append(key, prefix...)
+	return append(key, id...)
+}
+
+func archiveCanonicalKey(num uint64) []byte {
+	var enc [8]byte
+	binary.BigEndian.PutUint64(enc[:], num)
+	return archiveKey(archiveCanonicalPrefix, enc[:])
+}
+
+func archiveDAKey(prefix byte, ref []byte) []byte {
+	return archiveKey(archiveDAPrefix, append([]byte{prefix}, ref...))
+}
+
+// recordingL1Fetcher records the L1 data that is fetched through the inner L1 source.
+// Every fetched block is recorded with its header, so it can be served by hash and number again.
+type recordingL1Fetcher struct {
+	inner   L1Fetcher
+	archive *InputArchive
+}
+
+var _ L1Fetcher = (*recordingL1Fetcher)(nil)
+
+func (r *recordingL1Fetcher) L1BlockRefByLabel(ctx context.Context, label eth.BlockLabel) (eth.L1BlockRef, error) {
+	return r.inner.L1BlockRefByLabel(ctx, label)
+}
+
+func (r *recordingL1Fetcher) L1BlockRefByNumber(ctx context.Context, num uint64) (eth.L1BlockRef, error) {
+	ref, err := r.inner.L1BlockRefByNumber(ctx, num)
+	if err != nil {
+		return eth.L1BlockRef{}, err
+	}
+	if err := r.recordHeader(ctx, ref.Hash); err != nil {
+		return eth.L1BlockRef{}, err
+	}
+	if err := r.archive.putCanonical(ref); err != nil {
+		return eth.L1BlockRef{}, err
+	}
+	return ref, nil
+}
+
+func (r *recordingL1Fetcher) L1BlockRefByHash(ctx context.Context, hash common.Hash) (eth.L1BlockRef, error) {
+	ref, err := r.inner.L1BlockRefByHash(ctx, hash)
+	if err != nil {
+		return eth.L1BlockRef{}, err
+	}
+	if err := r.recordHeader(ctx, ref.Hash); err != nil {
+		return eth.L1BlockRef{}, err
+	}
+	return ref, nil
+}
+
+func (r *recordingL1Fetcher) InfoByHash(ctx context.Context, hash common.Hash) (eth.BlockInfo, error) {
+	info, err := r.inner.InfoByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.archive.putHeader(info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+func (r *recordingL1Fetcher) InfoAndTxsByHash(ctx context.Context, hash common.Hash) (eth.BlockInfo, types.Transactions, error) {
+	info, txs, err := r.inner.InfoAndTxsByHash(ctx, hash)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := r.archive.putHeader(info); err != nil {
+		return nil, nil, err
+	}
+	if err := r.archive.putTxs(info.Hash(), txs); err != nil {
+		return nil, nil, err
+	}
+	return info, txs, nil
+}
+
+func (r *recordingL1Fetcher) FetchReceipts(ctx context.Context, blockHash common.Hash) (eth.BlockInfo, types.Receipts, error) {
+	info, receipts, err := r.inner.FetchReceipts(ctx, blockHash)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := r.archive.putHeader(info); err != nil {
+		return nil, nil, err
+	}
+	if err := r.archive.putReceipts(info.Hash(), receipts); err != nil {
+		return nil, nil, err
+	}
+	return info, receipts, nil
+}
+
+// recordHeader records the header of a block that was fetched by reference only.
+// The header is fetched from the inner source, which generally has it cached already.
+func (r *recordingL1Fetcher) recordHeader(ctx context.Context, hash common.Hash) error {
+	if has, err := r.archive.db.Has(archiveKey(archiveHeaderPrefix, hash.Bytes())); err != nil || has {
+		return err
+	}
+	info, err := r.inner.InfoByHash(ctx, hash)
+	if err != nil {
+		return fmt.Errorf("failed to fetch header of L1 block %s to record: %w", hash, err)
+	}
+	return r.archive.putHeader(info)
+}
+
+// recordingDA records the data that is retrieved through the inner DA backend, by reference.
+type recordingDA struct {
+	da.DataAvailability
+	archive *InputArchive
+}
+
+func (r *recordingDA) Retrieve(ctx context.Context, ref []byte) ([]byte, error) {
+	data, err := r.DataAvailability.Retrieve(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.archive.putDA(r.Prefix(), ref, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// recordingCommittedDA records the data that is retrieved through an inner DA backend that retrieves data by commitment.
+// The data is recorded by reference all the same: the derivation verifies the data against the commitment on replay.
+type recordingCommittedDA struct {
+	*recordingDA
+	committed da.CommitmentRetriever
+}
+
+var _ da.CommitmentRetriever = (*recordingCommittedDA)(nil)
+
+func (r *recordingCommittedDA) RetrieveCommitted(ctx context.Context, ref []byte, commitment common.Hash) ([]byte, error) {
+	data, err := r.committed.RetrieveCommitted(ctx, ref, commitment)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.archive.putDA(r.Prefix(), ref, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
@@ -0,0 +1,243 @@
+package derive
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+	"github.com/ethereum-optimism/optimism/op-node/testutils"
+	"github.com/ethereum-optimism/optimism/op-service/da"
+)
+
+// archiveTestL1 serves a fixed set of L1 blocks.
+type archiveTestL1 struct {
+	blocks   map[common.Hash]*types.Block
+	receipts map[common.Hash]types.Receipts
+	byNumber map[uint64]common.Hash
+}
+
+func newArchiveTestL1(rng *rand.Rand, n int) *archiveTestL1 {
+	l1 := &archiveTestL1{
+		blocks:   make(map[common.Hash]*types.Block),
+		receipts: make(map[common.Hash]types.Receipts),
+		byNumber: make(map[uint64]common.Hash),
+	}
+	for i := 0; i < n; i++ {
+		block, receipts := testutils.RandomBlock(rng, 2)
+		l1.blocks[block.Hash()] = block
+		l1.receipts[block.Hash()] = receipts
+		l1.byNumber[block.NumberU64()] = block.Hash()
+	}
+	return l1
+}
+
+func (l *archiveTestL1) block(hash common.Hash) (*types.Block, error) {
+	if b, ok := l.blocks[hash]; ok {
+		return b, nil
+	}
+	return nil, ethereum.NotFound
+}
+
+func (l *archiveTestL1) L1BlockRefByLabel(_ context.Context, _ eth.BlockLabel) (eth.L1BlockRef, error) {
+	return eth.L1BlockRef{}, errors.New("not supported")
+}
+
+func (l *archiveTestL1) L1BlockRefByNumber(ctx context.Context, num uint64) (eth.L1BlockRef, error) {
+	hash, ok := l.byNumber[num]
+	if !ok {
+		return eth.L1BlockRef{}, ethereum.NotFound
+	}
+	return l.L1BlockRefByHash(ctx, hash)
+}
+
+func (l *archiveTestL1) L1BlockRefByHash(_ context.Context, hash common.Hash) (eth.L1BlockRef, error) {
+	b, err := l.block(hash)
+	if err != nil {
+		return eth.L1BlockRef{}, err
+	}
+	return eth.InfoToL1BlockRef(eth.BlockToInfo(b)), nil
+}
+
+func (l *archiveTestL1) InfoByHash(_ context.Context, hash common.Hash) (eth.BlockInfo, error) {
+	b, err := l.block(hash)
+	if err != nil {
+		return nil, err
+	}
+	return eth.BlockToInfo(b), nil
+}
+
+func (l *archiveTestL1) InfoAndTxsByHash(_ context.Context, hash common.Hash) (eth.BlockInfo, types.Transactions, error) {
+	b, err := l.block(hash)
+	if err != nil {
+		return nil, nil, err
+	}
+	return eth.BlockToInfo(b), b.Transactions(), nil
+}
+
+func (l *archiveTestL1) FetchReceipts(_ context.Context, blockHash common.Hash) (eth.BlockInfo, types.Receipts, error) {
+	b, err := l.block(blockHash)
+	if err != nil {
+		return nil, nil, err
+	}
+	return eth.BlockToInfo(b), l.receipts[blockHash], nil
+}
+
+// archiveTestDA serves DA data by reference.
+type archiveTestDA struct {
+	da.DataAvailability
+	data map[string][]byte
+}
+
+func (d *archiveTestDA) Retrieve(_ context.Context, ref []byte) ([]byte, error) {
+	if data, ok := d.data[string(ref)]; ok {
+		return data, nil
+	}
+	return nil, errors.New("unknown ref")
+}
+
+// archiveTestCommittedDA serves DA data by commitment, ignoring the commitment.
+type archiveTestCommittedDA struct {
+	*archiveTestDA
+}
+
+func (d *archiveTestCommittedDA) RetrieveCommitted(ctx context.Context, ref []byte, _ common.Hash) ([]byte, error) {
+	return d.Retrieve(ctx, ref)
+}
+
+func TestInputArchive(t *testing.T) {
+	logger := testlog.Logger(t, log.LvlInfo)
+	ctx := context.Background()
+
+	t.Run("L1", func(t *testing.T) {
+		rng := rand.New(rand.NewSource(1234))
+		l1 := newArchiveTestL1(rng, 3)
+		path := filepath.Join(t.TempDir(), "archive")
+		archive, err := OpenInputArchive(logger, path)
+		require.NoError(t, err)
+		rec := archive.RecordL1(l1)
+
+		var numbers []uint64
+		for num, hash := range l1.byNumber {
+			numbers = append(numbers, num)
+			_, err := rec.L1BlockRefByNumber(ctx, num)
+			require.NoError(t, err)
+			_, _, err = rec.InfoAndTxsByHash(ctx, hash)
+			require.NoError(t, err)
+			_, _, err = rec.FetchReceipts(ctx, hash)
+			require.NoError(t, err)
+		}
+		_, err = rec.L1BlockRefByHash(ctx, common.Hash{0xaa})
+		require.ErrorIs(t, err, ethereum.NotFound, "errors of the inner source are returned as-is")
+		require.NoError(t, archive.Close())
+
+		// reopen the archive to replay from
+		archive, err = OpenInputArchive(logger, path)
+		require.NoError(t, err)
+		defer archive.Close()
+		replay := NewReplayL1Fetcher(archive)
+		latest := numbers[0]
+		for num, hash := range l1.byNumber {
+			block := l1.blocks[hash]
+			expectedRef := eth.InfoToL1BlockRef(eth.BlockToInfo(block))
+			ref, err := replay.L1BlockRefByNumber(ctx, num)
+			require.NoError(t, err)
+			require.Equal(t, expectedRef, ref)
+			ref, err = replay.L1BlockRefByHash(ctx, hash)
+			require.NoError(t, err)
+			require.Equal(t, expectedRef, ref)
+
+			info, txs, err := replay.InfoAndTxsByHash(ctx, hash)
+			require.NoError(t, err)
+			require.Equal(t, hash, info.Hash())
+			require.Equal(t, block.Root(), info.Root())
+			require.Len(t, txs, len(block.Transactions()))
+			for i, tx := range txs {
+				require.Equal(t, block.Transactions()[i].Hash(), tx.Hash())
+			}
+
+			_, receipts, err := replay.FetchReceipts(ctx, hash)
+			require.NoError(t, err)
+			require.Len(t, receipts, len(l1.receipts[hash]))
+			for i, r := range receipts {
+				expected := l1.receipts[hash][i]
+				require.Equal(t, expected.TxHash, r.TxHash)
+				require.Equal(t, expected.Status, r.Status)
+				require.Equal(t, expected.Logs, r.Logs, "logs are replayed with their metadata")
+			}
+			if num > latest {
+				latest = num
+			}
+		}
+		head, err := replay.L1BlockRefByLabel(ctx, eth.Unsafe)
+		require.NoError(t, err)
+		require.Equal(t, l1.byNumber[latest], head.Hash, "latest recorded block is the head")
+
+		_, err = replay.L1BlockRefByNumber(ctx, latest+1)
+		require.ErrorIs(t, err, ethereum.NotFound, "blocks past the recording are not found")
+		_, _, err = replay.FetchReceipts(ctx, common.Hash{0xaa})
+		require.ErrorIs(t, err, ethereum.NotFound)
+	})
+
+	t.Run("DA", func(t *testing.T) {
+		archive := NewMemoryInputArchive(logger)
+		require.Equal(t, da.NewCalldata(), archive.RecordDA(da.NewCalldata()), "inline data is not recorded")
+
+		blobs := &archiveTestDA{
+			DataAvailability: da.NewHTTPBlob(da.CelestiaPrefix, da.HTTPBlobConfig{}),
+			data:             map[string][]byte{"a": []byte("blob a"), "b": []byte("blob b")},
+		}
+		committed := &archiveTestCommittedDA{&archiveTestDA{
+			DataAvailability: da.NewHTTPBlob(da.EigenPrefix, da.HTTPBlobConfig{}),
+			data:             map[string][]byte{"a": []byte("committed a")},
+		}}
+		rec := archive.RecordDA(blobs)
+		recCommitted := archive.RecordDA(committed)
+		require.Implements(t, (*da.CommitmentRetriever)(nil), recCommitted)
+		require.Equal(t, da.EigenPrefix, recCommitted.Prefix())
+
+		data, err := rec.Retrieve(ctx, []byte("a"))
+		require.NoError(t, err)
+		require.Equal(t, []byte("blob a"), data)
+		_, err = rec.Retrieve(ctx, []byte("c"))
+		require.Error(t, err)
+		data, err = recCommitted.(da.CommitmentRetriever).RetrieveCommitted(ctx, []byte("a"), common.Hash{})
+		require.NoError(t, err)
+		require.Equal(t, []byte("committed a"), data)
+
+		replay := archive.ReplayDA(da.CelestiaPrefix)
+		data, err = replay.Retrieve(ctx, []byte("a"))
+		require.NoError(t, err)
+		require.Equal(t, []byte("blob a"), data)
+		_, err = replay.Retrieve(ctx, []byte("b"))
+		require.ErrorIs(t, err, ethereum.NotFound, "data that was never retrieved is not recorded")
+		confs, err := replay.Confirmations(ctx, []byte("a"))
+		require.NoError(t, err)
+		require.Equal(t, da.Finalized, confs)
+
+		data, err = archive.ReplayDA(da.EigenPrefix).Retrieve(ctx, []byte("a"))
+		require.NoError(t, err)
+		require.Equal(t, []byte("committed a"), data, "data retrieved by commitment is replayed by reference")
+	})
+
+	t.Run("rollup config", func(t *testing.T) {
+		archive := NewMemoryInputArchive(logger)
+		_, err := archive.RollupConfig()
+		require.ErrorIs(t, err, ethereum.NotFound)
+		cfg := &rollup.Config{BlockTime: 2, SeqWindowSize: 100, DAType: da.CelestiaType}
+		require.NoError(t, archive.WriteRollupConfig(cfg))
+		got, err := archive.RollupConfig()
+		require.NoError(t, err)
+		require.Equal(t, cfg, got)
+	})
+}
@@ -0,0 +1,137 @@
+package derive
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-service/da"
+)
+
+// ReplayL1Fetcher serves the L1 data of an input archive, to replay the derivation offline.
+// L1 data that was not recorded is reported as not found, which the derivation treats as the end of the L1 chain.
+type ReplayL1Fetcher struct {
+	archive *InputArchive
+}
+
+var _ L1Fetcher = (*ReplayL1Fetcher)(nil)
+
+func NewReplayL1Fetcher(archive *InputArchive) *ReplayL1Fetcher {
+	return &ReplayL1Fetcher{archive: archive}
+}
+
+// L1BlockRefByLabel returns the latest recorded canonical L1 block for any label:
+// all of the recorded L1 chain is considered final when replaying.
+func (r *ReplayL1Fetcher) L1BlockRefByLabel(ctx context.Context, label eth.BlockLabel) (eth.L1BlockRef, error) {
+	it := r.archive.db.NewIterator(archiveCanonicalPrefix, nil)
+	defer it.Release()
+	var latest []byte
+	for it.Next() {
+		latest = common.CopyBytes(it.Value())
+	}
+	if err := it.Error(); err != nil {
+		return eth.L1BlockRef{}, fmt.Errorf("failed to read canonical L1 blocks: %w", err)
+	}
+	if latest == nil {
+		return eth.L1BlockRef{}, fmt.Errorf("no L1 block recorded for label %s: %w", label, ethereum.NotFound)
+	}
+	return r.L1BlockRefByHash(ctx, common.BytesToHash(latest))
+}
+
+func (r *ReplayL1Fetcher) L1BlockRefByNumber(ctx context.Context, num uint64) (eth.L1BlockRef, error) {
+	hash, err := r.archive.get(archiveCanonicalKey(num))
+	if err != nil {
+		return eth.L1BlockRef{}, fmt.Errorf("failed to read canonical L1 block %d: %w", num, err)
+	}
+	return r.L1BlockRefByHash(ctx, common.BytesToHash(hash))
+}
+
+func (r *ReplayL1Fetcher) L1BlockRefByHash(ctx context.Context, hash common.Hash) (eth.L1BlockRef, error) {
+	info, err := r.InfoByHash(ctx, hash)
+	if err != nil {
+		return eth.L1BlockRef{}, err
+	}
+	return eth.InfoToL1BlockRef(info), nil
+}
+
+func (r *ReplayL1Fetcher) InfoByHash(_ context.Context, hash common.Hash) (eth.BlockInfo, error) {
+	enc, err := r.archive.get(archiveKey(archiveHeaderPrefix, hash.Bytes()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header of L1 block %s: %w", hash, err)
+	}
+	var header types.Header
+	if err := rlp.DecodeBytes(enc, &header); err != nil {
+		return nil, fmt.Errorf("invalid header of L1 block %s: %w", hash, err)
+	}
+	return eth.HeaderBlockInfo(&header), nil
+}
+
+func (r *ReplayL1Fetcher) InfoAndTxsByHash(ctx context.Context, hash common.Hash) (eth.BlockInfo, types.Transactions, error) {
+	info, err := r.InfoByHash(ctx, hash)
+	if err != nil {
+		return nil, nil, err
+	}
+	enc, err := r.archive.get(archiveKey(archiveTxsPrefix, hash.Bytes()))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read transactions of L1 block %s: %w", hash, err)
+	}
+	var txs types.Transactions
+	if err := rlp.DecodeBytes(enc, &txs); err != nil {
+		return nil, nil, fmt.Errorf("invalid transactions of L1 block %s: %w", hash, err)
+	}
+	return info, txs, nil
+}
+
+func (r *ReplayL1Fetcher) FetchReceipts(ctx context.Context, blockHash common.Hash) (eth.BlockInfo, types.Receipts, error) {
+	info, err := r.InfoByHash(ctx, blockHash)
+	if err != nil {
+		return nil, nil, err
+	}
+	enc, err := r.archive.get(archiveKey(archiveReceiptsPrefix, blockHash.Bytes()))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read receipts of L1 block %s: %w", blockHash, err)
+	}
+	var receipts types.Receipts
+	if err := json.Unmarshal(enc, &receipts); err != nil {
+		return nil, nil, fmt.Errorf("invalid receipts of L1 block %s: %w", blockHash, err)
+	}
+	return info, receipts, nil
+}
+
+// ReplayDA returns a read-only DA backend for the given prefix, that serves the data recorded in the archive.
+// All recorded data is reported as final, the derivation only resolved the data once it was confirmed deep enough.
+func (a *InputArchive) ReplayDA(prefix byte) da.DataAvailability {
+	return &replayDA{prefix: prefix, archive: a}
+}
+
+type replayDA struct {
+	prefix  byte
+	archive *InputArchive
+}
+
+func (r *replayDA) Prefix() byte {
+	return r.prefix
+}
+
+func (r *replayDA) Store(_ context.Context, _ []byte) ([]byte, error) {
+	return nil, errors.New("replayed DA backend is read-only")
+}
+
+func (r *replayDA) Retrieve(_ context.Context, ref []byte) ([]byte, error) {
+	data, err := r.archive.get(archiveDAKey(r.prefix, ref))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read DA data (prefix %d): %w", r.prefix, err)
+	}
+	return data, nil
+}
+
+func (r *replayDA) Confirmations(_ context.Context, _ []byte) (uint64, error) {
+	return da.Finalized, nil
+}
@@ -0,0 +1,364 @@
+package derive
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+)
+
+const (
+	// journalEntryHeaderSize is the size of the header of each journal entry:
+	// a little-endian uint32 of the payload data size, followed by a little-endian uint32 CRC32 checksum of the data.
+	journalEntryHeaderSize = 8
+	// maxJournalEntrySize limits the size of a single journal entry, to not allocate arbitrary memory for a corrupted entry.
+	maxJournalEntrySize = 10 * 1024 * 1024
+)
+
+var errJournalCorrupted = errors.New("corrupted journal entry")
+
+type journalEntry struct {
+	id        eth.BlockID
+	timestamp uint64
+	// offset of the entry in the journal file, including the header
+	offset int64
+	// size of the entry in the journal file, including the header
+	size uint64
+}
+
+// PayloadsJournal persists the unsafe payloads that are buffered in memory by the PayloadsQueue,
+// so they can be processed again after a restart, instead of having to be synced from peers again.
+//
+// The journal is an append-only file of SSZ-encoded payloads, each prefixed with their size and checksum.
+// Payloads are pruned once the safe head passes them, and the file is compacted when it is mostly made up of pruned payloads.
+// The journal is bounded by MaxSize bytes, like the PayloadsQueue it drops the lowest block numbers first,
+// and by MaxAge: payloads with a timestamp older than that are not journaled, nor reloaded.
+//
+// PayloadsJournal is safe to use concurrently.
+type PayloadsJournal struct {
+	log  log.Logger
+	path string
+
+	maxSize uint64
+	maxAge  time.Duration
+
+	mu sync.Mutex
+	f  *os.File
+	// entries that are live in the journal file, in order of the file
+	entries []journalEntry
+	// hashes indexes the live entries by block hash
+	hashes map[common.Hash]struct{}
+	// liveSize is the size of all live entries, fileSize the size of the journal file
+	liveSize uint64
+	fileSize uint64
+}
+
+// OpenPayloadsJournal opens the journal at the given path, or creates it if it does not exist yet.
+// The valid payloads of the existing journal are returned, to be processed again.
+// A corrupted or partially written journal is truncated to the last valid entry before it.
+// A maxAge of 0 disables the age bound.
+func OpenPayloadsJournal(log log.Logger, path string, maxSize uint64, maxAge time.Duration) (*PayloadsJournal, []*eth.ExecutionPayload, error) {
+	j := &PayloadsJournal{
+		log:     log,
+		path:    path,
+		maxSize: maxSize,
+		maxAge:  maxAge,
+		hashes:  make(map[common.Hash]struct{}),
+	}
+	payloads, err := j.load()
+	if err != nil {
+		return nil, nil, err
+	}
+	if j.liveSize > j.maxSize {
+		j.dropLowest()
+	}
+	// Always write the loaded payloads back to a new journal file,
+	// to drop any corrupted data, and the payloads that exceed the bounds.
+	if err := j.compact(); err != nil {
+		return nil, nil, err
+	}
+	out := payloads[:0]
+	for _, p := range payloads {
+		if _, ok := j.hashes[p.BlockHash]; ok {
+			out = append(out, p)
+		}
+	}
+	return j, out, nil
+}
+
+// load reads the valid payloads of the existing journal file, if any, and indexes them.
+func (j *PayloadsJournal) load() ([]*eth.ExecutionPayload, error) {
+	f, err := os.Open(j.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to open unsafe payloads journal: %w", err)
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	var payloads []*eth.ExecutionPayload
+	var offset int64
+	for {
+		p, size, err := readJournalEntry(r)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			j.log.Warn("Dropping remainder of corrupted unsafe payloads journal", "offset", offset, "loaded", len(payloads), "err", err)
+			break
+		}
+		entryOffset := offset
+		offset += int64(size)
+		if _, ok := j.hashes[p.BlockHash]; ok || j.tooOld(uint64(p.Timestamp)) {
+			continue
+		}
+		payloads = append(payloads, p)
+		j.addEntry(journalEntry{id: p.ID(), timestamp: uint64(p.Timestamp), offset: entryOffset, size: size})
+	}
+	// the size of the valid part of the file, the remainder is dropped by the compaction after loading.
+	j.fileSize = uint64(offset)
+	j.log.Info("Loaded unsafe payloads journal", "path", j.path, "payloads", len(payloads))
+	return payloads, nil
+}
+
+// readJournalEntry reads the next entry, and returns the decoded payload and the size of the entry.
+// It returns io.EOF if there are no more entries, and errJournalCorrupted if the entry is incomplete or invalid.
+func readJournalEntry(r io.Reader) (*eth.ExecutionPayload, uint64, error) {
+	var header [journalEntryHeaderSize]byte
+	if _, err := io.ReadFull(r, header[:]); err == io.EOF {
+		return nil, 0, io.EOF
+	} else if err != nil {
+		return nil, 0, fmt.Errorf("%w: incomplete header: %v", errJournalCorrupted, err)
+	}
+	size := binary.LittleEndian.Uint32(header[:4])
+	if size > maxJournalEntrySize {
+		return nil, 0, fmt.Errorf("%w: entry size %d exceeds max size", errJournalCorrupted, size)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, 0, fmt.Errorf("%w: incomplete data: %v", errJournalCorrupted, err)
+	}
+	if crc32.ChecksumIEEE(data) != binary.LittleEndian.Uint32(header[4:]) {
+		return nil, 0, fmt.Errorf("%w: checksum mismatch", errJournalCorrupted)
+	}
+	var p eth.ExecutionPayload
+	if err := p.UnmarshalSSZ(size, bytes.NewReader(data)); err != nil {
+		return nil, 0, fmt.Errorf("%w: failed to decode payload: %v", errJournalCorrupted, err)
+	}
+	if actual, ok := p.CheckBlockHash(); !ok {
+		return nil, 0, fmt.Errorf("%w: payload %s has bad block hash, expected %s", errJournalCorrupted, p.ID(), actual)
+	}
+	return &p, journalEntryHeaderSize + uint64(size), nil
+}
+
+func (j *PayloadsJournal) tooOld(timestamp uint64) bool {
+	return j.maxAge != 0 && time.Unix(int64(timestamp), 0).Before(time.Now().Add(-j.maxAge))
+}
+
+// Insert appends the payload to the journal.
+// Payloads that are already journaled, or that are too old, are ignored.
+func (j *PayloadsJournal) Insert(p *eth.ExecutionPayload) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.f == nil {
+		return errors.New("unsafe payloads journal is closed")
+	}
+	if j.tooOld(uint64(p.Timestamp)) {
+		return nil
+	}
+	if _, ok := j.hashes[p.BlockHash]; ok {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	buf.Write(make([]byte, journalEntryHeaderSize))
+	if _, err := p.MarshalSSZ(&buf); err != nil {
+		return fmt.Errorf("failed to encode payload %s: %w", p.ID(), err)
+	}
+	data := buf.Bytes()
+	if len(data) > journalEntryHeaderSize+maxJournalEntrySize || uint64(len(data)) > j.maxSize {
+		return fmt.Errorf("cannot journal payload %s of %d bytes, it exceeds the max size", p.ID(), len(data))
+	}
+	binary.LittleEndian.PutUint32(data[:4], uint32(len(data)-journalEntryHeaderSize))
+	binary.LittleEndian.PutUint32(data[4:8], crc32.ChecksumIEEE(data[journalEntryHeaderSize:]))
+	if _, err := j.f.Write(data); err != nil {
+		return fmt.Errorf("failed to write payload %s to journal: %w", p.ID(), err)
+	}
+	j.addEntry(journalEntry{id: p.ID(), timestamp: uint64(p.Timestamp), offset: int64(j.fileSize), size: uint64(len(data))})
+	j.fileSize += uint64(len(data))
+
+	if j.liveSize > j.maxSize {
+		j.dropLowest()
+	}
+	return j.maybeCompact()
+}
+
+// dropLowest removes the lowest block numbers from the journal, until the journal does not exceed the max size.
+// We prefer higher block numbers over lower block numbers, like the PayloadsQueue does.
+func (j *PayloadsJournal) dropLowest() {
+	byNum := make([]journalEntry, len(j.entries))
+	copy(byNum, j.entries)
+	sort.SliceStable(byNum, func(a, b int) bool { return byNum[a].id.Number < byNum[b].id.Number })
+	drop := make(map[common.Hash]struct{})
+	size := j.liveSize
+	for _, e := range byNum {
+		if size <= j.maxSize {
+			break
+		}
+		drop[e.id.Hash] = struct{}{}
+		size -= e.size
+	}
+	j.removeEntries(func(e journalEntry) bool {
+		_, ok := drop[e.id.Hash]
+		return ok
+	})
+}
+
+// Prune removes the payloads up to and including the given block number from the journal.
+func (j *PayloadsJournal) Prune(number uint64) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.f == nil {
+		return errors.New("unsafe payloads journal is closed")
+	}
+	j.removeEntries(func(e journalEntry) bool {
+		return e.id.Number <= number || j.tooOld(e.timestamp)
+	})
+	return j.maybeCompact()
+}
+
+// SafeHeadUpdated prunes the payloads that have been consolidated into the safe chain.
+// This implements the SafeHeadListener interface.
+func (j *PayloadsJournal) SafeHeadUpdated(safeHead eth.L2BlockRef, l1Head eth.BlockID) error {
+	return j.Prune(safeHead.Number)
+}
+
+// SafeHeadReset is a no-op: payloads past a reset safe head may still be needed.
+// This implements the SafeHeadListener interface.
+func (j *PayloadsJournal) SafeHeadReset(resetSafeHead eth.L2BlockRef) error {
+	return nil
+}
+
+var _ SafeHeadListener = (*PayloadsJournal)(nil)
+
+func (j *PayloadsJournal) addEntry(e journalEntry) {
+	j.entries = append(j.entries, e)
+	j.hashes[e.id.Hash] = struct{}{}
+	j.liveSize += e.size
+}
+
+// removeEntries removes the live entries that match the given function.
+// The data remains in the journal file until the file is compacted.
+func (j *PayloadsJournal) removeEntries(remove func(e journalEntry) bool) {
+	out := j.entries[:0]
+	for _, e := range j.entries {
+		if remove(e) {
+			delete(j.hashes, e.id.Hash)
+			j.liveSize -= e.size
+		} else {
+			out = append(out, e)
+		}
+	}
+	j.entries = out
+}
+
+// maybeCompact compacts the journal file if more than half of it is made up of removed payloads.
+func (j *PayloadsJournal) maybeCompact() error {
+	if j.fileSize-j.liveSize <= j.liveSize {
+		return nil
+	}
+	return j.compact()
+}
+
+// compact writes the live entries to a new journal file, and replaces the existing journal file with it.
+func (j *PayloadsJournal) compact() error {
+	var old *os.File
+	if j.f != nil {
+		old = j.f
+	} else if f, err := os.Open(j.path); err == nil {
+		old = f
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to open unsafe payloads journal: %w", err)
+	}
+	if j.f == nil && old != nil {
+		defer old.Close()
+	}
+
+	tmpPath := j.path + ".new"
+	tmp, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to create new unsafe payloads journal: %w", err)
+	}
+	entries := make([]journalEntry, 0, len(j.entries))
+	var offset int64
+	for _, e := range j.entries {
+		data := make([]byte, e.size)
+		if _, err := old.ReadAt(data, e.offset); err != nil {
+			_ = tmp.Close()
+			return fmt.Errorf("failed to read payload %s from unsafe payloads journal: %w", e.id, err)
+		}
+		if _, err := tmp.Write(data); err != nil {
+			_ = tmp.Close()
+			return fmt.Errorf("failed to write payload %s to new unsafe payloads journal: %w", e.id, err)
+		}
+		e.offset = offset
+		offset += int64(e.size)
+		entries = append(entries, e)
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf("failed to sync new unsafe payloads journal: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close new unsafe payloads journal: %w", err)
+	}
+	if j.f != nil {
+		if err := j.f.Close(); err != nil {
+			return fmt.Errorf("failed to close unsafe payloads journal: %w", err)
+		}
+		j.f = nil
+	}
+	if err := os.Rename(tmpPath, j.path); err != nil {
+		return fmt.Errorf("failed to replace unsafe payloads journal: %w", err)
+	}
+	f, err := os.OpenFile(j.path, os.O_RDWR|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open unsafe payloads journal: %w", err)
+	}
+	j.f = f
+	j.entries = entries
+	j.liveSize = uint64(offset)
+	j.fileSize = uint64(offset)
+	return nil
+}
+
+// Len returns the number of payloads in the journal.
+func (j *PayloadsJournal) Len() int {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return len(j.entries)
+}
+
+// Close closes the journal file. The journal can be opened again with OpenPayloadsJournal.
+func (j *PayloadsJournal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.f == nil {
+		return nil
+	}
+	err := j.f.Close()
+	j.f = nil
+	return err
+}
@@ -0,0 +1,189 @@
+package derive
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+)
+
+func journalTestPayload(num uint64, timestamp uint64) *eth.ExecutionPayload {
+	p := &eth.ExecutionPayload{
+		BlockNumber:  eth.Uint64Quantity(num),
+		Timestamp:    eth.Uint64Quantity(timestamp),
+		Transactions: []eth.Data{make([]byte, 100)},
+	}
+	p.BlockHash, _ = p.CheckBlockHash()
+	return p
+}
+
+func journalIDs(payloads []*eth.ExecutionPayload) (out []eth.BlockID) {
+	for _, p := range payloads {
+		out = append(out, p.ID())
+	}
+	return out
+}
+
+func TestPayloadsJournal(t *testing.T) {
+	logger := testlog.Logger(t, log.LvlInfo)
+	now := uint64(time.Now().Unix())
+
+	openJournal := func(t *testing.T, path string) (*PayloadsJournal, []*eth.ExecutionPayload) {
+		j, loaded, err := OpenPayloadsJournal(logger, path, 100_000, time.Hour)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = j.Close() })
+		return j, loaded
+	}
+
+	t.Run("reload", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "journal")
+		j, loaded := openJournal(t, path)
+		require.Empty(t, loaded)
+		a, b, c := journalTestPayload(10, now), journalTestPayload(12, now), journalTestPayload(11, now)
+		require.NoError(t, j.Insert(a))
+		require.NoError(t, j.Insert(b))
+		require.NoError(t, j.Insert(c))
+		require.NoError(t, j.Insert(b), "duplicates are ignored")
+		require.Equal(t, 3, j.Len())
+		require.NoError(t, j.Close())
+
+		j, loaded = openJournal(t, path)
+		require.Equal(t, journalIDs([]*eth.ExecutionPayload{a, b, c}), journalIDs(loaded))
+		require.Equal(t, b.BlockHash, loaded[1].BlockHash, "payloads are reloaded with their contents")
+
+		// the journal can be appended to after reloading
+		d := journalTestPayload(13, now)
+		require.NoError(t, j.Insert(d))
+		require.NoError(t, j.Close())
+		_, loaded = openJournal(t, path)
+		require.Equal(t, journalIDs([]*eth.ExecutionPayload{a, b, c, d}), journalIDs(loaded))
+	})
+
+	t.Run("prune", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "journal")
+		j, _ := openJournal(t, path)
+		var payloads []*eth.ExecutionPayload
+		for i := uint64(0); i < 10; i++ {
+			payloads = append(payloads, journalTestPayload(100+i, now))
+			require.NoError(t, j.Insert(payloads[i]))
+		}
+		require.NoError(t, j.SafeHeadUpdated(eth.L2BlockRef{Number: 103}, eth.BlockID{}))
+		require.Equal(t, 6, j.Len())
+		require.NoError(t, j.SafeHeadReset(eth.L2BlockRef{Number: 101}), "reset does not restore pruned payloads")
+		require.Equal(t, 6, j.Len())
+		// prune most of the journal, to trigger compaction
+		require.NoError(t, j.Prune(107))
+		require.Equal(t, 2, j.Len())
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		require.Equal(t, j.liveSize, uint64(info.Size()), "expected compacted journal file")
+		require.NoError(t, j.Close())
+
+		_, loaded := openJournal(t, path)
+		require.Equal(t, journalIDs(payloads[8:]), journalIDs(loaded))
+	})
+
+	t.Run("max size", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "journal")
+		first := journalTestPayload(0, now)
+		j, _, err := OpenPayloadsJournal(logger, path, 0, 0)
+		require.NoError(t, err)
+		require.ErrorContains(t, j.Insert(first), "exceeds the max size")
+		require.NoError(t, j.Close())
+
+		// room for 3 payloads
+		entrySize := uint64(journalEntryHeaderSize + first.SizeSSZ())
+		j, _, err = OpenPayloadsJournal(logger, path, 3*entrySize, 0)
+		require.NoError(t, err)
+		defer j.Close()
+		for _, n := range []uint64{5, 3, 4, 6} {
+			require.NoError(t, j.Insert(journalTestPayload(n, now)))
+		}
+		require.Equal(t, 3, j.Len(), "lowest block number should be dropped")
+		require.NoError(t, j.Close())
+		j, loaded, err := OpenPayloadsJournal(logger, path, 3*entrySize, 0)
+		require.NoError(t, err)
+		require.Equal(t, journalIDs([]*eth.ExecutionPayload{
+			journalTestPayload(5, now), journalTestPayload(4, now), journalTestPayload(6, now),
+		}), journalIDs(loaded))
+		require.NoError(t, j.Close())
+	})
+
+	t.Run("max age", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "journal")
+		j, _ := openJournal(t, path)
+		old := journalTestPayload(1, now-2*60*60)
+		recent := journalTestPayload(2, now-30*60)
+		require.NoError(t, j.Insert(old))
+		require.NoError(t, j.Insert(recent))
+		require.Equal(t, 1, j.Len(), "too old payloads are not journaled")
+		require.NoError(t, j.Close())
+
+		// reload with a shorter max age
+		j, loaded, err := OpenPayloadsJournal(logger, path, 100_000, 10*time.Minute)
+		require.NoError(t, err)
+		require.Empty(t, loaded, "payloads that became too old are not reloaded")
+		require.Zero(t, j.Len())
+		require.NoError(t, j.Close())
+	})
+
+	t.Run("partial", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "journal")
+		j, _ := openJournal(t, path)
+		a, b := journalTestPayload(1, now), journalTestPayload(2, now)
+		require.NoError(t, j.Insert(a))
+		require.NoError(t, j.Insert(b))
+		require.NoError(t, j.Close())
+
+		// simulate a crash while writing the last entry
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		require.NoError(t, os.Truncate(path, info.Size()-10))
+
+		j, loaded := openJournal(t, path)
+		require.Equal(t, journalIDs([]*eth.ExecutionPayload{a}), journalIDs(loaded))
+
+		// new entries are appended after the last valid entry
+		c := journalTestPayload(3, now)
+		require.NoError(t, j.Insert(c))
+		require.NoError(t, j.Close())
+		_, loaded = openJournal(t, path)
+		require.Equal(t, journalIDs([]*eth.ExecutionPayload{a, c}), journalIDs(loaded))
+	})
+
+	t.Run("corrupted", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "journal")
+		j, _ := openJournal(t, path)
+		var payloads []*eth.ExecutionPayload
+		for i := uint64(0); i < 3; i++ {
+			payloads = append(payloads, journalTestPayload(i, now))
+			require.NoError(t, j.Insert(payloads[i]))
+		}
+		require.NoError(t, j.Close())
+
+		// flip a byte in the data of the second entry
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		entrySize := journalEntryHeaderSize + payloads[0].SizeSSZ()
+		data[entrySize+journalEntryHeaderSize+10] ^= 0xff
+		require.NoError(t, os.WriteFile(path, data, 0o644))
+
+		_, loaded := openJournal(t, path)
+		require.Equal(t, journalIDs(payloads[:1]), journalIDs(loaded), "entries from the corrupted entry onwards are dropped")
+	})
+
+	t.Run("garbage", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "journal")
+		require.NoError(t, os.WriteFile(path, []byte("not a journal"), 0o644))
+		j, loaded := openJournal(t, path)
+		require.Empty(t, loaded)
+		require.NoError(t, j.Insert(journalTestPayload(1, now)))
+		require.Equal(t, 1, j.Len())
+	})
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/ethereum/go-ethereum/params"
 
 	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-service/da"
 )
 
 var (
@@ -34,6 +35,7 @@ var (
 	ErrChainIDsSame                  = errors.New("L1 and L2 chain IDs must be different")
 	ErrL1ChainIDNotPositive          = errors.New("L1 chain ID must be non-zero and positive")
 	ErrL2ChainIDNotPositive          = errors.New("L2 chain ID must be non-zero and positive")
+	ErrUnknownDAType                 = errors.New("unknown data availability type")
 )
 
 type Genesis struct {
@@ -75,6 +77,17 @@ type Config struct {
 	// Active if RegolithTime != nil && L2 block timestamp >= *RegolithTime, inactive otherwise.
 	RegolithTime *uint64 `json:"regolith_time,omitempty"`
 
+	// ChannelCompressionTime sets the activation time of the channel compression network-upgrade:
+	// channel data may be compressed with brotli or zstd instead of zlib, selected by a channel version byte.
+	// Active if ChannelCompressionTime != nil && L1 inclusion block timestamp >= *ChannelCompressionTime, inactive otherwise.
+	ChannelCompressionTime *uint64 `json:"channel_compression_time,omitempty"`
+
+	// DACommitmentTime sets the activation time of the DA commitment network-upgrade:
+	// batch inbox payloads that refer to data on a DA backend other than L1 must commit to the data,
+	// legacy payloads without a commitment are ignored, like they are by the fault proof program.
+	// Active if DACommitmentTime != nil && L1 inclusion block timestamp >= *DACommitmentTime, inactive otherwise.
+	DACommitmentTime *uint64 `json:"da_commitment_time,omitempty"`
+
 	// Note: below addresses are part of the block-derivation process,
 	// and required to be the same network-wide to stay in consensus.
 
@@ -84,6 +97,10 @@ type Config struct {
 	DepositContractAddress common.Address `json:"deposit_contract_address"`
 	// L1 System Config Address
 	L1SystemConfigAddress common.Address `json:"l1_system_config_address"`
+
+	// DAType is the data availability backend that the batcher posts batch data to,
+	// one of the da.Types names. Defaults to calldata on L1 if empty.
+	DAType string `json:"da_type,omitempty"`
 }
 
 // ValidateL1Config checks L1 config variables for errors.
@@ -244,6 +261,9 @@ func (cfg *Config) Check() error {
 	if cfg.L2ChainID.Sign() < 1 {
 		return ErrL2ChainIDNotPositive
 	}
+	if _, ok := da.Types[da.TypeOrDefault(cfg.DAType)]; !ok {
+		return ErrUnknownDAType
+	}
 	return nil
 }
 
@@ -256,6 +276,16 @@ func (c *Config) IsRegolith(timestamp uint64) bool {
 	return c.RegolithTime != nil && timestamp >= *c.RegolithTime
 }
 
+// IsChannelCompression returns true if the channel compression upgrade is active at or past the given L1 timestamp.
+func (c *Config) IsChannelCompression(l1Timestamp uint64) bool {
+	return c.ChannelCompressionTime != nil && l1Timestamp >= *c.ChannelCompressionTime
+}
+
+// IsDACommitment returns true if the DA commitment upgrade is active at or past the given L1 timestamp.
+func (c *Config) IsDACommitment(l1Timestamp uint64) bool {
+	return c.DACommitmentTime != nil && l1Timestamp >= *c.DACommitmentTime
+}
+
 // Description outputs a banner describing the important parts of rollup configuration in a human-readable form.
 // Optionally provide a mapping of L2 chain IDs to network names to label the L2 chain with if not unknown.
 // The config should be config.Check()-ed before creating a description.
@@ -280,9 +310,12 @@ func (c *Config) Description(l2Chains map[string]string) string {
 	banner += fmt.Sprintf("  L2 starting time: %d ~ %s\n", c.Genesis.L2Time, fmtTime(c.Genesis.L2Time))
 	banner += fmt.Sprintf("  L2 block: %s %d\n", c.Genesis.L2.Hash, c.Genesis.L2.Number)
 	banner += fmt.Sprintf("  L1 block: %s %d\n", c.Genesis.L1.Hash, c.Genesis.L1.Number)
+	banner += fmt.Sprintf("Data availability: %s\n", da.TypeOrDefault(c.DAType))
 	// Report the upgrade configuration
 	banner += "Post-Bedrock Network Upgrades (timestamp based):\n"
 	banner += fmt.Sprintf("  - Regolith: %s\n", fmtForkTimeOrUnset(c.RegolithTime))
+	banner += fmt.Sprintf("  - Channel compression (L1 time): %s\n", fmtForkTimeOrUnset(c.ChannelCompressionTime))
+	banner += fmt.Sprintf("  - DA commitment (L1 time): %s\n", fmtForkTimeOrUnset(c.DACommitmentTime))
 	return banner
 }
 
@@ -305,7 +338,10 @@ func (c *Config) LogDescription(log log.Logger, l2Chains map[string]string) {
 	log.Info("Rollup Config", "l2_chain_id", c.L2ChainID, "l2_network", networkL2, "l1_chain_id", c.L1ChainID,
 		"l1_network", networkL1, "l2_start_time", c.Genesis.L2Time, "l2_block_hash", c.Genesis.L2.Hash.String(),
 		"l2_block_number", c.Genesis.L2.Number, "l1_block_hash", c.Genesis.L1.Hash.String(),
-		"l1_block_number", c.Genesis.L1.Number, "regolith_time", fmtForkTimeOrUnset(c.RegolithTime))
+		"l1_block_number", c.Genesis.L1.Number, "regolith_time", fmtForkTimeOrUnset(c.RegolithTime),
+		"channel_compression_time", fmtForkTimeOrUnset(c.ChannelCompressionTime),
+		"da_commitment_time", fmtForkTimeOrUnset(c.DACommitmentTime),
+		"da_type", da.TypeOrDefault(c.DAType))
 }
 
 func fmtForkTimeOrUnset(v *uint64) string {
@@ -178,6 +178,17 @@ func TestRandomConfigDescription(t *testing.T) {
 		// Don't make this test fail only in Australia :')
 		require.Contains(t, out, fmt.Sprintf("Regolith: @ %d ~ ", x))
 	})
+	t.Run("da type unset", func(t *testing.T) {
+		config := randConfig()
+		out := config.Description(nil)
+		require.Contains(t, out, "Data availability: BTC")
+	})
+	t.Run("da type", func(t *testing.T) {
+		config := randConfig()
+		config.DAType = "CELESTIA"
+		out := config.Description(nil)
+		require.Contains(t, out, "Data availability: CELESTIA")
+	})
 }
 
 // TestRegolithActivation tests the activation condition of the Regolith upgrade.
@@ -386,6 +397,11 @@ func TestConfig_Check(t *testing.T) {
 			modifier:    func(cfg *Config) { cfg.L2ChainID = big.NewInt(0) },
 			expectedErr: ErrL2ChainIDNotPositive,
 		},
+		{
+			name:        "UnknownDAType",
+			modifier:    func(cfg *Config) { cfg.DAType = "FLOPPY" },
+			expectedErr: ErrUnknownDAType,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -8,6 +8,12 @@ echo "CELESTIA" $CELESTIA
 echo "EIGEN" $EIGEN
 echo "NEARDA" $NEARDA
 
+# blob server of the CELESTIA, EIGEN or NEARDA DA types
+if [ "$DA_SERVER" == "" ]; then
+    DA_SERVER=${CELESTIA:-${EIGEN:-$NEARDA}}
+fi
+echo "DA_SERVER" $DA_SERVER
+
 #legacy config
 
 
@@ -42,6 +48,7 @@ if [ "$MASTER" == "1" ]; then
     --l1.rpckind=basic \
     --l1.epoch-poll-interval=10s \
     --l1-da-rpc=$POLYGON \
+    --da.server=$DA_SERVER \
     --log.level info 2>&1 | cronolog $PWD/resources/logs/%Y-%m-%d.log
 else
   ./bin/op-node \
@@ -62,6 +69,7 @@ else
     --l1.rpckind=basic \
     --l1.epoch-poll-interval=10s \
     --l1-da-rpc=$POLYGON \
+    --da.server=$DA_SERVER \
     --log.level info 2>&1 | cronolog $PWD/resources/logs/%Y-%m-%d.log
 fi
 
@@ -10,6 +10,7 @@ import (
 
 	"github.com/ethereum-optimism/optimism/op-node/chaincfg"
 	"github.com/ethereum-optimism/optimism/op-node/sources"
+	"github.com/ethereum-optimism/optimism/op-service/da"
 	oppprof "github.com/ethereum-optimism/optimism/op-service/pprof"
 
 	"github.com/urfave/cli"
@@ -62,6 +63,7 @@ func NewConfig(ctx *cli.Context, log log.Logger) (*node.Config, error) {
 		L2:     l2Endpoint,
 		L2Sync: l2SyncEndpoint,
 		Rollup: *rollupConfig,
+		DA:     da.ReadCLIConfig(ctx),
 		Driver: *driverConfig,
 		RPC: node.RPCConfig{
 			ListenAddr:  ctx.GlobalString(flags.RPCListenAddr.Name),
@@ -81,6 +83,13 @@ func NewConfig(ctx *cli.Context, log log.Logger) (*node.Config, error) {
 		P2P:                 p2pConfig,
 		P2PSigner:           p2pSignerSetup,
 		L1EpochPollInterval: ctx.GlobalDuration(flags.L1EpochPollIntervalFlag.Name),
+		SafeDBPath:          ctx.GlobalString(flags.SafeDBPath.Name),
+		UnsafePayloadsJournal: node.UnsafePayloadsJournalConfig{
+			Path:    ctx.GlobalString(flags.UnsafeJournalPath.Name),
+			MaxSize: ctx.GlobalUint64(flags.UnsafeJournalMaxSize.Name),
+			MaxAge:  ctx.GlobalDuration(flags.UnsafeJournalMaxAge.Name),
+		},
+		DerivationRecordPath: ctx.GlobalString(flags.DerivationRecordPath.Name),
 		Heartbeat: node.HeartbeatConfig{
 			Enabled: ctx.GlobalBool(flags.HeartbeatEnabledFlag.Name),
 			Moniker: ctx.GlobalString(flags.HeartbeatMonikerFlag.Name),
@@ -95,13 +104,14 @@ func NewConfig(ctx *cli.Context, log log.Logger) (*node.Config, error) {
 
 func NewL1EndpointConfig(ctx *cli.Context) *node.L1EndpointConfig {
 	return &node.L1EndpointConfig{
-		L1NodeAddr:       ctx.GlobalString(flags.L1NodeAddr.Name),
-		L1TrustRPC:       ctx.GlobalBool(flags.L1TrustRPC.Name),
-		L1DAAddr:         ctx.GlobalString(flags.L1DAAddr.Name),
-		L1RPCKind:        sources.RPCProviderKind(strings.ToLower(ctx.GlobalString(flags.L1RPCProviderKind.Name))),
-		RateLimit:        ctx.GlobalFloat64(flags.L1RPCRateLimit.Name),
-		BatchSize:        ctx.GlobalInt(flags.L1RPCMaxBatchSize.Name),
-		HttpPollInterval: ctx.Duration(flags.L1HTTPPollInterval.Name),
+		L1NodeAddr:          ctx.GlobalString(flags.L1NodeAddr.Name),
+		L1TrustRPC:          ctx.GlobalBool(flags.L1TrustRPC.Name),
+		L1RPCKind:           sources.RPCProviderKind(strings.ToLower(ctx.GlobalString(flags.L1RPCProviderKind.Name))),
+		RateLimit:           ctx.GlobalFloat64(flags.L1RPCRateLimit.Name),
+		BatchSize:           ctx.GlobalInt(flags.L1RPCMaxBatchSize.Name),
+		HttpPollInterval:    ctx.Duration(flags.L1HTTPPollInterval.Name),
+		HealthCheckInterval: ctx.GlobalDuration(flags.L1HealthCheckInterval.Name),
+		CrossCheck:          ctx.GlobalBool(flags.L1CrossCheck.Name),
 	}
 }
 
@@ -24,6 +24,18 @@ func (r *RollupClient) OutputAtBlock(ctx context.Context, blockNum uint64) (*eth
 	return output, err
 }
 
+func (r *RollupClient) DAConfirmations(ctx context.Context, start, end uint64) (*eth.DAConfirmations, error) {
+	var output *eth.DAConfirmations
+	err := r.rpc.CallContext(ctx, &output, "optimism_daConfirmations", hexutil.Uint64(start), hexutil.Uint64(end))
+	return output, err
+}
+
+func (r *RollupClient) SafeHeadAtL1Block(ctx context.Context, blockNum uint64) (*eth.SafeHeadResponse, error) {
+	var output *eth.SafeHeadResponse
+	err := r.rpc.CallContext(ctx, &output, "optimism_safeHeadAtL1Block", hexutil.Uint64(blockNum))
+	return output, err
+}
+
 func (r *RollupClient) SyncStatus(ctx context.Context) (*eth.SyncStatus, error) {
 	var output *eth.SyncStatus
 	err := r.rpc.CallContext(ctx, &output, "optimism_syncStatus")
@@ -1,6 +1,8 @@
 package testutils
 
 import (
+	"time"
+
 	"github.com/ethereum-optimism/optimism/op-node/eth"
 )
 
@@ -12,6 +14,7 @@ type TestDerivationMetrics struct {
 	FnRecordL2Ref             func(name string, ref eth.L2BlockRef)
 	FnRecordUnsafePayloads    func(length uint64, memSize uint64, next eth.BlockID)
 	FnRecordChannelInputBytes func(inputCompresedBytes int)
+	FnRecordDAFetch           func(backend string, size int, duration time.Duration, err error)
 }
 
 func (t *TestDerivationMetrics) RecordL1ReorgDepth(d uint64) {
@@ -44,6 +47,12 @@ func (t *TestDerivationMetrics) RecordChannelInputBytes(inputCompresedBytes int)
 	}
 }
 
+func (t *TestDerivationMetrics) RecordDAFetch(backend string, size int, duration time.Duration, err error) {
+	if t.FnRecordDAFetch != nil {
+		t.FnRecordDAFetch(backend, size, duration, err)
+	}
+}
+
 type TestRPCMetrics struct{}
 
 func (n *TestRPCMetrics) RecordRPCServerRequest(method string) func() {
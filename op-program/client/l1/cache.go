@@ -16,17 +16,20 @@ type CachingOracle struct {
 	blocks *simplelru.LRU[common.Hash, eth.BlockInfo]
 	txs    *simplelru.LRU[common.Hash, types.Transactions]
 	rcpts  *simplelru.LRU[common.Hash, types.Receipts]
+	blobs  *simplelru.LRU[common.Hash, []byte]
 }
 
 func NewCachingOracle(oracle Oracle) *CachingOracle {
 	blockLRU, _ := simplelru.NewLRU[common.Hash, eth.BlockInfo](cacheSize, nil)
 	txsLRU, _ := simplelru.NewLRU[common.Hash, types.Transactions](cacheSize, nil)
 	rcptsLRU, _ := simplelru.NewLRU[common.Hash, types.Receipts](cacheSize, nil)
+	blobsLRU, _ := simplelru.NewLRU[common.Hash, []byte](cacheSize, nil)
 	return &CachingOracle{
 		oracle: oracle,
 		blocks: blockLRU,
 		txs:    txsLRU,
 		rcpts:  rcptsLRU,
+		blobs:  blobsLRU,
 	}
 }
 
@@ -61,3 +64,13 @@ func (o *CachingOracle) ReceiptsByBlockHash(blockHash common.Hash) (eth.BlockInf
 	o.rcpts.Add(blockHash, rcpts)
 	return block, rcpts
 }
+
+func (o *CachingOracle) DABlob(prefix byte, ref []byte, commitment common.Hash) []byte {
+	blob, ok := o.blobs.Get(commitment)
+	if ok {
+		return blob
+	}
+	blob = o.oracle.DABlob(prefix, ref, commitment)
+	o.blobs.Add(commitment, blob)
+	return blob
+}
@@ -7,6 +7,8 @@ import (
 	"github.com/ethereum-optimism/optimism/op-node/eth"
 	"github.com/ethereum-optimism/optimism/op-node/testutils"
 	"github.com/ethereum-optimism/optimism/op-program/client/l1/test"
+	"github.com/ethereum-optimism/optimism/op-service/da"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/stretchr/testify/require"
 )
 
@@ -71,3 +73,22 @@ func TestCachingOracle_ReceiptsByBlockHash(t *testing.T) {
 	require.Equal(t, eth.BlockToInfo(block), actualBlock)
 	require.EqualValues(t, rcpts, actualRcpts)
 }
+
+func TestCachingOracle_DABlob(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	stub := test.NewStubOracle(t)
+	oracle := NewCachingOracle(stub)
+	blob := testutils.RandomData(rng, 100)
+	commitment := crypto.Keccak256Hash(blob)
+	ref := testutils.RandomData(rng, 32)
+
+	// Initial call retrieves from the stub
+	stub.Blobs[commitment] = blob
+	result := oracle.DABlob(da.CelestiaPrefix, ref, commitment)
+	require.Equal(t, blob, result)
+
+	// Later calls should retrieve from cache
+	delete(stub.Blobs, commitment)
+	result = oracle.DABlob(da.CelestiaPrefix, ref, commitment)
+	require.Equal(t, blob, result)
+}
@@ -0,0 +1,59 @@
+package l1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ethereum-optimism/optimism/op-service/da"
+)
+
+// OracleDA is a read-only data availability backend that retrieves DA blobs through the pre-image oracle.
+// Blobs are addressed by the commitment posted to L1, so legacy payloads without commitment cannot be resolved.
+// The derivation ignores such payloads once the DA commitment upgrade of the rollup config is active,
+// so only L1 blocks from that upgrade on can be proven.
+type OracleDA struct {
+	// mu serializes the hint and pre-image request of a retrieval,
+	// as the derivation pipeline may resolve DA blobs concurrently.
+	mu     sync.Mutex
+	prefix byte
+	oracle Oracle
+}
+
+var (
+	_ da.DataAvailability    = (*OracleDA)(nil)
+	_ da.CommitmentRetriever = (*OracleDA)(nil)
+)
+
+func NewOracleDA(prefix byte, oracle Oracle) *OracleDA {
+	return &OracleDA{prefix: prefix, oracle: oracle}
+}
+
+func (o *OracleDA) Prefix() byte {
+	return o.prefix
+}
+
+func (o *OracleDA) Store(_ context.Context, _ []byte) ([]byte, error) {
+	return nil, errors.New("oracle DA backend is read-only")
+}
+
+// Retrieve always fails: legacy payloads without commitment are only derived before the DA commitment upgrade,
+// which the program cannot prove.
+func (o *OracleDA) Retrieve(_ context.Context, _ []byte) ([]byte, error) {
+	return nil, fmt.Errorf("%w: the fault proof program requires the DA commitment upgrade", da.ErrNoCommitment)
+}
+
+func (o *OracleDA) RetrieveCommitted(_ context.Context, ref []byte, commitment common.Hash) ([]byte, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.oracle.DABlob(o.prefix, ref, commitment), nil
+}
+
+// Confirmations reports all DA data as final, as the program cannot observe the DA backend itself:
+// the host serves every blob that it can retrieve.
+func (o *OracleDA) Confirmations(_ context.Context, _ []byte) (uint64, error) {
+	return da.Finalized, nil
+}
@@ -0,0 +1,43 @@
+package l1
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/testutils"
+	"github.com/ethereum-optimism/optimism/op-program/client/l1/test"
+	"github.com/ethereum-optimism/optimism/op-service/da"
+)
+
+func TestOracleDA(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	stub := test.NewStubOracle(t)
+	backend := NewOracleDA(da.CelestiaPrefix, stub)
+	require.Equal(t, da.CelestiaPrefix, backend.Prefix())
+
+	blob := testutils.RandomData(rng, 100)
+	ref := testutils.RandomData(rng, 32)
+	stub.Blobs[crypto.Keccak256Hash(blob)] = blob
+	payload := da.EncodePayload(da.CelestiaPrefix, blob, ref)
+	payloads, err := da.DecodePayloads(payload)
+	require.NoError(t, err)
+	require.Len(t, payloads, 1)
+
+	result, err := backend.RetrieveCommitted(context.Background(), payloads[0].Ref, *payloads[0].Commitment)
+	require.NoError(t, err)
+	require.Equal(t, blob, result)
+	require.NoError(t, payloads[0].Verify(result))
+
+	_, err = backend.Retrieve(context.Background(), ref)
+	require.ErrorIs(t, err, da.ErrNoCommitment)
+	_, err = backend.Store(context.Background(), blob)
+	require.Error(t, err)
+
+	confs, err := backend.Confirmations(context.Background(), ref)
+	require.NoError(t, err)
+	require.Equal(t, da.Finalized, confs)
+}
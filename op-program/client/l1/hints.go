@@ -1,7 +1,10 @@
 package l1
 
 import (
+	"fmt"
+
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 
 	"github.com/ethereum-optimism/optimism/op-program/preimage"
 )
@@ -10,6 +13,7 @@ const (
 	HintL1BlockHeader  = "l1-block-header"
 	HintL1Transactions = "l1-transactions"
 	HintL1Receipts     = "l1-receipts"
+	HintDABlob         = "da-blob"
 )
 
 type BlockHeaderHint common.Hash
@@ -35,3 +39,15 @@ var _ preimage.Hint = ReceiptsHint{}
 func (l ReceiptsHint) Hint() string {
 	return HintL1Receipts + " " + (common.Hash)(l).String()
 }
+
+// DABlobHint requests the DA blob that the given reference points to on the backend with the given prefix.
+type DABlobHint struct {
+	Prefix byte
+	Ref    []byte
+}
+
+var _ preimage.Hint = DABlobHint{}
+
+func (l DABlobHint) Hint() string {
+	return fmt.Sprintf("%s %d %s", HintDABlob, l.Prefix, hexutil.Encode(l.Ref))
+}
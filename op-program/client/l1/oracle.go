@@ -21,6 +21,10 @@ type Oracle interface {
 
 	// ReceiptsByBlockHash retrieves the receipts from the block with the given hash.
 	ReceiptsByBlockHash(blockHash common.Hash) (eth.BlockInfo, types.Receipts)
+
+	// DABlob retrieves the data that the reference points to on the DA backend with the given prefix,
+	// addressed by the keccak256 commitment to the data.
+	DABlob(prefix byte, ref []byte, commitment common.Hash) []byte
 }
 
 // PreimageOracle implements Oracle using by interfacing with the pure preimage.Oracle
@@ -86,3 +90,8 @@ func (p *PreimageOracle) ReceiptsByBlockHash(blockHash common.Hash) (eth.BlockIn
 
 	return info, receipts
 }
+
+func (p *PreimageOracle) DABlob(prefix byte, ref []byte, commitment common.Hash) []byte {
+	p.hint.Hint(DABlobHint{Prefix: prefix, Ref: ref})
+	return p.oracle.Get(preimage.DABlobKey(commitment))
+}
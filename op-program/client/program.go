@@ -18,6 +18,7 @@ import (
 	"github.com/ethereum-optimism/optimism/op-program/client/l2"
 	oppio "github.com/ethereum-optimism/optimism/op-program/io"
 	"github.com/ethereum-optimism/optimism/op-program/preimage"
+	"github.com/ethereum-optimism/optimism/op-service/da"
 )
 
 // Main executes the client program in a detached context and exits the current process.
@@ -70,8 +71,18 @@ func runDerivation(logger log.Logger, cfg *rollup.Config, l2Cfg *params.ChainCon
 	}
 	l2Source := l2.NewOracleEngine(cfg, logger, engineBackend)
 
+	// Batch data stored off L1 is retrieved through the preimage oracle, addressed by its commitment.
+	backends := []da.DataAvailability{da.NewCalldata()}
+	if prefix := da.Types[da.TypeOrDefault(cfg.DAType)]; prefix != da.CalldataPrefix {
+		backends = append(backends, l1.NewOracleDA(prefix, l1Oracle))
+	}
+	daSources, err := da.NewRegistry(backends...)
+	if err != nil {
+		return fmt.Errorf("failed to create data availability sources: %w", err)
+	}
+
 	logger.Info("Starting derivation")
-	d := cldr.NewDriver(logger, cfg, l1Source, l2Source, l2ClaimBlockNum)
+	d := cldr.NewDriver(logger, cfg, l1Source, daSources, l2Source, l2ClaimBlockNum)
 	for {
 		if err = d.Step(context.Background()); errors.Is(err, io.EOF) {
 			break
@@ -259,6 +259,19 @@ func TestServerMode(t *testing.T) {
 	})
 }
 
+func TestDA(t *testing.T) {
+	t.Run("Server", func(t *testing.T) {
+		server := "http://localhost:26658"
+		cfg := configForArgs(t, addRequiredArgs("--da.server", server))
+		require.Equal(t, server, cfg.DA.Server)
+	})
+	t.Run("RPC", func(t *testing.T) {
+		rpc := "https://example.com:8545"
+		cfg := configForArgs(t, addRequiredArgs("--l1-da-rpc", rpc))
+		require.Equal(t, rpc, cfg.DA.RPC)
+	})
+}
+
 func verifyArgsInvalid(t *testing.T, messageContains string, cliArgs []string) {
 	_, _, err := runWithArgs(cliArgs)
 	require.ErrorContains(t, err, messageContains)
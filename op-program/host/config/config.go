@@ -10,6 +10,7 @@ import (
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
 	"github.com/ethereum-optimism/optimism/op-node/sources"
 	"github.com/ethereum-optimism/optimism/op-program/host/flags"
+	"github.com/ethereum-optimism/optimism/op-service/da"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/params"
@@ -57,6 +58,10 @@ type Config struct {
 	// ServerMode indicates that the program should run in pre-image server mode and wait for requests.
 	// No client program is run.
 	ServerMode bool
+
+	// DA configures the data availability backend that batch data is fetched from,
+	// if the rollup does not post its batches to L1 calldata.
+	DA da.CLIConfig
 }
 
 func (c *Config) Check() error {
@@ -90,6 +95,14 @@ func (c *Config) Check() error {
 	if c.ServerMode && c.ExecCmd != "" {
 		return ErrNoExecInServerMode
 	}
+	if c.FetchingEnabled() {
+		if err := c.DA.Check(); err != nil {
+			return err
+		}
+		if err := c.DA.CheckType(da.TypeOrDefault(c.Rollup.DAType)); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -107,6 +120,10 @@ func NewConfig(rollupCfg *rollup.Config, l2Genesis *params.ChainConfig, l1Head c
 		L2Claim:            l2Claim,
 		L2ClaimBlockNumber: l2ClaimBlockNum,
 		L1RPCKind:          sources.RPCKindBasic,
+		DA: da.CLIConfig{
+			StoreTimeout:    da.DefaultStoreTimeout,
+			RetrieveTimeout: da.DefaultRetrieveTimeout,
+		},
 	}
 }
 
@@ -159,6 +176,7 @@ func NewConfigFromCLI(ctx *cli.Context) (*Config, error) {
 		L1RPCKind:          sources.RPCProviderKind(ctx.GlobalString(flags.L1RPCProviderKind.Name)),
 		ExecCmd:            ctx.GlobalString(flags.Exec.Name),
 		ServerMode:         ctx.GlobalBool(flags.Server.Name),
+		DA:                 da.ReadCLIConfig(ctx),
 	}, nil
 }
 
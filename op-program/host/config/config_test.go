@@ -5,6 +5,7 @@ import (
 
 	"github.com/ethereum-optimism/optimism/op-node/chaincfg"
 	"github.com/ethereum-optimism/optimism/op-node/rollup"
+	"github.com/ethereum-optimism/optimism/op-service/da"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/stretchr/testify/require"
@@ -150,6 +151,37 @@ func TestRejectExecAndServerMode(t *testing.T) {
 	require.ErrorIs(t, err, ErrNoExecInServerMode)
 }
 
+func TestDAConfig(t *testing.T) {
+	t.Run("NotRequiredWithoutFetching", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.Rollup = celestiaRollupConfig()
+		require.NoError(t, cfg.Check())
+	})
+	t.Run("RequiredWhenFetching", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.Rollup = celestiaRollupConfig()
+		cfg.L1URL = "https://example.com:1234"
+		cfg.L2URL = "https://example.com:5678"
+		require.ErrorContains(t, cfg.Check(), da.ServerFlagName)
+
+		cfg.DA.Server = "http://localhost:26658"
+		require.NoError(t, cfg.Check())
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		cfg := validConfig()
+		cfg.L1URL = "https://example.com:1234"
+		cfg.L2URL = "https://example.com:5678"
+		cfg.DA.Server = "ftp://localhost"
+		require.ErrorContains(t, cfg.Check(), "must be http or https")
+	})
+}
+
+func celestiaRollupConfig() *rollup.Config {
+	rollupCfg := *validRollupConfig
+	rollupCfg.DAType = da.CelestiaType
+	return &rollupCfg
+}
+
 func validConfig() *Config {
 	cfg := NewConfig(validRollupConfig, validL2Genesis, validL1Head, validL2Head, validL2Claim, validL2ClaimBlockNum)
 	cfg.DataDir = "/tmp/configTest"
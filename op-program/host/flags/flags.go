@@ -10,6 +10,7 @@ import (
 	nodeflags "github.com/ethereum-optimism/optimism/op-node/flags"
 	"github.com/ethereum-optimism/optimism/op-node/sources"
 	service "github.com/ethereum-optimism/optimism/op-service"
+	"github.com/ethereum-optimism/optimism/op-service/da"
 	oplog "github.com/ethereum-optimism/optimism/op-service/log"
 )
 
@@ -119,6 +120,7 @@ func init() {
 	Flags = append(Flags, oplog.CLIFlags(EnvVarPrefix)...)
 	Flags = append(Flags, requiredFlags...)
 	Flags = append(Flags, programFlags...)
+	Flags = append(Flags, da.CLIFlags(EnvVarPrefix)...)
 }
 
 func CheckRequired(ctx *cli.Context) error {
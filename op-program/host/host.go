@@ -21,6 +21,7 @@ import (
 	oppio "github.com/ethereum-optimism/optimism/op-program/io"
 	"github.com/ethereum-optimism/optimism/op-program/preimage"
 	opservice "github.com/ethereum-optimism/optimism/op-service"
+	"github.com/ethereum-optimism/optimism/op-service/da"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
 )
@@ -210,7 +211,41 @@ func makePrefetcher(ctx context.Context, logger log.Logger, kv kvstore.KV, cfg *
 		return nil, fmt.Errorf("failed to create L2 client: %w", err)
 	}
 	l2DebugCl := &L2Source{L2Client: l2Cl, DebugClient: sources.NewDebugClient(l2RPC.CallContext)}
-	return prefetcher.NewPrefetcher(logger, l1Cl, l2DebugCl, kv), nil
+
+	daSources, err := makeDASources(ctx, logger, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup DA sources: %w", err)
+	}
+	return prefetcher.NewPrefetcher(logger, l1Cl, l2DebugCl, prefetcher.NewRegistryDASource(daSources), kv), nil
+}
+
+// makeDASources sets up the data availability backend of the rollup, next to L1 calldata, to fetch batch data from.
+func makeDASources(ctx context.Context, logger log.Logger, cfg *config.Config) (*da.Registry, error) {
+	typ := da.TypeOrDefault(cfg.Rollup.DAType)
+	backends := []da.DataAvailability{da.NewCalldata()}
+	switch typ {
+	case da.CalldataType:
+	case da.PolygonType:
+		logger.Info("Connecting to DA chain", "rpc", cfg.DA.RPC)
+		daRPC, err := client.NewRPC(ctx, logger, cfg.DA.RPC)
+		if err != nil {
+			return nil, fmt.Errorf("failed to setup DA chain RPC: %w", err)
+		}
+		rpcCfg := sources.L1ClientDefaultConfig(cfg.Rollup, false, sources.RPCKindBasic)
+		daChain, err := sources.NewEthClient(daRPC, logger, nil, &rpcCfg.EthClientConfig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create DA chain client: %w", err)
+		}
+		backends = append(backends, da.NewPolygon(nil, cfg.Rollup.BatchInboxAddress, daChain))
+	default:
+		logger.Info("Connecting to DA server", "type", typ, "server", cfg.DA.Server)
+		blob, err := cfg.DA.NewHTTPBlob(logger, typ)
+		if err != nil {
+			return nil, err
+		}
+		backends = append(backends, blob)
+	}
+	return da.NewRegistry(backends...)
 }
 
 func routeHints(logger log.Logger, hHostRW io.ReadWriter, hinter preimage.HintHandler) chan error {
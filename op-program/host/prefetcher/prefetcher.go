@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/ethereum-optimism/optimism/op-node/eth"
@@ -12,6 +13,7 @@ import (
 	"github.com/ethereum-optimism/optimism/op-program/client/mpt"
 	"github.com/ethereum-optimism/optimism/op-program/host/kvstore"
 	"github.com/ethereum-optimism/optimism/op-program/preimage"
+	"github.com/ethereum-optimism/optimism/op-service/da"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -31,21 +33,48 @@ type L2Source interface {
 	CodeByHash(ctx context.Context, hash common.Hash) ([]byte, error)
 }
 
+// DASource retrieves batch data stored off L1 on the DA backend with the given prefix.
+type DASource interface {
+	Retrieve(ctx context.Context, prefix byte, ref []byte) ([]byte, error)
+}
+
+// RegistryDASource retrieves DA blobs through the backends of a registry.
+type RegistryDASource struct {
+	daSources *da.Registry
+}
+
+func NewRegistryDASource(daSources *da.Registry) *RegistryDASource {
+	return &RegistryDASource{daSources: daSources}
+}
+
+func (s *RegistryDASource) Retrieve(ctx context.Context, prefix byte, ref []byte) ([]byte, error) {
+	backend, ok := s.daSources.Get(prefix)
+	if !ok {
+		return nil, fmt.Errorf("%w: %d", da.ErrUnknownPrefix, prefix)
+	}
+	return backend.Retrieve(ctx, ref)
+}
+
 type Prefetcher struct {
 	logger    log.Logger
 	l1Fetcher L1Source
 	l2Fetcher L2Source
+	daFetcher DASource
 	lastHint  string
 	kvStore   kvstore.KV
 }
 
-func NewPrefetcher(logger log.Logger, l1Fetcher L1Source, l2Fetcher L2Source, kvStore kvstore.KV) *Prefetcher {
-	return &Prefetcher{
+func NewPrefetcher(logger log.Logger, l1Fetcher L1Source, l2Fetcher L2Source, daFetcher DASource, kvStore kvstore.KV) *Prefetcher {
+	p := &Prefetcher{
 		logger:    logger,
 		l1Fetcher: NewRetryingL1Source(logger, l1Fetcher),
 		l2Fetcher: NewRetryingL2Source(logger, l2Fetcher),
 		kvStore:   kvStore,
 	}
+	if daFetcher != nil {
+		p.daFetcher = NewRetryingDASource(logger, daFetcher)
+	}
+	return p
 }
 
 func (p *Prefetcher) Hint(hint string) error {
@@ -70,6 +99,9 @@ func (p *Prefetcher) GetPreimage(ctx context.Context, key common.Hash) ([]byte,
 }
 
 func (p *Prefetcher) prefetch(ctx context.Context, hint string) error {
+	if hintType, args, _ := strings.Cut(hint, " "); hintType == l1.HintDABlob {
+		return p.prefetchDABlob(ctx, args)
+	}
 	hintType, hash, err := parseHint(hint)
 	if err != nil {
 		return err
@@ -128,6 +160,28 @@ func (p *Prefetcher) prefetch(ctx context.Context, hint string) error {
 	return fmt.Errorf("unknown hint type: %v", hintType)
 }
 
+// prefetchDABlob retrieves the DA blob of a da-blob hint, and stores it under the commitment to the blob.
+// The client requests the blob by the commitment posted to L1, so data that does not match it is never served.
+func (p *Prefetcher) prefetchDABlob(ctx context.Context, args string) error {
+	prefix, ref, err := parseDABlobHint(args)
+	if err != nil {
+		return err
+	}
+	if p.daFetcher == nil {
+		return fmt.Errorf("no DA source to fetch DA blob %x (prefix %d) from", ref, prefix)
+	}
+	p.logger.Debug("Prefetching", "type", l1.HintDABlob, "prefix", prefix, "ref", hexutil.Bytes(ref))
+	data, err := p.daFetcher.Retrieve(ctx, prefix, ref)
+	if err != nil {
+		return fmt.Errorf("failed to fetch DA blob %x (prefix %d): %w", ref, prefix, err)
+	}
+	key := preimage.DABlobKey(crypto.Keccak256Hash(data)).PreimageKey()
+	if err := p.kvStore.Put(key, data); err != nil && !errors.Is(err, kvstore.ErrAlreadyExists) {
+		return err
+	}
+	return nil
+}
+
 func (p *Prefetcher) storeReceipts(receipts types.Receipts) error {
 	opaqueReceipts, err := eth.EncodeReceipts(receipts)
 	if err != nil {
@@ -158,6 +212,23 @@ func (p *Prefetcher) storeTrieNodes(values []hexutil.Bytes) error {
 	return nil
 }
 
+// parseDABlobHint parses the arguments of a da-blob hint: the decimal backend prefix and the hex encoded reference.
+func parseDABlobHint(args string) (byte, []byte, error) {
+	prefixStr, refStr, found := strings.Cut(args, " ")
+	if !found {
+		return 0, nil, fmt.Errorf("unsupported hint: %s %s", l1.HintDABlob, args)
+	}
+	prefix, err := strconv.ParseUint(prefixStr, 10, 8)
+	if err != nil {
+		return 0, nil, fmt.Errorf("invalid DA prefix %q: %w", prefixStr, err)
+	}
+	ref, err := hexutil.Decode(refStr)
+	if err != nil {
+		return 0, nil, fmt.Errorf("invalid DA reference %q: %w", refStr, err)
+	}
+	return byte(prefix), ref, nil
+}
+
 // parseHint parses a hint string in wire protocol. Returns the hint type, requested hash and error (if any).
 func parseHint(hint string) (string, common.Hash, error) {
 	hintType, hashStr, found := strings.Cut(hint, " ")
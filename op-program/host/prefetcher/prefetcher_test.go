@@ -2,6 +2,7 @@ package prefetcher
 
 import (
 	"context"
+	"errors"
 	"math/rand"
 	"testing"
 
@@ -20,6 +21,7 @@ import (
 	"github.com/ethereum-optimism/optimism/op-program/client/mpt"
 	"github.com/ethereum-optimism/optimism/op-program/host/kvstore"
 	"github.com/ethereum-optimism/optimism/op-program/preimage"
+	"github.com/ethereum-optimism/optimism/op-service/da"
 )
 
 func TestNoHint(t *testing.T) {
@@ -281,6 +283,55 @@ func TestBadHints(t *testing.T) {
 	})
 }
 
+func TestFetchDABlob(t *testing.T) {
+	rng := rand.New(rand.NewSource(123))
+	data := testutils.RandomData(rng, 100)
+	commitment := crypto.Keccak256Hash(data)
+	ref := testutils.RandomData(rng, 32)
+	blobs := map[string][]byte{string(append([]byte{da.CelestiaPrefix}, ref...)): data}
+
+	t.Run("AlreadyKnown", func(t *testing.T) {
+		prefetcher, kv := createDAPrefetcher(t, nil)
+		require.NoError(t, kv.Put(preimage.DABlobKey(commitment).PreimageKey(), data))
+
+		oracle := l1.NewPreimageOracle(asOracleFn(t, prefetcher), asHinter(t, prefetcher))
+		require.Equal(t, data, oracle.DABlob(da.CelestiaPrefix, ref, commitment))
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		prefetcher, _ := createDAPrefetcher(t, blobs)
+
+		oracle := l1.NewPreimageOracle(asOracleFn(t, prefetcher), asHinter(t, prefetcher))
+		require.Equal(t, data, oracle.DABlob(da.CelestiaPrefix, ref, commitment))
+	})
+
+	t.Run("CommitmentMismatch", func(t *testing.T) {
+		prefetcher, _ := createDAPrefetcher(t, map[string][]byte{
+			string(append([]byte{da.CelestiaPrefix}, ref...)): testutils.RandomData(rng, 100),
+		})
+		key := preimage.DABlobKey(commitment).PreimageKey()
+		require.NoError(t, prefetcher.Hint(l1.DABlobHint{Prefix: da.CelestiaPrefix, Ref: ref}.Hint()))
+		_, err := prefetcher.GetPreimage(context.Background(), key)
+		require.ErrorIs(t, err, kvstore.ErrNotFound, "data that does not match the commitment must not be served")
+	})
+
+	t.Run("NoDASource", func(t *testing.T) {
+		prefetcher, _, _, _ := createPrefetcher(t)
+		key := preimage.DABlobKey(commitment).PreimageKey()
+		require.NoError(t, prefetcher.Hint(l1.DABlobHint{Prefix: da.CelestiaPrefix, Ref: ref}.Hint()))
+		_, err := prefetcher.GetPreimage(context.Background(), key)
+		require.ErrorContains(t, err, "no DA source")
+	})
+
+	t.Run("InvalidHint", func(t *testing.T) {
+		prefetcher, _ := createDAPrefetcher(t, blobs)
+		key := preimage.DABlobKey(commitment).PreimageKey()
+		require.NoError(t, prefetcher.Hint(l1.HintDABlob+" 300 0x1234"))
+		_, err := prefetcher.GetPreimage(context.Background(), key)
+		require.ErrorContains(t, err, "invalid DA prefix")
+	})
+}
+
 type l2Client struct {
 	*testutils.MockL2Client
 	*testutils.MockDebugClient
@@ -296,10 +347,29 @@ func createPrefetcher(t *testing.T) (*Prefetcher, *testutils.MockL1Source, *l2Cl
 		MockDebugClient: new(testutils.MockDebugClient),
 	}
 
-	prefetcher := NewPrefetcher(logger, l1Source, l2Source, kv)
+	prefetcher := NewPrefetcher(logger, l1Source, l2Source, nil, kv)
 	return prefetcher, l1Source, l2Source, kv
 }
 
+type stubDASource struct {
+	blobs map[string][]byte
+}
+
+func (s *stubDASource) Retrieve(_ context.Context, prefix byte, ref []byte) ([]byte, error) {
+	data, ok := s.blobs[string(append([]byte{prefix}, ref...))]
+	if !ok {
+		return nil, errors.New("blob not found")
+	}
+	return data, nil
+}
+
+func createDAPrefetcher(t *testing.T, blobs map[string][]byte) (*Prefetcher, kvstore.KV) {
+	logger := testlog.Logger(t, log.LvlDebug)
+	kv := kvstore.NewMemKV()
+	prefetcher := NewPrefetcher(logger, new(testutils.MockL1Source), new(l2Client), &stubDASource{blobs: blobs}, kv)
+	return prefetcher, kv
+}
+
 func storeBlock(t *testing.T, kv kvstore.KV, block *types.Block, receipts types.Receipts) {
 	// Pre-store receipts
 	opaqueRcpts, err := eth.EncodeReceipts(receipts)
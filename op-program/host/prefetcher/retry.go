@@ -134,3 +134,33 @@ func NewRetryingL2Source(logger log.Logger, source L2Source) *RetryingL2Source {
 }
 
 var _ L2Source = (*RetryingL2Source)(nil)
+
+type RetryingDASource struct {
+	logger   log.Logger
+	source   DASource
+	strategy backoff.Strategy
+}
+
+func NewRetryingDASource(logger log.Logger, source DASource) *RetryingDASource {
+	return &RetryingDASource{
+		logger:   logger,
+		source:   source,
+		strategy: backoff.Exponential(),
+	}
+}
+
+func (s *RetryingDASource) Retrieve(ctx context.Context, prefix byte, ref []byte) ([]byte, error) {
+	var data []byte
+	err := backoff.DoCtx(ctx, maxAttempts, s.strategy, func() error {
+		d, err := s.source.Retrieve(ctx, prefix, ref)
+		if err != nil {
+			s.logger.Warn("Failed to retrieve DA blob", "prefix", prefix, "ref", ref, "err", err)
+			return err
+		}
+		data = d
+		return nil
+	})
+	return data, err
+}
+
+var _ DASource = (*RetryingDASource)(nil)
@@ -35,6 +35,10 @@ const (
 	LocalKeyType KeyType = 1
 	// Keccak256KeyType is for keccak256 pre-images, for any global shared pre-images.
 	Keccak256KeyType KeyType = 2
+	// DABlobKeyType is for batch data stored off L1 on a data availability backend,
+	// addressed by the keccak256 commitment to the data that is posted to L1.
+	// It is taken from the application usage range, as the DA backends are specific to this rollup.
+	DABlobKeyType KeyType = 129
 )
 
 // LocalIndexKey is a key local to the program, indexing a special program input.
@@ -63,6 +67,23 @@ func (k Keccak256Key) TerminalString() string {
 	return common.Hash(k).String()
 }
 
+// DABlobKey wraps the keccak256 commitment to a DA blob to use it as a typed pre-image key.
+type DABlobKey common.Hash
+
+func (k DABlobKey) PreimageKey() (out common.Hash) {
+	out = common.Hash(k)         // copy the commitment
+	out[0] = byte(DABlobKeyType) // apply prefix
+	return
+}
+
+func (k DABlobKey) String() string {
+	return common.Hash(k).String()
+}
+
+func (k DABlobKey) TerminalString() string {
+	return common.Hash(k).String()
+}
+
 // Hint is an interface to enable any program type to function as a hint,
 // when passed to the Hinter interface, returning a string representation
 // of what data the host should prepare pre-images for.
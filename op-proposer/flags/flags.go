@@ -46,6 +46,12 @@ var (
 		Usage:  "Allow the proposer to submit proposals for L2 blocks derived from non-finalized L1 blocks.",
 		EnvVar: opservice.PrefixEnvVar(EnvVarPrefix, "ALLOW_NON_FINALIZED"),
 	}
+	DAFinalityDepthFlag = cli.Uint64Flag{
+		Name: "da-finality-depth",
+		Usage: "Number of DA-layer confirmations that the batch data of the proposed L2 blocks must have before the output is proposed. " +
+			"Requires the optimism_daConfirmations RPC of the rollup node. Disabled if 0.",
+		EnvVar: opservice.PrefixEnvVar(EnvVarPrefix, "DA_FINALITY_DEPTH"),
+	}
 	// Legacy Flags
 	L2OutputHDPathFlag = txmgr.L2OutputHDPathFlag
 )
@@ -59,6 +65,7 @@ var requiredFlags = []cli.Flag{
 var optionalFlags = []cli.Flag{
 	PollIntervalFlag,
 	AllowNonFinalizedFlag,
+	DAFinalityDepthFlag,
 	L2OutputHDPathFlag,
 }
 
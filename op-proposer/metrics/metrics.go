@@ -27,6 +27,8 @@ type Metricer interface {
 	txmetrics.TxMetricer
 
 	RecordL2BlocksProposed(l2ref eth.L2BlockRef)
+
+	RecordDAFinalityLag(lag uint64)
 }
 
 type Metrics struct {
@@ -39,6 +41,8 @@ type Metrics struct {
 
 	info prometheus.GaugeVec
 	up   prometheus.Gauge
+
+	daFinalityLag prometheus.Gauge
 }
 
 var _ Metricer = (*Metrics)(nil)
@@ -72,6 +76,11 @@ func NewMetrics(procName string) *Metrics {
 			Name:      "up",
 			Help:      "1 if the op-proposer has finished starting up",
 		}),
+		daFinalityLag: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace: ns,
+			Name:      "da_finality_lag",
+			Help:      "Number of DA-layer confirmations that the batch data of the next proposal is missing to reach the DA finality depth",
+		}),
 	}
 }
 
@@ -105,6 +114,12 @@ func (m *Metrics) RecordL2BlocksProposed(l2ref eth.L2BlockRef) {
 	m.RecordL2Ref(BlockProposed, l2ref)
 }
 
+// RecordDAFinalityLag records the number of DA-layer confirmations that the batch data
+// of the next proposal is missing to reach the DA finality depth.
+func (m *Metrics) RecordDAFinalityLag(lag uint64) {
+	m.daFinalityLag.Set(float64(lag))
+}
+
 func (m *Metrics) Document() []opmetrics.DocumentedMetric {
 	return m.factory.Document()
 }
@@ -27,6 +27,7 @@ type Config struct {
 	L1Client           *ethclient.Client
 	RollupClient       *sources.RollupClient
 	AllowNonFinalized  bool
+	DAFinalityDepth    uint64
 }
 
 // CLIConfig is a well typed config that is parsed from the CLI params.
@@ -52,6 +53,10 @@ type CLIConfig struct {
 	// for L2 blocks derived from non-finalized L1 data.
 	AllowNonFinalized bool
 
+	// DAFinalityDepth is the number of DA-layer confirmations that the batch data of the proposed
+	// L2 blocks must have before the output is proposed. The check is disabled if it is 0.
+	DAFinalityDepth uint64
+
 	TxMgrConfig txmgr.CLIConfig
 
 	RPCConfig oprpc.CLIConfig
@@ -93,6 +98,7 @@ func NewConfig(ctx *cli.Context) CLIConfig {
 		TxMgrConfig:  txmgr.ReadCLIConfig(ctx),
 		// Optional Flags
 		AllowNonFinalized: ctx.GlobalBool(flags.AllowNonFinalizedFlag.Name),
+		DAFinalityDepth:   ctx.GlobalUint64(flags.DAFinalityDepthFlag.Name),
 		RPCConfig:         oprpc.ReadCLIConfig(ctx),
 		LogConfig:         oplog.ReadCLIConfig(ctx),
 		MetricsConfig:     opmetrics.ReadCLIConfig(ctx),
@@ -116,6 +116,15 @@ func Main(version string, cliCtx *cli.Context) error {
 	return nil
 }
 
+// RollupClient is the rollup node RPC that outputs are retrieved from.
+type RollupClient interface {
+	SyncStatus(ctx context.Context) (*eth.SyncStatus, error)
+	OutputAtBlock(ctx context.Context, blockNum uint64) (*eth.OutputResponse, error)
+	DAConfirmations(ctx context.Context, start, end uint64) (*eth.DAConfirmations, error)
+}
+
+var _ RollupClient = (*sources.RollupClient)(nil)
+
 // L2OutputSubmitter is responsible for proposing outputs
 type L2OutputSubmitter struct {
 	txMgr txmgr.TxManager
@@ -128,7 +137,7 @@ type L2OutputSubmitter struct {
 	cancel context.CancelFunc
 
 	// RollupClient is used to retrieve output roots from
-	rollupClient *sources.RollupClient
+	rollupClient RollupClient
 
 	l2ooContract     *bindings.L2OutputOracleCaller
 	l2ooContractAddr common.Address
@@ -139,6 +148,9 @@ type L2OutputSubmitter struct {
 	// is never valid on an alternative L1 chain that would produce different L2 data.
 	// This option is not necessary when higher proposal latency is acceptable and L1 is healthy.
 	allowNonFinalized bool
+	// daFinalityDepth is the number of DA-layer confirmations that the batch data of the proposed L2 blocks
+	// must have before the output is proposed. The check is disabled if it is 0.
+	daFinalityDepth uint64
 	// How frequently to poll L2 for new finalized outputs
 	pollInterval   time.Duration
 	networkTimeout time.Duration
@@ -184,6 +196,7 @@ func NewL2OutputSubmitterConfigFromCLIConfig(cfg CLIConfig, l log.Logger, m metr
 		L1Client:           l1Client,
 		RollupClient:       rollupClient,
 		AllowNonFinalized:  cfg.AllowNonFinalized,
+		DAFinalityDepth:    cfg.DAFinalityDepth,
 		TxManager:          txManager,
 	}, nil
 
@@ -229,6 +242,7 @@ func NewL2OutputSubmitter(cfg Config, l log.Logger, m metrics.Metricer) (*L2Outp
 		l2ooABI:          parsed,
 
 		allowNonFinalized: cfg.AllowNonFinalized,
+		daFinalityDepth:   cfg.DAFinalityDepth,
 		pollInterval:      cfg.PollInterval,
 		networkTimeout:    cfg.NetworkTimeout,
 	}, nil
@@ -281,7 +295,51 @@ func (l *L2OutputSubmitter) FetchNextOutputInfo(ctx context.Context) (*eth.Outpu
 		return nil, false, nil
 	}
 	l.log.Info("proposer submission", "currentBlockNumber", currentBlockNumber, "nextBlockNumber", nextCheckpointBlock)
-	return l.fetchOuput(ctx, nextCheckpointBlock)
+	output, shouldPropose, err := l.fetchOuput(ctx, nextCheckpointBlock)
+	if err != nil || !shouldPropose || l.daFinalityDepth == 0 {
+		return output, shouldPropose, err
+	}
+
+	// The output covers the L2 blocks after the latest proposed block.
+	cCtx, cancel = context.WithTimeout(ctx, l.networkTimeout)
+	defer cancel()
+	callOpts.Context = cCtx
+	latestBlock, err := l.l2ooContract.LatestBlockNumber(callOpts)
+	if err != nil {
+		l.log.Error("proposer unable to get latest block number", "err", err)
+		return nil, false, err
+	}
+	daFinal, err := l.checkDAFinality(ctx, latestBlock.Uint64()+1, output.BlockRef.Number)
+	if err != nil || !daFinal {
+		return nil, false, err
+	}
+	return output, true, nil
+}
+
+// checkDAFinality returns whether the batch data of the L2 blocks start to end, inclusive, has at least
+// daFinalityDepth DA-layer confirmations, and records the number of confirmations it is missing.
+func (l *L2OutputSubmitter) checkDAFinality(ctx context.Context, start, end uint64) (bool, error) {
+	cCtx, cancel := context.WithTimeout(ctx, l.networkTimeout)
+	defer cancel()
+	confs, err := l.rollupClient.DAConfirmations(cCtx, start, end)
+	if err != nil {
+		l.log.Error("proposer unable to get DA confirmations", "start", start, "end", end, "err", err)
+		return false, err
+	}
+	var lag uint64
+	if confs.MinConfirmations < l.daFinalityDepth {
+		lag = l.daFinalityDepth - confs.MinConfirmations
+	}
+	l.metr.RecordDAFinalityLag(lag)
+	if lag > 0 {
+		l.log.Info("not proposing yet, batch data is not DA-final",
+			"start", start, "end", end,
+			"l1_start", confs.L1Start, "l1_end", confs.L1End,
+			"da_refs", confs.Refs, "min_confirmations", confs.MinConfirmations,
+			"da_finality_depth", l.daFinalityDepth)
+		return false, nil
+	}
+	return true, nil
 }
 
 func (l *L2OutputSubmitter) fetchOuput(ctx context.Context, block *big.Int) (*eth.OutputResponse, bool, error) {
@@ -0,0 +1,82 @@
+package proposer
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/eth"
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+	"github.com/ethereum-optimism/optimism/op-proposer/metrics"
+	"github.com/ethereum-optimism/optimism/op-service/da"
+)
+
+type mockRollupClient struct {
+	mock.Mock
+}
+
+func (m *mockRollupClient) SyncStatus(ctx context.Context) (*eth.SyncStatus, error) {
+	out := m.Mock.MethodCalled("SyncStatus")
+	return out.Get(0).(*eth.SyncStatus), out.Error(1)
+}
+
+func (m *mockRollupClient) OutputAtBlock(ctx context.Context, blockNum uint64) (*eth.OutputResponse, error) {
+	out := m.Mock.MethodCalled("OutputAtBlock", blockNum)
+	return out.Get(0).(*eth.OutputResponse), out.Error(1)
+}
+
+func (m *mockRollupClient) DAConfirmations(ctx context.Context, start, end uint64) (*eth.DAConfirmations, error) {
+	out := m.Mock.MethodCalled("DAConfirmations", start, end)
+	return out.Get(0).(*eth.DAConfirmations), out.Error(1)
+}
+
+type daLagMetrics struct {
+	metrics.Metricer
+	lag uint64
+}
+
+func (m *daLagMetrics) RecordDAFinalityLag(lag uint64) {
+	m.lag = lag
+}
+
+func TestCheckDAFinality(t *testing.T) {
+	rollupClient := &mockRollupClient{}
+	m := &daLagMetrics{Metricer: metrics.NoopMetrics}
+	l := &L2OutputSubmitter{
+		log:             testlog.Logger(t, log.LvlError),
+		metr:            m,
+		rollupClient:    rollupClient,
+		daFinalityDepth: 30,
+		networkTimeout:  time.Second,
+	}
+
+	rollupClient.On("DAConfirmations", uint64(11), uint64(20)).Return(&eth.DAConfirmations{Refs: 2, MinConfirmations: 12}, nil).Once()
+	final, err := l.checkDAFinality(context.Background(), 11, 20)
+	require.NoError(t, err)
+	require.False(t, final, "batch data is not confirmed deep enough")
+	require.Equal(t, uint64(18), m.lag)
+
+	rollupClient.On("DAConfirmations", uint64(11), uint64(20)).Return(&eth.DAConfirmations{Refs: 2, MinConfirmations: 30}, nil).Once()
+	final, err = l.checkDAFinality(context.Background(), 11, 20)
+	require.NoError(t, err)
+	require.True(t, final)
+	require.Equal(t, uint64(0), m.lag)
+
+	// batch data that is only posted to L1 is final with L1
+	rollupClient.On("DAConfirmations", uint64(21), uint64(30)).Return(&eth.DAConfirmations{MinConfirmations: da.Finalized}, nil).Once()
+	final, err = l.checkDAFinality(context.Background(), 21, 30)
+	require.NoError(t, err)
+	require.True(t, final)
+	require.Equal(t, uint64(0), m.lag)
+
+	rollupClient.On("DAConfirmations", uint64(21), uint64(30)).Return((*eth.DAConfirmations)(nil), errors.New("not safe yet")).Once()
+	_, err = l.checkDAFinality(context.Background(), 21, 30)
+	require.Error(t, err)
+
+	rollupClient.AssertExpectations(t)
+}
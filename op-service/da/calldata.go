@@ -0,0 +1,29 @@
+package da
+
+import "context"
+
+// Calldata is the backend that keeps batch data inline in the L1 inbox transaction.
+// The reference posted to L1 is the data itself.
+type Calldata struct{}
+
+var _ DataAvailability = Calldata{}
+
+func NewCalldata() Calldata {
+	return Calldata{}
+}
+
+func (Calldata) Prefix() byte {
+	return CalldataPrefix
+}
+
+func (Calldata) Store(_ context.Context, data []byte) ([]byte, error) {
+	return data, nil
+}
+
+func (Calldata) Retrieve(_ context.Context, ref []byte) ([]byte, error) {
+	return ref, nil
+}
+
+func (Calldata) Confirmations(_ context.Context, _ []byte) (uint64, error) {
+	return Finalized, nil
+}
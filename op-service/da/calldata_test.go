@@ -0,0 +1,28 @@
+package da
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalldata(t *testing.T) {
+	ctx := context.Background()
+	c := NewCalldata()
+	require.Equal(t, CalldataPrefix, c.Prefix())
+	require.True(t, IsInline(c))
+
+	data := []byte{0x00, 0xde, 0xad, 0xbe, 0xef}
+	ref, err := c.Store(ctx, data)
+	require.NoError(t, err)
+	require.Equal(t, data, ref)
+
+	out, err := c.Retrieve(ctx, ref)
+	require.NoError(t, err)
+	require.Equal(t, data, out)
+
+	confs, err := c.Confirmations(ctx, ref)
+	require.NoError(t, err)
+	require.Equal(t, Finalized, confs)
+}
@@ -0,0 +1,200 @@
+package da
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/urfave/cli"
+
+	opservice "github.com/ethereum-optimism/optimism/op-service"
+	optls "github.com/ethereum-optimism/optimism/op-service/tls"
+	"github.com/ethereum-optimism/optimism/op-service/tls/certman"
+)
+
+const (
+	RPCFlagName             = "l1-da-rpc"
+	ServerFlagName          = "da.server"
+	AuthTokenFlagName       = "da.auth-token"
+	StoreTimeoutFlagName    = "da.store-timeout"
+	RetrieveTimeoutFlagName = "da.retrieve-timeout"
+	TLSCaCertFlagName       = "da." + optls.TLSCaCertFlagName
+	TLSCertFlagName         = "da." + optls.TLSCertFlagName
+	TLSKeyFlagName          = "da." + optls.TLSKeyFlagName
+)
+
+func CLIFlags(envPrefix string) []cli.Flag {
+	return []cli.Flag{
+		cli.StringFlag{
+			Name:   RPCFlagName,
+			Usage:  "JSON-RPC endpoint of the DA chain (eth namespace required). Required for the " + PolygonType + " DA type.",
+			EnvVar: opservice.PrefixEnvVar(envPrefix, "L1_DA_RPC"),
+		},
+		cli.StringFlag{
+			Name: ServerFlagName,
+			Usage: "URL of the DA blob server. Required for the " +
+				strings.Join([]string{CelestiaType, EigenType, NearDAType}, ", ") + " DA types.",
+			EnvVar: opservice.PrefixEnvVar(envPrefix, "DA_SERVER"),
+		},
+		cli.StringFlag{
+			Name:   AuthTokenFlagName,
+			Usage:  "Bearer token to authenticate with at the DA blob server. Disabled if empty.",
+			EnvVar: opservice.PrefixEnvVar(envPrefix, "DA_AUTH_TOKEN"),
+		},
+		cli.DurationFlag{
+			Name:   StoreTimeoutFlagName,
+			Usage:  "Timeout for storing a blob on the DA blob server",
+			Value:  DefaultStoreTimeout,
+			EnvVar: opservice.PrefixEnvVar(envPrefix, "DA_STORE_TIMEOUT"),
+		},
+		cli.DurationFlag{
+			Name:   RetrieveTimeoutFlagName,
+			Usage:  "Timeout for retrieving a blob from the DA blob server",
+			Value:  DefaultRetrieveTimeout,
+			EnvVar: opservice.PrefixEnvVar(envPrefix, "DA_RETRIEVE_TIMEOUT"),
+		},
+		cli.StringFlag{
+			Name:   TLSCaCertFlagName,
+			Usage:  "tls ca cert path to verify the DA blob server with. TLS client auth is disabled if empty.",
+			EnvVar: opservice.PrefixEnvVar(envPrefix, "DA_TLS_CA"),
+		},
+		cli.StringFlag{
+			Name:   TLSCertFlagName,
+			Usage:  "tls client cert path for the DA blob server",
+			EnvVar: opservice.PrefixEnvVar(envPrefix, "DA_TLS_CERT"),
+		},
+		cli.StringFlag{
+			Name:   TLSKeyFlagName,
+			Usage:  "tls client key path for the DA blob server",
+			EnvVar: opservice.PrefixEnvVar(envPrefix, "DA_TLS_KEY"),
+		},
+	}
+}
+
+type CLIConfig struct {
+	// RPC is the JSON-RPC endpoint of the DA chain, used by the Polygon backend.
+	RPC string
+	// Server is the URL of the DA blob server, used by the Celestia, EigenDA and NEAR DA backends.
+	Server string
+	// AuthToken is sent as bearer token to the DA blob server, if not empty.
+	AuthToken string
+	// StoreTimeout and RetrieveTimeout bound the requests to the DA blob server.
+	// The defaults are used if they are zero.
+	StoreTimeout    time.Duration
+	RetrieveTimeout time.Duration
+	// TLSConfig enables TLS client authentication with the DA blob server, if set.
+	TLSConfig optls.CLIConfig
+}
+
+func (c CLIConfig) Check() error {
+	if c.Server != "" {
+		u, err := url.Parse(c.Server)
+		if err != nil {
+			return fmt.Errorf("invalid DA server URL: %w", err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return fmt.Errorf("DA server URL must be http or https: %q", c.Server)
+		}
+	}
+	if c.StoreTimeout < 0 || c.RetrieveTimeout < 0 {
+		return errors.New("DA timeouts cannot be negative")
+	}
+	if err := c.TLSConfig.Check(); err != nil {
+		return fmt.Errorf("DA tls config: %w", err)
+	}
+	return nil
+}
+
+// CheckType verifies that the configuration can be used to store or retrieve data on the DA backend of the given type.
+func (c CLIConfig) CheckType(typ string) error {
+	switch typ {
+	case CalldataType:
+		return nil
+	case PolygonType:
+		if c.RPC == "" {
+			return fmt.Errorf("the %s DA type requires the %s flag", typ, RPCFlagName)
+		}
+		return nil
+	case CelestiaType, EigenType, NearDAType:
+		if c.Server == "" {
+			return fmt.Errorf("the %s DA type requires the %s flag", typ, ServerFlagName)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown DA type %q, valid options: %s", typ, strings.Join(TypeKeys, ", "))
+	}
+}
+
+// NewHTTPBlob creates the blob server backend for the given DA type, which must be served over HTTP.
+func (c CLIConfig) NewHTTPBlob(logger log.Logger, typ string) (*HTTPBlob, error) {
+	switch typ {
+	case CelestiaType, EigenType, NearDAType:
+	default:
+		return nil, fmt.Errorf("DA type %q is not served by a blob server", typ)
+	}
+	client, err := c.httpClient(logger)
+	if err != nil {
+		return nil, err
+	}
+	return NewHTTPBlob(Types[typ], HTTPBlobConfig{
+		URL:             c.Server,
+		AuthToken:       c.AuthToken,
+		StoreTimeout:    c.StoreTimeout,
+		RetrieveTimeout: c.RetrieveTimeout,
+		Client:          client,
+	}), nil
+}
+
+func (c CLIConfig) httpClient(logger log.Logger) (*http.Client, error) {
+	if !c.TLSConfig.TLSEnabled() {
+		return &http.Client{}, nil
+	}
+	caCert, err := os.ReadFile(c.TLSConfig.TLSCaCert)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", TLSCaCertFlagName, err)
+	}
+	caCertPool := x509.NewCertPool()
+	caCertPool.AppendCertsFromPEM(caCert)
+
+	// certman watches for newer client certificates and automatically reloads them
+	cm, err := certman.New(logger, c.TLSConfig.TLSCert, c.TLSConfig.TLSKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read DA tls cert or key: %w", err)
+	}
+	if err := cm.Watch(); err != nil {
+		return nil, fmt.Errorf("failed to start DA certman watcher: %w", err)
+	}
+	return &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				MinVersion: tls.VersionTLS13,
+				RootCAs:    caCertPool,
+				GetClientCertificate: func(_ *tls.CertificateRequestInfo) (*tls.Certificate, error) {
+					return cm.GetCertificate(nil)
+				},
+			},
+		},
+	}, nil
+}
+
+func ReadCLIConfig(ctx *cli.Context) CLIConfig {
+	return CLIConfig{
+		RPC:             ctx.GlobalString(RPCFlagName),
+		Server:          ctx.GlobalString(ServerFlagName),
+		AuthToken:       ctx.GlobalString(AuthTokenFlagName),
+		StoreTimeout:    ctx.GlobalDuration(StoreTimeoutFlagName),
+		RetrieveTimeout: ctx.GlobalDuration(RetrieveTimeoutFlagName),
+		TLSConfig: optls.CLIConfig{
+			TLSCaCert: ctx.GlobalString(TLSCaCertFlagName),
+			TLSCert:   ctx.GlobalString(TLSCertFlagName),
+			TLSKey:    ctx.GlobalString(TLSKeyFlagName),
+		},
+	}
+}
@@ -0,0 +1,45 @@
+package da
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	optls "github.com/ethereum-optimism/optimism/op-service/tls"
+)
+
+func TestCLIConfigCheck(t *testing.T) {
+	require.NoError(t, CLIConfig{}.Check())
+	require.NoError(t, CLIConfig{Server: "https://da.example.com", StoreTimeout: time.Second}.Check())
+
+	require.Error(t, CLIConfig{Server: "da.example.com"}.Check(), "scheme is required")
+	require.Error(t, CLIConfig{Server: "ftp://da.example.com"}.Check())
+	require.Error(t, CLIConfig{RetrieveTimeout: -time.Second}.Check())
+	require.Error(t, CLIConfig{TLSConfig: optls.CLIConfig{TLSCert: "tls/tls.crt"}}.Check(), "incomplete tls config")
+}
+
+func TestCLIConfigCheckType(t *testing.T) {
+	empty := CLIConfig{}
+	require.NoError(t, empty.CheckType(CalldataType))
+	require.Error(t, empty.CheckType(PolygonType))
+	require.Error(t, empty.CheckType(CelestiaType))
+	require.Error(t, empty.CheckType(""))
+	require.ErrorContains(t, empty.CheckType("UNKNOWN"), "unknown DA type")
+
+	require.NoError(t, CLIConfig{RPC: "http://localhost:8545"}.CheckType(PolygonType))
+	for _, typ := range []string{CelestiaType, EigenType, NearDAType} {
+		require.NoError(t, CLIConfig{Server: "http://localhost:26659"}.CheckType(typ))
+	}
+}
+
+func TestCLIConfigNewHTTPBlob(t *testing.T) {
+	cfg := CLIConfig{Server: "http://localhost:26659"}
+	b, err := cfg.NewHTTPBlob(nil, EigenType)
+	require.NoError(t, err)
+	require.Equal(t, EigenPrefix, b.Prefix())
+	require.Equal(t, DefaultStoreTimeout, b.cfg.StoreTimeout)
+
+	_, err = cfg.NewHTTPBlob(nil, PolygonType)
+	require.Error(t, err)
+}
@@ -0,0 +1,171 @@
+package da
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Prefix bytes that identify the data availability backend an L1 inbox transaction refers to.
+// The prefix is the first byte of the inbox calldata, the remainder is the backend specific reference.
+const (
+	CalldataPrefix byte = 0x00
+	PolygonPrefix  byte = 0x01
+	CelestiaPrefix byte = 0x02
+	EigenPrefix    byte = 0x03
+	NearDAPrefix   byte = 0x04
+)
+
+// Type names of the supported data availability backends, as used in configuration.
+const (
+	CalldataType = "BTC"
+	PolygonType  = "POLYGON"
+	CelestiaType = "CELESTIA"
+	EigenType    = "EIGEN"
+	NearDAType   = "NEARDA"
+)
+
+// Types maps every supported backend type name to the prefix byte it is referenced by on L1.
+var Types = map[string]byte{
+	CalldataType: CalldataPrefix,
+	PolygonType:  PolygonPrefix,
+	CelestiaType: CelestiaPrefix,
+	EigenType:    EigenPrefix,
+	NearDAType:   NearDAPrefix,
+}
+
+// MaxBlobSizes are the maximum sizes of the data that the backends store in a single blob, with
+// some headroom for encoding overhead. Calldata is bounded by the L1 transaction size instead.
+var MaxBlobSizes = map[byte]uint64{
+	PolygonPrefix:  120_000,   // 128KB transaction size limit of the Polygon PoS chain
+	CelestiaPrefix: 1_900_000, // ~1.97MB blob size limit of a 64x64 Celestia data square
+	EigenPrefix:    2_000_000, // 2MiB blob size limit of EigenDA
+	NearDAPrefix:   4_000_000, // 4MiB transaction size limit of NEAR
+}
+
+var TypeKeys []string
+
+func init() {
+	for k := range Types {
+		TypeKeys = append(TypeKeys, k)
+	}
+	sort.Strings(TypeKeys)
+}
+
+// TypeName returns the type name of the backend with the given prefix,
+// or the prefix in decimal if it is unknown.
+func TypeName(prefix byte) string {
+	for typ, p := range Types {
+		if p == prefix {
+			return typ
+		}
+	}
+	return strconv.Itoa(int(prefix))
+}
+
+// IsKnownPrefix returns whether the prefix identifies one of the supported backends.
+func IsKnownPrefix(prefix byte) bool {
+	for _, p := range Types {
+		if p == prefix {
+			return true
+		}
+	}
+	return false
+}
+
+// TypeOrDefault returns the given type name, or CalldataType if it is empty.
+// Rollup configurations that predate the DA type setting post all batch data as calldata.
+func TypeOrDefault(typ string) string {
+	if typ == "" {
+		return CalldataType
+	}
+	return typ
+}
+
+// Finalized is the number of confirmations reported by backends that have no notion of DA-layer blocks:
+// data that can be retrieved from them at all is considered final.
+const Finalized uint64 = math.MaxUint64
+
+var ErrUnknownPrefix = errors.New("unknown data availability prefix")
+
+// ErrNotConfigured is returned for data that refers to a supported backend that is not configured.
+var ErrNotConfigured = errors.New("data availability backend not configured")
+
+// DataAvailability is a backend that batch data can be stored on, and retrieved from,
+// with only a short reference to the data being posted to the L1 batch inbox.
+type DataAvailability interface {
+	// Prefix is the byte that precedes references to this backend in L1 inbox transactions.
+	Prefix() byte
+	// Store persists the data and returns the reference to post to L1, without the prefix byte.
+	Store(ctx context.Context, data []byte) ([]byte, error)
+	// Retrieve resolves a reference, as read from L1 without the prefix byte, to the stored data.
+	Retrieve(ctx context.Context, ref []byte) ([]byte, error)
+	// Confirmations returns the number of DA-layer blocks built on top of the referenced data,
+	// or Finalized if the backend does not have a notion of DA-layer blocks.
+	Confirmations(ctx context.Context, ref []byte) (uint64, error)
+}
+
+// CommitmentRetriever is implemented by backends that retrieve data by the commitment that is posted to L1
+// next to the reference, rather than by the reference alone. Such backends cannot resolve legacy payloads,
+// which carry no commitment.
+type CommitmentRetriever interface {
+	RetrieveCommitted(ctx context.Context, ref []byte, commitment common.Hash) ([]byte, error)
+}
+
+// ErrNoCommitment is returned when a backend that retrieves data by commitment is asked to resolve a legacy payload.
+var ErrNoCommitment = errors.New("payload carries no commitment to the data")
+
+// Registry holds the data availability backends known to a batcher or node, indexed by prefix byte.
+type Registry struct {
+	backends map[byte]DataAvailability
+}
+
+// NewRegistry creates a registry of the given backends. The prefix of every backend must be unique.
+func NewRegistry(backends ...DataAvailability) (*Registry, error) {
+	r := &Registry{backends: make(map[byte]DataAvailability)}
+	for _, b := range backends {
+		if err := r.Register(b); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Register adds a backend to the registry. It fails if a backend with the same prefix is already registered.
+func (r *Registry) Register(b DataAvailability) error {
+	if _, ok := r.backends[b.Prefix()]; ok {
+		return fmt.Errorf("data availability backend with prefix %d already registered", b.Prefix())
+	}
+	r.backends[b.Prefix()] = b
+	return nil
+}
+
+// Get returns the backend registered for the given prefix, if any.
+func (r *Registry) Get(prefix byte) (DataAvailability, bool) {
+	b, ok := r.backends[prefix]
+	return b, ok
+}
+
+// Split decodes L1 inbox data into the backend it refers to and the payload to resolve through that backend.
+func (r *Registry) Split(data []byte) (DataAvailability, Payload, error) {
+	p, err := DecodePayload(data)
+	if err != nil {
+		return nil, Payload{}, err
+	}
+	b, ok := r.Get(p.Prefix)
+	if !ok {
+		return nil, Payload{}, fmt.Errorf("%w: %d", ErrUnknownPrefix, p.Prefix)
+	}
+	return b, p, nil
+}
+
+// IsInline returns whether the backend keeps the data itself in the L1 inbox transaction,
+// in which case no separate anchoring transaction is needed.
+func IsInline(b DataAvailability) bool {
+	return b.Prefix() == CalldataPrefix
+}
@@ -0,0 +1,75 @@
+package da
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	celestia := NewHTTPBlob(CelestiaPrefix, HTTPBlobConfig{URL: "http://localhost"})
+	r, err := NewRegistry(NewCalldata(), celestia)
+	require.NoError(t, err)
+
+	b, ok := r.Get(CalldataPrefix)
+	require.True(t, ok)
+	require.Equal(t, NewCalldata(), b)
+
+	b, ok = r.Get(CelestiaPrefix)
+	require.True(t, ok)
+	require.Same(t, celestia, b)
+
+	_, ok = r.Get(EigenPrefix)
+	require.False(t, ok)
+
+	err = r.Register(NewHTTPBlob(CelestiaPrefix, HTTPBlobConfig{URL: "http://other"}))
+	require.Error(t, err, "duplicate prefix must be rejected")
+
+	_, err = NewRegistry(NewCalldata(), NewCalldata())
+	require.Error(t, err)
+}
+
+func TestRegistrySplit(t *testing.T) {
+	r, err := NewRegistry(NewCalldata(), NewHTTPBlob(EigenPrefix, HTTPBlobConfig{URL: "http://localhost"}))
+	require.NoError(t, err)
+
+	b, p, err := r.Split([]byte{EigenPrefix, 'k', 'e', 'y'})
+	require.NoError(t, err)
+	require.Equal(t, EigenPrefix, b.Prefix())
+	require.Equal(t, []byte("key"), p.Ref)
+	require.Nil(t, p.Commitment)
+
+	b, p, err = r.Split([]byte{CalldataPrefix})
+	require.NoError(t, err)
+	require.Equal(t, CalldataPrefix, b.Prefix())
+	require.Empty(t, p.Ref)
+
+	b, p, err = r.Split(EncodePayload(EigenPrefix, []byte{0x00, 0x01}, []byte("key")))
+	require.NoError(t, err)
+	require.Equal(t, EigenPrefix, b.Prefix())
+	require.Equal(t, []byte("key"), p.Ref)
+	require.NotNil(t, p.Commitment)
+
+	_, _, err = r.Split(nil)
+	require.Error(t, err)
+
+	_, _, err = r.Split([]byte{NearDAPrefix, 0x01})
+	require.ErrorIs(t, err, ErrUnknownPrefix)
+
+	_, _, err = r.Split([]byte{PayloadVersionCommitment, EigenPrefix})
+	require.ErrorIs(t, err, ErrInvalidPayload)
+}
+
+func TestTypes(t *testing.T) {
+	prefixes := make(map[byte]string)
+	for typ, prefix := range Types {
+		other, ok := prefixes[prefix]
+		require.Falsef(t, ok, "types %s and %s share prefix %d", typ, other, prefix)
+		prefixes[prefix] = typ
+		require.Equal(t, typ, TypeName(prefix))
+		require.True(t, IsKnownPrefix(prefix))
+	}
+	require.Len(t, TypeKeys, len(Types))
+	require.Equal(t, "255", TypeName(0xff))
+	require.False(t, IsKnownPrefix(0xff))
+}
@@ -0,0 +1,122 @@
+package da
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	DefaultStoreTimeout    = 120 * time.Second
+	DefaultRetrieveTimeout = 60 * time.Second
+)
+
+// HTTPBlob stores batch data on a blob server (Celestia, EigenDA or NEAR DA gateway) speaking a simple HTTP protocol:
+//   - POST <url>/store with a JSON body {"data": <base64 data>} returns the blob key as response body.
+//   - GET <url>/get<blob key> returns the raw data.
+//
+// The reference posted to L1 is the blob key.
+type HTTPBlob struct {
+	prefix byte
+	cfg    HTTPBlobConfig
+}
+
+var _ DataAvailability = (*HTTPBlob)(nil)
+
+type HTTPBlobConfig struct {
+	// URL is the base URL of the blob server.
+	URL string
+	// AuthToken is sent as bearer token with every request, if not empty.
+	AuthToken string
+	// StoreTimeout and RetrieveTimeout default to DefaultStoreTimeout and DefaultRetrieveTimeout if zero.
+	StoreTimeout    time.Duration
+	RetrieveTimeout time.Duration
+	// Client defaults to a plain http.Client if nil.
+	Client *http.Client
+}
+
+// NewHTTPBlob creates a blob server backend that is referenced by the given prefix on L1.
+func NewHTTPBlob(prefix byte, cfg HTTPBlobConfig) *HTTPBlob {
+	if cfg.StoreTimeout == 0 {
+		cfg.StoreTimeout = DefaultStoreTimeout
+	}
+	if cfg.RetrieveTimeout == 0 {
+		cfg.RetrieveTimeout = DefaultRetrieveTimeout
+	}
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{}
+	}
+	return &HTTPBlob{
+		prefix: prefix,
+		cfg:    cfg,
+	}
+}
+
+func (b *HTTPBlob) Prefix() byte {
+	return b.prefix
+}
+
+type storeRequest struct {
+	Data string `json:"data"`
+}
+
+func (b *HTTPBlob) Store(ctx context.Context, data []byte) ([]byte, error) {
+	body, err := json.Marshal(storeRequest{Data: base64.StdEncoding.EncodeToString(data)})
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, b.cfg.StoreTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, b.cfg.URL+"/store", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	key, err := b.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to store blob: %w", err)
+	}
+	if len(key) == 0 {
+		return nil, errors.New("blob server returned an empty blob key")
+	}
+	return key, nil
+}
+
+func (b *HTTPBlob) Retrieve(ctx context.Context, ref []byte) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, b.cfg.RetrieveTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, b.cfg.URL+"/get"+string(ref), nil)
+	if err != nil {
+		return nil, err
+	}
+	data, err := b.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve blob %q: %w", ref, err)
+	}
+	return data, nil
+}
+
+func (b *HTTPBlob) Confirmations(_ context.Context, _ []byte) (uint64, error) {
+	return Finalized, nil
+}
+
+func (b *HTTPBlob) do(req *http.Request) ([]byte, error) {
+	if b.cfg.AuthToken != "" {
+		req.Header.Set("Authorization", "Bearer "+b.cfg.AuthToken)
+	}
+	resp, err := b.cfg.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,104 @@
+package da
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestBlobServer(t *testing.T, blobs map[string][]byte) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/store":
+			var req storeRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			data, err := base64.StdEncoding.DecodeString(req.Data)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			key := "blob" + string(rune('a'+len(blobs)))
+			blobs[key] = data
+			_, _ = w.Write([]byte(key))
+		case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, "/get"):
+			data, ok := blobs[strings.TrimPrefix(r.URL.Path, "/get")]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = w.Write(data)
+		default:
+			http.Error(w, "unsupported", http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestHTTPBlobRoundTrip(t *testing.T) {
+	blobs := make(map[string][]byte)
+	srv := newTestBlobServer(t, blobs)
+	b := NewHTTPBlob(CelestiaPrefix, HTTPBlobConfig{URL: srv.URL})
+	require.Equal(t, CelestiaPrefix, b.Prefix())
+	require.False(t, IsInline(b))
+
+	data := []byte{0x00, 0x01, 0xff, 0x10}
+	ref, err := b.Store(context.Background(), data)
+	require.NoError(t, err)
+	require.Equal(t, []byte("bloba"), ref)
+	require.Equal(t, data, blobs["bloba"])
+
+	out, err := b.Retrieve(context.Background(), ref)
+	require.NoError(t, err)
+	require.Equal(t, data, out)
+
+	confs, err := b.Confirmations(context.Background(), ref)
+	require.NoError(t, err)
+	require.Equal(t, Finalized, confs)
+}
+
+func TestHTTPBlobErrors(t *testing.T) {
+	srv := newTestBlobServer(t, make(map[string][]byte))
+	b := NewHTTPBlob(NearDAPrefix, HTTPBlobConfig{URL: srv.URL})
+
+	_, err := b.Retrieve(context.Background(), []byte("missing"))
+	require.ErrorContains(t, err, "404")
+
+	empty := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	t.Cleanup(empty.Close)
+	_, err = NewHTTPBlob(EigenPrefix, HTTPBlobConfig{URL: empty.URL}).Store(context.Background(), []byte{0x01})
+	require.Error(t, err, "empty blob key must be rejected")
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(failing.Close)
+	_, err = NewHTTPBlob(EigenPrefix, HTTPBlobConfig{URL: failing.URL}).Store(context.Background(), []byte{0x01})
+	require.ErrorContains(t, err, "503")
+}
+
+func TestHTTPBlobAuthToken(t *testing.T) {
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte{0x01})
+	}))
+	t.Cleanup(srv.Close)
+
+	_, err := NewHTTPBlob(CelestiaPrefix, HTTPBlobConfig{URL: srv.URL, AuthToken: "secret"}).Retrieve(context.Background(), []byte("key"))
+	require.NoError(t, err)
+	require.Equal(t, "Bearer secret", auth)
+
+	_, err = NewHTTPBlob(CelestiaPrefix, HTTPBlobConfig{URL: srv.URL}).Retrieve(context.Background(), []byte("key"))
+	require.NoError(t, err)
+	require.Empty(t, auth)
+}
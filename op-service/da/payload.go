@@ -0,0 +1,153 @@
+package da
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Inbox payload versions.
+//
+// Legacy payloads start with the backend prefix directly: prefix || ref.
+// Versioned payloads start with a version byte, which is chosen outside the range of backend prefixes
+// so that both can be told apart, followed by the backend prefix:
+//
+//	PayloadVersionCommitment || prefix || keccak256(data) || ref
+//
+// The commitment allows the data returned by a backend to be verified against what the batcher stored.
+//
+// Aggregate payloads pack multiple versioned commitment payloads into a single L1 inbox transaction,
+// each preceded by its length as a big-endian uint16:
+//
+//	PayloadVersionAggregate || len(payload_0) || payload_0 || ... || len(payload_n) || payload_n
+const (
+	PayloadVersionCommitment byte = 0x81
+	PayloadVersionAggregate  byte = 0x82
+)
+
+// MaxAggregatedPayloadSize is the maximum size of a single payload in an aggregate payload.
+const MaxAggregatedPayloadSize = math.MaxUint16
+
+var (
+	ErrInvalidPayload     = errors.New("invalid inbox payload")
+	ErrCommitmentMismatch = errors.New("DA data does not match commitment")
+)
+
+// Payload is a decoded L1 inbox payload, referring to data stored on a data availability backend.
+type Payload struct {
+	// Prefix identifies the backend that the data is stored on.
+	Prefix byte
+	// Commitment is the keccak256 hash of the stored data.
+	// It is nil for legacy payloads, which do not commit to the data.
+	Commitment *common.Hash
+	// Ref is the backend specific reference to the data.
+	Ref []byte
+}
+
+// EncodePayload encodes the L1 inbox payload that refers to data stored on the backend with the given prefix.
+// Calldata is posted inline in the legacy format, data on other backends is committed to.
+func EncodePayload(prefix byte, data, ref []byte) []byte {
+	if prefix == CalldataPrefix {
+		return append([]byte{prefix}, ref...)
+	}
+	out := make([]byte, 0, 2+common.HashLength+len(ref))
+	out = append(out, PayloadVersionCommitment, prefix)
+	out = append(out, crypto.Keccak256(data)...)
+	return append(out, ref...)
+}
+
+// EncodeAggregatePayload packs the given versioned commitment payloads, as returned by EncodePayload,
+// into a single L1 inbox payload.
+func EncodeAggregatePayload(payloads [][]byte) ([]byte, error) {
+	if len(payloads) == 0 {
+		return nil, fmt.Errorf("%w: no payloads to aggregate", ErrInvalidPayload)
+	}
+	size := 1
+	for i, p := range payloads {
+		if len(p) == 0 || p[0] != PayloadVersionCommitment {
+			return nil, fmt.Errorf("%w: payload %d to aggregate has no commitment", ErrInvalidPayload, i)
+		}
+		if len(p) > MaxAggregatedPayloadSize {
+			return nil, fmt.Errorf("%w: payload %d to aggregate too large: %d bytes", ErrInvalidPayload, i, len(p))
+		}
+		size += 2 + len(p)
+	}
+	out := make([]byte, 0, size)
+	out = append(out, PayloadVersionAggregate)
+	for _, p := range payloads {
+		out = binary.BigEndian.AppendUint16(out, uint16(len(p)))
+		out = append(out, p...)
+	}
+	return out, nil
+}
+
+// DecodePayloads decodes an L1 inbox payload in any of the supported formats, including aggregate payloads,
+// into the payloads it contains, in order.
+func DecodePayloads(b []byte) ([]Payload, error) {
+	if len(b) == 0 || b[0] != PayloadVersionAggregate {
+		p, err := DecodePayload(b)
+		if err != nil {
+			return nil, err
+		}
+		return []Payload{p}, nil
+	}
+	var out []Payload
+	for rest := b[1:]; len(rest) > 0; {
+		if len(rest) < 2 {
+			return nil, fmt.Errorf("%w: truncated length of aggregated payload %d", ErrInvalidPayload, len(out))
+		}
+		size := int(binary.BigEndian.Uint16(rest))
+		rest = rest[2:]
+		if len(rest) < size {
+			return nil, fmt.Errorf("%w: truncated aggregated payload %d: %d < %d bytes", ErrInvalidPayload, len(out), len(rest), size)
+		}
+		if size == 0 || rest[0] != PayloadVersionCommitment {
+			return nil, fmt.Errorf("%w: aggregated payload %d has no commitment", ErrInvalidPayload, len(out))
+		}
+		p, err := DecodePayload(rest[:size])
+		if err != nil {
+			return nil, fmt.Errorf("aggregated payload %d: %w", len(out), err)
+		}
+		out = append(out, p)
+		rest = rest[size:]
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("%w: empty aggregate", ErrInvalidPayload)
+	}
+	return out, nil
+}
+
+// DecodePayload decodes an L1 inbox payload in any of the supported formats, except for aggregate payloads,
+// which must be decoded with DecodePayloads.
+func DecodePayload(b []byte) (Payload, error) {
+	if len(b) == 0 {
+		return Payload{}, fmt.Errorf("%w: empty", ErrInvalidPayload)
+	}
+	switch b[0] {
+	case PayloadVersionAggregate:
+		return Payload{}, fmt.Errorf("%w: unexpected aggregate payload", ErrInvalidPayload)
+	case PayloadVersionCommitment:
+		if len(b) < 2+common.HashLength {
+			return Payload{}, fmt.Errorf("%w: commitment payload too short: %d bytes", ErrInvalidPayload, len(b))
+		}
+		commitment := common.BytesToHash(b[2 : 2+common.HashLength])
+		return Payload{Prefix: b[1], Commitment: &commitment, Ref: b[2+common.HashLength:]}, nil
+	default:
+		return Payload{Prefix: b[0], Ref: b[1:]}, nil
+	}
+}
+
+// Verify checks that the data retrieved for the payload matches its commitment, if it has one.
+func (p Payload) Verify(data []byte) error {
+	if p.Commitment == nil {
+		return nil
+	}
+	if got := crypto.Keccak256Hash(data); got != *p.Commitment {
+		return fmt.Errorf("%w: expected %s, got %s", ErrCommitmentMismatch, *p.Commitment, got)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package da
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodePayloadCalldata(t *testing.T) {
+	data := []byte{0x00, 0xaa, 0xbb}
+	payload := EncodePayload(CalldataPrefix, data, data)
+	require.Equal(t, append([]byte{CalldataPrefix}, data...), payload, "calldata stays in the legacy format")
+
+	p, err := DecodePayload(payload)
+	require.NoError(t, err)
+	require.Equal(t, CalldataPrefix, p.Prefix)
+	require.Nil(t, p.Commitment)
+	require.Equal(t, data, p.Ref)
+	require.NoError(t, p.Verify([]byte("anything")), "legacy payloads are not verified")
+}
+
+func TestPayloadCommitmentRoundTrip(t *testing.T) {
+	data := []byte{0x00, 0x01, 0x02, 0x03}
+	for _, prefix := range []byte{PolygonPrefix, CelestiaPrefix, EigenPrefix, NearDAPrefix} {
+		payload := EncodePayload(prefix, data, []byte("blob-key"))
+		require.Equal(t, PayloadVersionCommitment, payload[0])
+
+		p, err := DecodePayload(payload)
+		require.NoError(t, err)
+		require.Equal(t, prefix, p.Prefix)
+		require.Equal(t, []byte("blob-key"), p.Ref)
+		require.Equal(t, crypto.Keccak256Hash(data), *p.Commitment)
+
+		require.NoError(t, p.Verify(data))
+		require.ErrorIs(t, p.Verify([]byte{0x00, 0x01, 0x02, 0x04}), ErrCommitmentMismatch)
+		require.ErrorIs(t, p.Verify(nil), ErrCommitmentMismatch)
+	}
+}
+
+func TestDecodeLegacyPayload(t *testing.T) {
+	for _, prefix := range []byte{CelestiaPrefix, EigenPrefix, NearDAPrefix} {
+		p, err := DecodePayload(append([]byte{prefix}, "blob-key"...))
+		require.NoError(t, err)
+		require.Equal(t, prefix, p.Prefix)
+		require.Nil(t, p.Commitment)
+		require.Equal(t, []byte("blob-key"), p.Ref)
+	}
+}
+
+func TestDecodeInvalidPayload(t *testing.T) {
+	_, err := DecodePayload(nil)
+	require.ErrorIs(t, err, ErrInvalidPayload)
+	_, err = DecodePayload(make([]byte, 33))
+	require.NoError(t, err, "legacy payload of a zero prefix")
+	short := append([]byte{PayloadVersionCommitment, CelestiaPrefix}, make([]byte, 31)...)
+	_, err = DecodePayload(short)
+	require.ErrorIs(t, err, ErrInvalidPayload)
+}
+
+func TestAggregatePayloadRoundTrip(t *testing.T) {
+	var payloads [][]byte
+	var datas [][]byte
+	for i, prefix := range []byte{PolygonPrefix, CelestiaPrefix, EigenPrefix, NearDAPrefix} {
+		data := []byte{byte(i), 0xaa}
+		datas = append(datas, data)
+		payloads = append(payloads, EncodePayload(prefix, data, []byte{byte(i), 'k', 'e', 'y'}))
+	}
+	aggregate, err := EncodeAggregatePayload(payloads)
+	require.NoError(t, err)
+	require.Equal(t, PayloadVersionAggregate, aggregate[0])
+
+	decoded, err := DecodePayloads(aggregate)
+	require.NoError(t, err)
+	require.Len(t, decoded, len(payloads))
+	for i, p := range decoded {
+		expected, err := DecodePayload(payloads[i])
+		require.NoError(t, err)
+		require.Equal(t, expected, p)
+		require.NoError(t, p.Verify(datas[i]))
+	}
+
+	_, err = DecodePayload(aggregate)
+	require.ErrorIs(t, err, ErrInvalidPayload, "aggregates are only decoded as multiple payloads")
+}
+
+func TestDecodePayloadsSingle(t *testing.T) {
+	payload := EncodePayload(CelestiaPrefix, []byte("data"), []byte("blob-key"))
+	decoded, err := DecodePayloads(payload)
+	require.NoError(t, err)
+	expected, err := DecodePayload(payload)
+	require.NoError(t, err)
+	require.Equal(t, []Payload{expected}, decoded)
+}
+
+func TestEncodeAggregatePayloadInvalid(t *testing.T) {
+	_, err := EncodeAggregatePayload(nil)
+	require.ErrorIs(t, err, ErrInvalidPayload)
+	_, err = EncodeAggregatePayload([][]byte{EncodePayload(CalldataPrefix, []byte("data"), []byte("data"))})
+	require.ErrorIs(t, err, ErrInvalidPayload, "calldata is not aggregated")
+	_, err = EncodeAggregatePayload([][]byte{EncodePayload(EigenPrefix, nil, make([]byte, MaxAggregatedPayloadSize))})
+	require.ErrorIs(t, err, ErrInvalidPayload, "payload too large")
+}
+
+func TestDecodeInvalidAggregatePayload(t *testing.T) {
+	payload := EncodePayload(CelestiaPrefix, []byte("data"), []byte("blob-key"))
+	aggregate, err := EncodeAggregatePayload([][]byte{payload, payload})
+	require.NoError(t, err)
+
+	for name, b := range map[string][]byte{
+		"empty":            {PayloadVersionAggregate},
+		"truncated length": aggregate[:len(aggregate)-len(payload)-1],
+		"truncated data":   aggregate[:len(aggregate)-1],
+		"zero length":      {PayloadVersionAggregate, 0, 0},
+		"legacy":           append([]byte{PayloadVersionAggregate, 0, 2}, CelestiaPrefix, 0x01),
+		"nested":           append([]byte{PayloadVersionAggregate, 0, byte(len(aggregate))}, aggregate...),
+	} {
+		_, err := DecodePayloads(b)
+		require.ErrorIs(t, err, ErrInvalidPayload, name)
+	}
+}
@@ -0,0 +1,121 @@
+package da
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
+)
+
+// DAChain is the read access to an EVM chain used for data availability.
+type DAChain interface {
+	TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, error)
+	TransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error)
+	BlockNumber(ctx context.Context) (uint64, error)
+}
+
+// ethClientChain adapts a go-ethereum client to the DAChain interface.
+type ethClientChain struct {
+	*ethclient.Client
+}
+
+func (c ethClientChain) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, error) {
+	tx, _, err := c.Client.TransactionByHash(ctx, hash)
+	return tx, err
+}
+
+// NewEthClientChain wraps a go-ethereum client for use as DAChain.
+func NewEthClientChain(cl *ethclient.Client) DAChain {
+	return ethClientChain{cl}
+}
+
+// Polygon stores batch data as the calldata of a transaction on a separate EVM chain.
+// The reference posted to L1 is the hash of that transaction.
+type Polygon struct {
+	txMgr txmgr.TxManager
+	inbox common.Address
+	chain DAChain
+}
+
+var _ DataAvailability = (*Polygon)(nil)
+
+// NewPolygon creates a Polygon backend. The txMgr, used to send the data transactions to inbox, may be nil
+// if the backend is only used to retrieve data.
+func NewPolygon(txMgr txmgr.TxManager, inbox common.Address, chain DAChain) *Polygon {
+	return &Polygon{
+		txMgr: txMgr,
+		inbox: inbox,
+		chain: chain,
+	}
+}
+
+func (p *Polygon) Prefix() byte {
+	return PolygonPrefix
+}
+
+func (p *Polygon) Store(ctx context.Context, data []byte) ([]byte, error) {
+	if p.txMgr == nil {
+		return nil, errors.New("polygon backend is read-only")
+	}
+	intrinsicGas, err := core.IntrinsicGas(data, nil, false, true, true, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate intrinsic gas: %w", err)
+	}
+	receipt, err := p.txMgr.Send(ctx, txmgr.TxCandidate{
+		To:       &p.inbox,
+		TxData:   data,
+		GasLimit: intrinsicGas,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to send DA transaction: %w", err)
+	}
+	return receipt.TxHash.Bytes(), nil
+}
+
+func (p *Polygon) Retrieve(ctx context.Context, ref []byte) ([]byte, error) {
+	hash, err := txHashFromRef(ref)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := p.chain.TransactionByHash(ctx, hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch DA transaction %s: %w", hash, err)
+	}
+	return tx.Data(), nil
+}
+
+func (p *Polygon) Confirmations(ctx context.Context, ref []byte) (uint64, error) {
+	hash, err := txHashFromRef(ref)
+	if err != nil {
+		return 0, err
+	}
+	receipt, err := p.chain.TransactionReceipt(ctx, hash)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch DA transaction receipt %s: %w", hash, err)
+	}
+	if receipt == nil {
+		return 0, fmt.Errorf("DA transaction receipt %s: %w", hash, ethereum.NotFound)
+	}
+	head, err := p.chain.BlockNumber(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch DA block number: %w", err)
+	}
+	if included := receipt.BlockNumber.Uint64(); head > included {
+		return head - included, nil
+	}
+	return 0, nil
+}
+
+func txHashFromRef(ref []byte) (common.Hash, error) {
+	if len(ref) != common.HashLength {
+		return common.Hash{}, fmt.Errorf("invalid DA transaction hash length %d", len(ref))
+	}
+	return common.BytesToHash(ref), nil
+}
@@ -0,0 +1,111 @@
+package da
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-service/txmgr"
+	"github.com/ethereum-optimism/optimism/op-service/txmgr/mocks"
+)
+
+type testDAChain struct {
+	txs      map[common.Hash]*types.Transaction
+	receipts map[common.Hash]*types.Receipt
+	head     uint64
+	headErr  error
+}
+
+func (c *testDAChain) TransactionByHash(_ context.Context, hash common.Hash) (*types.Transaction, error) {
+	tx, ok := c.txs[hash]
+	if !ok {
+		return nil, ethereum.NotFound
+	}
+	return tx, nil
+}
+
+func (c *testDAChain) TransactionReceipt(_ context.Context, hash common.Hash) (*types.Receipt, error) {
+	return c.receipts[hash], nil
+}
+
+func (c *testDAChain) BlockNumber(_ context.Context) (uint64, error) {
+	return c.head, c.headErr
+}
+
+func TestPolygonStore(t *testing.T) {
+	inbox := common.Address{0xaa}
+	data := []byte{0x00, 0x01, 0x02, 0x03}
+	txHash := common.Hash{0x42}
+
+	txMgr := new(mocks.TxManager)
+	txMgr.On("Send", mock.Anything, mock.MatchedBy(func(c txmgr.TxCandidate) bool {
+		return *c.To == inbox && string(c.TxData) == string(data) && c.GasLimit > 0
+	})).Return(&types.Receipt{TxHash: txHash}, nil).Once()
+
+	p := NewPolygon(txMgr, inbox, &testDAChain{})
+	require.Equal(t, PolygonPrefix, p.Prefix())
+	require.False(t, IsInline(p))
+
+	ref, err := p.Store(context.Background(), data)
+	require.NoError(t, err)
+	require.Equal(t, txHash.Bytes(), ref)
+	txMgr.AssertExpectations(t)
+}
+
+func TestPolygonStoreErrors(t *testing.T) {
+	_, err := NewPolygon(nil, common.Address{}, &testDAChain{}).Store(context.Background(), []byte{0x01})
+	require.Error(t, err, "read-only backend must not store")
+
+	txMgr := new(mocks.TxManager)
+	txMgr.On("Send", mock.Anything, mock.Anything).Return(nil, errors.New("boom")).Once()
+	_, err = NewPolygon(txMgr, common.Address{}, &testDAChain{}).Store(context.Background(), []byte{0x01})
+	require.ErrorContains(t, err, "boom")
+}
+
+func TestPolygonRetrieve(t *testing.T) {
+	tx := types.NewTx(&types.LegacyTx{Data: []byte{0x00, 0xca, 0xfe}})
+	chain := &testDAChain{txs: map[common.Hash]*types.Transaction{tx.Hash(): tx}}
+	p := NewPolygon(nil, common.Address{}, chain)
+
+	data, err := p.Retrieve(context.Background(), tx.Hash().Bytes())
+	require.NoError(t, err)
+	require.Equal(t, tx.Data(), data)
+
+	_, err = p.Retrieve(context.Background(), common.Hash{0x01}.Bytes())
+	require.ErrorIs(t, err, ethereum.NotFound)
+
+	_, err = p.Retrieve(context.Background(), []byte{0x01, 0x02})
+	require.Error(t, err, "reference must be a tx hash")
+}
+
+func TestPolygonConfirmations(t *testing.T) {
+	txHash := common.Hash{0x42}
+	chain := &testDAChain{
+		receipts: map[common.Hash]*types.Receipt{txHash: {TxHash: txHash, BlockNumber: big.NewInt(100)}},
+		head:     130,
+	}
+	p := NewPolygon(nil, common.Address{}, chain)
+
+	confs, err := p.Confirmations(context.Background(), txHash.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, uint64(30), confs)
+
+	chain.head = 90
+	confs, err = p.Confirmations(context.Background(), txHash.Bytes())
+	require.NoError(t, err)
+	require.Zero(t, confs, "head behind inclusion block")
+
+	chain.headErr = errors.New("unavailable")
+	_, err = p.Confirmations(context.Background(), txHash.Bytes())
+	require.Error(t, err)
+
+	_, err = p.Confirmations(context.Background(), common.Hash{0x01}.Bytes())
+	require.ErrorIs(t, err, ethereum.NotFound, "unknown tx")
+}
@@ -0,0 +1,364 @@
+package txmgr
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/leveldb"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+)
+
+var (
+	// journalEntryPrefix + seq (uint64 big-endian) -> journaled entry
+	journalEntryPrefix = []byte("e")
+	// journalDataPrefix + keccak256(tx data) -> seq of the pending entry, or the anchored key once anchored
+	journalDataPrefix = []byte("d")
+	// journalAnchoredPrefix + anchor time (unix seconds, uint64 big-endian) + keccak256(tx data) -> empty,
+	// indexes the anchored tx data by the time it was anchored, to forget it after the retention window.
+	journalAnchoredPrefix = []byte("a")
+)
+
+const (
+	journalCache   = 16 // MB
+	journalHandles = 16
+
+	// JournalAnchoredRetention is how long the tx data of anchored entries is remembered,
+	// to not anchor the same data twice. Stored data is anchored well within this window, or not at all.
+	JournalAnchoredRetention = 24 * time.Hour
+)
+
+// JournalMetricer records the number of entries in the Journal.
+type JournalMetricer interface {
+	RecordAnchorJournalDepth(depth int)
+}
+
+// JournalEntry is an L1 anchor candidate for data stored on a DA backend.
+type JournalEntry struct {
+	// Seq orders the entries by the time they were added.
+	Seq uint64
+	// Candidate is the L1 transaction that references the stored data.
+	Candidate TxCandidate
+	// Confirmed is set once the stored data is confirmed deep enough on the DA backend to be referenced on L1.
+	Confirmed bool
+	// Data is the data stored on the DA backend, kept to store it again if it is reorged out of the DA chain.
+	Data []byte
+}
+
+type journalEntryJSON struct {
+	To        *common.Address `json:"to"`
+	TxData    hexutil.Bytes   `json:"txData"`
+	GasLimit  hexutil.Uint64  `json:"gasLimit"`
+	Confirmed bool            `json:"confirmed"`
+	Data      hexutil.Bytes   `json:"data,omitempty"`
+}
+
+// Journal persists the L1 anchor candidates of data stored on a DA backend until they are sent to L1,
+// so that stored data is not lost when the batcher restarts. The tx data of anchored entries is remembered
+// for JournalAnchoredRetention, so that the same data is not anchored twice.
+type Journal struct {
+	mu    sync.Mutex
+	db    ethdb.KeyValueStore
+	m     JournalMetricer
+	next  uint64
+	depth int
+
+	now       func() time.Time
+	retention time.Duration
+}
+
+// OpenJournal opens the journal stored at the given path, or creates a new one.
+// The journal is kept in memory only if the path is empty.
+func OpenJournal(path string, m JournalMetricer) (*Journal, error) {
+	if path == "" {
+		return NewMemoryJournal(m), nil
+	}
+	db, err := leveldb.New(path, journalCache, journalHandles, "", false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open journal at %s: %w", path, err)
+	}
+	j, err := newJournal(db, m)
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return j, nil
+}
+
+// NewMemoryJournal creates a journal that is not persisted.
+func NewMemoryJournal(m JournalMetricer) *Journal {
+	j, _ := newJournal(memorydb.New(), m) // an empty database cannot fail to load
+	return j
+}
+
+func newJournal(db ethdb.KeyValueStore, m JournalMetricer) (*Journal, error) {
+	j := &Journal{db: db, m: m, now: time.Now, retention: JournalAnchoredRetention}
+	if err := j.pruneAnchored(); err != nil {
+		return nil, err
+	}
+	entries, err := j.entries()
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) > 0 {
+		j.next = entries[len(entries)-1].Seq + 1
+	}
+	j.depth = len(entries)
+	j.recordDepth()
+	return j, nil
+}
+
+// Add journals a new anchor candidate for the given data stored on the DA backend. It returns false, without
+// journaling the candidate, if an entry with the same tx data is already pending or has been anchored before.
+func (j *Journal) Add(candidate TxCandidate, data []byte) (JournalEntry, bool, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	dataKey := journalDataKey(candidate.TxData)
+	if ok, err := j.db.Has(dataKey); err != nil {
+		return JournalEntry{}, false, err
+	} else if ok {
+		return JournalEntry{}, false, nil
+	}
+	entry := JournalEntry{Seq: j.next, Candidate: candidate, Data: data}
+	batch := j.db.NewBatch()
+	if err := putNewJournalEntry(batch, entry); err != nil {
+		return JournalEntry{}, false, err
+	}
+	if err := batch.Write(); err != nil {
+		return JournalEntry{}, false, fmt.Errorf("failed to write journal entry: %w", err)
+	}
+	j.next++
+	j.depth++
+	j.recordDepth()
+	return entry, true, nil
+}
+
+// Replace replaces the entry with a new, unconfirmed, entry for the given anchor candidate of the same data.
+// It is used when the data has been stored again, after it was reorged out of the DA chain.
+// The tx data of the replaced entry is forgotten, as it refers to data that no longer exists.
+func (j *Journal) Replace(entry JournalEntry, candidate TxCandidate) (JournalEntry, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if ok, err := j.db.Has(journalEntryKey(entry.Seq)); err != nil {
+		return JournalEntry{}, err
+	} else if !ok {
+		return JournalEntry{}, fmt.Errorf("journal entry %d not found", entry.Seq)
+	}
+	replacement := JournalEntry{Seq: j.next, Candidate: candidate, Data: entry.Data}
+	batch := j.db.NewBatch()
+	if err := batch.Delete(journalEntryKey(entry.Seq)); err != nil {
+		return JournalEntry{}, err
+	}
+	if err := batch.Delete(journalDataKey(entry.Candidate.TxData)); err != nil {
+		return JournalEntry{}, err
+	}
+	if err := putNewJournalEntry(batch, replacement); err != nil {
+		return JournalEntry{}, err
+	}
+	if err := batch.Write(); err != nil {
+		return JournalEntry{}, fmt.Errorf("failed to replace journal entry: %w", err)
+	}
+	j.next++
+	return replacement, nil
+}
+
+// Confirm marks the entry as confirmed on the DA backend.
+func (j *Journal) Confirm(entry JournalEntry) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if ok, err := j.db.Has(journalEntryKey(entry.Seq)); err != nil {
+		return err
+	} else if !ok {
+		return fmt.Errorf("journal entry %d not found", entry.Seq)
+	}
+	entry.Confirmed = true
+	batch := j.db.NewBatch()
+	if err := putJournalEntry(batch, entry); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// Anchored removes the entry from the journal, once it has been sent to L1.
+// The tx data of entries that were anchored longer than the retention window ago is forgotten.
+func (j *Journal) Anchored(entry JournalEntry) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if ok, err := j.db.Has(journalEntryKey(entry.Seq)); err != nil {
+		return err
+	} else if !ok {
+		return fmt.Errorf("journal entry %d not found", entry.Seq)
+	}
+	batch := j.db.NewBatch()
+	if err := batch.Delete(journalEntryKey(entry.Seq)); err != nil {
+		return err
+	}
+	if err := putAnchored(batch, journalDataKey(entry.Candidate.TxData), j.now()); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return fmt.Errorf("failed to remove journal entry: %w", err)
+	}
+	j.depth--
+	j.recordDepth()
+	return j.pruneAnchored()
+}
+
+// pendingSeq returns the seq of the pending entry with the given tx data, if any.
+func (j *Journal) pendingSeq(txData []byte) (uint64, bool, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if ok, err := j.db.Has(journalDataKey(txData)); err != nil || !ok {
+		return 0, false, err
+	}
+	v, err := j.db.Get(journalDataKey(txData))
+	if err != nil {
+		return 0, false, err
+	}
+	if len(v) != 8 {
+		return 0, false, nil // anchored
+	}
+	return binary.BigEndian.Uint64(v), true, nil
+}
+
+// Entries returns all journaled entries, in the order they were added.
+func (j *Journal) Entries() ([]JournalEntry, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.entries()
+}
+
+// Depth returns the number of journaled entries.
+func (j *Journal) Depth() int {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.depth
+}
+
+func (j *Journal) Close() error {
+	return j.db.Close()
+}
+
+func (j *Journal) entries() ([]JournalEntry, error) {
+	it := j.db.NewIterator(journalEntryPrefix, nil)
+	defer it.Release()
+	var out []JournalEntry
+	for it.Next() {
+		key := it.Key()
+		if len(key) != len(journalEntryPrefix)+8 {
+			return nil, fmt.Errorf("invalid journal key %x", key)
+		}
+		var dec journalEntryJSON
+		if err := json.Unmarshal(it.Value(), &dec); err != nil {
+			return nil, fmt.Errorf("invalid journal entry %x: %w", key, err)
+		}
+		out = append(out, JournalEntry{
+			Seq: binary.BigEndian.Uint64(key[len(journalEntryPrefix):]),
+			Candidate: TxCandidate{
+				To:       dec.To,
+				TxData:   dec.TxData,
+				GasLimit: uint64(dec.GasLimit),
+			},
+			Confirmed: dec.Confirmed,
+			Data:      dec.Data,
+		})
+	}
+	if err := it.Error(); err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+	return out, nil
+}
+
+// pruneAnchored forgets the tx data of entries that were anchored longer than the retention window ago.
+func (j *Journal) pruneAnchored() error {
+	cutoff := journalAnchoredKey(j.now().Add(-j.retention), nil)
+	it := j.db.NewIterator(journalAnchoredPrefix, nil)
+	defer it.Release()
+	batch := j.db.NewBatch()
+	for it.Next() {
+		key := it.Key()
+		if bytes.Compare(key, cutoff) >= 0 {
+			break
+		}
+		if len(key) != len(cutoff)+len(journalDataPrefix)+common.HashLength {
+			return fmt.Errorf("invalid anchored journal key %x", key)
+		}
+		if err := batch.Delete(key[len(cutoff):]); err != nil {
+			return err
+		}
+		if err := batch.Delete(common.CopyBytes(key)); err != nil {
+			return err
+		}
+	}
+	if err := it.Error(); err != nil {
+		return fmt.Errorf("failed to read anchored journal data: %w", err)
+	}
+	if err := batch.Write(); err != nil {
+		return fmt.Errorf("failed to prune anchored journal data: %w", err)
+	}
+	return nil
+}
+
+func (j *Journal) recordDepth() {
+	if j.m != nil {
+		j.m.RecordAnchorJournalDepth(j.depth)
+	}
+}
+
+func putJournalEntry(w ethdb.KeyValueWriter, entry JournalEntry) error {
+	enc, err := json.Marshal(journalEntryJSON{
+		To:        entry.Candidate.To,
+		TxData:    entry.Candidate.TxData,
+		GasLimit:  hexutil.Uint64(entry.Candidate.GasLimit),
+		Confirmed: entry.Confirmed,
+		Data:      entry.Data,
+	})
+	if err != nil {
+		return err
+	}
+	return w.Put(journalEntryKey(entry.Seq), enc)
+}
+
+// putNewJournalEntry writes the entry along with the key that marks its tx data as pending.
+func putNewJournalEntry(w ethdb.KeyValueWriter, entry JournalEntry) error {
+	if err := putJournalEntry(w, entry); err != nil {
+		return err
+	}
+	return w.Put(journalDataKey(entry.Candidate.TxData), journalSeqKey(entry.Seq))
+}
+
+// putAnchored marks the tx data as anchored at the given time. The data key refers to its anchored key,
+// which indexes the data by anchor time.
+func putAnchored(w ethdb.KeyValueWriter, dataKey []byte, t time.Time) error {
+	anchoredKey := journalAnchoredKey(t, dataKey)
+	if err := w.Put(dataKey, anchoredKey); err != nil {
+		return err
+	}
+	return w.Put(anchoredKey, nil)
+}
+
+func journalSeqKey(seq uint64) []byte {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], seq)
+	return b[:]
+}
+
+func journalEntryKey(seq uint64) []byte {
+	return append(append([]byte{}, journalEntryPrefix...), journalSeqKey(seq)...)
+}
+
+func journalDataKey(data []byte) []byte {
+	return append(append([]byte{}, journalDataPrefix...), crypto.Keccak256(data)...)
+}
+
+func journalAnchoredKey(t time.Time, dataKey []byte) []byte {
+	key := append(append([]byte{}, journalAnchoredPrefix...), journalSeqKey(uint64(t.Unix()))...)
+	return append(key, dataKey...)
+}
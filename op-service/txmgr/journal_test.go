@@ -0,0 +1,506 @@
+package txmgr
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum-optimism/optimism/op-node/testlog"
+	"github.com/ethereum-optimism/optimism/op-service/backoff"
+)
+
+type testJournalMetrics struct {
+	depth int
+}
+
+func (m *testJournalMetrics) RecordAnchorJournalDepth(depth int) {
+	m.depth = depth
+}
+
+func TestJournal(t *testing.T) {
+	to := common.Address{0xaa}
+	m := new(testJournalMetrics)
+	j := NewMemoryJournal(m)
+
+	a, added, err := j.Add(TxCandidate{TxData: []byte("a"), To: &to, GasLimit: 21_000}, nil)
+	require.NoError(t, err)
+	require.True(t, added)
+	b, added, err := j.Add(TxCandidate{TxData: []byte("b"), To: &to}, nil)
+	require.NoError(t, err)
+	require.True(t, added)
+	require.Equal(t, 2, j.Depth())
+	require.Equal(t, 2, m.depth)
+
+	_, added, err = j.Add(TxCandidate{TxData: []byte("a"), To: &to}, nil)
+	require.NoError(t, err)
+	require.False(t, added, "pending data must not be journaled twice")
+
+	require.NoError(t, j.Confirm(b))
+	entries, err := j.Entries()
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	require.Equal(t, a, entries[0])
+	require.False(t, entries[0].Confirmed)
+	require.Equal(t, []byte("b"), entries[1].Candidate.TxData)
+	require.True(t, entries[1].Confirmed)
+
+	require.NoError(t, j.Anchored(a))
+	require.Equal(t, 1, j.Depth())
+	require.Equal(t, 1, m.depth)
+	require.Error(t, j.Anchored(a), "entry is gone")
+	require.Error(t, j.Confirm(a), "entry is gone")
+
+	_, added, err = j.Add(TxCandidate{TxData: []byte("a"), To: &to}, nil)
+	require.NoError(t, err)
+	require.False(t, added, "anchored data must not be journaled again")
+}
+
+func TestJournalReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal")
+	to := common.Address{0xbb}
+	j, err := OpenJournal(path, nil)
+	require.NoError(t, err)
+
+	var entries []JournalEntry
+	for _, data := range []string{"a", "b", "c"} {
+		e, added, err := j.Add(TxCandidate{TxData: []byte(data), To: &to, GasLimit: 50_000}, nil)
+		require.NoError(t, err)
+		require.True(t, added)
+		entries = append(entries, e)
+	}
+	require.NoError(t, j.Confirm(entries[1]))
+	require.NoError(t, j.Anchored(entries[0]))
+	require.NoError(t, j.Close())
+
+	m := new(testJournalMetrics)
+	j, err = OpenJournal(path, m)
+	require.NoError(t, err)
+	defer j.Close()
+	require.Equal(t, 2, j.Depth())
+	require.Equal(t, 2, m.depth)
+
+	reloaded, err := j.Entries()
+	require.NoError(t, err)
+	require.Len(t, reloaded, 2)
+	require.Equal(t, entries[1].Seq, reloaded[0].Seq)
+	require.True(t, reloaded[0].Confirmed)
+	require.Equal(t, entries[2], reloaded[1])
+
+	_, added, err := j.Add(TxCandidate{TxData: []byte("a"), To: &to}, nil)
+	require.NoError(t, err)
+	require.False(t, added, "anchored data is remembered across restarts")
+	d, added, err := j.Add(TxCandidate{TxData: []byte("d"), To: &to}, nil)
+	require.NoError(t, err)
+	require.True(t, added)
+	require.Greater(t, d.Seq, entries[2].Seq, "new entries are ordered after reloaded ones")
+}
+
+func TestJournalPruneAnchored(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal")
+	to := common.Address{0xcc}
+	j, err := OpenJournal(path, nil)
+	require.NoError(t, err)
+	now := time.Now()
+	j.now = func() time.Time { return now }
+
+	anchor := func(data string) {
+		e, added, err := j.Add(TxCandidate{TxData: []byte(data), To: &to}, nil)
+		require.NoError(t, err)
+		require.True(t, added)
+		require.NoError(t, j.Anchored(e))
+	}
+	isNew := func(data string) bool {
+		_, added, err := j.Add(TxCandidate{TxData: []byte(data), To: &to}, nil)
+		require.NoError(t, err)
+		return added
+	}
+	anchor("a")
+	now = now.Add(JournalAnchoredRetention / 2)
+	anchor("b")
+	now = now.Add(JournalAnchoredRetention/2 + time.Second)
+	anchor("c")
+	require.True(t, isNew("a"), "anchored data is forgotten after the retention window")
+	require.False(t, isNew("b"))
+	require.False(t, isNew("c"))
+	require.NoError(t, j.Close())
+
+	// the anchor time is kept across restarts
+	j, err = OpenJournal(path, nil)
+	require.NoError(t, err)
+	defer j.Close()
+	require.False(t, isNew("b"))
+	j.now = func() time.Time { return now.Add(JournalAnchoredRetention + time.Second) }
+	require.NoError(t, j.pruneAnchored())
+	require.True(t, isNew("b"))
+	require.True(t, isNew("c"))
+
+	it := j.db.NewIterator(journalAnchoredPrefix, nil)
+	defer it.Release()
+	require.False(t, it.Next(), "index of forgotten data is pruned")
+}
+
+type testDAStore struct {
+	mu     sync.Mutex
+	confs  uint64
+	stores int
+	// failStores is the number of stores to fail before succeeding
+	failStores int
+	// reorged holds the refs that are no longer found on the DA chain
+	reorged map[string]bool
+}
+
+// Store returns a unique reference for every store, like a DA chain tx hash.
+func (s *testDAStore) Store(_ context.Context, data []byte) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.failStores > 0 {
+		s.failStores--
+		return nil, errors.New("store failed")
+	}
+	s.stores++
+	return append([]byte{byte(s.stores)}, data...), nil
+}
+
+func (s *testDAStore) Confirmations(_ context.Context, ref []byte) (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.reorged[string(ref)] {
+		return 0, ethereum.NotFound
+	}
+	return s.confs, nil
+}
+
+func (s *testDAStore) setConfs(confs uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.confs = confs
+}
+
+func (s *testDAStore) reorg(ref []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.reorged == nil {
+		s.reorged = make(map[string]bool)
+	}
+	s.reorged[string(ref)] = true
+}
+
+// testAnchorTxManager records the L1 anchors sent through it, and fails to send them while sendErr is set.
+type testAnchorTxManager struct {
+	mu      sync.Mutex
+	sent    [][]byte
+	sendErr error
+}
+
+func (m *testAnchorTxManager) Send(_ context.Context, candidate TxCandidate) (*types.Receipt, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.sendErr != nil {
+		return nil, m.sendErr
+	}
+	m.sent = append(m.sent, candidate.TxData)
+	return &types.Receipt{}, nil
+}
+
+func (m *testAnchorTxManager) From() common.Address {
+	return common.Address{}
+}
+
+func (m *testAnchorTxManager) setSendErr(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sendErr = err
+}
+
+func (m *testAnchorTxManager) numSent() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.sent)
+}
+
+func newTestDAQueue(t *testing.T, ctx context.Context, txMgr TxManager, j *Journal, policy AnchorPolicy) *Queue[int] {
+	q := NewQueueWithJournal[int](ctx, testlog.Logger(t, log.LvlInfo), txMgr, 1, j, policy)
+	q.backoff = backoff.Fixed(time.Millisecond)
+	t.Cleanup(q.Close)
+	return q
+}
+
+func TestQueueResumeDA(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	j := NewMemoryJournal(nil)
+	_, _, err := j.Add(TxCandidate{TxData: []byte("unconfirmed")}, nil)
+	require.NoError(t, err)
+
+	q := newTestDAQueue(t, ctx, new(testAnchorTxManager), j, AnchorPolicy{MinBatch: 2, MaxWait: time.Hour, ConfirmationDepth: 5})
+	require.NoError(t, q.ResumeDA(&testDAStore{confs: 5}))
+	require.Eventually(t, func() bool {
+		entries, err := j.Entries()
+		require.NoError(t, err)
+		return len(entries) == 1 && entries[0].Confirmed
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestJournalReplace(t *testing.T) {
+	to := common.Address{0xcc}
+	j := NewMemoryJournal(nil)
+	a, _, err := j.Add(TxCandidate{TxData: []byte("ref-a"), To: &to}, []byte("data"))
+	require.NoError(t, err)
+
+	b, err := j.Replace(a, TxCandidate{TxData: []byte("ref-b"), To: &to})
+	require.NoError(t, err)
+	require.Greater(t, b.Seq, a.Seq)
+	require.Equal(t, []byte("data"), b.Data, "data is kept")
+	require.False(t, b.Confirmed)
+	require.Equal(t, 1, j.Depth())
+	entries, err := j.Entries()
+	require.NoError(t, err)
+	require.Equal(t, []JournalEntry{b}, entries)
+
+	_, err = j.Replace(a, TxCandidate{TxData: []byte("ref-c")})
+	require.Error(t, err, "replaced entry is gone")
+	_, added, err := j.Add(TxCandidate{TxData: []byte("ref-a"), To: &to}, []byte("data"))
+	require.NoError(t, err)
+	require.True(t, added, "replaced ref is forgotten")
+}
+
+func TestQueueStoreOnDAFailure(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	j := NewMemoryJournal(nil)
+	q := newTestDAQueue(t, ctx, new(testAnchorTxManager), j, AnchorPolicy{MinBatch: 2, MaxWait: time.Hour})
+	store := &testDAStore{failStores: daStoreAttempts}
+
+	receiptCh := make(chan TxReceipt[int], 1)
+	q.StoreOnDA(store, 1, TxCandidate{TxData: []byte("data")}, receiptCh)
+	r := <-receiptCh
+	require.Equal(t, 1, r.ID)
+	require.ErrorIs(t, r.Err, ErrStoreOnDA)
+	require.Zero(t, j.Depth(), "nothing is journaled")
+
+	// the next attempt is retried until it succeeds
+	store.failStores = daStoreAttempts - 1
+	q.StoreOnDA(store, 2, TxCandidate{TxData: []byte("data")}, receiptCh)
+	r = <-receiptCh
+	require.Equal(t, 2, r.ID)
+	require.NoError(t, r.Err)
+	require.Nil(t, r.Receipt, "there is no L1 receipt for data stored on DA")
+	require.NotNil(t, r.DAInclusion)
+	require.Equal(t, 1, j.Depth())
+	entries, err := j.Entries()
+	require.NoError(t, err)
+	require.Equal(t, entries[0].Candidate.TxData, r.DAInclusion.Ref)
+}
+
+func TestQueueStoreOnDAReorg(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	j := NewMemoryJournal(nil)
+	q := newTestDAQueue(t, ctx, new(testAnchorTxManager), j, AnchorPolicy{MinBatch: 2, MaxWait: time.Hour, ConfirmationDepth: 3})
+	store := &testDAStore{}
+
+	receiptCh := make(chan TxReceipt[int], 1)
+	q.StoreOnDA(store, 1, TxCandidate{TxData: []byte("data")}, receiptCh)
+	require.NoError(t, (<-receiptCh).Err)
+	entries, err := j.Entries()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	stored := entries[0]
+	require.Equal(t, []byte("data"), stored.Data)
+
+	// the stored data is reorged out before it is confirmed deep enough, so it is stored again
+	store.reorg(stored.Candidate.TxData)
+	store.setConfs(3)
+	require.Eventually(t, func() bool {
+		entries, err := j.Entries()
+		require.NoError(t, err)
+		return len(entries) == 1 && entries[0].Confirmed
+	}, 5*time.Second, 10*time.Millisecond)
+	entries, err = j.Entries()
+	require.NoError(t, err)
+	require.NotEqual(t, stored.Candidate.TxData, entries[0].Candidate.TxData, "anchor refers to the new store")
+	require.Equal(t, []byte("data"), entries[0].Data)
+}
+
+func TestQueueStoreOnDAAnchorReceipt(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	j := NewMemoryJournal(nil)
+	txMgr := new(testAnchorTxManager)
+	q := newTestDAQueue(t, ctx, txMgr, j, AnchorPolicy{MinBatch: 1, ConfirmationDepth: 3})
+	store := &testDAStore{}
+
+	receiptCh := make(chan TxReceipt[int], 1)
+	q.StoreOnDA(store, 1, TxCandidate{TxData: []byte("data")}, receiptCh)
+	r := <-receiptCh
+	require.NoError(t, r.Err)
+	require.Nil(t, r.DAInclusion.Anchor, "data is not anchored yet")
+
+	// the anchor of the data that is stored again after a reorg is reported for the original store
+	store.reorg(r.DAInclusion.Ref)
+	store.setConfs(3)
+	select {
+	case r = <-receiptCh:
+	case <-time.After(5 * time.Second):
+		t.Fatal("anchor was not reported")
+	}
+	require.Equal(t, 1, r.ID)
+	require.NoError(t, r.Err)
+	require.NotNil(t, r.DAInclusion.Anchor)
+	require.Equal(t, txMgr.sent[0], r.DAInclusion.Ref, "anchor refers to the new store")
+	require.Zero(t, j.Depth())
+
+	// data that was anchored before cannot be reported again
+	store.mu.Lock()
+	store.stores = 1
+	store.mu.Unlock()
+	q.StoreOnDA(store, 2, TxCandidate{TxData: []byte("data")}, receiptCh)
+	require.Error(t, (<-receiptCh).Err)
+}
+
+func TestQueueAnchorPolicy(t *testing.T) {
+	t.Run("min batch", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		j := NewMemoryJournal(nil)
+		txMgr := new(testAnchorTxManager)
+		q := newTestDAQueue(t, ctx, txMgr, j, AnchorPolicy{MinBatch: 2, MaxWait: time.Hour})
+		store := &testDAStore{}
+
+		receiptCh := make(chan TxReceipt[int], 1)
+		q.StoreOnDA(store, 1, TxCandidate{TxData: []byte("a")}, receiptCh)
+		require.NoError(t, (<-receiptCh).Err)
+		time.Sleep(2 * anchorPollInterval)
+		require.Zero(t, txMgr.numSent(), "a single anchor waits for the batch to fill")
+
+		q.StoreOnDA(store, 2, TxCandidate{TxData: []byte("b")}, receiptCh)
+		require.NoError(t, (<-receiptCh).Err)
+		require.Eventually(t, func() bool {
+			return txMgr.numSent() == 2 && j.Depth() == 0
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("max wait", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		j := NewMemoryJournal(nil)
+		txMgr := new(testAnchorTxManager)
+		q := newTestDAQueue(t, ctx, txMgr, j, AnchorPolicy{MinBatch: 10, MaxWait: anchorPollInterval})
+
+		receiptCh := make(chan TxReceipt[int], 1)
+		q.StoreOnDA(&testDAStore{}, 1, TxCandidate{TxData: []byte("a")}, receiptCh)
+		require.NoError(t, (<-receiptCh).Err)
+		require.Eventually(t, func() bool {
+			return txMgr.numSent() == 1 && j.Depth() == 0
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("aggregate", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		j := NewMemoryJournal(nil)
+		txMgr := new(testAnchorTxManager)
+		q := newTestDAQueue(t, ctx, txMgr, j, AnchorPolicy{
+			MinBatch: 3,
+			MaxWait:  time.Hour,
+			MaxBatch: 2,
+			Aggregate: func(txData [][]byte) ([]byte, error) {
+				return bytes.Join(txData, []byte("|")), nil
+			},
+		})
+
+		receiptCh := make(chan TxReceipt[int], 1)
+		store := &testDAStore{}
+		for i, data := range []string{"a", "b", "c"} {
+			q.StoreOnDA(store, i, TxCandidate{TxData: []byte(data)}, receiptCh)
+			require.NoError(t, (<-receiptCh).Err)
+		}
+		require.Eventually(t, func() bool {
+			return txMgr.numSent() == 2 && j.Depth() == 0
+		}, 5*time.Second, 10*time.Millisecond)
+		sent := string(txMgr.sent[0]) + "," + string(txMgr.sent[1])
+		require.Contains(t, []string{"\x01a|\x02b,\x03c", "\x03c,\x01a|\x02b"}, sent, "first two anchors are aggregated")
+	})
+
+	t.Run("flush", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		j := NewMemoryJournal(nil)
+		txMgr := new(testAnchorTxManager)
+		q := newTestDAQueue(t, ctx, txMgr, j, AnchorPolicy{MinBatch: 10, MaxWait: time.Hour, ConfirmationDepth: 3})
+
+		receiptCh := make(chan TxReceipt[int], 1)
+		q.StoreOnDA(&testDAStore{confs: 3}, 1, TxCandidate{TxData: []byte("a")}, receiptCh)
+		require.NoError(t, (<-receiptCh).Err)
+		q.StoreOnDA(&testDAStore{confs: 2}, 2, TxCandidate{TxData: []byte("b")}, receiptCh)
+		require.NoError(t, (<-receiptCh).Err)
+		require.Eventually(t, func() bool {
+			entries, err := j.Entries()
+			require.NoError(t, err)
+			return len(entries) == 2 && entries[0].Confirmed
+		}, 5*time.Second, 10*time.Millisecond)
+
+		n, err := q.FlushAnchors(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 1, n, "only the confirmed anchor is flushed")
+		require.Equal(t, 1, txMgr.numSent())
+		require.Equal(t, 1, j.Depth())
+	})
+
+	t.Run("send failure", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		j := NewMemoryJournal(nil)
+		txMgr := &testAnchorTxManager{sendErr: errors.New("send failed")}
+		q := newTestDAQueue(t, ctx, txMgr, j, AnchorPolicy{MinBatch: 1})
+
+		receiptCh := make(chan TxReceipt[int], 1)
+		q.StoreOnDA(&testDAStore{}, 1, TxCandidate{TxData: []byte("a")}, receiptCh)
+		require.NoError(t, (<-receiptCh).Err)
+		time.Sleep(2 * anchorPollInterval)
+		require.Equal(t, 1, j.Depth(), "anchor that failed to be sent stays in the journal")
+
+		txMgr.setSendErr(nil)
+		require.Eventually(t, func() bool {
+			return txMgr.numSent() == 1 && j.Depth() == 0
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+}
+
+func TestQueueClose(t *testing.T) {
+	j := NewMemoryJournal(nil)
+	txMgr := new(testAnchorTxManager)
+	q := newTestDAQueue(t, context.Background(), txMgr, j, AnchorPolicy{MinBatch: 1})
+
+	q.Close()
+	receiptCh := make(chan TxReceipt[int], 1)
+	q.StoreOnDA(&testDAStore{}, 1, TxCandidate{TxData: []byte("a")}, receiptCh)
+	require.NoError(t, (<-receiptCh).Err)
+	time.Sleep(2 * anchorPollInterval)
+	require.Zero(t, txMgr.numSent(), "anchors are not sent once the queue is closed")
+	require.Equal(t, 1, j.Depth())
+	_, err := q.FlushAnchors(context.Background())
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestQueueWithoutJournal(t *testing.T) {
+	q := NewQueue[int](context.Background(), new(testAnchorTxManager), 1)
+	defer q.Close()
+
+	receiptCh := make(chan TxReceipt[int], 1)
+	q.StoreOnDA(&testDAStore{}, 1, TxCandidate{TxData: []byte("a")}, receiptCh)
+	require.ErrorIs(t, (<-receiptCh).Err, ErrNoJournal)
+	require.ErrorIs(t, q.ResumeDA(&testDAStore{}), ErrNoJournal)
+	_, err := q.FlushAnchors(context.Background())
+	require.ErrorIs(t, err, ErrNoJournal)
+}
@@ -18,6 +18,7 @@ type TxMetricer interface {
 	TxConfirmed(*types.Receipt)
 	TxPublished(string)
 	RPCError()
+	RecordAnchorJournalDepth(depth int)
 }
 
 type TxMetrics struct {
@@ -32,6 +33,7 @@ type TxMetrics struct {
 	publishEvent       metrics.Event
 	confirmEvent       metrics.EventVec
 	rpcError           prometheus.Counter
+	anchorJournalDepth prometheus.Gauge
 }
 
 func receiptStatusString(receipt *types.Receipt) string {
@@ -106,6 +108,12 @@ func MakeTxMetrics(ns string, factory metrics.Factory) TxMetrics {
 			Help:      "Temporary: Count of RPC errors (like timeouts) that have occurred",
 			Subsystem: "txmgr",
 		}),
+		anchorJournalDepth: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace: ns,
+			Name:      "anchor_journal_depth",
+			Help:      "Number of references to data stored on a DA backend that are journaled to be sent to L1",
+			Subsystem: "txmgr",
+		}),
 	}
 }
 
@@ -151,3 +159,7 @@ func (t *TxMetrics) TxPublished(errString string) {
 func (t *TxMetrics) RPCError() {
 	t.rpcError.Inc()
 }
+
+func (t *TxMetrics) RecordAnchorJournalDepth(depth int) {
+	t.anchorJournalDepth.Set(float64(depth))
+}
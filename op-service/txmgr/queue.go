@@ -2,17 +2,28 @@ package txmgr
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"golang.org/x/sync/semaphore"
 	"math"
-	"math/big"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
 	"golang.org/x/sync/errgroup"
+	"golang.org/x/sync/semaphore"
+
+	"github.com/ethereum-optimism/optimism/op-service/backoff"
 )
 
+// ErrStoreOnDA is the error of the receipts of tx data that could not be stored on the DA backend.
+var ErrStoreOnDA = errors.New("store on DA failed")
+
+// ErrNoJournal is returned by the DA methods of a queue that was created without a journal.
+var ErrNoJournal = errors.New("queue has no DA journal")
+
 type TxReceipt[T any] struct {
 	// ID can be used to identify unique tx receipts within the recept channel
 	ID T
@@ -20,40 +31,149 @@ type TxReceipt[T any] struct {
 	Receipt *types.Receipt
 	// Err contains any error that occurred during the tx send
 	Err error
+	// DAInclusion is set instead of Receipt if the tx data was stored on a DA backend, rather than sent to L1
+	DAInclusion *DAInclusion
+}
+
+// DAInclusion is the receipt of tx data that was stored on a DA backend. The L1 anchor that references
+// the stored data is sent separately, and later, so there is no L1 inclusion block yet when the data is stored.
+// A second DAInclusion receipt, with the Anchor set, is returned once the L1 anchor is included.
+type DAInclusion struct {
+	// Ref is the reference to the stored data, as it is sent to L1 as tx data.
+	Ref []byte
+	// Anchor is the receipt of the L1 transaction that carries the reference,
+	// or nil if the data was only stored on the DA backend yet.
+	Anchor *types.Receipt
+}
+
+// AnchorPolicy controls when the L1 anchors of data stored on a DA backend are sent.
+type AnchorPolicy struct {
+	// MinBatch is the number of confirmed anchors to wait for, so that they are sent to L1 together.
+	MinBatch int
+	// MaxWait is the longest time that confirmed anchors are held back while waiting for MinBatch anchors.
+	MaxWait time.Duration
+	// ConfirmationDepth is the number of DA-blocks that stored data must be buried under before it is anchored.
+	ConfirmationDepth uint64
+	// MaxBatch is the maximum number of anchors that are aggregated into a single L1 transaction.
+	// Anchors are sent in separate transactions if it is less than 2, or if Aggregate is nil.
+	MaxBatch int
+	// Aggregate packs the tx data of multiple anchors into the tx data of a single L1 transaction.
+	Aggregate func(txData [][]byte) ([]byte, error)
 }
 
+func (p AnchorPolicy) Check() error {
+	if p.MinBatch < 1 {
+		return errors.New("anchor min batch must be at least 1")
+	}
+	if p.MaxWait < 0 {
+		return errors.New("anchor max wait cannot be negative")
+	}
+	if p.MaxBatch < 0 {
+		return errors.New("anchor max batch cannot be negative")
+	}
+	return nil
+}
+
+// DAStore is a data availability backend that tx data can be stored on,
+// with only a reference to the stored data being sent to L1.
+type DAStore interface {
+	// Store persists the data and returns the reference to it, which is sent to L1 as tx data.
+	Store(ctx context.Context, data []byte) ([]byte, error)
+	// Confirmations returns the number of DA-layer blocks built on top of the referenced data.
+	Confirmations(ctx context.Context, ref []byte) (uint64, error)
+}
+
+const (
+	// daStoreAttempts is the number of times a store on the DA backend is attempted before it fails.
+	daStoreAttempts = 5
+	// anchorSendAttempts is the number of times an L1 anchor is sent before it is left for the next tick.
+	anchorSendAttempts = 3
+
+	daConfirmationPollInterval = time.Second
+	anchorPollInterval         = time.Second
+)
+
 type Queue[T any] struct {
 	ctx        context.Context
+	l          log.Logger
 	txMgr      TxManager
 	maxPending uint64
-	daTxMgr    TxManager
 	groupLock  sync.Mutex
 	groupCtx   context.Context
 	group      *errgroup.Group
 
-	sem                     *semaphore.Weighted
-	daConfirmedReceiptQueue chan TxCandidate
+	sem     *semaphore.Weighted
+	journal *Journal
+	policy  AnchorPolicy
+	backoff backoff.Strategy
+	// flushReqs receives requests to send the confirmed anchors regardless of the policy,
+	// which are answered with the number of anchors that were sent.
+	flushReqs chan chan int
+	// daCtx is the context of the DA background routines, which is canceled on Close
+	daCtx    context.Context
+	daCancel context.CancelFunc
+	// anchorsDone is closed once the anchor routine returned
+	anchorsDone chan struct{}
+
+	// anchorWaiters are called with the L1 anchor receipt of the journal entry with the given seq, once it is
+	// included. Only entries that are stored by this queue are waited for, not those left by a previous queue.
+	anchorMu      sync.Mutex
+	anchorWaiters map[uint64][]func(ref []byte, receipt *types.Receipt)
 }
 
 // NewQueue creates a new transaction sending Queue, with the following parameters:
 //   - maxPending: max number of pending txs at once (0 == no limit)
 //   - pendingChanged: called whenever a tx send starts or finishes. The
 //     number of currently pending txs is passed as a parameter.
-func NewQueue[T any](ctx context.Context, txMgr TxManager, daTxMgr TxManager, maxPending uint64) *Queue[T] {
+//
+// The queue has no DA journal: StoreOnDA, ResumeDA and FlushAnchors fail with ErrNoJournal.
+func NewQueue[T any](ctx context.Context, txMgr TxManager, maxPending uint64) *Queue[T] {
+	return newQueue[T](ctx, log.Root(), txMgr, maxPending, nil, AnchorPolicy{})
+}
+
+// NewQueueWithJournal creates a new transaction sending Queue like NewQueue, which keeps the L1 anchors of data
+// stored with StoreOnDA in the given journal until they are sent according to the given policy. Confirmed anchors
+// left in the journal by a previous queue are sent too, see ResumeDA for the unconfirmed ones.
+// The anchors are sent by a background routine, which runs until the context is canceled or the queue is closed.
+func NewQueueWithJournal[T any](ctx context.Context, l log.Logger, txMgr TxManager, maxPending uint64, journal *Journal, policy AnchorPolicy) *Queue[T] {
+	q := newQueue[T](ctx, l, txMgr, maxPending, journal, policy)
+	q.daCtx, q.daCancel = context.WithCancel(ctx)
+	q.anchorsDone = make(chan struct{})
+	go func() {
+		defer close(q.anchorsDone)
+		q.sendStep2Routine()
+	}()
+	return q
+}
+
+func newQueue[T any](ctx context.Context, l log.Logger, txMgr TxManager, maxPending uint64, journal *Journal, policy AnchorPolicy) *Queue[T] {
 	if maxPending > math.MaxInt {
 		// ensure we don't overflow as errgroup only accepts int; in reality this will never be an issue
 		maxPending = math.MaxInt
 	}
-	q := &Queue[T]{
-		ctx:                     ctx,
-		txMgr:                   txMgr,
-		daTxMgr:                 daTxMgr,
-		maxPending:              maxPending,
-		sem:                     semaphore.NewWeighted(10),
-		daConfirmedReceiptQueue: make(chan TxCandidate),
+	return &Queue[T]{
+		ctx:        ctx,
+		l:          l,
+		txMgr:      txMgr,
+		maxPending: maxPending,
+		sem:        semaphore.NewWeighted(10),
+		journal:    journal,
+		policy:     policy,
+		backoff:    backoff.Exponential(),
+		flushReqs:  make(chan chan int),
+
+		anchorWaiters: make(map[uint64][]func(ref []byte, receipt *types.Receipt)),
 	}
-	go q.SendStep2Routine()
-	return q
+}
+
+// Close stops the DA background routines of the queue, and waits for the anchor routine to return.
+// Anchors that are not sent yet stay in the journal. Txs sent with Send or TrySend are not affected.
+func (q *Queue[T]) Close() {
+	if q.journal == nil {
+		return
+	}
+	q.daCancel()
+	<-q.anchorsDone
 }
 
 // Wait waits for all pending txs to complete (or fail).
@@ -77,139 +197,343 @@ func (q *Queue[T]) Send(id T, candidate TxCandidate, receiptCh chan TxReceipt[T]
 	})
 }
 
-func (q *Queue[T]) SendStep2Routine() {
-	wg := sync.WaitGroup{}
-	lock := sync.Mutex{}
-	candidates := []TxCandidate{}
-	go func() {
-		for {
-			select {
-			case c := <-q.daConfirmedReceiptQueue:
-				fmt.Println("============ add candidate to l1 queue ============")
-				lock.Lock()
-				candidates = append(candidates, c)
-				lock.Unlock()
-			}
-		}
-	}()
-
-	ticker := time.NewTicker(time.Minute)
+// sendStep2Routine sends the confirmed L1 anchors in the journal to L1, once at least the policy's MinBatch
+// anchors are confirmed, or the first of them has waited for the policy's MaxWait.
+// Anchors that fail to be sent, or are not sent before the queue is closed, stay in the journal
+// and are retried on the next tick.
+func (q *Queue[T]) sendStep2Routine() {
+	ticker := time.NewTicker(anchorPollInterval)
+	defer ticker.Stop()
+	// waitingSince is the time the currently confirmed anchors started waiting to be sent
+	var waitingSince time.Time
 	for {
-		if len(candidates) < 3 {
-			time.Sleep(time.Minute)
-			continue
-		}
+		var flushed chan int
 		select {
+		case <-q.daCtx.Done():
+			return
 		case <-ticker.C:
-			lock.Lock()
-			for _, l1Candidate := range candidates {
-				wg.Add(1)
-				go func(candidate TxCandidate) {
-					fmt.Println("== prepare send tx to l1")
-					receipt, err := q.txMgr.Send(context.Background(), candidate)
-					if err != nil {
-						panic(fmt.Errorf("send tx to l1 failed: %w", err))
-					}
-					fmt.Println("== send tx to l1 success", receipt.BlockNumber)
-					wg.Done()
-					q.sem.Release(1)
-				}(l1Candidate)
-				time.Sleep(time.Millisecond * 100)
+		case flushed = <-q.flushReqs:
+		}
+		entries, err := q.journal.Entries()
+		if err != nil {
+			q.l.Error("Failed to read DA journal", "err", err)
+			if flushed != nil {
+				flushed <- 0
 			}
-			candidates = []TxCandidate{}
-			lock.Unlock()
-			wg.Wait()
+			continue
+		}
+		var confirmed []JournalEntry
+		for _, e := range entries {
+			if e.Confirmed {
+				confirmed = append(confirmed, e)
+			}
+		}
+		if len(confirmed) == 0 {
+			waitingSince = time.Time{}
+			if flushed != nil {
+				flushed <- 0
+			}
+			continue
+		}
+		if waitingSince.IsZero() {
+			waitingSince = time.Now()
+		}
+		if flushed == nil && len(confirmed) < q.policy.MinBatch && time.Since(waitingSince) < q.policy.MaxWait {
+			continue
+		}
+		q.l.Info("Sending L1 anchors", "count", len(confirmed), "waited", time.Since(waitingSince), "flush", flushed != nil)
+		waitingSince = time.Time{}
+		var (
+			wg   sync.WaitGroup
+			sent atomic.Int64
+		)
+		for _, batch := range q.anchorBatches(confirmed) {
+			wg.Add(1)
+			go func(batch []JournalEntry) {
+				defer wg.Done()
+				if q.sendAnchors(batch) {
+					sent.Add(int64(len(batch)))
+				}
+			}(batch)
+		}
+		wg.Wait()
+		if flushed != nil {
+			flushed <- int(sent.Load())
 		}
 	}
+}
 
+// FlushAnchors sends the confirmed L1 anchors in the journal right away, regardless of the policy's
+// MinBatch and MaxWait, and returns the number of anchors that were sent. Anchors of data that is not yet
+// ConfirmationDepth DA-blocks deep are not sent.
+func (q *Queue[T]) FlushAnchors(ctx context.Context) (int, error) {
+	if q.journal == nil {
+		return 0, ErrNoJournal
+	}
+	flushed := make(chan int, 1)
+	select {
+	case q.flushReqs <- flushed:
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	case <-q.daCtx.Done():
+		return 0, q.daCtx.Err()
+	}
+	select {
+	case n := <-flushed:
+		return n, nil
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
 }
 
-func (q *Queue[T]) StoreOnDaServer(daServer string, prefixByte byte, id T, candidate TxCandidate, receiptCh chan TxReceipt[T]) {
-	// clone candidate
-	l1Candidate := candidate
-	if !q.sem.TryAcquire(1) {
-		time.Sleep(time.Minute)
-		receiptCh <- TxReceipt[T]{
-			ID:      id,
-			Receipt: nil,
-			Err:     fmt.Errorf("too many pending txs"),
+// anchorBatches splits the entries into the batches that are each sent in a single L1 transaction.
+func (q *Queue[T]) anchorBatches(entries []JournalEntry) [][]JournalEntry {
+	size := q.policy.MaxBatch
+	if q.policy.Aggregate == nil || size < 2 {
+		size = 1
+	}
+	var batches [][]JournalEntry
+	for len(entries) > size {
+		batches = append(batches, entries[:size])
+		entries = entries[size:]
+	}
+	return append(batches, entries)
+}
+
+// sendAnchors sends the L1 anchors of the journal entries in a single transaction, aggregating them if there are
+// multiple, and removes the entries from the journal once it is sent. It returns whether the transaction was sent.
+func (q *Queue[T]) sendAnchors(entries []JournalEntry) bool {
+	first := entries[0]
+	candidate := first.Candidate
+	if len(entries) > 1 {
+		txData := make([][]byte, len(entries))
+		for i, e := range entries {
+			txData[i] = e.Candidate.TxData
 		}
-		return
+		data, err := q.policy.Aggregate(txData)
+		if err != nil {
+			q.l.Error("Failed to aggregate L1 anchors", "first_seq", first.Seq, "count", len(entries), "err", err)
+			return false
+		}
+		// the gas limit of the aggregate is estimated
+		candidate = TxCandidate{To: first.Candidate.To, TxData: data}
+	}
+	var receipt *types.Receipt
+	err := backoff.DoCtx(q.daCtx, anchorSendAttempts, q.backoff, func() error {
+		var err error
+		receipt, err = q.txMgr.Send(q.daCtx, candidate)
+		if err != nil && q.daCtx.Err() == nil {
+			q.l.Warn("Failed to send L1 anchor", "first_seq", first.Seq, "count", len(entries), "err", err)
+		}
+		return err
+	})
+	if err != nil {
+		if q.daCtx.Err() == nil {
+			q.l.Error("Giving up on L1 anchor until next attempt", "first_seq", first.Seq, "count", len(entries), "err", err)
+		}
+		return false
+	}
+	for _, e := range entries {
+		if err := q.journal.Anchored(e); err != nil {
+			q.l.Error("Failed to remove anchored tx from journal", "seq", e.Seq, "err", err)
+		}
+		q.anchored(e, receipt)
+	}
+	return true
+}
+
+// waitAnchor registers the callback to be called with the L1 anchor receipt of the journal entry, once it is included.
+func (q *Queue[T]) waitAnchor(seq uint64, fn func(ref []byte, receipt *types.Receipt)) {
+	q.anchorMu.Lock()
+	defer q.anchorMu.Unlock()
+	q.anchorWaiters[seq] = append(q.anchorWaiters[seq], fn)
+}
+
+// replaceAnchorWaiters moves the callbacks waiting for the anchor of a journal entry over to its replacement.
+func (q *Queue[T]) replaceAnchorWaiters(old uint64, replacement uint64) {
+	q.anchorMu.Lock()
+	defer q.anchorMu.Unlock()
+	if fns, ok := q.anchorWaiters[old]; ok {
+		q.anchorWaiters[replacement] = fns
+		delete(q.anchorWaiters, old)
 	}
-	group, _ := q.groupContext()
+}
+
+// anchored calls the callbacks waiting for the anchor of the journal entry.
+func (q *Queue[T]) anchored(entry JournalEntry, receipt *types.Receipt) {
+	q.anchorMu.Lock()
+	fns := q.anchorWaiters[entry.Seq]
+	delete(q.anchorWaiters, entry.Seq)
+	q.anchorMu.Unlock()
+	for _, fn := range fns {
+		fn(entry.Candidate.TxData, receipt)
+	}
+}
+
+// StoreOnDA stores the candidate tx data on the given data availability backend, instead of sending it to L1.
+// The reference to the stored data is journaled, and once the stored data is at least the policy's
+// ConfirmationDepth DA-blocks deep, a transaction carrying the reference is sent to L1. Data that is reorged out of the DA chain before that
+// is stored again.
+//
+// The receipt returned on the provided receipt channel is a DAInclusion, which reflects the store on the DA backend.
+// It carries an error if the data could not be stored, so that the data can be sent again. A second DAInclusion
+// receipt, with the Anchor set, is returned once the L1 anchor is included. It is not returned if the queue
+// is closed before.
+func (q *Queue[T]) StoreOnDA(store DAStore, id T, candidate TxCandidate, receiptCh chan TxReceipt[T]) {
+	group, ctx := q.groupContext()
 	group.Go(func() error {
-		blobKey, err := StoreBlob(daServer+"/store", candidate.TxData)
+		if q.journal == nil {
+			receiptCh <- TxReceipt[T]{ID: id, Err: ErrNoJournal}
+			return ErrNoJournal
+		}
+		if err := q.sem.Acquire(ctx, 1); err != nil {
+			receiptCh <- TxReceipt[T]{
+				ID:  id,
+				Err: fmt.Errorf("too many pending DA stores: %w", err),
+			}
+			return err
+		}
+		defer q.sem.Release(1)
+		ref, err := q.storeOnDA(ctx, store, candidate.TxData)
+		if err != nil {
+			receiptCh <- TxReceipt[T]{
+				ID:  id,
+				Err: fmt.Errorf("%w: %v", ErrStoreOnDA, err),
+			}
+			return err
+		}
+		l1Candidate := candidate
+		l1Candidate.TxData = ref
+		entry, added, err := q.journal.Add(l1Candidate, candidate.TxData)
 		if err != nil {
-			time.Sleep(time.Minute)
 			receiptCh <- TxReceipt[T]{
-				ID:      id,
-				Receipt: nil,
-				Err:     fmt.Errorf("Store blob on daServer failed: %w", err),
+				ID:  id,
+				Err: fmt.Errorf("failed to journal DA reference: %w", err),
 			}
-			q.sem.Release(1)
 			return err
 		}
-		fmt.Println("blobkey", blobKey)
 
-		blockHeight := big.NewInt(time.Now().Unix() / 600)
+		seq := entry.Seq
+		if !added {
+			// the same data is already journaled, the anchor of the pending entry is waited for
+			var pending bool
+			seq, pending, err = q.journal.pendingSeq(l1Candidate.TxData)
+			if err != nil || !pending {
+				if err == nil {
+					// the anchor receipt of data that was anchored before is no longer known
+					err = errors.New("DA reference was anchored before")
+				}
+				receiptCh <- TxReceipt[T]{
+					ID:  id,
+					Err: fmt.Errorf("failed to journal DA reference: %w", err),
+				}
+				return err
+			}
+		}
+		q.waitAnchor(seq, func(ref []byte, receipt *types.Receipt) {
+			// the anchor is reported from the anchor routine, which must not block on the receipt channel
+			go func() {
+				select {
+				case receiptCh <- TxReceipt[T]{ID: id, DAInclusion: &DAInclusion{Ref: ref, Anchor: receipt}}:
+				case <-q.daCtx.Done():
+				}
+			}()
+		})
 		receiptCh <- TxReceipt[T]{
-			ID: id,
-			Receipt: &types.Receipt{
-				BlockNumber: blockHeight,
-			},
-			Err: err,
+			ID:          id,
+			DAInclusion: &DAInclusion{Ref: ref},
 		}
+		if added {
+			go q.awaitDAConfirmation(store, entry)
+		}
+		return nil
+	})
+}
 
-		l1Candidate.TxData = append([]byte{prefixByte}, blobKey...)
-		q.daConfirmedReceiptQueue <- l1Candidate
-
+// storeOnDA stores the data on the DA backend, retrying with backoff, and returns the reference to it.
+func (q *Queue[T]) storeOnDA(ctx context.Context, store DAStore, data []byte) ([]byte, error) {
+	var ref []byte
+	err := backoff.DoCtx(ctx, daStoreAttempts, q.backoff, func() error {
+		var err error
+		ref, err = store.Store(ctx, data)
+		if err != nil && ctx.Err() == nil {
+			q.l.Warn("Failed to store data on DA", "err", err)
+		}
 		return err
 	})
+	return ref, err
 }
 
-func (q *Queue[T]) Send2Step(id T, candidate TxCandidate, receiptCh chan TxReceipt[T]) {
-	// clone candidate
-	l1Candidate := candidate
-	if !q.sem.TryAcquire(1) {
-		time.Sleep(time.Minute)
-		receiptCh <- TxReceipt[T]{
-			ID:      id,
-			Receipt: nil,
-			Err:     fmt.Errorf("too many pending txs"),
-		}
-		return
+// ResumeDA waits for the DA confirmation of the unconfirmed anchors left in the journal by a previous queue,
+// which must have been stored on the given DA backend.
+func (q *Queue[T]) ResumeDA(store DAStore) error {
+	if q.journal == nil {
+		return ErrNoJournal
 	}
-	group, ctx := q.groupContext()
-	group.Go(func() error {
-		receipt, err := q.daTxMgr.Send(ctx, candidate) //after short period of time
-		receiptCh <- TxReceipt[T]{
-			ID:      id,
-			Receipt: receipt,
-			Err:     err,
-		}
-		if err != nil {
-			return err
+	entries, err := q.journal.Entries()
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if !e.Confirmed {
+			go q.awaitDAConfirmation(store, e)
 		}
+	}
+	return nil
+}
 
-		go func() {
-			for {
-				latestBlock, _ := q.daTxMgr.(*SimpleTxManager).backend.BlockNumber(context.Background())
-				if receipt.BlockNumber.Uint64() < latestBlock-375 {
-					break
+// awaitDAConfirmation marks the journal entry as confirmed once its data is the policy's ConfirmationDepth DA-blocks deep.
+// If the data is reorged out of the DA chain in the meantime, it is stored again and the entry is replaced.
+func (q *Queue[T]) awaitDAConfirmation(store DAStore, entry JournalEntry) {
+	for {
+		confs, err := store.Confirmations(q.daCtx, entry.Candidate.TxData)
+		if errors.Is(err, ethereum.NotFound) {
+			q.l.Warn("DA data was reorged out, storing it again", "seq", entry.Seq)
+			replaced, err := q.restoreOnDA(store, entry)
+			if err != nil {
+				if q.daCtx.Err() == nil {
+					q.l.Error("Failed to store reorged DA data again", "seq", entry.Seq, "err", err)
 				}
-				time.Sleep(time.Second * 1)
-			}
-			header, _ := q.daTxMgr.(*SimpleTxManager).backend.HeaderByNumber(context.Background(), receipt.BlockNumber)
-			if header.Hash().String() != receipt.BlockHash.String() {
-				panic("DA tx is forked! We need to reboot")
+				return
 			}
+			q.replaceAnchorWaiters(entry.Seq, replaced.Seq)
+			entry = replaced
+			continue
+		}
+		if err != nil {
+			q.l.Warn("Failed to fetch DA confirmations", "seq", entry.Seq, "err", err)
+		} else if confs >= q.policy.ConfirmationDepth {
+			break
+		}
+		select {
+		case <-q.daCtx.Done():
+			return
+		case <-time.After(daConfirmationPollInterval):
+		}
+	}
+	if err := q.journal.Confirm(entry); err != nil {
+		// the entry stays unconfirmed, and is awaited again when the journal is resumed
+		q.l.Error("Failed to confirm DA reference in journal", "seq", entry.Seq, "err", err)
+	}
+}
 
-			l1Candidate.TxData = append([]byte{1}, receipt.TxHash.Bytes()...)
-			q.daConfirmedReceiptQueue <- l1Candidate
-		}()
-
-		return err
-	})
+// restoreOnDA stores the data of the journal entry on the DA backend again, until it succeeds or the
+// queue is closed, and replaces the entry with one that refers to the newly stored data.
+func (q *Queue[T]) restoreOnDA(store DAStore, entry JournalEntry) (JournalEntry, error) {
+	if len(entry.Data) == 0 {
+		return entry, fmt.Errorf("data of journal entry %d is unknown", entry.Seq)
+	}
+	for {
+		ref, err := q.storeOnDA(q.daCtx, store, entry.Data)
+		if err == nil {
+			candidate := entry.Candidate
+			candidate.TxData = ref
+			return q.journal.Replace(entry, candidate)
+		}
+		if q.daCtx.Err() != nil {
+			return entry, q.daCtx.Err()
+		}
+	}
 }
 
 // TrySend sends the next tx, but only if the number of pending txs is below the